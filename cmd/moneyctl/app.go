@@ -0,0 +1,146 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/config"
+	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/domain/data"
+	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/fraud"
+	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/infrastructure/database/postgresql"
+	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/logging"
+	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/metrics"
+	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/usecases"
+)
+
+// Store runs the maintenance tasks that work on the database directly
+type Store interface {
+	Migrate() error
+	MigrateDown(steps int) error
+	MigrationStatus() ([]postgresql.MigrationState, error)
+	CreateSystemAccount(ctx context.Context) error
+}
+
+// App holds the business logic the commands run, the same the API servers run
+type App struct {
+	Uc      usecases.MoneyTransferUsecases
+	Periods usecases.PeriodUsecases
+	Reports usecases.ReportingUsecases
+	Queries usecases.QueryUsecases
+	Store   Store
+
+	// Location is the business time zone plain dates are read in
+	Location *time.Location
+}
+
+// CheckPreconditions ensures a correct App struct is initialized
+func (a App) CheckPreconditions() {
+	if a.Uc == nil {
+		log.Panic("moneyctl has not initialized the money transfer business logic")
+	}
+
+	if a.Periods == nil {
+		log.Panic("moneyctl has not initialized the accounting periods business logic")
+	}
+
+	if a.Reports == nil {
+		log.Panic("moneyctl has not initialized the reporting business logic")
+	}
+
+	if a.Queries == nil {
+		log.Panic("moneyctl has not initialized the query business logic")
+	}
+
+	if a.Store == nil {
+		log.Panic("moneyctl has not initialized the store")
+	}
+
+	if a.Location == nil {
+		log.Panic("moneyctl has not initialized the business time zone")
+	}
+}
+
+// NewApp initializes the commands' business logic
+func NewApp(
+	uc usecases.MoneyTransferUsecases,
+	periods usecases.PeriodUsecases,
+	reports usecases.ReportingUsecases,
+	queries usecases.QueryUsecases,
+	store Store,
+	location *time.Location,
+) *App {
+	a := &App{
+		Uc:       uc,
+		Periods:  periods,
+		Reports:  reports,
+		Queries:  queries,
+		Store:    store,
+		Location: location,
+	}
+	a.CheckPreconditions()
+	return a
+}
+
+// database runs the maintenance tasks against PostgreSQL
+type database struct {
+	*postgresql.PostgreSQL
+}
+
+// Migrate applies the pending schema migrations
+func (d database) Migrate() error {
+	return postgresql.Migrate(d.ORM)
+}
+
+// MigrateDown reverts the latest schema migrations
+func (d database) MigrateDown(steps int) error {
+	return postgresql.MigrateDown(d.ORM, steps)
+}
+
+// MigrationStatus lists the schema migrations and whether they are applied
+func (d database) MigrationStatus() ([]postgresql.MigrationState, error) {
+	return postgresql.MigrationStatus(d.ORM)
+}
+
+// connect wires the commands to the database configured by the same file and environment variables as the server
+func connect(configFile string) (*App, error) {
+	cfg, err := config.Read(configFile)
+	if err != nil {
+		return nil, err
+	}
+	if err := cfg.Database.Validate(); err != nil {
+		return nil, err
+	}
+	chart, err := data.LoadChartOfAccounts(cfg.Accounting.ChartOfAccountsFile)
+	if err != nil {
+		return nil, fmt.Errorf("unable to load the chart of accounts: %v", err)
+	}
+	location, err := time.LoadLocation(cfg.Accounting.TimeZone)
+	if err != nil {
+		return nil, fmt.Errorf("unable to load the business time zone: %v", err)
+	}
+
+	// Only warnings and errors are logged, on stderr, so they do not mix with the commands' output
+	logger, err := logging.New("warn", os.Stderr)
+	if err != nil {
+		return nil, err
+	}
+
+	db, err := postgresql.ConnectToDatabase(cfg.Database, logger)
+	if err != nil {
+		return nil, err
+	}
+
+	store := postgresql.NewPostgreSQLDatabase(db, chart)
+	return NewApp(
+		// Operators' transfers are not screened for fraud
+		usecases.NewMoneyTransferUsecases(store, store, store, fraud.NewEngine(store), metrics.Noop{}, logger),
+		usecases.NewPeriodUsecases(store, store, store, logger, location),
+		usecases.NewReportingUsecases(store, store),
+		usecases.NewQueryUsecases(store),
+		database{store},
+		location,
+	), nil
+}
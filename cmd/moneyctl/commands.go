@@ -0,0 +1,409 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/application"
+	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/domain"
+	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/presentation/validation"
+	"github.com/shopspring/decimal"
+)
+
+var dateLayout = "2006-01-02"
+
+// newFlags returns a command's flag set, its errors are reported by the command
+func newFlags(name string) *flag.FlagSet {
+	flags := flag.NewFlagSet(name, flag.ContinueOnError)
+	flags.SetOutput(io.Discard)
+	return flags
+}
+
+// parseAmount decodes a decimal amount flag
+func parseAmount(name string, value string) (*decimal.Decimal, error) {
+	if value == "" {
+		return nil, fmt.Errorf("-%s is required", name)
+	}
+	amount, err := decimal.NewFromString(value)
+	if err != nil {
+		return nil, fmt.Errorf("-%s should be a decimal amount, got %q", name, value)
+	}
+	return &amount, nil
+}
+
+// parseDate decodes a YYYY-MM-DD date in the business time zone, or an RFC3339 date flag. A day ends at its last
+// instant when endOfDay is set
+func parseDate(name string, value string, location *time.Location, endOfDay bool) (*time.Time, error) {
+	if value == "" {
+		return nil, nil
+	}
+
+	if date, err := time.Parse(time.RFC3339, value); err == nil {
+		return &date, nil
+	}
+
+	date, err := time.ParseInLocation(dateLayout, value, location)
+	if err != nil {
+		return nil, fmt.Errorf("-%s should be a date (YYYY-MM-DD) or an RFC3339 timestamp", name)
+	}
+	if endOfDay {
+		date = date.AddDate(0, 0, 1).Add(-time.Nanosecond)
+	}
+	return &date, nil
+}
+
+// accountID reads the single account ID argument of a command
+func accountID(flags *flag.FlagSet) (string, error) {
+	if flags.NArg() != 1 {
+		return "", fmt.Errorf("expected a single account ID argument after the flags")
+	}
+	return flags.Arg(0), nil
+}
+
+// formatTime formats an optional timestamp for a table cell
+func formatTime(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.Format(time.RFC3339)
+}
+
+// accountOutput shows a single account
+func accountOutput(account *application.AccountInformationOutput) *output {
+	balance := ""
+	if account.Balance != nil {
+		balance = account.Balance.String()
+	}
+	return &output{
+		Value:  account,
+		Header: []string{"ID", "NUMBER", "NAME", "HEADER", "CURRENCY", "BALANCE"},
+		Rows: [][]string{{
+			account.UUID,
+			account.Number,
+			account.Name,
+			string(account.Header),
+			string(account.Currency),
+			balance,
+		}},
+	}
+}
+
+// transactionOutput shows a posted transaction
+func transactionOutput(transaction *domain.Transaction) *output {
+	return &output{
+		Value:  transaction,
+		Header: []string{"TRANSACTION", "DESCRIPTION", "CREATED AT"},
+		Rows:   [][]string{{transaction.UUID, transaction.Description, formatTime(transaction.CreatedAt)}},
+	}
+}
+
+func createAccount(ctx context.Context, app *App, args []string) (*output, error) {
+	flags := newFlags("create-account")
+	name := flags.String("name", "", "customer name")
+	amount := flags.String("amount", "", "initial deposit")
+	currency := flags.String("currency", string(domain.Kenyan), "account currency")
+	header := flags.String("header", string(domain.Deposit), "account header")
+	if err := flags.Parse(args); err != nil {
+		return nil, err
+	}
+
+	deposit, err := parseAmount("amount", *amount)
+	if err != nil {
+		return nil, err
+	}
+	accountCurrency := domain.CurrencyType(*currency)
+	input := application.AccountCreationInput{
+		CustomerName: *name,
+		Amount:       deposit,
+		Currency:     &accountCurrency,
+		Header:       domain.HeaderType(*header),
+	}
+	if err := validation.Validate(input); err != nil {
+		return nil, err
+	}
+
+	account, err := app.Uc.CreateCustomerAccount(ctx, input)
+	if err != nil {
+		return nil, err
+	}
+	return accountOutput(account), nil
+}
+
+func transfer(ctx context.Context, app *App, args []string) (*output, error) {
+	flags := newFlags("transfer")
+	from := flags.String("from", "", "source account ID")
+	to := flags.String("to", "", "destination account ID")
+	amount := flags.String("amount", "", "amount to transfer")
+	if err := flags.Parse(args); err != nil {
+		return nil, err
+	}
+
+	transferAmount, err := parseAmount("amount", *amount)
+	if err != nil {
+		return nil, err
+	}
+	payload := application.TransferPayload{
+		SourceAccountID:      *from,
+		DestinationAccountID: *to,
+		Amount:               transferAmount,
+	}
+	if err := validation.Validate(payload); err != nil {
+		return nil, err
+	}
+
+	sourceAccount, err := app.Uc.Account(ctx, payload.SourceAccountID)
+	if err != nil {
+		return nil, err
+	}
+
+	destinationAccount, err := app.Uc.Account(ctx, payload.DestinationAccountID)
+	if err != nil {
+		return nil, err
+	}
+
+	transaction, err := app.Uc.Transfer(ctx, application.TransferInput{
+		SourceAccount:      sourceAccount,
+		DestinationAccount: destinationAccount,
+		Amount:             payload.Amount,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return transactionOutput(transaction), nil
+}
+
+func adjust(ctx context.Context, app *App, args []string) (*output, error) {
+	flags := newFlags("adjust")
+	debit := flags.String("debit", "", "account ID to debit")
+	credit := flags.String("credit", "", "account ID to credit")
+	amount := flags.String("amount", "", "amount to adjust by")
+	description := flags.String("description", "", "reason for the adjustment")
+	date := flags.String("date", "", "effective date, YYYY-MM-DD or RFC3339, defaults to now")
+	if err := flags.Parse(args); err != nil {
+		return nil, err
+	}
+
+	adjustment, err := parseAmount("amount", *amount)
+	if err != nil {
+		return nil, err
+	}
+	effectiveDate, err := parseDate("date", *date, app.Location, false)
+	if err != nil {
+		return nil, err
+	}
+
+	transaction, err := app.Periods.PostAdjustment(ctx, application.AdjustmentInput{
+		DebitAccountID:  *debit,
+		CreditAccountID: *credit,
+		Amount:          adjustment,
+		Description:     *description,
+		EffectiveDate:   effectiveDate,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return transactionOutput(transaction), nil
+}
+
+func balance(ctx context.Context, app *App, args []string) (*output, error) {
+	flags := newFlags("balance")
+	if err := flags.Parse(args); err != nil {
+		return nil, err
+	}
+	id, err := accountID(flags)
+	if err != nil {
+		return nil, err
+	}
+
+	account, err := app.Uc.Account(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return accountOutput(account), nil
+}
+
+// accountBalance is an account listed with its current balance
+type accountBalance struct {
+	*domain.Account
+	Balance decimal.Decimal
+}
+
+func balances(ctx context.Context, app *App, args []string) (*output, error) {
+	flags := newFlags("balances")
+	currency := flags.String("currency", "", "only accounts in this currency")
+	header := flags.String("header", "", "only accounts under this header")
+	first := flags.Int("first", 0, "number of accounts to list")
+	after := flags.String("after", "", "cursor of the last account already listed")
+	if err := flags.Parse(args); err != nil {
+		return nil, err
+	}
+
+	var filter application.AccountFilter
+	if *currency != "" {
+		accountCurrency := domain.CurrencyType(*currency)
+		filter.Currency = &accountCurrency
+	}
+	if *header != "" {
+		accountHeader := domain.HeaderType(*header)
+		filter.Header = &accountHeader
+	}
+
+	page, err := app.Queries.Accounts(ctx, filter, application.PageInput{First: *first, After: *after})
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]string, len(page.Items))
+	for i, account := range page.Items {
+		ids[i] = account.UUID
+	}
+	accountBalances, err := app.Queries.AccountBalances(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	listed := application.Page[*accountBalance]{HasNextPage: page.HasNextPage, EndCursor: page.EndCursor}
+	result := output{Header: []string{"ID", "NUMBER", "NAME", "HEADER", "CURRENCY", "BALANCE"}}
+	for _, account := range page.Items {
+		listed.Items = append(listed.Items, &accountBalance{Account: account, Balance: accountBalances[account.UUID]})
+		result.Rows = append(result.Rows, []string{
+			account.UUID,
+			account.Number,
+			account.Name,
+			string(account.Header),
+			string(account.Currency),
+			accountBalances[account.UUID].String(),
+		})
+	}
+	if page.HasNextPage {
+		result.Rows = append(result.Rows, []string{"", "", "more with -after " + page.EndCursor})
+	}
+	result.Value = listed
+	return &result, nil
+}
+
+func entries(ctx context.Context, app *App, args []string) (*output, error) {
+	flags := newFlags("entries")
+	from := flags.String("from", "", "start date, YYYY-MM-DD or RFC3339")
+	to := flags.String("to", "", "end date, YYYY-MM-DD or RFC3339")
+	first := flags.Int("first", 0, "number of entries to list")
+	after := flags.String("after", "", "cursor of the last entry already listed")
+	if err := flags.Parse(args); err != nil {
+		return nil, err
+	}
+	id, err := accountID(flags)
+	if err != nil {
+		return nil, err
+	}
+
+	filter := application.EntryFilter{AccountID: id}
+	if filter.From, err = parseDate("from", *from, app.Location, false); err != nil {
+		return nil, err
+	}
+	if filter.To, err = parseDate("to", *to, app.Location, true); err != nil {
+		return nil, err
+	}
+
+	page, err := app.Queries.Entries(ctx, filter, application.PageInput{First: *first, After: *after})
+	if err != nil {
+		return nil, err
+	}
+
+	result := output{
+		Value:  page,
+		Header: []string{"ENTRY", "TRANSACTION", "DATE", "DEBIT", "CREDIT"},
+	}
+	for _, entry := range page.Items {
+		date := entry.EffectiveDate
+		if date == nil {
+			date = entry.CreatedAt
+		}
+		result.Rows = append(result.Rows, []string{
+			entry.UUID,
+			entry.TransactionID,
+			formatTime(date),
+			entry.DebitAmount.String(),
+			entry.CreditAmount.String(),
+		})
+	}
+	if page.HasNextPage {
+		result.Rows = append(result.Rows, []string{"", "more with -after " + page.EndCursor})
+	}
+	return &result, nil
+}
+
+func migrate(ctx context.Context, app *App, args []string) (*output, error) {
+	flags := newFlags("migrate")
+	down := flags.Int("down", 0, "number of migrations to revert")
+	status := flags.Bool("status", false, "list the migrations without changing the schema")
+	if err := flags.Parse(args); err != nil {
+		return nil, err
+	}
+	if *down < 0 {
+		return nil, fmt.Errorf("-down should be a positive number of migrations")
+	}
+	if *down > 0 && *status {
+		return nil, fmt.Errorf("-down and -status can't be combined")
+	}
+
+	if !*status {
+		apply := app.Store.Migrate
+		if *down > 0 {
+			apply = func() error { return app.Store.MigrateDown(*down) }
+		}
+		if err := apply(); err != nil {
+			return nil, err
+		}
+	}
+
+	states, err := app.Store.MigrationStatus()
+	if err != nil {
+		return nil, err
+	}
+
+	result := output{Value: states, Header: []string{"VERSION", "NAME", "STATUS", "APPLIED AT"}}
+	for _, state := range states {
+		result.Rows = append(result.Rows, []string{
+			fmt.Sprintf("%04d", state.Version),
+			state.Name,
+			state.Status,
+			formatTime(state.AppliedAt),
+		})
+	}
+	return &result, nil
+}
+
+func seed(ctx context.Context, app *App, args []string) (*output, error) {
+	if err := app.Store.CreateSystemAccount(ctx); err != nil {
+		return nil, err
+	}
+	return &output{Value: map[string]bool{"seeded": true}, Header: []string{"SEEDED"}, Rows: [][]string{{"true"}}}, nil
+}
+
+// check exits with an error when the ledger is out of balance, so that it can gate scripts
+func check(ctx context.Context, app *App, args []string) (*output, error) {
+	result, err := app.Reports.LedgerCheck(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	out := &output{
+		Value:  result,
+		Header: []string{"BALANCED", "TOTAL DEBIT", "TOTAL CREDIT", "DIFFERENCE", "UNBALANCED TRANSACTIONS"},
+		Rows: [][]string{{
+			strconv.FormatBool(result.Balanced),
+			result.TotalDebit.String(),
+			result.TotalCredit.String(),
+			result.Difference.String(),
+			strconv.Itoa(len(result.UnbalancedTransactions)),
+		}},
+	}
+	if !result.Balanced {
+		return out, fmt.Errorf("the ledger is out of balance")
+	}
+	return out, nil
+}
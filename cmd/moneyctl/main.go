@@ -0,0 +1,158 @@
+// Command moneyctl runs routine ledger operations against the service's database, through the same
+// usecases as the APIs:
+//
+//	moneyctl [-o table|json] <command> [flags]
+//
+// It reads the database settings from the same environment variables as the server.
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/presentation/validation"
+)
+
+// output is a command's result, Value is printed as JSON while Header and Rows are printed as a table
+type output struct {
+	Value  interface{}
+	Header []string
+	Rows   [][]string
+}
+
+// command is a moneyctl subcommand
+type command struct {
+	usage   string
+	summary string
+	run     func(ctx context.Context, app *App, args []string) (*output, error)
+}
+
+// COMMANDS lists moneyctl's subcommands by name
+var COMMANDS = map[string]command{
+	"create-account": {"-name NAME -amount AMOUNT [-currency KSH] [-header DEPOSIT]", "Open a customer account with an initial deposit", createAccount},
+	"transfer":       {"-from ACCOUNT_ID -to ACCOUNT_ID -amount AMOUNT", "Transfer money between accounts", transfer},
+	"adjust":         {"-debit ACCOUNT_ID -credit ACCOUNT_ID -amount AMOUNT -description TEXT [-date DATE]", "Post a manual journal adjustment", adjust},
+	"balance":        {"ACCOUNT_ID", "Show an account with its current balance", balance},
+	"balances":       {"[-currency KSH] [-header DEPOSIT] [-first N] [-after CURSOR]", "List accounts with their current balances", balances},
+	"entries":        {"[-from DATE] [-to DATE] [-first N] [-after CURSOR] ACCOUNT_ID", "List the entries posted to an account", entries},
+	"migrate":        {"[-down N | -status]", "Apply the pending schema migrations, revert the latest N or list them", migrate},
+	"seed":           {"", "Create the chart of accounts and the system accounts", seed},
+	"check":          {"", "Check that the ledger's debits and credits balance", check},
+}
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr, connect))
+}
+
+// run executes a command line, connecting only once the command is known, and returns the exit code
+func run(args []string, stdout io.Writer, stderr io.Writer, connect func(configFile string) (*App, error)) int {
+	flags := flag.NewFlagSet("moneyctl", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	format := flags.String("o", "table", "output format, table or json")
+	configFile := flags.String("config", "", "YAML configuration file, settings in the environment take precedence")
+	flags.Usage = func() { usage(stderr) }
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+
+	if *format != "table" && *format != "json" {
+		fmt.Fprintf(stderr, "unknown output format %q, use table or json\n", *format)
+		return 2
+	}
+
+	if flags.NArg() == 0 || flags.Arg(0) == "help" {
+		usage(stderr)
+		return 2
+	}
+
+	cmd, ok := COMMANDS[flags.Arg(0)]
+	if !ok {
+		fmt.Fprintf(stderr, "unknown command %q\n", flags.Arg(0))
+		usage(stderr)
+		return 2
+	}
+
+	if err := validation.RegisterValidators(); err != nil {
+		fmt.Fprintf(stderr, "unable to register request validators: %v\n", err)
+		return 1
+	}
+
+	app, err := connect(*configFile)
+	if err != nil {
+		fmt.Fprintf(stderr, "unable to connect to the database: %v\n", err)
+		return 1
+	}
+
+	result, err := cmd.run(context.Background(), app, flags.Args()[1:])
+	if result != nil {
+		if printErr := render(stdout, *format, result); printErr != nil {
+			fmt.Fprintf(stderr, "unable to print the result: %v\n", printErr)
+			return 1
+		}
+	}
+	if err != nil {
+		reportError(stderr, flags.Arg(0), err)
+		return 1
+	}
+	return 0
+}
+
+// reportError explains why a command failed, failed validations are reported per field
+func reportError(w io.Writer, name string, err error) {
+	fields, ok := validation.FieldErrors(err)
+	if !ok {
+		fmt.Fprintf(w, "%s: %v\n", name, err)
+		return
+	}
+
+	fmt.Fprintf(w, "%s: the request has invalid fields\n", name)
+	for _, field := range sortedKeys(fields) {
+		fmt.Fprintf(w, "  %s: %s\n", field, fields[field])
+	}
+}
+
+// sortedKeys lists a map's keys in order
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// usage lists the commands and their flags
+func usage(w io.Writer) {
+	fmt.Fprintln(w, "usage: moneyctl [-o table|json] <command> [flags]")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "commands:")
+
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	for _, name := range sortedKeys(COMMANDS) {
+		fmt.Fprintf(tw, "  %s %s\t%s\n", name, COMMANDS[name].usage, COMMANDS[name].summary)
+	}
+	tw.Flush()
+}
+
+// render writes a command's result in the requested format
+func render(w io.Writer, format string, result *output) error {
+	if format == "json" {
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(result.Value)
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, strings.Join(result.Header, "\t"))
+	for _, row := range result.Rows {
+		fmt.Fprintln(tw, strings.Join(row, "\t"))
+	}
+	return tw.Flush()
+}
@@ -0,0 +1,324 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/application"
+	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/domain"
+	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/infrastructure/database/postgresql"
+	"github.com/shopspring/decimal"
+)
+
+var (
+	sourceID      = "0b0e4f5c-6f5e-4a4e-9b3a-4a1f2d3c4b5a"
+	destinationID = "9f1c2d3e-4b5a-4c6d-8e7f-0a1b2c3d4e5f"
+)
+
+type stubMoneyTransfer struct{}
+
+func (stubMoneyTransfer) CreateCustomerAccount(ctx context.Context, input application.AccountCreationInput) (*application.AccountInformationOutput, error) {
+	return &application.AccountInformationOutput{UUID: destinationID, Name: input.CustomerName, Currency: *input.Currency, Header: input.Header, Balance: input.Amount}, nil
+}
+
+func (stubMoneyTransfer) Account(ctx context.Context, accountID string) (*application.AccountInformationOutput, error) {
+	balance := decimal.NewFromInt(100)
+	return &application.AccountInformationOutput{UUID: accountID, Number: "AC-1", Currency: domain.Kenyan, Balance: &balance}, nil
+}
+
+func (stubMoneyTransfer) Transfer(ctx context.Context, input application.TransferInput) (*domain.Transaction, error) {
+	if input.Amount.GreaterThan(*input.SourceAccount.Balance) {
+		return nil, domain.NewInsufficientFundsError("%v is more than the balance", input.Amount)
+	}
+	return &domain.Transaction{AbstractBase: domain.AbstractBase{UUID: "transaction-1"}, Description: "transfer"}, nil
+}
+
+func (stubMoneyTransfer) ReverseTransfer(context.Context, string, string) (*domain.Transaction, error) {
+	return nil, fmt.Errorf("not implemented")
+}
+
+type stubPeriods struct{}
+
+func (stubPeriods) EndOfDay(context.Context, time.Time, string) (*domain.AccountingPeriod, error) {
+	return nil, nil
+}
+func (stubPeriods) ClosePeriod(context.Context, domain.PeriodType, time.Time, string) (*domain.AccountingPeriod, error) {
+	return nil, nil
+}
+func (stubPeriods) Periods(context.Context) ([]*domain.AccountingPeriod, error) { return nil, nil }
+func (stubPeriods) Period(context.Context, string) (*application.PeriodOutput, error) {
+	return nil, nil
+}
+func (stubPeriods) RequestReopen(context.Context, string, string, string) (*domain.AccountingPeriod, error) {
+	return nil, nil
+}
+func (stubPeriods) ApproveReopen(context.Context, string, string) (*domain.AccountingPeriod, error) {
+	return nil, nil
+}
+func (stubPeriods) RejectReopen(context.Context, string, string, string) (*domain.AccountingPeriod, error) {
+	return nil, nil
+}
+
+func (stubPeriods) PostAdjustment(ctx context.Context, input application.AdjustmentInput) (*domain.Transaction, error) {
+	if input.EffectiveDate == nil || input.EffectiveDate.Format(dateLayout) != "2023-10-01" {
+		return nil, fmt.Errorf("unexpected effective date %v", input.EffectiveDate)
+	}
+	return &domain.Transaction{AbstractBase: domain.AbstractBase{UUID: "adjustment-1"}, Description: input.Description}, nil
+}
+
+type stubReports struct {
+	balanced bool
+}
+
+func (stubReports) TrialBalance(context.Context, time.Time) (*application.TrialBalance, error) {
+	return nil, nil
+}
+func (stubReports) GeneralLedger(context.Context, string, time.Time, time.Time) (*application.GeneralLedger, error) {
+	return nil, nil
+}
+
+func (s stubReports) LedgerCheck(ctx context.Context) (*application.LedgerCheck, error) {
+	check := application.LedgerCheck{Balanced: s.balanced, TotalDebit: decimal.NewFromInt(10), TotalCredit: decimal.NewFromInt(10)}
+	if !s.balanced {
+		check.TotalCredit = decimal.NewFromInt(9)
+		check.Difference = decimal.NewFromInt(1)
+		check.UnbalancedTransactions = []string{"transaction-1"}
+	}
+	return &check, nil
+}
+
+type stubQueries struct{}
+
+func (stubQueries) Accounts(context.Context, application.AccountFilter, application.PageInput) (*application.Page[*domain.Account], error) {
+	return &application.Page[*domain.Account]{
+		Items:       []*domain.Account{{AbstractBase: domain.AbstractBase{UUID: sourceID}, Name: "Jane DEPOSIT account"}},
+		HasNextPage: true,
+		EndCursor:   "next",
+	}, nil
+}
+
+func (stubQueries) AccountsByID(context.Context, []string) ([]*domain.Account, error) {
+	return nil, nil
+}
+
+func (stubQueries) AccountBalances(ctx context.Context, ids []string) (map[string]decimal.Decimal, error) {
+	return map[string]decimal.Decimal{sourceID: decimal.NewFromInt(60)}, nil
+}
+
+func (stubQueries) Transactions(context.Context, application.TransactionFilter, application.PageInput) (*application.Page[*domain.Transaction], error) {
+	return nil, nil
+}
+
+func (stubQueries) TransactionsByID(context.Context, []string) ([]*domain.Transaction, error) {
+	return nil, nil
+}
+
+func (stubQueries) Entries(ctx context.Context, filter application.EntryFilter, page application.PageInput) (*application.Page[*domain.AccountEntry], error) {
+	return &application.Page[*domain.AccountEntry]{
+		Items: []*domain.AccountEntry{{AbstractBase: domain.AbstractBase{UUID: "entry-1"}, CreditAmount: decimal.NewFromInt(40), AccountID: filter.AccountID, TransactionID: "transaction-1"}},
+	}, nil
+}
+
+func (stubQueries) EntriesByTransaction(context.Context, []string) ([]*domain.AccountEntry, error) {
+	return nil, nil
+}
+
+type stubStore struct {
+	applied *int
+}
+
+func (s stubStore) Migrate() error {
+	*s.applied = 2
+	return nil
+}
+
+func (s stubStore) MigrateDown(steps int) error {
+	*s.applied -= steps
+	return nil
+}
+
+func (s stubStore) MigrationStatus() ([]postgresql.MigrationState, error) {
+	appliedAt := time.Date(2023, 10, 2, 0, 0, 0, 0, time.UTC)
+	states := []postgresql.MigrationState{
+		{Version: 1, Name: "initial_schema", Status: postgresql.MIGRATION_PENDING},
+		{Version: 2, Name: "audit_records_append_only", Status: postgresql.MIGRATION_PENDING},
+	}
+	for i := 0; i < *s.applied; i++ {
+		states[i].Status = postgresql.MIGRATION_APPLIED
+		states[i].AppliedAt = &appliedAt
+	}
+	return states, nil
+}
+
+func (stubStore) CreateSystemAccount(context.Context) error { return nil }
+
+func TestRun(t *testing.T) {
+	tests := []struct {
+		name       string
+		args       []string
+		balanced   bool
+		applied    int
+		wantCode   int
+		wantStdout []string
+		wantStderr []string
+	}{
+		{
+			name:       "no command",
+			wantCode:   2,
+			wantStderr: []string{"usage: moneyctl", "create-account"},
+		},
+		{
+			name:       "unknown command",
+			args:       []string{"drop-tables"},
+			wantCode:   2,
+			wantStderr: []string{`unknown command "drop-tables"`},
+		},
+		{
+			name:       "unknown output format",
+			args:       []string{"-o", "yaml", "check"},
+			wantCode:   2,
+			wantStderr: []string{"unknown output format"},
+		},
+		{
+			name:       "create an account",
+			args:       []string{"create-account", "-name", "Jane", "-amount", "100"},
+			wantStdout: []string{"BALANCE", destinationID, "KSH", "100"},
+		},
+		{
+			name:       "invalid account",
+			args:       []string{"create-account", "-name", "Jane", "-amount", "100", "-currency", "EUR"},
+			wantCode:   1,
+			wantStderr: []string{"invalid fields", "Currency"},
+		},
+		{
+			name:       "transfer",
+			args:       []string{"transfer", "-from", sourceID, "-to", destinationID, "-amount", "10"},
+			wantStdout: []string{"TRANSACTION", "transaction-1"},
+		},
+		{
+			name:       "insufficient funds",
+			args:       []string{"transfer", "-from", sourceID, "-to", destinationID, "-amount", "1000"},
+			wantCode:   1,
+			wantStderr: []string{"transfer: 1000 is more than the balance"},
+		},
+		{
+			name:       "missing amount",
+			args:       []string{"transfer", "-from", sourceID, "-to", destinationID},
+			wantCode:   1,
+			wantStderr: []string{"-amount is required"},
+		},
+		{
+			name:       "adjustment",
+			args:       []string{"adjust", "-debit", sourceID, "-credit", destinationID, "-amount", "5", "-description", "fix", "-date", "2023-10-01"},
+			wantStdout: []string{"adjustment-1", "fix"},
+		},
+		{
+			name:       "balance",
+			args:       []string{"balance", sourceID},
+			wantStdout: []string{"AC-1", "100"},
+		},
+		{
+			name:       "balance without an account",
+			args:       []string{"balance"},
+			wantCode:   1,
+			wantStderr: []string{"expected a single account ID argument"},
+		},
+		{
+			name:       "balances",
+			args:       []string{"balances", "-currency", "KSH"},
+			wantStdout: []string{"Jane DEPOSIT account", "60", "more with -after next"},
+		},
+		{
+			name:       "entries",
+			args:       []string{"entries", "-from", "2023-10-01", sourceID},
+			wantStdout: []string{"entry-1", "transaction-1", "40"},
+		},
+		{
+			name:       "balanced ledger",
+			args:       []string{"check"},
+			balanced:   true,
+			wantStdout: []string{"BALANCED", "true"},
+		},
+		{
+			name:       "unbalanced ledger",
+			args:       []string{"check"},
+			wantCode:   1,
+			wantStdout: []string{"false", "1"},
+			wantStderr: []string{"the ledger is out of balance"},
+		},
+		{
+			name:       "migrate",
+			args:       []string{"migrate"},
+			wantStdout: []string{"0002", "audit_records_append_only", "applied", "2023-10-02"},
+		},
+		{
+			name:       "revert a migration",
+			args:       []string{"migrate", "-down", "1"},
+			applied:    2,
+			wantStdout: []string{"0001", "applied", "0002", "pending"},
+		},
+		{
+			name:       "migration status",
+			args:       []string{"migrate", "-status"},
+			wantStdout: []string{"0001", "pending"},
+		},
+		{
+			name:       "conflicting migration flags",
+			args:       []string{"migrate", "-down", "1", "-status"},
+			wantCode:   1,
+			wantStderr: []string{"-down and -status can't be combined"},
+		},
+		{
+			name:       "seed",
+			args:       []string{"seed"},
+			wantStdout: []string{"SEEDED"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			applied := tt.applied
+			connect := func(string) (*App, error) {
+				return NewApp(stubMoneyTransfer{}, stubPeriods{}, stubReports{balanced: tt.balanced}, stubQueries{}, stubStore{applied: &applied}, time.UTC), nil
+			}
+
+			if code := run(tt.args, &stdout, &stderr, connect); code != tt.wantCode {
+				t.Errorf("run() = %v, want %v, stderr: %s", code, tt.wantCode, stderr.String())
+			}
+			for _, want := range tt.wantStdout {
+				if !strings.Contains(stdout.String(), want) {
+					t.Errorf("expected %q in the output, got:\n%s", want, stdout.String())
+				}
+			}
+			for _, want := range tt.wantStderr {
+				if !strings.Contains(stderr.String(), want) {
+					t.Errorf("expected %q in the errors, got:\n%s", want, stderr.String())
+				}
+			}
+		})
+	}
+}
+
+func TestRun_JSONOutput(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	connect := func(string) (*App, error) {
+		return NewApp(stubMoneyTransfer{}, stubPeriods{}, stubReports{}, stubQueries{}, stubStore{applied: new(int)}, time.UTC), nil
+	}
+
+	if code := run([]string{"-o", "json", "balance", sourceID}, &stdout, &stderr, connect); code != 0 {
+		t.Fatalf("run() = %v, stderr: %s", code, stderr.String())
+	}
+
+	var account application.AccountInformationOutput
+	if err := json.Unmarshal(stdout.Bytes(), &account); err != nil {
+		t.Fatalf("expected JSON output, got %s: %v", stdout.String(), err)
+	}
+	if account.UUID != sourceID || !account.Balance.Equal(decimal.NewFromInt(100)) {
+		t.Errorf("unexpected account %v", account)
+	}
+}
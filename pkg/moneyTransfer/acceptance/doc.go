@@ -0,0 +1,4 @@
+// Package acceptance exercises the REST API end to end: the whole router served over HTTP, backed by the testing
+// database and authenticating with access tokens from a local issuer standing in for Auth0. The suite expects
+// the testing database configured as for the integration tests, and migrates it
+package acceptance
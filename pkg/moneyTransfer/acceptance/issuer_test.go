@@ -0,0 +1,120 @@
+package acceptance_test
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"time"
+
+	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/application"
+	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/presentation/middleware"
+	jose "gopkg.in/square/go-jose.v2"
+	"gopkg.in/square/go-jose.v2/jwt"
+)
+
+// Credentials and audience of the API registered with the local issuer
+var (
+	CLIENT_ID     = "acceptance-client"
+	CLIENT_SECRET = "acceptance-secret"
+	AUDIENCE      = "https://money-transfer.test/api"
+)
+
+// issuer stands in for the Auth0 tenant: it serves its signing keys over TLS, as Auth0 does, and issues access
+// tokens for the client credentials above
+type issuer struct {
+	*httptest.Server
+	key *rsa.PrivateKey
+}
+
+func newIssuer() (*issuer, error) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		return nil, err
+	}
+
+	i := &issuer{key: key}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, map[string]string{
+			"issuer":   i.issuerURL(),
+			"jwks_uri": i.URL + "/.well-known/jwks.json",
+		})
+	})
+	mux.HandleFunc("/.well-known/jwks.json", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, jose.JSONWebKeySet{Keys: []jose.JSONWebKey{
+			{Key: &key.PublicKey, KeyID: "acceptance", Algorithm: string(jose.RS256), Use: "sig"},
+		}})
+	})
+	mux.HandleFunc("/oauth/token", i.issue)
+	i.Server = httptest.NewTLSServer(mux)
+	return i, nil
+}
+
+// domain is where the issuer is served, as an Auth0 tenant's domain
+func (i *issuer) domain() string {
+	return strings.TrimPrefix(i.URL, "https://")
+}
+
+func (i *issuer) issuerURL() string {
+	return i.URL + "/"
+}
+
+// issue answers a client credentials grant
+func (i *issuer) issue(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost || r.ParseForm() != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid_request"})
+		return
+	}
+	if r.PostForm.Get("grant_type") != "client_credentials" ||
+		r.PostForm.Get("client_id") != CLIENT_ID ||
+		r.PostForm.Get("client_secret") != CLIENT_SECRET ||
+		r.PostForm.Get("audience") != AUDIENCE {
+		writeJSON(w, http.StatusUnauthorized, map[string]string{"error": "access_denied"})
+		return
+	}
+
+	token, err := i.sign(i.key, i.claims(CLIENT_ID+"@clients"), "")
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		return
+	}
+	writeJSON(w, http.StatusOK, application.AccessToken{
+		AccessToken: token,
+		TokenType:   "Bearer",
+		ExpiresIn:   int(time.Hour.Seconds()),
+	})
+}
+
+// claims are those of an access token to the API, valid for an hour
+func (i *issuer) claims(subject string) jwt.Claims {
+	now := time.Now()
+	return jwt.Claims{
+		Issuer:    i.issuerURL(),
+		Subject:   subject,
+		Audience:  jwt.Audience{AUDIENCE},
+		IssuedAt:  jwt.NewNumericDate(now),
+		NotBefore: jwt.NewNumericDate(now),
+		Expiry:    jwt.NewNumericDate(now.Add(time.Hour)),
+	}
+}
+
+// sign signs claims with key, granting scope
+func (i *issuer) sign(key *rsa.PrivateKey, claims jwt.Claims, scope string) (string, error) {
+	signer, err := jose.NewSigner(
+		jose.SigningKey{Algorithm: jose.RS256, Key: jose.JSONWebKey{Key: key, KeyID: "acceptance"}},
+		(&jose.SignerOptions{}).WithType("JWT"),
+	)
+	if err != nil {
+		return "", err
+	}
+	return jwt.Signed(signer).Claims(claims).Claims(middleware.CustomClaims{Scope: scope}).CompactSerialize()
+}
+
+func writeJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(body)
+}
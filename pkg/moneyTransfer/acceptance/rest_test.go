@@ -0,0 +1,477 @@
+package acceptance_test
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/app"
+	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/application"
+	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/config"
+	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/domain"
+	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/infrastructure/database/postgresql"
+	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/presentation"
+	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/presentation/middleware"
+	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/presentation/rest"
+	"github.com/auth0/go-jwt-middleware/v2/jwks"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"go.uber.org/zap"
+	"gopkg.in/square/go-jose.v2/jwt"
+)
+
+// The API served for the suite, the issuer of its access tokens and a token obtained through the API
+var (
+	api         *httptest.Server
+	authIssuer  *issuer
+	accessToken string
+)
+
+func TestMain(m *testing.M) {
+	os.Exit(runSuite(m))
+}
+
+// runSuite serves the API over the testing database, closing everything once the tests have run
+func runSuite(m *testing.M) int {
+	gin.SetMode(gin.TestMode)
+
+	var err error
+	authIssuer, err = newIssuer()
+	if err != nil {
+		log.Panicf("error starting the local auth issuer: %v", err)
+	}
+	defer authIssuer.Close()
+
+	cfg, err := config.Read("")
+	if err != nil {
+		log.Panicf("error reading the testing database configuration: %v", err)
+	}
+	cfg.Auth = config.Auth{
+		Domain:       authIssuer.domain(),
+		Audience:     AUDIENCE,
+		ClientID:     CLIENT_ID,
+		ClientSecret: CLIENT_SECRET,
+		GrantType:    "client_credentials",
+		JWKSCacheTTL: time.Minute,
+	}
+
+	db, err := postgresql.ConnectToDatabase(cfg.Database, zap.NewNop())
+	if err != nil {
+		log.Panicf("error connecting to the testing database: %v", err)
+	}
+	if err := postgresql.Migrate(db); err != nil {
+		log.Panicf("error migrating the testing database: %v", err)
+	}
+	store := postgresql.NewPostgreSQLDatabase(db, nil)
+	if err := store.CreateSystemAccount(context.Background()); err != nil {
+		log.Panicf("error creating the system accounts: %v", err)
+	}
+
+	keys, err := middleware.NewIssuerKeys(cfg.Auth, jwks.WithCustomClient(authIssuer.Client()))
+	if err != nil {
+		log.Panicf("error setting up the issuer keys: %v", err)
+	}
+	tokens, err := middleware.NewTokenValidator(cfg.Auth, keys)
+	if err != nil {
+		log.Panicf("error setting up the token validation: %v", err)
+	}
+
+	service, err := app.Build(cfg, app.Dependencies{
+		Storage:    app.PostgreSQLStorage(store),
+		Tokens:     tokens,
+		AuthClient: authIssuer.Client(),
+		Checks:     []rest.Check{{Name: "database", Run: store.Ping}},
+	}, zap.NewNop())
+	if err != nil {
+		log.Panicf("error building the service: %v", err)
+	}
+	defer service.Close()
+
+	api = httptest.NewServer(service.Router)
+	defer api.Close()
+
+	// The suite authenticates the way clients do
+	var response struct {
+		Response application.AccessToken `json:"response"`
+	}
+	status, err := send(http.MethodPost, "/access_token", "", "", &response)
+	if err != nil || status != http.StatusOK || response.Response.AccessToken == "" {
+		log.Panicf("error obtaining an access token: %v %v %+v", err, status, response)
+	}
+	accessToken = response.Response.AccessToken
+
+	return m.Run()
+}
+
+// send sends body to the API, decoding its response into out when given, and returns the response's status
+func send(method string, path string, token string, body string, out interface{}) (int, error) {
+	request, err := http.NewRequest(method, api.URL+presentation.API_BASE_PATH+path, strings.NewReader(body))
+	if err != nil {
+		return 0, fmt.Errorf("unable to build the request: %v", err)
+	}
+	request.Header.Set("Content-Type", "application/json")
+	if token != "" {
+		request.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	response, err := api.Client().Do(request)
+	if err != nil {
+		return 0, fmt.Errorf("unable to call %s %s: %v", method, path, err)
+	}
+	defer response.Body.Close()
+
+	content, err := io.ReadAll(response.Body)
+	if err != nil {
+		return 0, fmt.Errorf("unable to read the response to %s %s: %v", method, path, err)
+	}
+	if out != nil && len(content) > 0 {
+		if err := json.Unmarshal(content, out); err != nil {
+			return 0, fmt.Errorf("unable to decode the response to %s %s: %v: %s", method, path, err, content)
+		}
+	}
+	return response.StatusCode, nil
+}
+
+// call sends body to the API, failing the test when the API cannot be reached
+func call(t *testing.T, method string, path string, token string, body string, out interface{}) int {
+	t.Helper()
+	status, err := send(method, path, token, body, out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return status
+}
+
+// accountResponse is the body of the account creation and lookup responses
+type accountResponse struct {
+	Account application.AccountInformationOutput `json:"account"`
+}
+
+// transactionResponse is the body of the transfer responses
+type transactionResponse struct {
+	Transaction domain.Transaction `json:"transaction"`
+}
+
+func openAccount(t *testing.T, amount string) application.AccountInformationOutput {
+	t.Helper()
+	var response accountResponse
+	body := `{"CustomerName": "Jane Doe", "Amount": "` + amount + `", "Currency": "KSH", "Header": "DEPOSIT"}`
+	if status := call(t, http.MethodPost, "/account", accessToken, body, &response); status != http.StatusOK {
+		t.Fatalf("unable to open an account, got %v", status)
+	}
+	return response.Account
+}
+
+func balance(t *testing.T, accountID string) decimal.Decimal {
+	t.Helper()
+	var response accountResponse
+	if status := call(t, http.MethodGet, "/account/"+accountID, accessToken, "", &response); status != http.StatusOK {
+		t.Fatalf("unable to look account %s up, got %v", accountID, status)
+	}
+	if response.Account.Balance == nil {
+		t.Fatalf("expected account %s to have a balance", accountID)
+	}
+	return *response.Account.Balance
+}
+
+func transferBody(source string, destination string, amount string) string {
+	return `{"SourceAccountID": "` + source + `", "DestinationAccountID": "` + destination + `", "Amount": "` + amount + `"}`
+}
+
+func TestCreateAccount(t *testing.T) {
+	tests := []struct {
+		name       string
+		body       string
+		wantStatus int
+		wantCode   string
+	}{
+		{
+			name:       "funded deposit account",
+			body:       `{"CustomerName": "Jane Doe", "Amount": "100", "Currency": "KSH", "Header": "DEPOSIT"}`,
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "without a customer name",
+			body:       `{"Amount": "100", "Currency": "KSH", "Header": "DEPOSIT"}`,
+			wantStatus: http.StatusUnprocessableEntity,
+			wantCode:   "invalid_request",
+		},
+		{
+			name:       "negative opening deposit",
+			body:       `{"CustomerName": "Jane Doe", "Amount": "-100", "Currency": "KSH", "Header": "DEPOSIT"}`,
+			wantStatus: http.StatusUnprocessableEntity,
+			wantCode:   "invalid_request",
+		},
+		{
+			name:       "unknown currency",
+			body:       `{"CustomerName": "Jane Doe", "Amount": "100", "Currency": "USD", "Header": "DEPOSIT"}`,
+			wantStatus: http.StatusUnprocessableEntity,
+			wantCode:   "invalid_request",
+		},
+		{
+			name:       "malformed JSON",
+			body:       `{"CustomerName": "Jane Doe",`,
+			wantStatus: http.StatusBadRequest,
+			wantCode:   "malformed_request",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var response struct {
+				accountResponse
+				rest.ErrorResponse
+			}
+			status := call(t, http.MethodPost, "/account", accessToken, tt.body, &response)
+			if status != tt.wantStatus {
+				t.Fatalf("expected %v, got %v: %+v", tt.wantStatus, status, response.ErrorResponse)
+			}
+			if response.Code != tt.wantCode {
+				t.Errorf("expected the %q error code, got %q", tt.wantCode, response.Code)
+			}
+			if status != http.StatusOK {
+				return
+			}
+
+			account := response.Account
+			if account.UUID == "" || account.Name != "Jane Doe" || account.Currency != domain.Kenyan {
+				t.Errorf("expected the opened account, got %+v", account)
+			}
+			if got := balance(t, account.UUID); !got.Equal(decimal.NewFromInt(100)) {
+				t.Errorf("expected the opening deposit as the balance, got %v", got)
+			}
+		})
+	}
+}
+
+func TestAccount(t *testing.T) {
+	account := openAccount(t, "50")
+
+	tests := []struct {
+		name       string
+		accountID  string
+		wantStatus int
+	}{
+		{
+			name:       "existing account",
+			accountID:  account.UUID,
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "unknown account",
+			accountID:  uuid.NewString(),
+			wantStatus: http.StatusNotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var response accountResponse
+			status := call(t, http.MethodGet, "/account/"+tt.accountID, accessToken, "", &response)
+			if status != tt.wantStatus {
+				t.Fatalf("expected %v, got %v", tt.wantStatus, status)
+			}
+			if status == http.StatusOK && response.Account.UUID != tt.accountID {
+				t.Errorf("expected account %s, got %+v", tt.accountID, response.Account)
+			}
+		})
+	}
+}
+
+func TestTransfer(t *testing.T) {
+	source := openAccount(t, "100")
+	destination := openAccount(t, "20")
+
+	tests := []struct {
+		name       string
+		body       string
+		wantStatus int
+		wantCode   string
+	}{
+		{
+			name:       "within the balance",
+			body:       transferBody(source.UUID, destination.UUID, "30"),
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "insufficient funds",
+			body:       transferBody(source.UUID, destination.UUID, "1000"),
+			wantStatus: http.StatusUnprocessableEntity,
+			wantCode:   "insufficient_funds",
+		},
+		{
+			name:       "to the same account",
+			body:       transferBody(source.UUID, source.UUID, "10"),
+			wantStatus: http.StatusUnprocessableEntity,
+			wantCode:   "invalid_request",
+		},
+		{
+			name:       "to an unknown account",
+			body:       transferBody(source.UUID, uuid.NewString(), "10"),
+			wantStatus: http.StatusNotFound,
+			wantCode:   "account_not_found",
+		},
+		{
+			name:       "non positive amount",
+			body:       transferBody(source.UUID, destination.UUID, "0"),
+			wantStatus: http.StatusUnprocessableEntity,
+			wantCode:   "invalid_request",
+		},
+		{
+			name:       "malformed JSON",
+			body:       `{"SourceAccountID": `,
+			wantStatus: http.StatusBadRequest,
+			wantCode:   "malformed_request",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var response struct {
+				transactionResponse
+				rest.ErrorResponse
+			}
+			status := call(t, http.MethodPost, "/transfers", accessToken, tt.body, &response)
+			if status != tt.wantStatus {
+				t.Fatalf("expected %v, got %v: %+v", tt.wantStatus, status, response.ErrorResponse)
+			}
+			if response.Code != tt.wantCode {
+				t.Errorf("expected the %q error code, got %q", tt.wantCode, response.Code)
+			}
+			if status == http.StatusOK && response.Transaction.UUID == "" {
+				t.Errorf("expected the posted transaction, got %+v", response.Transaction)
+			}
+		})
+	}
+
+	// Only the transfer within the balance was posted
+	if got := balance(t, source.UUID); !got.Equal(decimal.NewFromInt(70)) {
+		t.Errorf("expected the source balance to be 70, got %v", got)
+	}
+	if got := balance(t, destination.UUID); !got.Equal(decimal.NewFromInt(50)) {
+		t.Errorf("expected the destination balance to be 50, got %v", got)
+	}
+}
+
+func TestUnauthorized(t *testing.T) {
+	account := openAccount(t, "10")
+
+	otherKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("unable to generate a signing key: %v", err)
+	}
+	sign := func(key *rsa.PrivateKey, change func(claims *jwt.Claims)) string {
+		claims := authIssuer.claims("intruder")
+		change(&claims)
+		token, err := authIssuer.sign(key, claims, "")
+		if err != nil {
+			t.Fatalf("unable to sign a token: %v", err)
+		}
+		return token
+	}
+
+	tests := []struct {
+		name  string
+		token string
+	}{
+		{
+			name: "without an access token",
+		},
+		{
+			name:  "malformed token",
+			token: "not-a-token",
+		},
+		{
+			name:  "signed with another key",
+			token: sign(otherKey, func(claims *jwt.Claims) {}),
+		},
+		{
+			name: "expired",
+			token: sign(authIssuer.key, func(claims *jwt.Claims) {
+				claims.Expiry = jwt.NewNumericDate(time.Now().Add(-time.Hour))
+			}),
+		},
+		{
+			name: "for another audience",
+			token: sign(authIssuer.key, func(claims *jwt.Claims) {
+				claims.Audience = jwt.Audience{"https://another.test/api"}
+			}),
+		},
+		{
+			name: "from another issuer",
+			token: sign(authIssuer.key, func(claims *jwt.Claims) {
+				claims.Issuer = "https://another.test/"
+			}),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if status := call(t, http.MethodGet, "/account/"+account.UUID, tt.token, "", nil); status != http.StatusUnauthorized {
+				t.Errorf("expected the lookup to be unauthorized, got %v", status)
+			}
+			body := transferBody(account.UUID, uuid.NewString(), "1")
+			if status := call(t, http.MethodPost, "/transfers", tt.token, body, nil); status != http.StatusUnauthorized {
+				t.Errorf("expected the transfer to be unauthorized, got %v", status)
+			}
+		})
+	}
+
+	if status := call(t, http.MethodGet, "/account/"+account.UUID, accessToken, "", nil); status != http.StatusOK {
+		t.Errorf("expected the issued access token to be accepted, got %v", status)
+	}
+}
+
+func TestConcurrentTransfers(t *testing.T) {
+	source := openAccount(t, "100")
+	destination := openAccount(t, "10")
+
+	// Fifteen at a time, only six transfers fit in the balance however they interleave
+	const transfers = 10
+	var wg sync.WaitGroup
+	statuses := make([]int, transfers)
+	errs := make([]error, transfers)
+	for i := 0; i < transfers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			statuses[i], errs[i] = send(http.MethodPost, "/transfers", accessToken, transferBody(source.UUID, destination.UUID, "15"), nil)
+		}(i)
+	}
+	wg.Wait()
+	for _, err := range errs {
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	posted, declined := 0, 0
+	for _, status := range statuses {
+		switch status {
+		case http.StatusOK:
+			posted++
+		case http.StatusUnprocessableEntity:
+			declined++
+		default:
+			t.Errorf("expected transfers to be posted or declined, got %v", status)
+		}
+	}
+	if posted != 6 || declined != 4 {
+		t.Errorf("expected 6 transfers posted and 4 declined, got %d and %d", posted, declined)
+	}
+
+	if got := balance(t, source.UUID); !got.Equal(decimal.NewFromInt(10)) {
+		t.Errorf("expected the source balance to be 10, got %v", got)
+	}
+	if got := balance(t, destination.UUID); !got.Equal(decimal.NewFromInt(100)) {
+		t.Errorf("expected the destination balance to be 100, got %v", got)
+	}
+}
@@ -0,0 +1,381 @@
+// Package app builds the service from its configuration: the storage and external services it depends on, the
+// business logic over them and the REST, GraphQL and gRPC transports serving it
+package app
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/config"
+	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/domain"
+	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/fraud"
+	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/infrastructure/monitoring"
+	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/infrastructure/outbox"
+	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/infrastructure/tracing"
+	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/infrastructure/webhook"
+	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/metrics"
+	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/presentation"
+	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/presentation/graph"
+	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/presentation/middleware"
+	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/presentation/rest"
+	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/presentation/rpc"
+	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/presentation/rpc/pb"
+	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/presentation/validation"
+	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/ratelimit"
+	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/repository"
+	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/usecases"
+	"github.com/getsentry/sentry-go"
+	sentrygin "github.com/getsentry/sentry-go/gin"
+	"github.com/gin-gonic/gin"
+	adapter "github.com/gwatts/gin-adapter"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/gin-gonic/gin/otelgin"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+)
+
+// Storage holds the repositories the business logic is built on
+type Storage struct {
+	Create  repository.CreateRepository
+	Get     repository.GetRepository
+	Report  repository.ReportRepository
+	Period  repository.PeriodRepository
+	Outbox  repository.OutboxRepository
+	Webhook repository.WebhookRepository
+	Audit   repository.AuditRepository
+	Query   repository.QueryRepository
+	Fraud   repository.FraudRepository
+	Payment repository.PaymentRepository
+}
+
+// missing names the repositories that were not provided
+func (s Storage) missing() []string {
+	var missing []string
+	for _, dependency := range []struct {
+		name     string
+		provided bool
+	}{
+		{"create", s.Create != nil},
+		{"get", s.Get != nil},
+		{"report", s.Report != nil},
+		{"period", s.Period != nil},
+		{"outbox", s.Outbox != nil},
+		{"webhook", s.Webhook != nil},
+		{"audit", s.Audit != nil},
+		{"query", s.Query != nil},
+		{"fraud", s.Fraud != nil},
+		{"payment", s.Payment != nil},
+	} {
+		if !dependency.provided {
+			missing = append(missing, dependency.name+" repository")
+		}
+	}
+	return missing
+}
+
+// Dependencies are what the service is built on besides its configuration. Connect provides them from
+// PostgreSQL and Auth0, tests swap them for in-memory ones
+type Dependencies struct {
+	Storage Storage
+	Tokens  middleware.TokenValidator
+
+	// AuthClient requests access tokens from the Auth0 tenant, http.DefaultClient is used when it is nil
+	AuthClient *http.Client
+
+	// Metrics records the service's metrics, a new recorder is used when it is nil
+	Metrics *monitoring.Prometheus
+
+	// RateLimits keeps the rate limits' buckets, a memory store limiting this instance alone is used when it is nil
+	RateLimits ratelimit.Store
+
+	// Checks are run by the readiness probe
+	Checks []rest.Check
+
+	// Close releases the dependencies once the service has shut down, it may be nil
+	Close func() error
+}
+
+// check reports the dependencies that were not provided
+func (d Dependencies) check() error {
+	missing := d.Storage.missing()
+	if d.Tokens == nil {
+		missing = append(missing, "access token validator")
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("missing dependencies: %s", strings.Join(missing, ", "))
+	}
+	return nil
+}
+
+// App is the service built from its configuration and dependencies, ready to be served
+type App struct {
+	Config  *config.Config
+	Logger  *zap.Logger
+	Metrics *monitoring.Prometheus
+
+	// Router serves the REST and GraphQL APIs, the probes and the metrics
+	Router *gin.Engine
+	// GRPC serves the gRPC API, it is nil when the gRPC API is disabled
+	GRPC *grpc.Server
+	// Health serves the probes, drained before the service shuts down
+	Health *rest.Health
+
+	workers []func(ctx context.Context)
+	close   func() error
+}
+
+// New connects to the service's dependencies and builds the service over them
+func New(ctx context.Context, cfg *config.Config, logger *zap.Logger) (*App, error) {
+	deps, err := Connect(ctx, cfg, logger)
+	if err != nil {
+		return nil, err
+	}
+
+	app, err := Build(cfg, *deps, logger)
+	if err != nil {
+		if deps.Close != nil {
+			if closeErr := deps.Close(); closeErr != nil {
+				logger.Warn("unable to release the dependencies", zap.Error(closeErr))
+			}
+		}
+		return nil, err
+	}
+	return app, nil
+}
+
+// Build wires the business logic over deps and the transports serving it, without starting anything
+func Build(cfg *config.Config, deps Dependencies, logger *zap.Logger) (*App, error) {
+	if err := deps.check(); err != nil {
+		return nil, err
+	}
+	if err := validation.RegisterValidators(); err != nil {
+		return nil, fmt.Errorf("unable to register the request validators: %v", err)
+	}
+
+	recorder := deps.Metrics
+	if recorder == nil {
+		recorder = monitoring.NewPrometheus()
+	}
+	// Balance checks and the postings that depend on them are serialized across every presentation layer moving money
+	lock := &metrics.Mutex{Name: "money_movement", Recorder: recorder}
+
+	router := gin.New()
+	if err := router.SetTrustedProxies(cfg.Server.TrustedProxies); err != nil {
+		return nil, fmt.Errorf("unable to trust the proxies: %v", err)
+	}
+	router.Use(
+		middleware.RequestID(),
+		otelgin.Middleware(tracing.SERVICE_NAME),
+		middleware.Logger(logger),
+		middleware.Recovery(),
+		middleware.Metrics(recorder),
+	)
+	// Errors are reported to Sentry once Connect has set it up
+	if sentry.CurrentHub().Client() != nil {
+		router.Use(sentrygin.New(sentrygin.Options{}))
+	}
+	router.GET(presentation.METRICS_PATH, gin.WrapH(recorder.Handler()))
+
+	store := deps.Storage
+	var rules []fraud.Rule
+	if cfg.Fraud.Enabled {
+		rules = fraudRules(cfg.Fraud)
+	}
+	screening := fraud.NewEngine(store.Fraud, rules...)
+	uc := usecases.NewMoneyTransferUsecases(store.Create, store.Get, store.Fraud, screening, recorder, logger)
+	statements := usecases.NewStatementUsecases(store.Get, store.Report)
+	audit := usecases.NewAuditUsecases(store.Audit)
+
+	app := &App{
+		Config:  cfg,
+		Logger:  logger,
+		Metrics: recorder,
+		Router:  router,
+		Health:  rest.NewHealthHandlers(deps.Checks...),
+		close:   deps.Close,
+	}
+	router.GET(presentation.LIVENESS_PATH, app.Health.Live)
+	router.GET(presentation.READINESS_PATH, app.Health.Ready)
+	router.GET(presentation.BUILD_INFO_PATH, app.Health.BuildInfo)
+
+	var graphQL *graph.Handler
+	if cfg.Features.GraphQL {
+		graphQL = graph.NewHandler(uc, usecases.NewQueryUsecases(store.Query), lock)
+	}
+
+	// Publish domain events recorded in the outbox and deliver them to webhook subscribers
+	var sinks []outbox.Sink
+	var webhooks *rest.Webhooks
+	if cfg.Features.Webhooks {
+		webhooks = rest.NewWebhookHandlers(usecases.NewWebhookUsecases(store.Webhook, store.Get, logger))
+		sinks = append(sinks, webhook.NewDispatcher(store.Webhook))
+		app.workers = append(app.workers, webhook.NewWorker(store.Webhook, logger.Named("webhooks")).Run)
+	}
+	if cfg.Outbox.File != "" {
+		sinks = append(sinks, outbox.NewFileSink(cfg.Outbox.File))
+	}
+	// Without any sink, events are kept in the outbox until one is configured
+	if len(sinks) > 0 {
+		app.workers = append(app.workers, outbox.NewRelay(store.Outbox, recorder, logger.Named("outbox"), sinks...).Run)
+	}
+
+	var limits map[string]gin.HandlerFunc
+	var callLimits rpc.RateLimits
+	if cfg.RateLimits.Enabled {
+		limits, callLimits = rateLimits(cfg.RateLimits.Routes, deps.RateLimits)
+	}
+
+	location := cfg.Accounting.Location()
+	handlers := rest.NewRestHandlers(uc, cfg.Auth, lock)
+	if deps.AuthClient != nil {
+		handlers.Client = deps.AuthClient
+	}
+	presentation.RegisterRoutes(router, presentation.Handlers{
+		Rest:          handlers,
+		Reports:       rest.NewReportHandlers(usecases.NewReportingUsecases(store.Get, store.Report), location),
+		Periods:       rest.NewPeriodHandlers(usecases.NewPeriodUsecases(store.Create, store.Get, store.Period, logger, location), location, lock),
+		Statements:    rest.NewStatementHandlers(statements, location),
+		Payments:      rest.NewPaymentHandlers(usecases.NewPaymentInitiationUsecases(uc, store.Get, store.Payment, logger, lock)),
+		Webhooks:      webhooks,
+		Audit:         rest.NewAuditHandlers(audit, location),
+		Review:        rest.NewTransferReviewHandlers(usecases.NewTransferReviewUsecases(store.Get, store.Fraud, logger), lock),
+		GraphQL:       graphQL,
+		Authenticated: []gin.HandlerFunc{adapter.Wrap(middleware.EnsureValidToken(deps.Tokens))},
+		RateLimits:    limits,
+		Audited:       []gin.HandlerFunc{middleware.Audit(audit)},
+	})
+
+	registered := map[string]bool{}
+	for _, route := range router.Routes() {
+		registered[route.Method+" "+route.Path] = true
+	}
+	for route := range limits {
+		if !registered[route] {
+			return nil, fmt.Errorf("rate limits configured for %s, which is not served", route)
+		}
+	}
+
+	if cfg.Features.GRPC {
+		var opts []grpc.ServerOption
+		if cfg.Server.TLS.Enabled() {
+			creds, err := credentials.NewServerTLSFromFile(cfg.Server.TLS.CertFile, cfg.Server.TLS.KeyFile)
+			if err != nil {
+				return nil, fmt.Errorf("unable to load the TLS certificate: %v", err)
+			}
+			opts = append(opts, grpc.Creds(creds))
+		}
+		app.GRPC = rpc.NewGRPCServer(rpc.NewServer(uc, statements, lock), deps.Tokens, audit, recorder, callLimits, logger, opts...)
+	}
+
+	return app, nil
+}
+
+// GRPC_ROUTES are the REST routes serving the same operations as the gRPC methods, a method is limited
+// by its route's rate limits
+var GRPC_ROUTES = map[string]string{
+	pb.MoneyTransfer_CreateAccount_FullMethodName: "POST " + presentation.API_BASE_PATH + "/account",
+	pb.MoneyTransfer_GetAccount_FullMethodName:    "GET " + presentation.API_BASE_PATH + "/account/:id",
+	pb.MoneyTransfer_Transfer_FullMethodName:      "POST " + presentation.API_BASE_PATH + "/transfers",
+}
+
+// rateLimits groups the configured limits by route and by the gRPC methods serving the same operations,
+// keeping their buckets in store
+func rateLimits(limits []config.RouteLimit, store ratelimit.Store) (map[string]gin.HandlerFunc, rpc.RateLimits) {
+	if store == nil {
+		store = ratelimit.NewMemoryStore()
+	}
+
+	rules := map[string][]middleware.RateLimitRule{}
+	callRules := map[string][]rpc.RateLimitRule{}
+	for _, limit := range limits {
+		rule := ratelimit.Limit{
+			Requests: limit.Requests,
+			Period:   limit.Period,
+			Burst:    limit.Burst,
+		}
+		rules[limit.Route] = append(rules[limit.Route], middleware.RateLimitRule{
+			Name:  limit.Key,
+			Key:   middleware.RATE_LIMIT_KEYS[limit.Key],
+			Limit: rule,
+		})
+		callRules[limit.Route] = append(callRules[limit.Route], rpc.RateLimitRule{
+			Name:  limit.Key,
+			Key:   rpc.RATE_LIMIT_KEYS[limit.Key],
+			Limit: rule,
+		})
+	}
+
+	handlers := make(map[string]gin.HandlerFunc, len(rules))
+	for route, routeRules := range rules {
+		handlers[route] = middleware.RateLimit(store, routeRules...)
+	}
+
+	calls := rpc.RateLimits{Store: store, Methods: map[string]rpc.MethodLimits{}}
+	for method, route := range GRPC_ROUTES {
+		if methodRules, ok := callRules[route]; ok {
+			calls.Methods[method] = rpc.MethodLimits{Route: route, Rules: methodRules}
+		}
+	}
+	return handlers, calls
+}
+
+// fraudRules builds the configured fraud rules, the configuration having been validated
+func fraudRules(cfg config.Fraud) []fraud.Rule {
+	action := func(action string) domain.FraudAction {
+		return domain.FraudAction(strings.ToUpper(action))
+	}
+	location, err := time.LoadLocation(cfg.UnusualHours.TimeZone)
+	if err != nil {
+		location = time.UTC
+	}
+
+	return []fraud.Rule{
+		fraud.NewAccountRule{
+			Action:  action(cfg.NewAccount.Action),
+			MaxAge:  cfg.NewAccount.MaxAge,
+			Amounts: fraud.Amounts(cfg.NewAccount.Amounts),
+		},
+		fraud.FanOutRule{
+			Action:       action(cfg.FanOut.Action),
+			Window:       cfg.FanOut.Window,
+			Destinations: cfg.FanOut.Destinations,
+		},
+		fraud.RoundTripRule{
+			Action: action(cfg.RoundTrip.Action),
+			Window: cfg.RoundTrip.Window,
+		},
+		fraud.UnusualHoursRule{
+			Action:   action(cfg.UnusualHours.Action),
+			From:     cfg.UnusualHours.From,
+			To:       cfg.UnusualHours.To,
+			Location: location,
+			Amounts:  fraud.Amounts(cfg.UnusualHours.Amounts),
+		},
+	}
+}
+
+// Run starts the background workers, publishing domain events and delivering webhooks until ctx is done.
+// The returned function waits for them to stop
+func (a *App) Run(ctx context.Context) (wait func()) {
+	var running sync.WaitGroup
+	for _, work := range a.workers {
+		running.Add(1)
+		go func(work func(ctx context.Context)) {
+			defer running.Done()
+			work(ctx)
+		}(work)
+	}
+	return running.Wait
+}
+
+// Close releases the dependencies, once the servers and workers have stopped
+func (a *App) Close() error {
+	if a.close == nil {
+		return nil
+	}
+	return a.close()
+}
@@ -0,0 +1,328 @@
+package app_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/app"
+	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/application"
+	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/config"
+	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/domain"
+	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/presentation"
+	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/presentation/rest"
+	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/repository"
+	"github.com/auth0/go-jwt-middleware/v2/validator"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"go.uber.org/zap"
+)
+
+// memoryStore keeps the accounts and audit records the tests need in memory, the other repository methods are
+// not expected to be called
+type memoryStore struct {
+	repository.CreateRepository
+	repository.GetRepository
+	repository.ReportRepository
+	repository.PeriodRepository
+	repository.OutboxRepository
+	repository.WebhookRepository
+	repository.AuditRepository
+	repository.QueryRepository
+	repository.FraudRepository
+	repository.PaymentRepository
+
+	mu       sync.Mutex
+	accounts map[string]*application.AccountInformationOutput
+	audit    []*domain.AuditRecord
+	held     []*domain.HeldTransfer
+}
+
+func (m *memoryStore) Account(ctx context.Context, accountID string) (*application.AccountInformationOutput, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	account, ok := m.accounts[accountID]
+	if !ok {
+		return nil, domain.NewNotFoundError("account_not_found", "account %s was not found", accountID)
+	}
+	return account, nil
+}
+
+func (m *memoryStore) AppendAudit(ctx context.Context, record *domain.AuditRecord) (*domain.AuditRecord, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.audit = append(m.audit, record)
+	return record, nil
+}
+
+func (m *memoryStore) TransfersFrom(ctx context.Context, accountID string, since time.Time) ([]*application.TransferRecord, error) {
+	return nil, nil
+}
+
+func (m *memoryStore) HoldTransfer(ctx context.Context, held *domain.HeldTransfer) (*domain.HeldTransfer, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	held.UUID = fmt.Sprintf("held-%d", len(m.held)+1)
+	held.Status = domain.HoldPending
+	m.held = append(m.held, held)
+	return held, nil
+}
+
+func (m *memoryStore) PendingEvents(ctx context.Context, limit int) ([]*domain.OutboxEvent, error) {
+	return nil, nil
+}
+
+func (m *memoryStore) ClaimDueDeliveries(ctx context.Context, asOf time.Time, lease time.Duration, limit int) ([]*domain.WebhookDelivery, error) {
+	return nil, nil
+}
+
+func (m *memoryStore) storage() app.Storage {
+	return app.Storage{Create: m, Get: m, Report: m, Period: m, Outbox: m, Webhook: m, Audit: m, Query: m, Fraud: m, Payment: m}
+}
+
+// staticTokens accepts a single access token
+type staticTokens struct{}
+
+func (staticTokens) ValidateToken(ctx context.Context, token string) (interface{}, error) {
+	if token != "valid" {
+		return nil, errors.New("unknown token")
+	}
+	return &validator.ValidatedClaims{RegisteredClaims: validator.RegisteredClaims{Subject: "client-1"}}, nil
+}
+
+func newTestApp(t *testing.T, cfg *config.Config, checks ...rest.Check) (*app.App, *memoryStore) {
+	gin.SetMode(gin.TestMode)
+	store := &memoryStore{accounts: map[string]*application.AccountInformationOutput{
+		"acc-1": {UUID: "acc-1", Name: "Jane Doe", Currency: "KSH"},
+	}}
+
+	service, err := app.Build(cfg, app.Dependencies{
+		Storage: store.storage(),
+		Tokens:  staticTokens{},
+		Checks:  checks,
+	}, zap.NewNop())
+	if err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+	return service, store
+}
+
+func serve(service *app.App, method string, path string, body string, token string) *httptest.ResponseRecorder {
+	request := httptest.NewRequest(method, path, strings.NewReader(body))
+	request.Header.Set("Content-Type", "application/json")
+	if token != "" {
+		request.Header.Set("Authorization", "Bearer "+token)
+	}
+	recorder := httptest.NewRecorder()
+	service.Router.ServeHTTP(recorder, request)
+	return recorder
+}
+
+func TestBuild_ServesTheAPI(t *testing.T) {
+	service, store := newTestApp(t, config.Default())
+
+	tests := []struct {
+		name       string
+		method     string
+		path       string
+		body       string
+		token      string
+		wantStatus int
+	}{
+		{
+			name:       "liveness probe",
+			method:     http.MethodGet,
+			path:       presentation.LIVENESS_PATH,
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "metrics",
+			method:     http.MethodGet,
+			path:       presentation.METRICS_PATH,
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "without an access token",
+			method:     http.MethodGet,
+			path:       presentation.API_BASE_PATH + "/account/acc-1",
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name:       "account from the store",
+			method:     http.MethodGet,
+			path:       presentation.API_BASE_PATH + "/account/acc-1",
+			token:      "valid",
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "account missing from the store",
+			method:     http.MethodGet,
+			path:       presentation.API_BASE_PATH + "/account/acc-2",
+			token:      "valid",
+			wantStatus: http.StatusNotFound,
+		},
+		{
+			name:       "invalid transfer",
+			method:     http.MethodPost,
+			path:       presentation.API_BASE_PATH + "/transfers",
+			body:       `{}`,
+			token:      "valid",
+			wantStatus: http.StatusUnprocessableEntity,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := serve(service, tt.method, tt.path, tt.body, tt.token)
+			if recorder.Code != tt.wantStatus {
+				t.Errorf("expected %v, got %v: %s", tt.wantStatus, recorder.Code, recorder.Body.String())
+			}
+		})
+	}
+
+	var response struct {
+		Account application.AccountInformationOutput `json:"account"`
+	}
+	recorder := serve(service, http.MethodGet, presentation.API_BASE_PATH+"/account/acc-1", "", "valid")
+	if err := json.Unmarshal(recorder.Body.Bytes(), &response); err != nil || response.Account.Name != "Jane Doe" {
+		t.Errorf("expected the stored account, got %s", recorder.Body.String())
+	}
+
+	if len(store.audit) != 1 || store.audit[0].Actor != "client-1" || store.audit[0].StatusCode != http.StatusUnprocessableEntity {
+		t.Errorf("expected the rejected transfer to be audited, got %+v", store.audit)
+	}
+	if service.GRPC == nil {
+		t.Errorf("expected the gRPC API to be served")
+	}
+}
+
+func TestBuild_ReadinessChecks(t *testing.T) {
+	service, _ := newTestApp(t, config.Default(), rest.Check{
+		Name: "database",
+		Run:  func(ctx context.Context) error { return errors.New("connection refused") },
+	})
+
+	if recorder := serve(service, http.MethodGet, presentation.READINESS_PATH, "", ""); recorder.Code != http.StatusServiceUnavailable {
+		t.Errorf("expected the failing dependency to make the service unready, got %v: %s", recorder.Code, recorder.Body.String())
+	}
+}
+
+func TestBuild_DisabledFeatures(t *testing.T) {
+	cfg := config.Default()
+	cfg.Features = config.Features{}
+	service, _ := newTestApp(t, cfg)
+
+	if service.GRPC != nil {
+		t.Errorf("expected no gRPC server with the gRPC API disabled")
+	}
+	if recorder := serve(service, http.MethodPost, presentation.API_BASE_PATH+"/graphql", `{"query":"{__typename}"}`, "valid"); recorder.Code != http.StatusNotFound {
+		t.Errorf("expected GraphQL not to be served, got %v", recorder.Code)
+	}
+}
+
+func TestBuild_RateLimits(t *testing.T) {
+	cfg := config.Default()
+	cfg.RateLimits.Routes = []config.RouteLimit{
+		{Route: "GET /api/v1/account/:id", Key: "subject", Requests: 2, Period: time.Minute},
+	}
+	service, _ := newTestApp(t, cfg)
+
+	want := []int{http.StatusOK, http.StatusOK, http.StatusTooManyRequests}
+	for i, status := range want {
+		recorder := serve(service, http.MethodGet, presentation.API_BASE_PATH+"/account/acc-1", "", "valid")
+		if recorder.Code != status {
+			t.Fatalf("request %d: expected %v, got %v", i, status, recorder.Code)
+		}
+		if status == http.StatusTooManyRequests && recorder.Header().Get("Retry-After") == "" {
+			t.Errorf("expected to be told when to retry")
+		}
+	}
+
+	cfg.RateLimits.Routes = []config.RouteLimit{
+		{Route: "POST /api/v1/transfers", Key: "subject", Requests: 1, Period: time.Minute},
+	}
+	service, store := newTestApp(t, cfg)
+	for _, status := range []int{http.StatusUnprocessableEntity, http.StatusTooManyRequests} {
+		if recorder := serve(service, http.MethodPost, presentation.API_BASE_PATH+"/transfers", `{}`, "valid"); recorder.Code != status {
+			t.Fatalf("expected %v, got %v", status, recorder.Code)
+		}
+	}
+	if len(store.audit) != 1 || store.audit[0].StatusCode != http.StatusUnprocessableEntity {
+		t.Errorf("expected the throttled transfer to be left out of the audit log, got %+v", store.audit)
+	}
+
+	cfg.RateLimits.Routes = []config.RouteLimit{
+		{Route: "POST /api/v1/transfer", Key: "ip", Requests: 1, Period: time.Minute},
+	}
+	if _, err := app.Build(cfg, app.Dependencies{Storage: (&memoryStore{}).storage(), Tokens: staticTokens{}}, zap.NewNop()); err == nil {
+		t.Errorf("expected limits on a route that is not served to be rejected")
+	}
+}
+
+func TestBuild_FraudScreening(t *testing.T) {
+	cfg := config.Default()
+	cfg.Fraud.NewAccount.Amounts = config.Amounts{domain.Kenyan: decimal.Zero}
+	service, store := newTestApp(t, cfg)
+
+	opened := time.Now().Add(-time.Hour)
+	balance := decimal.NewFromInt(100)
+	source, destination := uuid.NewString(), uuid.NewString()
+	store.accounts[source] = &application.AccountInformationOutput{UUID: source, Currency: domain.Kenyan, Header: domain.Deposit, Balance: &balance, CreatedAt: &opened}
+	store.accounts[destination] = &application.AccountInformationOutput{UUID: destination, Currency: domain.Kenyan, Header: domain.Deposit, CreatedAt: &opened}
+
+	body := fmt.Sprintf(`{"SourceAccountID": %q, "DestinationAccountID": %q, "Amount": 10}`, source, destination)
+	recorder := serve(service, http.MethodPost, presentation.API_BASE_PATH+"/transfers", body, "valid")
+	var response rest.HeldTransferResponse
+	if recorder.Code != http.StatusAccepted || json.Unmarshal(recorder.Body.Bytes(), &response) != nil || response.Status != domain.HoldPending {
+		t.Fatalf("expected the transfer out of a new account to be held, got %v: %s", recorder.Code, recorder.Body.String())
+	}
+	if len(store.held) != 1 || store.held[0].Rules != "new_account" || !store.held[0].Amount.Equal(decimal.NewFromInt(10)) {
+		t.Fatalf("expected the transfer to be queued for review, got %+v", store.held)
+	}
+	if response.HeldTransferID != store.held[0].UUID {
+		t.Errorf("expected the response to name held transfer %s, got %s", store.held[0].UUID, recorder.Body.String())
+	}
+	if recorder := serve(service, http.MethodGet, presentation.METRICS_PATH, "", ""); !strings.Contains(recorder.Body.String(), `lock="money_movement"`) {
+		t.Errorf("expected the wait for the money movement lock to be recorded, got %s", recorder.Body.String())
+	}
+
+	if recorder := serve(service, http.MethodGet, presentation.API_BASE_PATH+"/held_transfers", "", "valid"); recorder.Code != http.StatusForbidden {
+		t.Errorf("expected the review queue to need the admin scope, got %v", recorder.Code)
+	}
+}
+
+func TestBuild_MissingDependencies(t *testing.T) {
+	store := &memoryStore{}
+	storage := store.storage()
+	storage.Audit = nil
+
+	_, err := app.Build(config.Default(), app.Dependencies{Storage: storage}, zap.NewNop())
+	if err == nil {
+		t.Fatalf("expected the missing dependencies to be reported")
+	}
+	for _, missing := range []string{"audit repository", "access token validator"} {
+		if !strings.Contains(err.Error(), missing) {
+			t.Errorf("expected %q to be reported missing, got %v", missing, err)
+		}
+	}
+}
+
+func TestApp_Run(t *testing.T) {
+	service, _ := newTestApp(t, config.Default())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	// The workers stop once their context is done
+	service.Run(ctx)()
+
+	if err := service.Close(); err != nil {
+		t.Errorf("Close() error = %v", err)
+	}
+}
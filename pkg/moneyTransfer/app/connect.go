@@ -0,0 +1,114 @@
+package app
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/config"
+	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/domain/data"
+	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/infrastructure/database/postgresql"
+	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/infrastructure/monitoring"
+	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/presentation/middleware"
+	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/presentation/rest"
+	"github.com/getsentry/sentry-go"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// Connect sets up the service's dependencies: error reporting to Sentry, the PostgreSQL database seeded with the
+// system accounts and the validation of access tokens issued by Auth0
+func Connect(ctx context.Context, cfg *config.Config, logger *zap.Logger) (*Dependencies, error) {
+	if err := sentry.Init(sentry.ClientOptions{
+		Dsn: cfg.Sentry.DSN,
+	}); err != nil {
+		logger.Warn("Sentry initialization failed", zap.Error(err))
+	}
+
+	recorder := monitoring.NewPrometheus()
+
+	db, err := postgresql.ConnectToDatabase(cfg.Database, logger)
+	if err != nil {
+		return nil, fmt.Errorf("unable to connect to the database: %v", err)
+	}
+	deps, err := connect(ctx, cfg, db, recorder)
+	if err != nil {
+		if closeErr := closeDatabase(db); closeErr != nil {
+			logger.Warn("unable to close the database connection", zap.Error(closeErr))
+		}
+		return nil, err
+	}
+	return deps, nil
+}
+
+// connect builds the dependencies over an open database connection
+func connect(ctx context.Context, cfg *config.Config, db *gorm.DB, recorder *monitoring.Prometheus) (*Dependencies, error) {
+	if err := postgresql.Instrument(db, recorder); err != nil {
+		return nil, fmt.Errorf("unable to instrument the database: %v", err)
+	}
+	if err := postgresql.Trace(db); err != nil {
+		return nil, fmt.Errorf("unable to trace the database: %v", err)
+	}
+
+	chart, err := data.LoadChartOfAccounts(cfg.Accounting.ChartOfAccountsFile)
+	if err != nil {
+		return nil, fmt.Errorf("unable to load the chart of accounts: %v", err)
+	}
+
+	store := postgresql.NewPostgreSQLDatabase(db, chart)
+	if err := store.CreateSystemAccount(ctx); err != nil {
+		return nil, fmt.Errorf("unable to create the system accounts: %v", err)
+	}
+
+	keys, err := middleware.NewIssuerKeys(cfg.Auth)
+	if err != nil {
+		return nil, fmt.Errorf("unable to set up the access token signing keys: %v", err)
+	}
+	tokens, err := middleware.NewTokenValidator(cfg.Auth, keys)
+	if err != nil {
+		return nil, fmt.Errorf("unable to set up the access token validation: %v", err)
+	}
+
+	return &Dependencies{
+		Storage: PostgreSQLStorage(store),
+		Tokens:  tokens,
+		Metrics: recorder,
+		// Ready once the database is migrated and seeded and access tokens can be validated
+		Checks: []rest.Check{
+			{Name: "database", Run: store.Ping},
+			{Name: "migrations", Run: store.VerifyMigrations},
+			{Name: "system_accounts", Run: store.VerifySystemAccounts},
+			{Name: "jwks", Run: func(ctx context.Context) error {
+				_, err := keys.KeyFunc(ctx)
+				return err
+			}},
+		},
+		Close: func() error {
+			return closeDatabase(db)
+		},
+	}, nil
+}
+
+// PostgreSQLStorage keeps every repository in the PostgreSQL database
+func PostgreSQLStorage(store *postgresql.PostgreSQL) Storage {
+	return Storage{
+		Create:  store,
+		Get:     store,
+		Report:  store,
+		Period:  store,
+		Outbox:  store,
+		Webhook: store,
+		Audit:   store,
+		Query:   store,
+		Fraud:   store,
+		Payment: store,
+	}
+}
+
+// closeDatabase closes the connection pool behind db
+func closeDatabase(db *gorm.DB) error {
+	sqlDB, err := db.DB()
+	if err != nil {
+		return err
+	}
+	return sqlDB.Close()
+}
@@ -0,0 +1,21 @@
+package application
+
+import "time"
+
+// AuditFilter represents the criteria audit records are queried by
+type AuditFilter struct {
+	Actor    string
+	Route    string
+	Resource string
+	From     *time.Time
+	To       *time.Time
+	Limit    int
+}
+
+// AuditVerification represents the outcome of checking the audit log's hash chain
+type AuditVerification struct {
+	Verified bool   `json:"verified"`
+	Records  int64  `json:"records"`
+	BrokenAt int64  `json:"broken_at,omitempty"`
+	Reason   string `json:"reason,omitempty"`
+}
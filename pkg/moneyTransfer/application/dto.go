@@ -9,17 +9,17 @@ import (
 
 // AccountCreationInput represents input object for account creation
 type AccountCreationInput struct {
-	CustomerName string
-	Amount       *decimal.Decimal
-	Currency     *domain.CurrencyType
-	Header       domain.HeaderType
+	CustomerName string               `binding:"required"`
+	Amount       *decimal.Decimal     `binding:"required,positive_amount"`
+	Currency     *domain.CurrencyType `binding:"required,currency"`
+	Header       domain.HeaderType    `binding:"required,customer_header"`
 }
 
 // TransferPayload defines the presentation layer transfer payload
 type TransferPayload struct {
-	SourceAccountID      string
-	DestinationAccountID string
-	Amount               *decimal.Decimal
+	SourceAccountID      string           `binding:"required,uuid"`
+	DestinationAccountID string           `binding:"required,uuid,nefield=SourceAccountID"`
+	Amount               *decimal.Decimal `binding:"required,positive_amount"`
 }
 
 // TransferInput represents input object for a transfer transaction
@@ -29,6 +29,11 @@ type TransferInput struct {
 	Amount             *decimal.Decimal
 }
 
+// ReversalInput represents input object for reversing a transfer
+type ReversalInput struct {
+	Reason string
+}
+
 // AccountInformationOutput represents a robust output object for accounts
 type AccountInformationOutput struct {
 	UUID            string
@@ -42,6 +47,8 @@ type AccountInformationOutput struct {
 	BalanceType     domain.BalanceType
 	Header          domain.HeaderType
 	IsSystemAccount bool
+	GLCode          string
+	Role            domain.AccountRole
 	Balance         *decimal.Decimal
 	BalanceAsOf     *time.Time
 }
@@ -52,3 +59,36 @@ type AccessToken struct {
 	TokenType   string `json:"token_type"`
 	ExpiresIn   int    `json:"expires_in"`
 }
+
+// CreditTransferInstruction represents a single credit transfer requested in a payment initiation
+type CreditTransferInstruction struct {
+	PaymentInformationID  string
+	InstructionID         string
+	EndToEndID            string
+	DebtorAccountNumber   string
+	CreditorAccountNumber string
+	Amount                decimal.Decimal
+	Currency              domain.CurrencyType
+	RemittanceInformation string
+}
+
+// CreditTransferResult represents the outcome of executing a credit transfer instruction
+type CreditTransferResult struct {
+	Instruction   *CreditTransferInstruction
+	Accepted      bool
+	ReasonCode    string
+	Reason        string
+	TransactionID string
+
+	// HeldTransferID names the transfer fraud screening held for review, the instruction is pending until it is released
+	HeldTransferID string
+}
+
+// PaymentInitiationInput represents a batch of credit transfer instructions sent by a customer
+type PaymentInitiationInput struct {
+	MessageID    string
+	Instructions []*CreditTransferInstruction
+
+	// Initiator identifies the party that sent the message, its message and end to end identifications are unique
+	Initiator string
+}
@@ -0,0 +1,20 @@
+package application
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// TransferRecord is a posted transfer out of an account, as fraud screening looks back on it
+type TransferRecord struct {
+	TransactionID        string
+	DestinationAccountID string
+	Amount               decimal.Decimal
+	PostedAt             time.Time
+}
+
+// ReviewInput represents input object for rejecting a held transfer
+type ReviewInput struct {
+	Reason string
+}
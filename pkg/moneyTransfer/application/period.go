@@ -0,0 +1,35 @@
+package application
+
+import (
+	"time"
+
+	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/domain"
+	"github.com/shopspring/decimal"
+)
+
+// ClosePeriodInput represents input object for closing a business day or month
+type ClosePeriodInput struct {
+	Type domain.PeriodType
+	Date string
+}
+
+// ReopenPeriodInput represents input object for requesting or rejecting a period reopen
+type ReopenPeriodInput struct {
+	Reason string
+}
+
+// AdjustmentInput represents input object for a manual journal adjustment
+type AdjustmentInput struct {
+	DebitAccountID  string
+	CreditAccountID string
+	Amount          *decimal.Decimal
+	Description     string
+	EffectiveDate   *time.Time
+}
+
+// PeriodOutput represents an accounting period with its closing balances and audit trail
+type PeriodOutput struct {
+	Period    *domain.AccountingPeriod   `json:"period"`
+	Snapshots []*domain.BalanceSnapshot  `json:"snapshots"`
+	Audit     []*domain.PeriodAuditEntry `json:"audit"`
+}
@@ -0,0 +1,86 @@
+package application
+
+import (
+	"encoding/base64"
+	"strings"
+	"time"
+
+	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/domain"
+)
+
+// Cursor represents the position of a record in a listing ordered by creation time
+type Cursor struct {
+	CreatedAt time.Time
+	ID        string
+}
+
+// EncodeCursor encodes a record's position as an opaque cursor
+func EncodeCursor(createdAt *time.Time, id string) string {
+	var position string
+	if createdAt != nil {
+		position = createdAt.UTC().Format(time.RFC3339Nano)
+	}
+	return base64.RawURLEncoding.EncodeToString([]byte(position + "|" + id))
+}
+
+// DecodeCursor decodes a cursor handed out by EncodeCursor
+func DecodeCursor(cursor string) (*Cursor, error) {
+	decoded, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, domain.NewValidationError("invalid_cursor", "%q is not a valid cursor", cursor)
+	}
+
+	position, id, ok := strings.Cut(string(decoded), "|")
+	if !ok || id == "" {
+		return nil, domain.NewValidationError("invalid_cursor", "%q is not a valid cursor", cursor)
+	}
+
+	createdAt, err := time.Parse(time.RFC3339Nano, position)
+	if err != nil {
+		return nil, domain.NewValidationError("invalid_cursor", "%q is not a valid cursor", cursor)
+	}
+
+	return &Cursor{CreatedAt: createdAt, ID: id}, nil
+}
+
+// PageInput represents a request for the records following a cursor
+type PageInput struct {
+	First int
+	After string
+}
+
+// Page represents a page of records along with the cursor to continue from
+type Page[T any] struct {
+	Items       []T
+	HasNextPage bool
+	EndCursor   string
+}
+
+// AccountFilter represents the criteria accounts are listed by, oldest first
+type AccountFilter struct {
+	Currency        *domain.CurrencyType
+	Header          *domain.HeaderType
+	IsSystemAccount *bool
+	Name            string
+	After           *Cursor
+	Limit           int
+}
+
+// TransactionFilter represents the criteria transactions are listed by, oldest first
+type TransactionFilter struct {
+	AccountID string
+	From      *time.Time
+	To        *time.Time
+	After     *Cursor
+	Limit     int
+}
+
+// EntryFilter represents the criteria account entries are listed by, oldest first
+type EntryFilter struct {
+	AccountID     string
+	TransactionID string
+	From          *time.Time
+	To            *time.Time
+	After         *Cursor
+	Limit         int
+}
@@ -0,0 +1,71 @@
+package application
+
+import (
+	"time"
+
+	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/domain"
+	"github.com/shopspring/decimal"
+)
+
+// TrialBalanceLine represents the debit and credit totals of a single account
+type TrialBalanceLine struct {
+	AccountID     string              `json:"account_id"`
+	AccountNumber string              `json:"account_number"`
+	AccountName   string              `json:"account_name"`
+	GLCode        string              `json:"gl_code"`
+	Header        domain.HeaderType   `json:"header"`
+	Currency      domain.CurrencyType `json:"currency"`
+	BalanceType   domain.BalanceType  `json:"balance_type"`
+	TotalDebit    decimal.Decimal     `json:"total_debit"`
+	TotalCredit   decimal.Decimal     `json:"total_credit"`
+	Balance       decimal.Decimal     `json:"balance"`
+}
+
+// TrialBalanceTotal represents the debit and credit totals of a header in a currency
+type TrialBalanceTotal struct {
+	Header      domain.HeaderType   `json:"header"`
+	Currency    domain.CurrencyType `json:"currency"`
+	TotalDebit  decimal.Decimal     `json:"total_debit"`
+	TotalCredit decimal.Decimal     `json:"total_credit"`
+}
+
+// TrialBalance proves that the books balance as of a given date
+type TrialBalance struct {
+	AsOf        time.Time            `json:"as_of"`
+	Accounts    []*TrialBalanceLine  `json:"accounts"`
+	Totals      []*TrialBalanceTotal `json:"totals"`
+	TotalDebit  decimal.Decimal      `json:"total_debit"`
+	TotalCredit decimal.Decimal      `json:"total_credit"`
+	Balanced    bool                 `json:"balanced"`
+}
+
+// GeneralLedgerEntry represents a single posting to an account with its running balance
+type GeneralLedgerEntry struct {
+	EntryID        string          `json:"entry_id"`
+	TransactionID  string          `json:"transaction_id"`
+	Description    string          `json:"description"`
+	EffectiveDate  time.Time       `json:"effective_date"`
+	DebitAmount    decimal.Decimal `json:"dr_amount"`
+	CreditAmount   decimal.Decimal `json:"cr_amount"`
+	RunningBalance decimal.Decimal `json:"running_balance"`
+}
+
+// GeneralLedger lists the postings to an account over a period
+type GeneralLedger struct {
+	Account        *AccountInformationOutput `json:"account"`
+	From           time.Time                 `json:"from"`
+	To             time.Time                 `json:"to"`
+	OpeningBalance decimal.Decimal           `json:"opening_balance"`
+	ClosingBalance decimal.Decimal           `json:"closing_balance"`
+	Entries        []*GeneralLedgerEntry     `json:"entries"`
+}
+
+// LedgerCheck is the result of verifying that total debits equal total credits
+type LedgerCheck struct {
+	CheckedAt              time.Time       `json:"checked_at"`
+	TotalDebit             decimal.Decimal `json:"total_debit"`
+	TotalCredit            decimal.Decimal `json:"total_credit"`
+	Difference             decimal.Decimal `json:"difference"`
+	UnbalancedTransactions []string        `json:"unbalanced_transactions"`
+	Balanced               bool            `json:"balanced"`
+}
@@ -0,0 +1,31 @@
+package application
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// StatementLine represents a single movement on an account's statement.
+// Amount is signed from the account holder's perspective: positive for money in, negative for money out
+type StatementLine struct {
+	EntryID        string          `json:"entry_id"`
+	TransactionID  string          `json:"transaction_id"`
+	Description    string          `json:"description"`
+	BookingDate    time.Time       `json:"booking_date"`
+	Amount         decimal.Decimal `json:"amount"`
+	RunningBalance decimal.Decimal `json:"running_balance"`
+}
+
+// Statement represents an account's movements over a period with balances derived from the ledger
+type Statement struct {
+	Account        *AccountInformationOutput `json:"account"`
+	From           time.Time                 `json:"from"`
+	To             time.Time                 `json:"to"`
+	GeneratedAt    time.Time                 `json:"generated_at"`
+	OpeningBalance decimal.Decimal           `json:"opening_balance"`
+	ClosingBalance decimal.Decimal           `json:"closing_balance"`
+	TotalIn        decimal.Decimal           `json:"total_in"`
+	TotalOut       decimal.Decimal           `json:"total_out"`
+	Lines          []*StatementLine          `json:"lines"`
+}
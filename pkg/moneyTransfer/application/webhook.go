@@ -0,0 +1,21 @@
+package application
+
+import "github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/domain"
+
+// WebhookSubscriptionInput represents input object for subscribing an endpoint to events
+type WebhookSubscriptionInput struct {
+	URL        string             `json:"url"`
+	EventTypes []domain.EventType `json:"event_types"`
+	Secret     string             `json:"secret"`
+
+	// AccountIDs are the accounts whose events are delivered, only admins can leave them out to be notified
+	// about every account
+	AccountIDs []string `json:"account_ids"`
+}
+
+// WebhookSubscriptionOutput represents a created subscription with the secret its deliveries are signed with.
+// The secret is only returned when the subscription is created
+type WebhookSubscriptionOutput struct {
+	Subscription *domain.WebhookSubscription `json:"subscription"`
+	Secret       string                      `json:"secret"`
+}
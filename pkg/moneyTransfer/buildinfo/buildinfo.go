@@ -0,0 +1,57 @@
+// Package buildinfo identifies the build of the service that is running
+package buildinfo
+
+import (
+	"runtime"
+	"runtime/debug"
+)
+
+// VERSION, COMMIT and BUILT_AT are stamped at build time, e.g.
+//
+//	go build -ldflags "-X github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/buildinfo.VERSION=1.4.0"
+//
+// COMMIT and BUILT_AT default to the version control information Go records in binaries built from a checkout
+var (
+	VERSION  = "dev"
+	COMMIT   = ""
+	BUILT_AT = ""
+)
+
+// Info describes a build of the service
+type Info struct {
+	Version   string `json:"version"`
+	Commit    string `json:"commit,omitempty"`
+	BuiltAt   string `json:"builtAt,omitempty"`
+	Modified  bool   `json:"modified,omitempty"`
+	GoVersion string `json:"goVersion"`
+}
+
+// Get returns the running build's information
+func Get() Info {
+	info := Info{
+		Version:   VERSION,
+		Commit:    COMMIT,
+		BuiltAt:   BUILT_AT,
+		GoVersion: runtime.Version(),
+	}
+
+	build, ok := debug.ReadBuildInfo()
+	if !ok {
+		return info
+	}
+	for _, setting := range build.Settings {
+		switch setting.Key {
+		case "vcs.revision":
+			if info.Commit == "" {
+				info.Commit = setting.Value
+			}
+		case "vcs.time":
+			if info.BuiltAt == "" {
+				info.BuiltAt = setting.Value
+			}
+		case "vcs.modified":
+			info.Modified = setting.Value == "true"
+		}
+	}
+	return info
+}
@@ -0,0 +1,39 @@
+package buildinfo_test
+
+import (
+	"runtime"
+	"testing"
+
+	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/buildinfo"
+)
+
+func TestGet(t *testing.T) {
+	tests := []struct {
+		name        string
+		version     string
+		commit      string
+		wantVersion string
+		wantCommit  string
+	}{
+		{name: "unstamped build", version: "dev", wantVersion: "dev"},
+		{name: "stamped build", version: "1.4.0", commit: "9f2c1ab", wantVersion: "1.4.0", wantCommit: "9f2c1ab"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			version, commit := buildinfo.VERSION, buildinfo.COMMIT
+			defer func() { buildinfo.VERSION, buildinfo.COMMIT = version, commit }()
+			buildinfo.VERSION, buildinfo.COMMIT = tt.version, tt.commit
+
+			info := buildinfo.Get()
+			if info.Version != tt.wantVersion {
+				t.Errorf("expected version %q, got %q", tt.wantVersion, info.Version)
+			}
+			if tt.wantCommit != "" && info.Commit != tt.wantCommit {
+				t.Errorf("expected the stamped commit %q, got %q", tt.wantCommit, info.Commit)
+			}
+			if info.GoVersion != runtime.Version() {
+				t.Errorf("expected go version %q, got %q", runtime.Version(), info.GoVersion)
+			}
+		})
+	}
+}
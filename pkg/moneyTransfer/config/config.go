@@ -0,0 +1,390 @@
+// Package config loads the service's settings from an optional YAML file and the environment, validating them
+// before anything is started with them
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/domain"
+	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/ratelimit"
+	"github.com/shopspring/decimal"
+	"gopkg.in/yaml.v3"
+)
+
+// FILE_ENV names the environment variable pointing to the YAML configuration file, when no path is given
+var FILE_ENV = "CONFIG_FILE"
+
+// Config holds every setting of the servers and the admin CLI
+type Config struct {
+	Server     Server     `yaml:"server"`
+	Log        Log        `yaml:"log"`
+	Database   Database   `yaml:"database"`
+	Auth       Auth       `yaml:"auth"`
+	Tracing    Tracing    `yaml:"tracing"`
+	Sentry     Sentry     `yaml:"sentry"`
+	Accounting Accounting `yaml:"accounting"`
+	Outbox     Outbox     `yaml:"outbox"`
+	Features   Features   `yaml:"features"`
+	RateLimits RateLimits `yaml:"rateLimits"`
+	Fraud      Fraud      `yaml:"fraud"`
+}
+
+// Server configures the HTTP and gRPC listeners and their shutdown
+type Server struct {
+	Port            string        `yaml:"port" env:"PORT"`
+	GRPCPort        string        `yaml:"grpcPort" env:"GRPC_PORT"`
+	ShutdownTimeout time.Duration `yaml:"shutdownTimeout" env:"SHUTDOWN_TIMEOUT"`
+	DrainDelay      time.Duration `yaml:"drainDelay" env:"SHUTDOWN_DRAIN_DELAY"`
+	TLS             TLS           `yaml:"tls"`
+
+	// TrustedProxies are the addresses or CIDR ranges of the load balancers whose X-Forwarded-For header is
+	// believed, client addresses are not taken from the header when it is empty
+	TrustedProxies []string `yaml:"trustedProxies" env:"TRUSTED_PROXIES"`
+}
+
+// TLS configures the certificate both servers are served with, they serve plaintext when it is unset
+type TLS struct {
+	CertFile string `yaml:"certFile" env:"TLS_CERT_FILE"`
+	KeyFile  string `yaml:"keyFile" env:"TLS_KEY_FILE"`
+}
+
+// Enabled reports whether the servers are served over TLS
+func (t TLS) Enabled() bool {
+	return t.CertFile != ""
+}
+
+// Log configures the structured logs
+type Log struct {
+	Level string `yaml:"level" env:"LOG_LEVEL"`
+}
+
+// Database configures the PostgreSQL connection and its pool
+type Database struct {
+	Connection      string        `yaml:"connection" env:"DB_CONNECTION"`
+	Host            string        `yaml:"host" env:"DB_HOST"`
+	Port            string        `yaml:"port" env:"DB_PORT"`
+	User            string        `yaml:"user" env:"DB_USER"`
+	Password        string        `yaml:"password" env:"DB_PASS"`
+	Name            string        `yaml:"name" env:"DB_NAME"`
+	SSLMode         string        `yaml:"sslMode" env:"DB_SSLMODE"`
+	SSLRootCert     string        `yaml:"sslRootCert" env:"DB_SSLROOTCERT"`
+	TimeZone        string        `yaml:"timeZone" env:"DB_TIMEZONE"`
+	MaxOpenConns    int           `yaml:"maxOpenConns" env:"DB_MAX_OPEN_CONNS"`
+	MaxIdleConns    int           `yaml:"maxIdleConns" env:"DB_MAX_IDLE_CONNS"`
+	ConnMaxLifetime time.Duration `yaml:"connMaxLifetime" env:"DB_CONN_MAX_LIFETIME"`
+	ConnMaxIdleTime time.Duration `yaml:"connMaxIdleTime" env:"DB_CONN_MAX_IDLE_TIME"`
+}
+
+// Auth configures the Auth0 tenant issuing and validating access tokens
+type Auth struct {
+	Domain       string        `yaml:"domain" env:"AUTH0_DOMAIN"`
+	Audience     string        `yaml:"audience" env:"AUTH0_AUDIENCE"`
+	ClientID     string        `yaml:"clientId" env:"AUTH0_CLIENT_ID"`
+	ClientSecret string        `yaml:"clientSecret" env:"AUTH0_CLIENT_SECRET"`
+	GrantType    string        `yaml:"grantType" env:"AUTH0_GRANT_TYPE"`
+	JWKSCacheTTL time.Duration `yaml:"jwksCacheTTL" env:"AUTH0_JWKS_CACHE_TTL"`
+	ClockSkew    time.Duration `yaml:"clockSkew" env:"AUTH0_CLOCK_SKEW"`
+}
+
+// IssuerURL is the Auth0 tenant's issuer, also where its signing keys and tokens are served
+func (a Auth) IssuerURL() string {
+	return "https://" + a.Domain + "/"
+}
+
+// Tracing configures where spans are exported
+type Tracing struct {
+	Exporter string `yaml:"exporter" env:"OTEL_TRACES_EXPORTER"`
+}
+
+// Sentry configures the reporting of panics and errors
+type Sentry struct {
+	DSN string `yaml:"dsn" env:"SENTRY_DSN"`
+}
+
+// Accounting configures the ledger. Business days and months, and the dates periods are closed for, are
+// taken in TimeZone
+type Accounting struct {
+	ChartOfAccountsFile string `yaml:"chartOfAccountsFile" env:"CHART_OF_ACCOUNTS_FILE"`
+	TimeZone            string `yaml:"timeZone" env:"BUSINESS_TIMEZONE"`
+}
+
+// Location is the business time zone, the configuration having been validated
+func (a Accounting) Location() *time.Location {
+	location, err := time.LoadLocation(a.TimeZone)
+	if err != nil {
+		return time.UTC
+	}
+	return location
+}
+
+// Outbox configures the sinks domain events are published to, besides webhooks
+type Outbox struct {
+	File string `yaml:"file" env:"OUTBOX_FILE"`
+}
+
+// Features toggles the optional APIs and workers
+type Features struct {
+	GraphQL  bool `yaml:"graphql" env:"FEATURE_GRAPHQL"`
+	GRPC     bool `yaml:"grpc" env:"FEATURE_GRPC"`
+	Webhooks bool `yaml:"webhooks" env:"FEATURE_WEBHOOKS"`
+}
+
+// RateLimits configures the token buckets throttling callers
+type RateLimits struct {
+	Enabled bool         `yaml:"enabled" env:"RATE_LIMITS_ENABLED"`
+	Routes  []RouteLimit `yaml:"routes"`
+}
+
+// RouteLimit limits the requests to a route, such as "POST /api/v1/transfers", counting them by key: the
+// client's address, the subject of its access token or the account money is moved from
+type RouteLimit struct {
+	Route    string        `yaml:"route"`
+	Key      string        `yaml:"key"`
+	Requests int           `yaml:"requests"`
+	Period   time.Duration `yaml:"period"`
+	Burst    int           `yaml:"burst"`
+}
+
+// Fraud configures the rules transfers are screened with before they are posted. Each rule allows, holds for review
+// or blocks the transfers it flags, a rule allowing them only logs its findings so that its thresholds can be tried
+type Fraud struct {
+	Enabled      bool             `yaml:"enabled" env:"FRAUD_ENABLED"`
+	NewAccount   NewAccountRule   `yaml:"newAccount"`
+	FanOut       FanOutRule       `yaml:"fanOut"`
+	RoundTrip    RoundTripRule    `yaml:"roundTrip"`
+	UnusualHours UnusualHoursRule `yaml:"unusualHours"`
+}
+
+// Amounts are the smallest amount, per currency, a rule screens. Transfers in other currencies are not screened
+type Amounts map[domain.CurrencyType]decimal.Decimal
+
+// NewAccountRule flags transfers of at least Amounts into or out of an account opened less than MaxAge ago
+type NewAccountRule struct {
+	Action  string        `yaml:"action" env:"FRAUD_NEW_ACCOUNT_ACTION"`
+	MaxAge  time.Duration `yaml:"maxAge" env:"FRAUD_NEW_ACCOUNT_MAX_AGE"`
+	Amounts Amounts       `yaml:"amounts"`
+}
+
+// FanOutRule flags a transfer making an account send money to Destinations different accounts within Window
+type FanOutRule struct {
+	Action       string        `yaml:"action" env:"FRAUD_FAN_OUT_ACTION"`
+	Window       time.Duration `yaml:"window" env:"FRAUD_FAN_OUT_WINDOW"`
+	Destinations int           `yaml:"destinations" env:"FRAUD_FAN_OUT_DESTINATIONS"`
+}
+
+// RoundTripRule flags a transfer sending money back to an account it came from within Window
+type RoundTripRule struct {
+	Action string        `yaml:"action" env:"FRAUD_ROUND_TRIP_ACTION"`
+	Window time.Duration `yaml:"window" env:"FRAUD_ROUND_TRIP_WINDOW"`
+}
+
+// UnusualHoursRule flags transfers of at least Amounts made from the hour From up to the hour To in TimeZone,
+// wrapping around midnight when From is after To
+type UnusualHoursRule struct {
+	Action   string  `yaml:"action" env:"FRAUD_UNUSUAL_HOURS_ACTION"`
+	From     int     `yaml:"from" env:"FRAUD_UNUSUAL_HOURS_FROM"`
+	To       int     `yaml:"to" env:"FRAUD_UNUSUAL_HOURS_TO"`
+	TimeZone string  `yaml:"timeZone" env:"FRAUD_UNUSUAL_HOURS_TIMEZONE"`
+	Amounts  Amounts `yaml:"amounts"`
+}
+
+// Default returns the settings used for whatever the file and environment leave unset
+func Default() *Config {
+	return &Config{
+		Server: Server{
+			Port:            "8080",
+			GRPCPort:        "9090",
+			ShutdownTimeout: 5 * time.Second,
+			DrainDelay:      5 * time.Second,
+		},
+		Log: Log{Level: "info"},
+		Database: Database{
+			Connection:      DB_LOCAL,
+			Port:            "5432",
+			SSLMode:         "prefer",
+			TimeZone:        "Africa/Nairobi",
+			MaxOpenConns:    25,
+			MaxIdleConns:    5,
+			ConnMaxLifetime: 30 * time.Minute,
+			ConnMaxIdleTime: 5 * time.Minute,
+		},
+		Auth: Auth{
+			GrantType:    "client_credentials",
+			JWKSCacheTTL: 5 * time.Minute,
+			ClockSkew:    time.Minute,
+		},
+		Tracing:    Tracing{Exporter: "none"},
+		Accounting: Accounting{TimeZone: "Africa/Nairobi"},
+		Features: Features{
+			GraphQL:  true,
+			GRPC:     true,
+			Webhooks: true,
+		},
+		RateLimits: RateLimits{
+			Enabled: true,
+			Routes: []RouteLimit{
+				{Route: "POST /api/v1/access_token", Key: ratelimit.KEY_IP, Requests: 10, Period: time.Minute},
+				{Route: "POST /api/v1/transfers", Key: ratelimit.KEY_IP, Requests: 300, Period: time.Minute},
+				{Route: "POST /api/v1/transfers", Key: ratelimit.KEY_SUBJECT, Requests: 120, Period: time.Minute},
+				{Route: "POST /api/v1/transfers", Key: ratelimit.KEY_ACCOUNT, Requests: 30, Period: time.Minute, Burst: 10},
+			},
+		},
+		Fraud: Fraud{
+			Enabled: true,
+			NewAccount: NewAccountRule{
+				Action:  FRAUD_HOLD,
+				MaxAge:  72 * time.Hour,
+				Amounts: Amounts{domain.Kenyan: decimal.NewFromInt(100000), domain.Ugandan: decimal.NewFromInt(3000000)},
+			},
+			FanOut: FanOutRule{
+				Action:       FRAUD_HOLD,
+				Window:       time.Hour,
+				Destinations: 10,
+			},
+			RoundTrip: RoundTripRule{
+				Action: FRAUD_HOLD,
+				Window: time.Hour,
+			},
+			UnusualHours: UnusualHoursRule{
+				Action:   FRAUD_HOLD,
+				From:     0,
+				To:       5,
+				TimeZone: "Africa/Nairobi",
+				Amounts:  Amounts{domain.Kenyan: decimal.NewFromInt(50000), domain.Ugandan: decimal.NewFromInt(1500000)},
+			},
+		},
+	}
+}
+
+// Read layers the YAML file at path, or at CONFIG_FILE when path is empty, and then the environment over the
+// defaults. The result is not validated
+func Read(path string) (*Config, error) {
+	config := Default()
+
+	if path == "" {
+		path = os.Getenv(FILE_ENV)
+	}
+	if path != "" {
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("unable to read the configuration file: %v", err)
+		}
+		if err := decodeYAML(bytes.NewReader(content), config); err != nil {
+			return nil, fmt.Errorf("unable to decode the configuration file %s: %v", path, err)
+		}
+	}
+
+	if err := applyEnv(reflect.ValueOf(config).Elem(), os.LookupEnv); err != nil {
+		return nil, err
+	}
+	return config, nil
+}
+
+// Load reads the configuration and validates it
+func Load(path string) (*Config, error) {
+	config, err := Read(path)
+	if err != nil {
+		return nil, err
+	}
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+	return config, nil
+}
+
+// decodeYAML decodes a configuration file, rejecting the keys that are not settings so that typos are caught
+func decodeYAML(r io.Reader, config *Config) error {
+	decoder := yaml.NewDecoder(r)
+	decoder.KnownFields(true)
+	if err := decoder.Decode(config); err != nil && !errors.Is(err, io.EOF) {
+		return err
+	}
+	return nil
+}
+
+// applyEnv sets the fields tagged with an environment variable that is set, recursing into nested settings
+func applyEnv(v reflect.Value, lookup func(string) (string, bool)) error {
+	var problems []string
+	for i := 0; i < v.NumField(); i++ {
+		field, value := v.Type().Field(i), v.Field(i)
+		if field.Type.Kind() == reflect.Struct {
+			if err := applyEnv(value, lookup); err != nil {
+				var invalid *ValidationError
+				if !errors.As(err, &invalid) {
+					return err
+				}
+				problems = append(problems, invalid.Problems...)
+			}
+			continue
+		}
+
+		// Variables set empty, as in a template env file, are left unset
+		name := field.Tag.Get("env")
+		raw, ok := lookup(name)
+		if name == "" || !ok || raw == "" {
+			continue
+		}
+		if err := setField(value, raw); err != nil {
+			problems = append(problems, fmt.Sprintf("%s: %v", name, err))
+		}
+	}
+
+	if len(problems) > 0 {
+		return &ValidationError{Problems: problems}
+	}
+	return nil
+}
+
+// setField parses an environment variable's value into a setting, lists being separated by commas
+func setField(value reflect.Value, raw string) error {
+	switch {
+	case value.Type() == reflect.TypeOf(time.Duration(0)):
+		duration, err := time.ParseDuration(raw)
+		if err != nil {
+			return fmt.Errorf("%q is not a duration, such as 30s or 5m", raw)
+		}
+		value.SetInt(int64(duration))
+	case value.Kind() == reflect.String:
+		value.SetString(raw)
+	case value.Kind() == reflect.Int:
+		number, err := strconv.Atoi(raw)
+		if err != nil {
+			return fmt.Errorf("%q is not a whole number", raw)
+		}
+		value.SetInt(int64(number))
+	case value.Type() == reflect.TypeOf([]string{}):
+		var values []string
+		for _, item := range strings.Split(raw, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				values = append(values, item)
+			}
+		}
+		value.Set(reflect.ValueOf(values))
+	case value.Kind() == reflect.Bool:
+		enabled, err := strconv.ParseBool(raw)
+		if err != nil {
+			return fmt.Errorf("%q is not true or false", raw)
+		}
+		value.SetBool(enabled)
+	default:
+		return fmt.Errorf("unsupported setting type %s", value.Type())
+	}
+	return nil
+}
+
+// ValidationError lists every problem found with a configuration
+type ValidationError struct {
+	Problems []string
+}
+
+func (e *ValidationError) Error() string {
+	return "invalid configuration:\n  - " + strings.Join(e.Problems, "\n  - ")
+}
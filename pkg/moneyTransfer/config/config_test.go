@@ -0,0 +1,300 @@
+package config_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/config"
+	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/domain"
+	"github.com/shopspring/decimal"
+)
+
+// validConfig is a configuration every check passes
+func validConfig() *config.Config {
+	cfg := config.Default()
+	cfg.Database.Host = "localhost"
+	cfg.Database.User = "ledger"
+	cfg.Database.Name = "ledger"
+	cfg.Auth.Domain = "tenant.eu.auth0.com"
+	cfg.Auth.Audience = "https://api.example.com"
+	cfg.Auth.ClientID = "client"
+	cfg.Auth.ClientSecret = "secret"
+	return cfg
+}
+
+func writeFile(t *testing.T, content string) string {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("unable to write the configuration file: %v", err)
+	}
+	return path
+}
+
+func TestRead(t *testing.T) {
+	tests := []struct {
+		name    string
+		file    string
+		env     map[string]string
+		check   func(t *testing.T, cfg *config.Config)
+		wantErr string
+	}{
+		{
+			name: "defaults",
+			check: func(t *testing.T, cfg *config.Config) {
+				if cfg.Server.GRPCPort != "9090" || cfg.Database.SSLMode != "prefer" || !cfg.Features.GraphQL {
+					t.Errorf("expected the defaults, got %+v", cfg)
+				}
+			},
+		},
+		{
+			name: "file over the defaults",
+			file: "database:\n  host: db.internal\n  maxOpenConns: 50\n  connMaxLifetime: 1h\nfeatures:\n  graphql: false\n",
+			check: func(t *testing.T, cfg *config.Config) {
+				if cfg.Database.Host != "db.internal" || cfg.Database.MaxOpenConns != 50 || cfg.Database.ConnMaxLifetime != time.Hour {
+					t.Errorf("expected the file's database settings, got %+v", cfg.Database)
+				}
+				if cfg.Features.GraphQL || !cfg.Features.Webhooks {
+					t.Errorf("expected only GraphQL to be disabled, got %+v", cfg.Features)
+				}
+				if cfg.Database.Port != "5432" {
+					t.Errorf("expected the settings missing from the file to keep their default, got port %q", cfg.Database.Port)
+				}
+			},
+		},
+		{
+			name: "environment over the file",
+			file: "database:\n  host: db.internal\n  sslMode: require\n",
+			env:  map[string]string{"DB_HOST": "db.override", "DB_MAX_IDLE_CONNS": "2", "FEATURE_GRPC": "false", "SHUTDOWN_DRAIN_DELAY": "0s", "TRUSTED_PROXIES": "10.0.0.0/8, 192.0.2.1"},
+			check: func(t *testing.T, cfg *config.Config) {
+				if cfg.Database.Host != "db.override" || cfg.Database.SSLMode != "require" || cfg.Database.MaxIdleConns != 2 {
+					t.Errorf("expected the environment to override the file, got %+v", cfg.Database)
+				}
+				if cfg.Features.GRPC || cfg.Server.DrainDelay != 0 {
+					t.Errorf("expected the environment's toggles and durations, got %+v", cfg)
+				}
+				if len(cfg.Server.TrustedProxies) != 2 || cfg.Server.TrustedProxies[1] != "192.0.2.1" {
+					t.Errorf("expected the environment's list of proxies, got %q", cfg.Server.TrustedProxies)
+				}
+			},
+		},
+		{
+			name: "empty environment variables left unset",
+			env:  map[string]string{"GRPC_PORT": "", "LOG_LEVEL": ""},
+			check: func(t *testing.T, cfg *config.Config) {
+				if cfg.Server.GRPCPort != "9090" || cfg.Log.Level != "info" {
+					t.Errorf("expected the defaults, got %+v %+v", cfg.Server, cfg.Log)
+				}
+			},
+		},
+		{
+			name: "fraud amounts merged over the defaults",
+			file: "fraud:\n  newAccount:\n    amounts:\n      KSH: \"5000.50\"\n",
+			env:  map[string]string{"FRAUD_FAN_OUT_ACTION": "block", "FRAUD_UNUSUAL_HOURS_FROM": "23"},
+			check: func(t *testing.T, cfg *config.Config) {
+				if !cfg.Fraud.NewAccount.Amounts[domain.Kenyan].Equal(decimal.RequireFromString("5000.50")) || cfg.Fraud.NewAccount.Amounts[domain.Ugandan].IsZero() {
+					t.Errorf("expected the file's amount beside the default ones, got %v", cfg.Fraud.NewAccount.Amounts)
+				}
+				if cfg.Fraud.FanOut.Action != "block" || cfg.Fraud.UnusualHours.From != 23 {
+					t.Errorf("expected the environment's fraud settings, got %+v", cfg.Fraud)
+				}
+			},
+		},
+		{
+			name:    "unknown file setting",
+			file:    "database:\n  hots: db.internal\n",
+			wantErr: "field hots not found",
+		},
+		{
+			name:    "malformed environment variables",
+			env:     map[string]string{"DB_MAX_OPEN_CONNS": "many", "AUTH0_JWKS_CACHE_TTL": "5"},
+			wantErr: `DB_MAX_OPEN_CONNS: "many" is not a whole number`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv(config.FILE_ENV, "")
+			for name, value := range tt.env {
+				t.Setenv(name, value)
+			}
+			path := ""
+			if tt.file != "" {
+				path = writeFile(t, tt.file)
+			}
+
+			cfg, err := config.Read(path)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("expected an error mentioning %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Read() error = %v", err)
+			}
+			tt.check(t, cfg)
+		})
+	}
+}
+
+func TestRead_FileFromEnvironment(t *testing.T) {
+	t.Setenv(config.FILE_ENV, writeFile(t, "log:\n  level: debug\n"))
+	t.Setenv("LOG_LEVEL", "")
+
+	cfg, err := config.Read("")
+	if err != nil {
+		t.Fatalf("Read() error = %v", err)
+	}
+	if cfg.Log.Level != "debug" {
+		t.Errorf("expected the file named by %s to be read, got level %q", config.FILE_ENV, cfg.Log.Level)
+	}
+}
+
+func TestConfig_Validate(t *testing.T) {
+	tests := []struct {
+		name      string
+		change    func(cfg *config.Config)
+		wantProbs []string
+	}{
+		{
+			name:   "valid",
+			change: func(cfg *config.Config) {},
+		},
+		{
+			name: "every problem reported at once",
+			change: func(cfg *config.Config) {
+				cfg.Database.Host = ""
+				cfg.Auth.Audience = ""
+				cfg.Server.Port = "80800"
+			},
+			wantProbs: []string{
+				"database.host (DB_HOST) is required",
+				"auth.audience (AUTH0_AUDIENCE) is required",
+				`server.port (PORT) should be a port between 1 and 65535, got "80800"`,
+			},
+		},
+		{
+			name: "database pool",
+			change: func(cfg *config.Config) {
+				cfg.Database.MaxOpenConns = 4
+				cfg.Database.MaxIdleConns = 10
+				cfg.Database.ConnMaxIdleTime = 0
+			},
+			wantProbs: []string{"database.maxIdleConns (DB_MAX_IDLE_CONNS)", "database.connMaxIdleTime (DB_CONN_MAX_IDLE_TIME)"},
+		},
+		{
+			name: "database connection",
+			change: func(cfg *config.Config) {
+				cfg.Database.SSLMode = "on"
+				cfg.Database.TimeZone = "Mars/Olympus_Mons"
+				cfg.Database.Connection = "remote"
+			},
+			wantProbs: []string{"database.sslMode (DB_SSLMODE)", "database.timeZone (DB_TIMEZONE)", "database.connection (DB_CONNECTION)"},
+		},
+		{
+			name: "business time zone",
+			change: func(cfg *config.Config) {
+				cfg.Accounting.TimeZone = ""
+			},
+			wantProbs: []string{`accounting.timeZone (BUSINESS_TIMEZONE) should be an IANA time zone such as Africa/Nairobi, got ""`},
+		},
+		{
+			name: "tls without a key",
+			change: func(cfg *config.Config) {
+				cfg.Server.TLS.CertFile = "/missing/server.crt"
+			},
+			wantProbs: []string{"server.tls (TLS_CERT_FILE, TLS_KEY_FILE) needs both a certificate and a key file", "server.tls.certFile (TLS_CERT_FILE)"},
+		},
+		{
+			name: "rate limits",
+			change: func(cfg *config.Config) {
+				cfg.Server.TrustedProxies = []string{"10.0.0.0/8", "load-balancer"}
+				cfg.RateLimits.Routes = append(cfg.RateLimits.Routes,
+					config.RouteLimit{Route: "/transfers", Key: "ip", Requests: 1, Period: time.Minute},
+					config.RouteLimit{Route: "POST /api/v1/transfers", Key: "account", Requests: 0, Period: time.Minute},
+					config.RouteLimit{Route: "POST /api/v1/periods", Key: "tenant", Requests: 1},
+				)
+			},
+			wantProbs: []string{
+				`server.trustedProxies (TRUSTED_PROXIES) should list addresses or CIDR ranges, got "load-balancer"`,
+				"rateLimits.routes[4].route",
+				"rateLimits.routes[5] limits POST /api/v1/transfers by account more than once",
+				"rateLimits.routes[5].requests should be at least 1",
+				"rateLimits.routes[6].key",
+				"rateLimits.routes[6].period",
+			},
+		},
+		{
+			name: "fraud rules",
+			change: func(cfg *config.Config) {
+				cfg.Fraud.NewAccount.Action = "review"
+				cfg.Fraud.NewAccount.Amounts = config.Amounts{"USD": decimal.NewFromInt(100), domain.Kenyan: decimal.NewFromInt(-1)}
+				cfg.Fraud.FanOut.Destinations = 1
+				cfg.Fraud.RoundTrip.Window = 0
+				cfg.Fraud.UnusualHours.To = 24
+				cfg.Fraud.UnusualHours.TimeZone = "Nairobi"
+			},
+			wantProbs: []string{
+				`fraud.newAccount.action (FRAUD_NEW_ACCOUNT_ACTION) should be one of [allow hold block], got "review"`,
+				"fraud.newAccount.amounts.KSH should not be negative",
+				`fraud.newAccount.amounts should be keyed by one of`,
+				"fraud.fanOut.destinations (FRAUD_FAN_OUT_DESTINATIONS) should be at least 2, got 1",
+				"fraud.roundTrip.window (FRAUD_ROUND_TRIP_WINDOW)",
+				"fraud.unusualHours.to (FRAUD_UNUSUAL_HOURS_TO) should be an hour between 0 and 23, got 24",
+				"fraud.unusualHours.timeZone (FRAUD_UNUSUAL_HOURS_TIMEZONE)",
+			},
+		},
+		{
+			name: "unknown log level and exporter",
+			change: func(cfg *config.Config) {
+				cfg.Log.Level = "loud"
+				cfg.Tracing.Exporter = "jaeger"
+			},
+			wantProbs: []string{"log.level (LOG_LEVEL)", "tracing.exporter (OTEL_TRACES_EXPORTER)"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := validConfig()
+			tt.change(cfg)
+
+			err := cfg.Validate()
+			if len(tt.wantProbs) == 0 {
+				if err != nil {
+					t.Fatalf("Validate() error = %v", err)
+				}
+				return
+			}
+
+			var invalid *config.ValidationError
+			if !errors.As(err, &invalid) {
+				t.Fatalf("expected a validation error, got %v", err)
+			}
+			if len(invalid.Problems) != len(tt.wantProbs) {
+				t.Errorf("expected %d problems, got %v", len(tt.wantProbs), invalid.Problems)
+			}
+			for _, want := range tt.wantProbs {
+				if !strings.Contains(err.Error(), want) {
+					t.Errorf("expected %q to be reported, got %v", want, err)
+				}
+			}
+		})
+	}
+}
+
+func TestDatabase_Validate(t *testing.T) {
+	cfg := config.Default()
+	cfg.Database.Host = "localhost"
+	cfg.Database.User = "ledger"
+	cfg.Database.Name = "ledger"
+
+	if err := cfg.Database.Validate(); err != nil {
+		t.Errorf("expected the database settings to be enough for the admin CLI, got %v", err)
+	}
+	if err := cfg.Validate(); err == nil {
+		t.Errorf("expected the servers to need the auth settings as well")
+	}
+}
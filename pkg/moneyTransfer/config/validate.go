@@ -0,0 +1,241 @@
+package config
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/domain"
+	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/infrastructure/tracing"
+	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/ratelimit"
+	"go.uber.org/zap/zapcore"
+)
+
+// Database connections
+var (
+	DB_LOCAL = "local"
+	DB_CLOUD = "cloud"
+)
+
+// Actions a fraud rule takes on the transfers it flags
+var (
+	FRAUD_ALLOW = "allow"
+	FRAUD_HOLD  = "hold"
+	FRAUD_BLOCK = "block"
+)
+
+// FRAUD_ACTIONS are the actions fraud rules can take
+var FRAUD_ACTIONS = []string{FRAUD_ALLOW, FRAUD_HOLD, FRAUD_BLOCK}
+
+// SSL_MODES are the PostgreSQL sslmode values accepted
+var SSL_MODES = []string{"disable", "allow", "prefer", "require", "verify-ca", "verify-full"}
+
+// problems collects what is wrong with a configuration, naming each setting by its YAML path and environment variable
+type problems []string
+
+func (p *problems) add(setting string, env string, format string, args ...interface{}) {
+	if env == "" {
+		*p = append(*p, setting+" "+fmt.Sprintf(format, args...))
+		return
+	}
+	*p = append(*p, fmt.Sprintf("%s (%s) ", setting, env)+fmt.Sprintf(format, args...))
+}
+
+func (p *problems) required(setting string, env string, value string) {
+	if value == "" {
+		p.add(setting, env, "is required")
+	}
+}
+
+func (p *problems) port(setting string, env string, value string) {
+	port, err := strconv.Atoi(value)
+	if err != nil || port < 1 || port > 65535 {
+		p.add(setting, env, "should be a port between 1 and 65535, got %q", value)
+	}
+}
+
+func (p *problems) oneOf(setting string, env string, value string, allowed ...string) {
+	for _, candidate := range allowed {
+		if value == candidate {
+			return
+		}
+	}
+	p.add(setting, env, "should be one of %v, got %q", allowed, value)
+}
+
+func (p *problems) positive(setting string, env string, value time.Duration) {
+	if value <= 0 {
+		p.add(setting, env, "should be a positive duration, got %v", value)
+	}
+}
+
+func (p *problems) hour(setting string, env string, value int) {
+	if value < 0 || value > 23 {
+		p.add(setting, env, "should be an hour between 0 and 23, got %d", value)
+	}
+}
+
+func (p *problems) amounts(setting string, amounts Amounts) {
+	currencies := make([]string, 0, len(amounts))
+	for currency := range amounts {
+		currencies = append(currencies, string(currency))
+	}
+	sort.Strings(currencies)
+
+	for _, key := range currencies {
+		currency, amount := domain.CurrencyType(key), amounts[domain.CurrencyType(key)]
+		if !domain.IsKnownCurrency(currency) {
+			p.add(setting, "", "should be keyed by one of %v, got %q", domain.CURRENCIES, currency)
+		}
+		if amount.IsNegative() {
+			p.add(setting+"."+string(currency), "", "should not be negative, got %v", amount)
+		}
+	}
+}
+
+func (p *problems) readable(setting string, env string, path string) {
+	if path == "" {
+		return
+	}
+	if _, err := os.Stat(path); err != nil {
+		p.add(setting, env, "should name a readable file: %v", err)
+	}
+}
+
+// err reports the problems found, if any
+func (p problems) err() error {
+	if len(p) == 0 {
+		return nil
+	}
+	return &ValidationError{Problems: p}
+}
+
+// Validate checks every setting the servers need, reporting all the problems found at once
+func (c *Config) Validate() error {
+	var found problems
+
+	found.port("server.port", "PORT", c.Server.Port)
+	found.port("server.grpcPort", "GRPC_PORT", c.Server.GRPCPort)
+	found.positive("server.shutdownTimeout", "SHUTDOWN_TIMEOUT", c.Server.ShutdownTimeout)
+	if c.Server.DrainDelay < 0 {
+		found.add("server.drainDelay", "SHUTDOWN_DRAIN_DELAY", "should not be negative, got %v", c.Server.DrainDelay)
+	}
+	if (c.Server.TLS.CertFile == "") != (c.Server.TLS.KeyFile == "") {
+		found.add("server.tls", "TLS_CERT_FILE, TLS_KEY_FILE", "needs both a certificate and a key file")
+	}
+	found.readable("server.tls.certFile", "TLS_CERT_FILE", c.Server.TLS.CertFile)
+	found.readable("server.tls.keyFile", "TLS_KEY_FILE", c.Server.TLS.KeyFile)
+	for _, proxy := range c.Server.TrustedProxies {
+		if _, _, err := net.ParseCIDR(proxy); err != nil && net.ParseIP(proxy) == nil {
+			found.add("server.trustedProxies", "TRUSTED_PROXIES", "should list addresses or CIDR ranges, got %q", proxy)
+		}
+	}
+
+	var level zapcore.Level
+	if err := level.UnmarshalText([]byte(c.Log.Level)); err != nil {
+		found.add("log.level", "LOG_LEVEL", "should be one of debug, info, warn or error, got %q", c.Log.Level)
+	}
+
+	found = append(found, c.Database.problems()...)
+
+	found.required("auth.domain", "AUTH0_DOMAIN", c.Auth.Domain)
+	found.required("auth.audience", "AUTH0_AUDIENCE", c.Auth.Audience)
+	found.required("auth.clientId", "AUTH0_CLIENT_ID", c.Auth.ClientID)
+	found.required("auth.clientSecret", "AUTH0_CLIENT_SECRET", c.Auth.ClientSecret)
+	found.required("auth.grantType", "AUTH0_GRANT_TYPE", c.Auth.GrantType)
+	found.positive("auth.jwksCacheTTL", "AUTH0_JWKS_CACHE_TTL", c.Auth.JWKSCacheTTL)
+	if c.Auth.ClockSkew < 0 {
+		found.add("auth.clockSkew", "AUTH0_CLOCK_SKEW", "should not be negative, got %v", c.Auth.ClockSkew)
+	}
+
+	found.oneOf("tracing.exporter", "OTEL_TRACES_EXPORTER", c.Tracing.Exporter, tracing.EXPORTER_NONE, tracing.EXPORTER_STDOUT, tracing.EXPORTER_OTLP)
+	found.readable("accounting.chartOfAccountsFile", "CHART_OF_ACCOUNTS_FILE", c.Accounting.ChartOfAccountsFile)
+	if _, err := time.LoadLocation(c.Accounting.TimeZone); err != nil || c.Accounting.TimeZone == "" {
+		found.add("accounting.timeZone", "BUSINESS_TIMEZONE", "should be an IANA time zone such as Africa/Nairobi, got %q", c.Accounting.TimeZone)
+	}
+
+	limited := map[string]bool{}
+	for i, limit := range c.RateLimits.Routes {
+		setting := fmt.Sprintf("rateLimits.routes[%d]", i)
+		if method, path, ok := strings.Cut(limit.Route, " "); !ok || method == "" || !strings.HasPrefix(path, "/") {
+			found.add(setting+".route", "", "should be a method and a path such as \"POST /api/v1/transfers\", got %q", limit.Route)
+		}
+		found.oneOf(setting+".key", "", limit.Key, ratelimit.KEYS...)
+		if limited[limit.Route+" "+limit.Key] {
+			found.add(setting, "", "limits %s by %s more than once", limit.Route, limit.Key)
+		}
+		limited[limit.Route+" "+limit.Key] = true
+		if limit.Requests < 1 {
+			found.add(setting+".requests", "", "should be at least 1, got %d", limit.Requests)
+		}
+		found.positive(setting+".period", "", limit.Period)
+		if limit.Burst < 0 {
+			found.add(setting+".burst", "", "should not be negative, got %d", limit.Burst)
+		}
+	}
+
+	found = append(found, c.Fraud.problems()...)
+
+	return found.err()
+}
+
+func (f Fraud) problems() problems {
+	var found problems
+
+	found.oneOf("fraud.newAccount.action", "FRAUD_NEW_ACCOUNT_ACTION", f.NewAccount.Action, FRAUD_ACTIONS...)
+	found.positive("fraud.newAccount.maxAge", "FRAUD_NEW_ACCOUNT_MAX_AGE", f.NewAccount.MaxAge)
+	found.amounts("fraud.newAccount.amounts", f.NewAccount.Amounts)
+
+	found.oneOf("fraud.fanOut.action", "FRAUD_FAN_OUT_ACTION", f.FanOut.Action, FRAUD_ACTIONS...)
+	found.positive("fraud.fanOut.window", "FRAUD_FAN_OUT_WINDOW", f.FanOut.Window)
+	if f.FanOut.Destinations < 2 {
+		found.add("fraud.fanOut.destinations", "FRAUD_FAN_OUT_DESTINATIONS", "should be at least 2, got %d", f.FanOut.Destinations)
+	}
+
+	found.oneOf("fraud.roundTrip.action", "FRAUD_ROUND_TRIP_ACTION", f.RoundTrip.Action, FRAUD_ACTIONS...)
+	found.positive("fraud.roundTrip.window", "FRAUD_ROUND_TRIP_WINDOW", f.RoundTrip.Window)
+
+	found.oneOf("fraud.unusualHours.action", "FRAUD_UNUSUAL_HOURS_ACTION", f.UnusualHours.Action, FRAUD_ACTIONS...)
+	found.hour("fraud.unusualHours.from", "FRAUD_UNUSUAL_HOURS_FROM", f.UnusualHours.From)
+	found.hour("fraud.unusualHours.to", "FRAUD_UNUSUAL_HOURS_TO", f.UnusualHours.To)
+	if _, err := time.LoadLocation(f.UnusualHours.TimeZone); err != nil || f.UnusualHours.TimeZone == "" {
+		found.add("fraud.unusualHours.timeZone", "FRAUD_UNUSUAL_HOURS_TIMEZONE", "should be an IANA time zone such as Africa/Nairobi, got %q", f.UnusualHours.TimeZone)
+	}
+	found.amounts("fraud.unusualHours.amounts", f.UnusualHours.Amounts)
+
+	return found
+}
+
+// Validate checks the database settings, all the admin CLI needs
+func (d Database) Validate() error {
+	return d.problems().err()
+}
+
+func (d Database) problems() problems {
+	var found problems
+
+	found.oneOf("database.connection", "DB_CONNECTION", d.Connection, DB_LOCAL, DB_CLOUD)
+	found.required("database.host", "DB_HOST", d.Host)
+	found.port("database.port", "DB_PORT", d.Port)
+	found.required("database.user", "DB_USER", d.User)
+	found.required("database.name", "DB_NAME", d.Name)
+	found.oneOf("database.sslMode", "DB_SSLMODE", d.SSLMode, SSL_MODES...)
+	found.readable("database.sslRootCert", "DB_SSLROOTCERT", d.SSLRootCert)
+	if _, err := time.LoadLocation(d.TimeZone); err != nil || d.TimeZone == "" {
+		found.add("database.timeZone", "DB_TIMEZONE", "should be an IANA time zone such as Africa/Nairobi, got %q", d.TimeZone)
+	}
+	if d.MaxOpenConns < 1 {
+		found.add("database.maxOpenConns", "DB_MAX_OPEN_CONNS", "should be at least 1, got %d", d.MaxOpenConns)
+	}
+	if d.MaxIdleConns < 0 || d.MaxIdleConns > d.MaxOpenConns {
+		found.add("database.maxIdleConns", "DB_MAX_IDLE_CONNS", "should be between 0 and maxOpenConns (%d), got %d", d.MaxOpenConns, d.MaxIdleConns)
+	}
+	found.positive("database.connMaxLifetime", "DB_CONN_MAX_LIFETIME", d.ConnMaxLifetime)
+	found.positive("database.connMaxIdleTime", "DB_CONN_MAX_IDLE_TIME", d.ConnMaxIdleTime)
+
+	return found
+}
@@ -37,6 +37,40 @@ const (
 	Ugandan CurrencyType = "UGX"
 )
 
+// CURRENCIES lists the currencies accounts can be held in
+var CURRENCIES = []CurrencyType{Kenyan, Ugandan}
+
+// IsKnownCurrency reports whether accounts can be held in a currency
+func IsKnownCurrency(currency CurrencyType) bool {
+	for _, known := range CURRENCIES {
+		if currency == known {
+			return true
+		}
+	}
+	return false
+}
+
+// ISOCode returns the ISO 4217 code of a currency, used when exchanging data with other systems
+func (c CurrencyType) ISOCode() string {
+	if c == Kenyan {
+		return "KES"
+	}
+	return string(c)
+}
+
+// MinorUnits returns the number of decimal places amounts in a currency are expressed in
+func (c CurrencyType) MinorUnits() int32 {
+	if c == Ugandan {
+		return 0
+	}
+	return 2
+}
+
+// Fits reports whether an amount has no more decimal places than the currency's minor units
+func (c CurrencyType) Fits(amount decimal.Decimal) bool {
+	return amount.Equal(amount.Round(c.MinorUnits()))
+}
+
 // BalanceType defines how an account's end balance is computed
 type BalanceType string
 
@@ -60,6 +94,42 @@ const (
 
 	// Cash ..
 	Cash HeaderType = "CASH"
+
+	// Suspense is a grouping for accounts holding unreconciled value
+	Suspense HeaderType = "SUSPENSE"
+
+	// Fee is a grouping for accounts collecting transaction fees
+	Fee HeaderType = "FEE"
+)
+
+// HEADERS lists the headers accounts can be grouped under
+var HEADERS = []HeaderType{Deposit, Loan, Cash, Suspense, Fee}
+
+// CUSTOMER_HEADERS lists the headers customers can open accounts under, the others group system accounts
+var CUSTOMER_HEADERS = []HeaderType{Deposit, Loan}
+
+// IsCustomerHeader reports whether customers can open accounts under a header
+func IsCustomerHeader(header HeaderType) bool {
+	for _, known := range CUSTOMER_HEADERS {
+		if header == known {
+			return true
+		}
+	}
+	return false
+}
+
+// AccountRole identifies the purpose a system account serves
+type AccountRole string
+
+const (
+	// FundingRole is the role of the account that funds new customer accounts
+	FundingRole AccountRole = "FUNDING"
+
+	// SuspenseRole is the role of the account that holds unreconciled value
+	SuspenseRole AccountRole = "SUSPENSE"
+
+	// FeeRole is the role of the account that collects fees
+	FeeRole AccountRole = "FEE"
 )
 
 // Account denotes a virtual storage and tracker for value (money/loyalty points)
@@ -70,8 +140,10 @@ type Account struct {
 	Number          string
 	Currency        CurrencyType `gorm:"default: KSH"`
 	BalanceType     BalanceType
-	Header          HeaderType `gorm:"default: DEPOSIT"`
-	IsSystemAccount bool       `gorm:"default: false"`
+	Header          HeaderType  `gorm:"default: DEPOSIT"`
+	IsSystemAccount bool        `gorm:"default: false"`
+	GLCode          string      `gorm:"column:gl_code;index"`
+	Role            AccountRole `gorm:"index"`
 }
 
 // BeforeCreate ensures an account number is generated
@@ -85,10 +157,19 @@ func (acc *Account) BeforeCreate(tx *gorm.DB) (err error) {
 	return
 }
 
+// ComputeBalance derives an account's balance from its debit and credit totals
+func (acc Account) ComputeBalance(debits, credits decimal.Decimal) decimal.Decimal {
+	if acc.BalanceType == Credit {
+		return debits.Sub(credits)
+	}
+	return credits.Sub(debits)
+}
+
 // Transaction maintains the movement/transfer of money from one account to another
 type Transaction struct {
 	AbstractBase `gorm:"embedded"`
-	Description  string `json:"description"`
+	Description  string  `json:"description"`
+	ReversalOf   *string `json:"reversal_of,omitempty" gorm:"index"`
 }
 
 // AccountEntry hold information about the value, accounts involved in the transfer of money
@@ -106,7 +187,7 @@ type AccountEntry struct {
 // ValidateDebitAmount validates that a debit amount is non-negative
 func (ae AccountEntry) ValidateDebitAmount() error {
 	if ae.DebitAmount.LessThanOrEqual(decimal.Zero) {
-		return fmt.Errorf("you can not debit a 0 or negative amount")
+		return NewValidationError("invalid_amount", "you can not debit a 0 or negative amount")
 	}
 
 	return nil
@@ -115,7 +196,7 @@ func (ae AccountEntry) ValidateDebitAmount() error {
 // ValidateDebitAmount validates that a credit amount is non-negative
 func (ae AccountEntry) ValidateCreditAmount() error {
 	if ae.CreditAmount.LessThanOrEqual(decimal.Zero) {
-		return fmt.Errorf("you can not credit a 0 or negative amount")
+		return NewValidationError("invalid_amount", "you can not credit a 0 or negative amount")
 	}
 
 	return nil
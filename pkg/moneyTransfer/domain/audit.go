@@ -0,0 +1,67 @@
+package domain
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// AuditOutcome records whether an audited call succeeded
+type AuditOutcome string
+
+const (
+	// AuditSuccess is a call that responded with a 2xx or 3xx status
+	AuditSuccess AuditOutcome = "SUCCESS"
+
+	// AuditFailure is a call that responded with a 4xx or 5xx status
+	AuditFailure AuditOutcome = "FAILURE"
+)
+
+// OutcomeOf classifies a response status code
+func OutcomeOf(statusCode int) AuditOutcome {
+	if statusCode >= 400 {
+		return AuditFailure
+	}
+	return AuditSuccess
+}
+
+// AuditRecord is an append only record of a state changing API call.
+// Each record's hash covers the previous record's hash, so editing or removing a record breaks the chain
+type AuditRecord struct {
+	Sequence     int64        `json:"sequence" gorm:"primaryKey;autoIncrement:false"`
+	Actor        string       `json:"actor" gorm:"index"`
+	ClientIP     string       `json:"client_ip"`
+	Method       string       `json:"method"`
+	Route        string       `json:"route" gorm:"index"`
+	Path         string       `json:"path"`
+	PayloadHash  string       `json:"payload_hash"`
+	ResourceIDs  string       `json:"resource_ids"`
+	StatusCode   int          `json:"status_code"`
+	Outcome      AuditOutcome `json:"outcome" gorm:"index"`
+	OccurredAt   time.Time    `json:"occurred_at" gorm:"index"`
+	PreviousHash string       `json:"previous_hash"`
+	Hash         string       `json:"hash" gorm:"uniqueIndex"`
+}
+
+// ComputeHash hashes the record's content together with the hash of the record before it
+func (a AuditRecord) ComputeHash() string {
+	fields := []string{
+		strconv.FormatInt(a.Sequence, 10),
+		a.Actor,
+		a.ClientIP,
+		a.Method,
+		a.Route,
+		a.Path,
+		a.PayloadHash,
+		a.ResourceIDs,
+		strconv.Itoa(a.StatusCode),
+		string(a.Outcome),
+		a.OccurredAt.UTC().Format(time.RFC3339Nano),
+		a.PreviousHash,
+	}
+
+	sum := sha256.Sum256([]byte(strings.Join(fields, "\x1f")))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,43 @@
+package domain
+
+import "time"
+
+// AccountCategory classifies a general ledger code within the chart of accounts
+type AccountCategory string
+
+const (
+	// Asset is the category of resources owned by the business
+	Asset AccountCategory = "ASSET"
+
+	// Liability is the category of obligations owed by the business e.g customer deposits
+	Liability AccountCategory = "LIABILITY"
+
+	// Income is the category of value earned by the business e.g fees
+	Income AccountCategory = "INCOME"
+
+	// Expense is the category of value spent by the business
+	Expense AccountCategory = "EXPENSE"
+
+	// Equity is the category of the owners' stake in the business
+	Equity AccountCategory = "EQUITY"
+)
+
+// IsValid checks that a category is one of the known chart of accounts categories
+func (c AccountCategory) IsValid() bool {
+	switch c {
+	case Asset, Liability, Income, Expense, Equity:
+		return true
+	}
+	return false
+}
+
+// LedgerCode is a general ledger (GL) code in the chart of accounts.
+// Codes form a tree through their parent code e.g 2100 (Customer deposits) under 2000 (Liabilities)
+type LedgerCode struct {
+	Code       string          `json:"code" gorm:"primaryKey"`
+	Name       string          `json:"name"`
+	Category   AccountCategory `json:"category"`
+	ParentCode *string         `json:"parent_code,omitempty" gorm:"index"`
+	CreatedAt  *time.Time      `json:"created_at,omitempty"`
+	UpdatedAt  *time.Time      `json:"updated_at,omitempty"`
+}
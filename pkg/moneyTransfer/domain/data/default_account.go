@@ -1,23 +1,159 @@
 package data
 
-import "github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/domain"
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"os"
 
-var SYSTEM_CASH_ACCOUNT = "ddff1ec2-edb2-4d8e-90f0-115766cace6b"
+	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/domain"
+	"github.com/google/uuid"
+)
 
-// SystemAccounts creates system related control accounts
-func SystemAccounts() []*domain.Account {
-	return []*domain.Account{
-		{
+//go:embed chart_of_accounts.json
+var defaultChartOfAccounts []byte
+
+// SystemAccountConfig describes a system account seeded for a given currency
+type SystemAccountConfig struct {
+	UUID        string              `json:"uuid"`
+	Name        string              `json:"name"`
+	Number      string              `json:"number"`
+	Currency    domain.CurrencyType `json:"currency"`
+	Header      domain.HeaderType   `json:"header"`
+	BalanceType domain.BalanceType  `json:"balance_type"`
+	Role        domain.AccountRole  `json:"role"`
+}
+
+// ChartOfAccounts holds the general ledger codes, the GL code each account header
+// is booked under and the system accounts to seed per currency
+type ChartOfAccounts struct {
+	LedgerCodes    []*domain.LedgerCode         `json:"ledger_codes"`
+	Headers        map[domain.HeaderType]string `json:"headers"`
+	SystemAccounts []SystemAccountConfig        `json:"system_accounts"`
+}
+
+// DefaultChartOfAccounts loads the bundled chart of accounts
+func DefaultChartOfAccounts() (*ChartOfAccounts, error) {
+	return ParseChartOfAccounts(defaultChartOfAccounts)
+}
+
+// LoadChartOfAccounts loads the chart of accounts in a file, or the bundled one when no file is given
+func LoadChartOfAccounts(path string) (*ChartOfAccounts, error) {
+	if path == "" {
+		return DefaultChartOfAccounts()
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read chart of accounts file %s: %v", path, err)
+	}
+	return ParseChartOfAccounts(content)
+}
+
+// ParseChartOfAccounts decodes and validates a JSON chart of accounts
+func ParseChartOfAccounts(content []byte) (*ChartOfAccounts, error) {
+	var coa ChartOfAccounts
+	if err := json.Unmarshal(content, &coa); err != nil {
+		return nil, fmt.Errorf("unable to decode chart of accounts: %v", err)
+	}
+
+	if err := coa.Validate(); err != nil {
+		return nil, err
+	}
+
+	return &coa, nil
+}
+
+// Validate ensures GL codes form a valid tree and every system account maps to a known GL code
+func (coa ChartOfAccounts) Validate() error {
+	codes := map[string]*domain.LedgerCode{}
+	for _, code := range coa.LedgerCodes {
+		if code.Code == "" {
+			return fmt.Errorf("chart of accounts has a GL code without a code")
+		}
+		if _, ok := codes[code.Code]; ok {
+			return fmt.Errorf("GL code %s is defined more than once", code.Code)
+		}
+		if !code.Category.IsValid() {
+			return fmt.Errorf("GL code %s has an unknown category %q", code.Code, code.Category)
+		}
+		if code.ParentCode != nil {
+			// Parents are declared before their children so that seeding can insert in order
+			parent, ok := codes[*code.ParentCode]
+			if !ok {
+				return fmt.Errorf("GL code %s refers to undeclared parent %s", code.Code, *code.ParentCode)
+			}
+			if parent.Category != code.Category {
+				return fmt.Errorf("GL code %s is not in the same category as its parent %s", code.Code, parent.Code)
+			}
+		}
+		codes[code.Code] = code
+	}
+
+	for header, code := range coa.Headers {
+		if _, ok := codes[code]; !ok {
+			return fmt.Errorf("header %s is mapped to unknown GL code %s", header, code)
+		}
+	}
+
+	roles := map[string]bool{}
+	for _, account := range coa.SystemAccounts {
+		if account.Number == "" {
+			return fmt.Errorf("system account %q has no account number", account.Name)
+		}
+		if account.Currency == "" || account.Role == "" {
+			return fmt.Errorf("system account %s should have a currency and a role", account.Number)
+		}
+		if account.BalanceType != domain.Debit && account.BalanceType != domain.Credit {
+			return fmt.Errorf("system account %s has an unknown balance type %q", account.Number, account.BalanceType)
+		}
+		if _, ok := coa.Headers[account.Header]; !ok {
+			return fmt.Errorf("system account %s has header %s without a GL code", account.Number, account.Header)
+		}
+		key := fmt.Sprintf("%s/%s", account.Currency, account.Role)
+		if roles[key] {
+			return fmt.Errorf("more than one %s system account defined for %s", account.Role, account.Currency)
+		}
+		roles[key] = true
+	}
+
+	return nil
+}
+
+// LedgerCodeForHeader returns the GL code accounts with the given header are booked under
+func (coa ChartOfAccounts) LedgerCodeForHeader(header domain.HeaderType) (string, error) {
+	code, ok := coa.Headers[header]
+	if !ok {
+		return "", domain.NewValidationError("invalid_header", "no GL code is configured for %s accounts", header)
+	}
+	return code, nil
+}
+
+// SeedAccounts builds the system related control accounts the chart declares, as they are seeded
+func (coa ChartOfAccounts) SeedAccounts() []*domain.Account {
+	var accounts []*domain.Account
+	for _, config := range coa.SystemAccounts {
+		accountID := config.UUID
+		if accountID == "" {
+			// A stable ID keeps seeding idempotent across restarts
+			accountID = uuid.NewSHA1(uuid.NameSpaceOID, []byte(config.Number)).String()
+		}
+
+		accounts = append(accounts, &domain.Account{
 			AbstractBase: domain.AbstractBase{
-				UUID: SYSTEM_CASH_ACCOUNT,
+				UUID: accountID,
 			},
-			Name:            "Default System's Payment Method account",
-			Description:     "Default System's Payment Method account",
-			Number:          "AC-0123456789",
-			Currency:        domain.Kenyan,
-			BalanceType:     domain.Debit,
-			Header:          domain.Cash,
+			Name:            config.Name,
+			Description:     config.Name,
+			Number:          config.Number,
+			Currency:        config.Currency,
+			BalanceType:     config.BalanceType,
+			Header:          config.Header,
 			IsSystemAccount: true,
-		},
+			GLCode:          coa.Headers[config.Header],
+			Role:            config.Role,
+		})
 	}
+
+	return accounts
 }
@@ -0,0 +1,151 @@
+package data_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/domain"
+	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/domain/data"
+)
+
+func TestParseChartOfAccounts(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		wantErr bool
+	}{
+		{
+			name: "happy case",
+			content: `{
+				"ledger_codes": [
+					{"code": "1000", "name": "Assets", "category": "ASSET"},
+					{"code": "1100", "name": "Cash", "category": "ASSET", "parent_code": "1000"}
+				],
+				"headers": {"CASH": "1100"},
+				"system_accounts": [
+					{"name": "Cash", "number": "AC-1", "currency": "KSH", "header": "CASH", "balance_type": "DR", "role": "FUNDING"}
+				]
+			}`,
+			wantErr: false,
+		},
+		{
+			name:    "sad case - invalid json",
+			content: `{`,
+			wantErr: true,
+		},
+		{
+			name: "sad case - unknown category",
+			content: `{
+				"ledger_codes": [{"code": "1000", "name": "Assets", "category": "CASH"}]
+			}`,
+			wantErr: true,
+		},
+		{
+			name: "sad case - undeclared parent",
+			content: `{
+				"ledger_codes": [{"code": "1100", "name": "Cash", "category": "ASSET", "parent_code": "1000"}]
+			}`,
+			wantErr: true,
+		},
+		{
+			name: "sad case - header mapped to unknown code",
+			content: `{
+				"ledger_codes": [{"code": "1000", "name": "Assets", "category": "ASSET"}],
+				"headers": {"CASH": "1100"}
+			}`,
+			wantErr: true,
+		},
+		{
+			name: "sad case - duplicate role per currency",
+			content: `{
+				"ledger_codes": [{"code": "1100", "name": "Cash", "category": "ASSET"}],
+				"headers": {"CASH": "1100"},
+				"system_accounts": [
+					{"name": "Cash", "number": "AC-1", "currency": "KSH", "header": "CASH", "balance_type": "DR", "role": "FUNDING"},
+					{"name": "Cash", "number": "AC-2", "currency": "KSH", "header": "CASH", "balance_type": "DR", "role": "FUNDING"}
+				]
+			}`,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			coa, err := data.ParseChartOfAccounts([]byte(tt.content))
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ParseChartOfAccounts() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr && coa != nil {
+				t.Errorf("did not expect a chart of accounts")
+				return
+			}
+		})
+	}
+}
+
+func TestChartOfAccounts_SeedAccounts(t *testing.T) {
+	coa, err := data.DefaultChartOfAccounts()
+	if err != nil {
+		t.Errorf("unable to load the default chart of accounts: %v", err)
+		return
+	}
+
+	accounts := coa.SeedAccounts()
+	funding := map[domain.CurrencyType]bool{}
+	for _, account := range accounts {
+		if account.UUID == "" || account.GLCode == "" {
+			t.Errorf("expected system account %s to have an ID and a GL code", account.Number)
+			return
+		}
+		if account.Role == domain.FundingRole {
+			funding[account.Currency] = true
+		}
+	}
+
+	for _, currency := range []domain.CurrencyType{domain.Kenyan, domain.Ugandan} {
+		if !funding[currency] {
+			t.Errorf("expected a funding system account for %s", currency)
+		}
+	}
+}
+
+func TestLoadChartOfAccounts(t *testing.T) {
+	custom := filepath.Join(t.TempDir(), "chart.json")
+	content := `{
+		"ledger_codes": [{"code": "1000", "name": "Assets", "category": "ASSET"}],
+		"headers": {"CASH": "1000"},
+		"system_accounts": [
+			{"name": "Cash", "number": "AC-1", "currency": "KSH", "header": "CASH", "balance_type": "DR", "role": "FUNDING"}
+		]
+	}`
+	if err := os.WriteFile(custom, []byte(content), 0o600); err != nil {
+		t.Fatalf("unable to write the chart of accounts: %v", err)
+	}
+
+	tests := []struct {
+		name         string
+		path         string
+		wantAccounts int // 0 for any number of system accounts
+		wantErr      bool
+	}{
+		{name: "happy case - bundled chart"},
+		{name: "happy case - custom chart", path: custom, wantAccounts: 1},
+		{name: "sad case - missing file", path: filepath.Join(t.TempDir(), "missing.json"), wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			coa, err := data.LoadChartOfAccounts(tt.path)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("LoadChartOfAccounts() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			accounts := coa.SeedAccounts()
+			if len(accounts) == 0 || tt.wantAccounts > 0 && len(accounts) != tt.wantAccounts {
+				t.Errorf("expected %d system accounts, got %d", tt.wantAccounts, len(accounts))
+			}
+		})
+	}
+}
@@ -0,0 +1,122 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrorKind classifies a failure so that every presentation layer reports it consistently
+type ErrorKind string
+
+const (
+	// NotFound is a failure to find a requested resource
+	NotFound ErrorKind = "NOT_FOUND"
+
+	// InsufficientFunds is a debit the account's balance can not cover
+	InsufficientFunds ErrorKind = "INSUFFICIENT_FUNDS"
+
+	// Validation is a request that breaks a business rule
+	Validation ErrorKind = "VALIDATION"
+
+	// Conflict is a request that clashes with the current state of a resource
+	Conflict ErrorKind = "CONFLICT"
+
+	// Forbidden is a request the caller is not allowed to make
+	Forbidden ErrorKind = "FORBIDDEN"
+
+	// Held is a transfer held for review rather than posted, it is posted if an analyst releases it
+	Held ErrorKind = "HELD"
+
+	// Internal is an unexpected failure, its details are not shown to clients
+	Internal ErrorKind = "INTERNAL"
+)
+
+// Error is a typed failure with a stable machine readable code and a message safe to show to clients.
+// The underlying cause, which may hold database details, is kept for logs only
+type Error struct {
+	Kind    ErrorKind
+	Code    string
+	Message string
+	Err     error
+
+	// HeldTransferID names the transfer a Held error reports held for review
+	HeldTransferID string
+}
+
+func (e *Error) Error() string {
+	if e.Err != nil {
+		return fmt.Sprintf("%s: %v", e.Message, e.Err)
+	}
+	return e.Message
+}
+
+// Unwrap exposes the underlying cause to errors.Is and errors.As
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// NewError creates a typed error with a formatted client message
+func NewError(kind ErrorKind, code string, format string, args ...interface{}) *Error {
+	return &Error{
+		Kind:    kind,
+		Code:    code,
+		Message: fmt.Sprintf(format, args...),
+	}
+}
+
+// NewNotFoundError reports a missing resource
+func NewNotFoundError(code string, format string, args ...interface{}) *Error {
+	return NewError(NotFound, code, format, args...)
+}
+
+// NewValidationError reports a request breaking a business rule
+func NewValidationError(code string, format string, args ...interface{}) *Error {
+	return NewError(Validation, code, format, args...)
+}
+
+// NewConflictError reports a request clashing with a resource's state
+func NewConflictError(code string, format string, args ...interface{}) *Error {
+	return NewError(Conflict, code, format, args...)
+}
+
+// NewForbiddenError reports a request the caller may not make
+func NewForbiddenError(code string, format string, args ...interface{}) *Error {
+	return NewError(Forbidden, code, format, args...)
+}
+
+// NewHeldError reports a transfer held for review, naming the held transfer it can be followed with
+func NewHeldError(heldTransferID string) *Error {
+	err := NewError(Held, "transfer_held", "the transfer is held for review as %s", heldTransferID)
+	err.HeldTransferID = heldTransferID
+	return err
+}
+
+// NewInsufficientFundsError reports a debit the balance can not cover
+func NewInsufficientFundsError(format string, args ...interface{}) *Error {
+	return NewError(InsufficientFunds, "insufficient_funds", format, args...)
+}
+
+// NewInternalError wraps an unexpected failure, describing what was being done when it happened
+func NewInternalError(err error, format string, args ...interface{}) *Error {
+	return &Error{
+		Kind:    Internal,
+		Code:    "internal_error",
+		Message: fmt.Sprintf(format, args...),
+		Err:     err,
+	}
+}
+
+// AsError returns the typed error in an error's chain, treating untyped errors as internal
+func AsError(err error) *Error {
+	var typed *Error
+	if errors.As(err, &typed) {
+		return typed
+	}
+	return NewInternalError(err, "internal server error")
+}
+
+// IsKind reports whether an error is a typed error of a given kind
+func IsKind(err error, kind ErrorKind) bool {
+	var typed *Error
+	return errors.As(err, &typed) && typed.Kind == kind
+}
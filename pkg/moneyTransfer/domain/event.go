@@ -0,0 +1,118 @@
+package domain
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+// EventType names a domain event published to other systems
+type EventType string
+
+const (
+	// AccountOpened is published when a customer account is created
+	AccountOpened EventType = "account.opened"
+
+	// TransferPosted is published when a transaction is posted to the ledger
+	TransferPosted EventType = "transfer.posted"
+
+	// TransferReversed is published when a posted transaction is reversed
+	TransferReversed EventType = "transfer.reversed"
+)
+
+// AccountOpenedPayload describes a newly opened account
+type AccountOpenedPayload struct {
+	AccountID string       `json:"account_id"`
+	Number    string       `json:"number"`
+	Name      string       `json:"name"`
+	Currency  CurrencyType `json:"currency"`
+	Header    HeaderType   `json:"header"`
+}
+
+// TransferPayload describes the movement of money recorded by a transaction.
+// The source account is credited and the destination account debited
+type TransferPayload struct {
+	TransactionID         string          `json:"transaction_id"`
+	ReversedTransactionID string          `json:"reversed_transaction_id,omitempty"`
+	Description           string          `json:"description"`
+	SourceAccountID       string          `json:"source_account_id"`
+	DestinationAccountID  string          `json:"destination_account_id"`
+	Amount                decimal.Decimal `json:"amount"`
+	Currency              CurrencyType    `json:"currency"`
+	EffectiveDate         time.Time       `json:"effective_date"`
+}
+
+// OutboxEvent is a domain event stored with the change that raised it until it is published.
+// Sequence orders events in the order they were committed
+type OutboxEvent struct {
+	Sequence    int64      `json:"sequence" gorm:"primaryKey;autoIncrement"`
+	EventID     string     `json:"event_id" gorm:"uniqueIndex"`
+	Type        EventType  `json:"type" gorm:"index"`
+	AggregateID string     `json:"aggregate_id" gorm:"index"`
+	Payload     string     `json:"-" gorm:"type:jsonb"`
+	OccurredAt  time.Time  `json:"occurred_at"`
+	PublishedAt *time.Time `json:"published_at,omitempty" gorm:"index"`
+	Attempts    int        `json:"attempts"`
+	LastError   string     `json:"last_error,omitempty"`
+}
+
+// NewOutboxEvent prepares an event about an aggregate (account or transaction) for the outbox
+func NewOutboxEvent(eventType EventType, aggregateID string, payload interface{}) (*OutboxEvent, error) {
+	content, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("unable to encode %s event: %v", eventType, err)
+	}
+
+	return &OutboxEvent{
+		EventID:     uuid.New().String(),
+		Type:        eventType,
+		AggregateID: aggregateID,
+		Payload:     string(content),
+		OccurredAt:  time.Now(),
+	}, nil
+}
+
+// EventEnvelope is the representation of an event delivered to sinks
+type EventEnvelope struct {
+	ID          string          `json:"id"`
+	Sequence    int64           `json:"sequence"`
+	Type        EventType       `json:"type"`
+	AggregateID string          `json:"aggregate_id"`
+	OccurredAt  time.Time       `json:"occurred_at"`
+	Data        json.RawMessage `json:"data"`
+}
+
+// AccountIDs lists the accounts an event is about, as named by its payload
+func (e EventEnvelope) AccountIDs() []string {
+	var accounts struct {
+		AccountID            string `json:"account_id"`
+		SourceAccountID      string `json:"source_account_id"`
+		DestinationAccountID string `json:"destination_account_id"`
+	}
+	if err := json.Unmarshal(e.Data, &accounts); err != nil {
+		return nil
+	}
+
+	var accountIDs []string
+	for _, accountID := range []string{accounts.AccountID, accounts.SourceAccountID, accounts.DestinationAccountID} {
+		if accountID != "" {
+			accountIDs = append(accountIDs, accountID)
+		}
+	}
+	return accountIDs
+}
+
+// Envelope wraps an event's payload with its identifiers for delivery
+func (e OutboxEvent) Envelope() EventEnvelope {
+	return EventEnvelope{
+		ID:          e.EventID,
+		Sequence:    e.Sequence,
+		Type:        e.Type,
+		AggregateID: e.AggregateID,
+		OccurredAt:  e.OccurredAt,
+		Data:        json.RawMessage(e.Payload),
+	}
+}
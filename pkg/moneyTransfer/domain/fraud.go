@@ -0,0 +1,64 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// FraudAction is what screening a transfer decides to do with it
+type FraudAction string
+
+const (
+	// Allow posts the transfer, a rule allowing what it flags only reports it
+	Allow FraudAction = "ALLOW"
+
+	// Hold keeps the transfer in the review queue until an analyst releases or rejects it
+	Hold FraudAction = "HOLD"
+
+	// Block rejects the transfer outright
+	Block FraudAction = "BLOCK"
+)
+
+// FRAUD_ACTIONS lists the actions from the least to the most severe
+var FRAUD_ACTIONS = []FraudAction{Allow, Hold, Block}
+
+// Severity ranks an action, the most severe action of the rules flagging a transfer is taken
+func (a FraudAction) Severity() int {
+	for severity, action := range FRAUD_ACTIONS {
+		if a == action {
+			return severity
+		}
+	}
+	return 0
+}
+
+// HoldStatus tracks a held transfer through its review
+type HoldStatus string
+
+const (
+	// HoldPending is a transfer waiting for an analyst's review
+	HoldPending HoldStatus = "PENDING"
+
+	// HoldReleased is a transfer an analyst released, it has been posted
+	HoldReleased HoldStatus = "RELEASED"
+
+	// HoldRejected is a transfer an analyst rejected, it is never posted
+	HoldRejected HoldStatus = "REJECTED"
+)
+
+// HeldTransfer is a transfer fraud screening held for review instead of posting it
+type HeldTransfer struct {
+	AbstractBase         `gorm:"embedded"`
+	SourceAccountID      string          `json:"source_account_id" gorm:"index"`
+	DestinationAccountID string          `json:"destination_account_id"`
+	Amount               decimal.Decimal `json:"amount"`
+	Currency             CurrencyType    `json:"currency"`
+	Rules                string          `json:"rules"`
+	Reasons              string          `json:"reasons"`
+	Status               HoldStatus      `json:"status" gorm:"index"`
+	ReviewedBy           string          `json:"reviewed_by,omitempty"`
+	ReviewedAt           *time.Time      `json:"reviewed_at,omitempty"`
+	ReviewReason         string          `json:"review_reason,omitempty"`
+	TransactionID        *string         `json:"transaction_id,omitempty"`
+}
@@ -0,0 +1,21 @@
+package domain
+
+// PaymentMessage is a payment initiation message received from an initiating party. A party's message
+// identifications are unique, a message is executed once however many times it is sent
+type PaymentMessage struct {
+	AbstractBase `gorm:"embedded"`
+	Initiator    string `json:"initiator"`
+	MessageID    string `json:"message_id"`
+}
+
+// PaymentInstruction is a credit transfer instruction executed for an initiating party. A party's end to end
+// identifications are unique, an instruction repeated in a later message is not executed again
+type PaymentInstruction struct {
+	AbstractBase         `gorm:"embedded"`
+	Initiator            string  `json:"initiator"`
+	MessageID            string  `json:"message_id"`
+	PaymentInformationID string  `json:"payment_information_id"`
+	EndToEndID           string  `json:"end_to_end_id"`
+	TransactionID        *string `json:"transaction_id,omitempty"`
+	HeldTransferID       *string `json:"held_transfer_id,omitempty"`
+}
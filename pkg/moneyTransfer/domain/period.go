@@ -0,0 +1,105 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// PeriodType specifies the length of an accounting period
+type PeriodType string
+
+const (
+	// Day is a single business day, closed by the end of day (EOD) process
+	Day PeriodType = "DAY"
+
+	// Month is a calendar month, closed at month end
+	Month PeriodType = "MONTH"
+)
+
+// PeriodStatus defines whether entries can be posted into an accounting period
+type PeriodStatus string
+
+const (
+	// PeriodOpen allows entries to be posted into the period
+	PeriodOpen PeriodStatus = "OPEN"
+
+	// PeriodClosed rejects entries posted into the period
+	PeriodClosed PeriodStatus = "CLOSED"
+
+	// PeriodReopenRequested is a closed period awaiting an admin's approval to reopen
+	PeriodReopenRequested PeriodStatus = "REOPEN_REQUESTED"
+)
+
+// IsLocked reports whether entries are rejected in a period with this status
+func (s PeriodStatus) IsLocked() bool {
+	return s == PeriodClosed || s == PeriodReopenRequested
+}
+
+// PeriodAction names an event in the life of an accounting period
+type PeriodAction string
+
+const (
+	// PeriodClosedAction records a period being closed
+	PeriodClosedAction PeriodAction = "CLOSED"
+
+	// ReopenRequestedAction records a request to reopen a closed period
+	ReopenRequestedAction PeriodAction = "REOPEN_REQUESTED"
+
+	// ReopenApprovedAction records an admin approving a reopen request
+	ReopenApprovedAction PeriodAction = "REOPEN_APPROVED"
+
+	// ReopenRejectedAction records an admin rejecting a reopen request
+	ReopenRejectedAction PeriodAction = "REOPEN_REJECTED"
+)
+
+// AccountingPeriod is a span of time [StartDate, EndDate) that can be closed against postings
+type AccountingPeriod struct {
+	AbstractBase      `gorm:"embedded"`
+	Type              PeriodType   `json:"type"`
+	StartDate         time.Time    `json:"start_date" gorm:"index"`
+	EndDate           time.Time    `json:"end_date" gorm:"index"`
+	Status            PeriodStatus `json:"status" gorm:"index"`
+	ClosedBy          string       `json:"closed_by"`
+	ClosedAt          *time.Time   `json:"closed_at"`
+	ReopenRequestedBy string       `json:"reopen_requested_by,omitempty"`
+	ReopenReason      string       `json:"reopen_reason,omitempty"`
+}
+
+// Contains reports whether a date falls within the period
+func (p AccountingPeriod) Contains(date time.Time) bool {
+	return !date.Before(p.StartDate) && date.Before(p.EndDate)
+}
+
+// BalanceSnapshot is an account's balance captured when a period is closed
+type BalanceSnapshot struct {
+	AbstractBase `gorm:"embedded"`
+	PeriodID     string          `json:"period_id" gorm:"index"`
+	AccountID    string          `json:"account_id" gorm:"index"`
+	Currency     CurrencyType    `json:"currency"`
+	TotalDebit   decimal.Decimal `json:"total_debit"`
+	TotalCredit  decimal.Decimal `json:"total_credit"`
+	Balance      decimal.Decimal `json:"balance"`
+}
+
+// PeriodAuditEntry records who did what to an accounting period
+type PeriodAuditEntry struct {
+	AbstractBase `gorm:"embedded"`
+	PeriodID     string       `json:"period_id" gorm:"index"`
+	Action       PeriodAction `json:"action"`
+	Actor        string       `json:"actor"`
+	Reason       string       `json:"reason,omitempty"`
+}
+
+// PeriodBounds returns the start and (exclusive) end of the period of a given type containing a date, the
+// period's days starting at midnight in the business time zone
+func PeriodBounds(periodType PeriodType, date time.Time, location *time.Location) (time.Time, time.Time) {
+	year, month, day := date.In(location).Date()
+	if periodType == Month {
+		start := time.Date(year, month, 1, 0, 0, 0, 0, location)
+		return start, start.AddDate(0, 1, 0)
+	}
+
+	start := time.Date(year, month, day, 0, 0, 0, 0, location)
+	return start, start.AddDate(0, 0, 1)
+}
@@ -0,0 +1,97 @@
+package domain
+
+import (
+	"net"
+	"strings"
+	"time"
+)
+
+// DeliveryStatus tracks a webhook delivery through its retries
+type DeliveryStatus string
+
+const (
+	// DeliveryPending is a delivery waiting for its next attempt
+	DeliveryPending DeliveryStatus = "PENDING"
+
+	// DeliveryDelivered is a delivery the subscriber acknowledged with a 2xx response
+	DeliveryDelivered DeliveryStatus = "DELIVERED"
+
+	// DeliveryDead is a delivery that exhausted its retries and sits in the dead letter list
+	DeliveryDead DeliveryStatus = "DEAD"
+)
+
+// sharedAddressSpace is the carrier-grade NAT range, cloud metadata services are also served from it
+var sharedAddressSpace = &net.IPNet{IP: net.IPv4(100, 64, 0, 0), Mask: net.CIDRMask(10, 32)}
+
+// IsPublicAddress reports whether webhooks may be delivered to an address. Loopback, private, link-local, shared
+// and unspecified addresses belong to the network the service runs in, along with the cloud metadata services
+func IsPublicAddress(ip net.IP) bool {
+	return !ip.IsLoopback() &&
+		!ip.IsPrivate() &&
+		!ip.IsLinkLocalUnicast() &&
+		!ip.IsLinkLocalMulticast() &&
+		!ip.IsInterfaceLocalMulticast() &&
+		!ip.IsMulticast() &&
+		!ip.IsUnspecified() &&
+		!sharedAddressSpace.Contains(ip)
+}
+
+// IsKnownEventType reports whether an event type is published by the service
+func IsKnownEventType(eventType EventType) bool {
+	switch eventType {
+	case AccountOpened, TransferPosted, TransferReversed:
+		return true
+	}
+	return false
+}
+
+// WebhookSubscription is a partner endpoint notified of the event types it subscribed to, about the accounts it
+// names. A subscription naming no accounts is notified about every account
+type WebhookSubscription struct {
+	AbstractBase `gorm:"embedded"`
+	Owner        string `json:"owner" gorm:"index"`
+	URL          string `json:"url"`
+	EventTypes   string `json:"event_types"`
+	AccountIDs   string `json:"account_ids"`
+	Secret       string `json:"-"`
+}
+
+// Subscribes reports whether the subscription wants events of a type
+func (s WebhookSubscription) Subscribes(eventType EventType) bool {
+	for _, subscribed := range strings.Split(s.EventTypes, ",") {
+		if EventType(subscribed) == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+// Covers reports whether the subscription wants the events about any of the accounts
+func (s WebhookSubscription) Covers(accountIDs []string) bool {
+	if s.AccountIDs == "" {
+		return true
+	}
+	for _, covered := range strings.Split(s.AccountIDs, ",") {
+		for _, accountID := range accountIDs {
+			if covered == accountID {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// WebhookDelivery is an event to be posted to a subscription's endpoint
+type WebhookDelivery struct {
+	AbstractBase   `gorm:"embedded"`
+	SubscriptionID string         `json:"subscription_id" gorm:"uniqueIndex:idx_webhook_delivery_event"`
+	EventID        string         `json:"event_id" gorm:"uniqueIndex:idx_webhook_delivery_event"`
+	EventType      EventType      `json:"event_type"`
+	Payload        string         `json:"-" gorm:"type:jsonb"`
+	Status         DeliveryStatus `json:"status" gorm:"index"`
+	Attempts       int            `json:"attempts"`
+	NextAttemptAt  time.Time      `json:"next_attempt_at" gorm:"index"`
+	LastStatusCode int            `json:"last_status_code,omitempty"`
+	LastError      string         `json:"last_error,omitempty"`
+	DeliveredAt    *time.Time     `json:"delivered_at,omitempty"`
+}
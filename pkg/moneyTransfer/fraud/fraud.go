@@ -0,0 +1,122 @@
+// Package fraud screens transfers with rules before they are posted, deciding whether each is allowed, held for an
+// analyst's review or blocked
+package fraud
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/application"
+	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/domain"
+	"github.com/shopspring/decimal"
+)
+
+// History looks back on the transfers posted out of an account
+type History interface {
+	TransfersFrom(ctx context.Context, accountID string, since time.Time) ([]*application.TransferRecord, error)
+}
+
+// Transfer is a transfer about to be posted
+type Transfer struct {
+	Source      *application.AccountInformationOutput
+	Destination *application.AccountInformationOutput
+	Amount      decimal.Decimal
+	At          time.Time
+}
+
+// Finding is a rule flagging a transfer, with the action the rule takes on what it flags
+type Finding struct {
+	Rule   string
+	Action domain.FraudAction
+	Reason string
+}
+
+// Rule flags the transfers that look fraudulent, returning no finding for the others
+type Rule interface {
+	Name() string
+	Check(ctx context.Context, transfer Transfer, history History) (*Finding, error)
+}
+
+// flag reports a rule flagging a transfer
+func flag(rule Rule, action domain.FraudAction, format string, args ...interface{}) *Finding {
+	return &Finding{
+		Rule:   rule.Name(),
+		Action: action,
+		Reason: fmt.Sprintf(format, args...),
+	}
+}
+
+// Verdict is the most severe action taken by the rules that flagged a transfer, a transfer nothing flagged is allowed
+type Verdict struct {
+	Action   domain.FraudAction
+	Findings []Finding
+}
+
+// Rules names the rules that flagged the transfer, separated by commas
+func (v Verdict) Rules() string {
+	rules := make([]string, 0, len(v.Findings))
+	for _, finding := range v.Findings {
+		rules = append(rules, finding.Rule)
+	}
+	return strings.Join(rules, ",")
+}
+
+// Reasons explains why each rule flagged the transfer
+func (v Verdict) Reasons() string {
+	reasons := make([]string, 0, len(v.Findings))
+	for _, finding := range v.Findings {
+		reasons = append(reasons, finding.Reason)
+	}
+	return strings.Join(reasons, "; ")
+}
+
+// Engine screens transfers with its rules
+type Engine struct {
+	History History
+	Rules   []Rule
+}
+
+// CheckPreconditions ensures the rules can look back on the transfers made
+func (e Engine) CheckPreconditions() {
+	if len(e.Rules) > 0 && e.History == nil {
+		log.Panic("fraud engine did not initialize the transfer history")
+	}
+}
+
+// NewEngine initializes an engine screening transfers with rules, an engine without rules allows every transfer
+func NewEngine(history History, rules ...Rule) *Engine {
+	e := &Engine{
+		History: history,
+		Rules:   rules,
+	}
+	e.CheckPreconditions()
+	return e
+}
+
+// Screen checks a transfer with every rule. Transfers out of system accounts, such as the deposits funding new
+// accounts, are not screened
+func (e Engine) Screen(ctx context.Context, transfer Transfer) (Verdict, error) {
+	verdict := Verdict{Action: domain.Allow}
+	if transfer.Source == nil || transfer.Destination == nil || transfer.Source.IsSystemAccount {
+		return verdict, nil
+	}
+
+	for _, rule := range e.Rules {
+		finding, err := rule.Check(ctx, transfer, e.History)
+		if err != nil {
+			return Verdict{}, fmt.Errorf("unable to screen the transfer with the %s rule: %v", rule.Name(), err)
+		}
+		if finding == nil {
+			continue
+		}
+
+		verdict.Findings = append(verdict.Findings, *finding)
+		if finding.Action.Severity() > verdict.Action.Severity() {
+			verdict.Action = finding.Action
+		}
+	}
+	return verdict, nil
+}
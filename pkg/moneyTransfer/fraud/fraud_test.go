@@ -0,0 +1,265 @@
+package fraud_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/application"
+	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/domain"
+	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/fraud"
+	"github.com/shopspring/decimal"
+)
+
+// history serves the transfers out of each account, keyed by account ID
+type history map[string][]*application.TransferRecord
+
+func (h history) TransfersFrom(ctx context.Context, accountID string, since time.Time) ([]*application.TransferRecord, error) {
+	var transfers []*application.TransferRecord
+	for _, transfer := range h[accountID] {
+		if !transfer.PostedAt.Before(since) {
+			transfers = append(transfers, transfer)
+		}
+	}
+	return transfers, nil
+}
+
+type unavailableHistory struct{}
+
+func (unavailableHistory) TransfersFrom(context.Context, string, time.Time) ([]*application.TransferRecord, error) {
+	return nil, errors.New("connection refused")
+}
+
+// staticRule flags every transfer with its action
+type staticRule struct {
+	name   string
+	action domain.FraudAction
+}
+
+func (r staticRule) Name() string {
+	return r.name
+}
+
+func (r staticRule) Check(ctx context.Context, transfer fraud.Transfer, history fraud.History) (*fraud.Finding, error) {
+	return &fraud.Finding{Rule: r.name, Action: r.action, Reason: r.name + " flagged it"}, nil
+}
+
+var now = time.Date(2023, 8, 1, 12, 0, 0, 0, time.UTC)
+
+func account(id string, opened time.Duration) *application.AccountInformationOutput {
+	created := now.Add(-opened)
+	return &application.AccountInformationOutput{UUID: id, Number: "AC-" + id, Currency: domain.Kenyan, CreatedAt: &created}
+}
+
+func transfer(amount int64) fraud.Transfer {
+	return fraud.Transfer{
+		Source:      account("source", 365*24*time.Hour),
+		Destination: account("destination", 365*24*time.Hour),
+		Amount:      decimal.NewFromInt(amount),
+		At:          now,
+	}
+}
+
+func TestEngine_Screen(t *testing.T) {
+	systemTransfer := transfer(100)
+	systemTransfer.Source.IsSystemAccount = true
+
+	tests := []struct {
+		name      string
+		rules     []fraud.Rule
+		transfer  fraud.Transfer
+		want      domain.FraudAction
+		wantRules string
+	}{
+		{
+			name:     "nothing flagged",
+			transfer: transfer(100),
+			want:     domain.Allow,
+		},
+		{
+			name:      "flagged and allowed",
+			rules:     []fraud.Rule{staticRule{"watch", domain.Allow}},
+			transfer:  transfer(100),
+			want:      domain.Allow,
+			wantRules: "watch",
+		},
+		{
+			name:      "the most severe action is taken",
+			rules:     []fraud.Rule{staticRule{"hold", domain.Hold}, staticRule{"block", domain.Block}, staticRule{"watch", domain.Allow}},
+			transfer:  transfer(100),
+			want:      domain.Block,
+			wantRules: "hold,block,watch",
+		},
+		{
+			name:     "system accounts are not screened",
+			rules:    []fraud.Rule{staticRule{"block", domain.Block}},
+			transfer: systemTransfer,
+			want:     domain.Allow,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			verdict, err := fraud.NewEngine(history{}, tt.rules...).Screen(context.Background(), tt.transfer)
+			if err != nil {
+				t.Fatalf("Screen() error = %v", err)
+			}
+			if verdict.Action != tt.want || verdict.Rules() != tt.wantRules {
+				t.Errorf("Screen() = %v by %q, want %v by %q", verdict.Action, verdict.Rules(), tt.want, tt.wantRules)
+			}
+		})
+	}
+}
+
+func TestEngine_UnavailableHistory(t *testing.T) {
+	engine := fraud.NewEngine(unavailableHistory{}, fraud.FanOutRule{Action: domain.Hold, Window: time.Hour, Destinations: 2})
+	if _, err := engine.Screen(context.Background(), transfer(100)); err == nil {
+		t.Errorf("expected the transfer not to be screened without its history")
+	}
+}
+
+func TestNewAccountRule(t *testing.T) {
+	rule := fraud.NewAccountRule{
+		Action:  domain.Hold,
+		MaxAge:  72 * time.Hour,
+		Amounts: fraud.Amounts{domain.Kenyan: decimal.NewFromInt(1000)},
+	}
+	newSource := transfer(1000)
+	newSource.Source = account("source", time.Hour)
+	newDestination := transfer(1000)
+	newDestination.Destination = account("destination", 71*time.Hour)
+	smallAmount := transfer(999)
+	smallAmount.Source = account("source", time.Hour)
+	otherCurrency := transfer(1000)
+	otherCurrency.Source = account("source", time.Hour)
+	otherCurrency.Source.Currency = domain.Ugandan
+
+	tests := []struct {
+		name     string
+		transfer fraud.Transfer
+		want     bool
+	}{
+		{name: "new source account", transfer: newSource, want: true},
+		{name: "new destination account", transfer: newDestination, want: true},
+		{name: "established accounts", transfer: transfer(1000)},
+		{name: "below the amount", transfer: smallAmount},
+		{name: "currency without an amount", transfer: otherCurrency},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			finding, err := rule.Check(context.Background(), tt.transfer, history{})
+			if err != nil {
+				t.Fatalf("Check() error = %v", err)
+			}
+			if (finding != nil) != tt.want {
+				t.Errorf("Check() = %+v, want flagged %v", finding, tt.want)
+			}
+		})
+	}
+}
+
+func TestFanOutRule(t *testing.T) {
+	rule := fraud.FanOutRule{Action: domain.Hold, Window: time.Hour, Destinations: 3}
+	sent := func(destination string, ago time.Duration) *application.TransferRecord {
+		return &application.TransferRecord{DestinationAccountID: destination, Amount: decimal.NewFromInt(10), PostedAt: now.Add(-ago)}
+	}
+
+	tests := []struct {
+		name    string
+		history history
+		want    bool
+	}{
+		{
+			name:    "third destination within the window",
+			history: history{"source": {sent("a", 50*time.Minute), sent("b", time.Minute)}},
+			want:    true,
+		},
+		{
+			name:    "repeated destinations",
+			history: history{"source": {sent("a", 50*time.Minute), sent("a", time.Minute), sent("destination", time.Minute)}},
+		},
+		{
+			name:    "destinations outside the window",
+			history: history{"source": {sent("a", 2*time.Hour), sent("b", time.Minute)}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			finding, err := rule.Check(context.Background(), transfer(10), tt.history)
+			if err != nil {
+				t.Fatalf("Check() error = %v", err)
+			}
+			if (finding != nil) != tt.want {
+				t.Errorf("Check() = %+v, want flagged %v", finding, tt.want)
+			}
+		})
+	}
+}
+
+func TestRoundTripRule(t *testing.T) {
+	rule := fraud.RoundTripRule{Action: domain.Block, Window: time.Hour}
+	sent := func(destination string, ago time.Duration) *application.TransferRecord {
+		return &application.TransferRecord{DestinationAccountID: destination, Amount: decimal.NewFromInt(10), PostedAt: now.Add(-ago)}
+	}
+
+	tests := []struct {
+		name    string
+		history history
+		want    bool
+	}{
+		{name: "money sent back", history: history{"destination": {sent("source", 10*time.Minute)}}, want: true},
+		{name: "money sent back long ago", history: history{"destination": {sent("source", 2*time.Hour)}}},
+		{name: "destination sent money elsewhere", history: history{"destination": {sent("other", 10*time.Minute)}}},
+		{name: "source sent money before", history: history{"source": {sent("destination", 10*time.Minute)}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			finding, err := rule.Check(context.Background(), transfer(10), tt.history)
+			if err != nil {
+				t.Fatalf("Check() error = %v", err)
+			}
+			if (finding != nil) != tt.want {
+				t.Errorf("Check() = %+v, want flagged %v", finding, tt.want)
+			}
+			if finding != nil && finding.Action != domain.Block {
+				t.Errorf("expected the rule's action, got %v", finding.Action)
+			}
+		})
+	}
+}
+
+func TestUnusualHoursRule(t *testing.T) {
+	nairobi := time.FixedZone("EAT", 3*60*60)
+	at := func(hour int) fraud.Transfer {
+		tt := transfer(500)
+		tt.At = time.Date(2023, 8, 1, hour, 30, 0, 0, nairobi).UTC()
+		return tt
+	}
+	amounts := fraud.Amounts{domain.Kenyan: decimal.NewFromInt(500)}
+
+	tests := []struct {
+		name     string
+		from, to int
+		transfer fraud.Transfer
+		want     bool
+	}{
+		{name: "within the hours", from: 0, to: 5, transfer: at(2), want: true},
+		{name: "at the end of the hours", from: 0, to: 5, transfer: at(5)},
+		{name: "outside the hours", from: 0, to: 5, transfer: at(14)},
+		{name: "before midnight, wrapping around it", from: 22, to: 5, transfer: at(23), want: true},
+		{name: "after midnight, wrapping around it", from: 22, to: 5, transfer: at(1), want: true},
+		{name: "outside hours wrapping around midnight", from: 22, to: 5, transfer: at(12)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule := fraud.UnusualHoursRule{Action: domain.Hold, From: tt.from, To: tt.to, Location: nairobi, Amounts: amounts}
+			finding, err := rule.Check(context.Background(), tt.transfer, history{})
+			if err != nil {
+				t.Fatalf("Check() error = %v", err)
+			}
+			if (finding != nil) != tt.want {
+				t.Errorf("Check() = %+v, want flagged %v", finding, tt.want)
+			}
+		})
+	}
+}
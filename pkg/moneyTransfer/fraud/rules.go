@@ -0,0 +1,163 @@
+package fraud
+
+import (
+	"context"
+	"time"
+
+	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/application"
+	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/domain"
+	"github.com/shopspring/decimal"
+)
+
+// Names of the built-in rules, as findings and held transfers report them
+var (
+	RULE_NEW_ACCOUNT   = "new_account"
+	RULE_FAN_OUT       = "fan_out"
+	RULE_ROUND_TRIP    = "round_trip"
+	RULE_UNUSUAL_HOURS = "unusual_hours"
+)
+
+// Amounts are the smallest amount, per currency, a rule screens. Transfers in other currencies are not screened
+type Amounts map[domain.CurrencyType]decimal.Decimal
+
+// covers reports whether an amount is large enough to be screened
+func (a Amounts) covers(currency domain.CurrencyType, amount decimal.Decimal) bool {
+	threshold, ok := a[currency]
+	return ok && amount.GreaterThanOrEqual(threshold)
+}
+
+// NewAccountRule flags large transfers into or out of an account opened less than MaxAge ago
+type NewAccountRule struct {
+	Action  domain.FraudAction
+	MaxAge  time.Duration
+	Amounts Amounts
+}
+
+// Name names the rule
+func (r NewAccountRule) Name() string {
+	return RULE_NEW_ACCOUNT
+}
+
+// Check flags the transfer when either account is new
+func (r NewAccountRule) Check(ctx context.Context, transfer Transfer, history History) (*Finding, error) {
+	if !r.Amounts.covers(transfer.Source.Currency, transfer.Amount) {
+		return nil, nil
+	}
+
+	for _, account := range []*application.AccountInformationOutput{transfer.Source, transfer.Destination} {
+		if account.IsSystemAccount || account.CreatedAt == nil {
+			continue
+		}
+		if age := transfer.At.Sub(*account.CreatedAt); age < r.MaxAge {
+			return flag(r, r.Action, "%v %s moved with account %s, opened %v ago",
+				transfer.Amount,
+				transfer.Source.Currency,
+				account.Number,
+				age.Round(time.Minute),
+			), nil
+		}
+	}
+	return nil, nil
+}
+
+// FanOutRule flags a transfer making its source account send money to Destinations different accounts within Window
+type FanOutRule struct {
+	Action       domain.FraudAction
+	Window       time.Duration
+	Destinations int
+}
+
+// Name names the rule
+func (r FanOutRule) Name() string {
+	return RULE_FAN_OUT
+}
+
+// Check counts the accounts the source sent money to within the window, the transfer's destination included
+func (r FanOutRule) Check(ctx context.Context, transfer Transfer, history History) (*Finding, error) {
+	recent, err := history.TransfersFrom(ctx, transfer.Source.UUID, transfer.At.Add(-r.Window))
+	if err != nil {
+		return nil, err
+	}
+
+	destinations := map[string]bool{transfer.Destination.UUID: true}
+	for _, record := range recent {
+		destinations[record.DestinationAccountID] = true
+	}
+	if len(destinations) < r.Destinations {
+		return nil, nil
+	}
+	return flag(r, r.Action, "account %s sent money to %d accounts within %v",
+		transfer.Source.Number,
+		len(destinations),
+		r.Window,
+	), nil
+}
+
+// RoundTripRule flags a transfer sending money back to an account it came from within Window
+type RoundTripRule struct {
+	Action domain.FraudAction
+	Window time.Duration
+}
+
+// Name names the rule
+func (r RoundTripRule) Name() string {
+	return RULE_ROUND_TRIP
+}
+
+// Check looks for transfers from the destination to the source within the window
+func (r RoundTripRule) Check(ctx context.Context, transfer Transfer, history History) (*Finding, error) {
+	recent, err := history.TransfersFrom(ctx, transfer.Destination.UUID, transfer.At.Add(-r.Window))
+	if err != nil {
+		return nil, err
+	}
+
+	for _, record := range recent {
+		if record.DestinationAccountID == transfer.Source.UUID {
+			return flag(r, r.Action, "account %s received %v from account %s %v before sending money back",
+				transfer.Source.Number,
+				record.Amount,
+				transfer.Destination.Number,
+				transfer.At.Sub(record.PostedAt).Round(time.Minute),
+			), nil
+		}
+	}
+	return nil, nil
+}
+
+// UnusualHoursRule flags large transfers made from the hour From up to the hour To, in Location. The hours may wrap
+// around midnight, such as from 22 to 5
+type UnusualHoursRule struct {
+	Action   domain.FraudAction
+	From     int
+	To       int
+	Location *time.Location
+	Amounts  Amounts
+}
+
+// Name names the rule
+func (r UnusualHoursRule) Name() string {
+	return RULE_UNUSUAL_HOURS
+}
+
+// Check flags the transfer when it is made within the unusual hours
+func (r UnusualHoursRule) Check(ctx context.Context, transfer Transfer, history History) (*Finding, error) {
+	if !r.Amounts.covers(transfer.Source.Currency, transfer.Amount) {
+		return nil, nil
+	}
+
+	location := r.Location
+	if location == nil {
+		location = time.UTC
+	}
+	at := transfer.At.In(location)
+	hour := at.Hour()
+
+	unusual := r.From <= hour && hour < r.To
+	if r.From > r.To {
+		unusual = hour >= r.From || hour < r.To
+	}
+	if !unusual {
+		return nil, nil
+	}
+	return flag(r, r.Action, "%v %s moved at %s", transfer.Amount, transfer.Source.Currency, at.Format("15:04 MST")), nil
+}
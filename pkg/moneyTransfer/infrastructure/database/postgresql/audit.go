@@ -0,0 +1,88 @@
+package postgresql
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/application"
+	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/domain"
+	"gorm.io/gorm"
+)
+
+// AUDIT_LOCK_KEY is the advisory lock serializing appends so every record chains onto the latest one
+var AUDIT_LOCK_KEY = 2023100101
+
+// AppendAudit chains a record onto the latest audit record and stores it
+func (p PostgreSQL) AppendAudit(ctx context.Context, record *domain.AuditRecord) (*domain.AuditRecord, error) {
+	if record == nil {
+		return nil, domain.NewValidationError("missing_audit_record", "missing audit record information")
+	}
+
+	if err := p.ORM.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := advisoryLock(tx, "pg_advisory_xact_lock", AUDIT_LOCK_KEY); err != nil {
+			return fmt.Errorf("unable to lock the audit log: %v", err)
+		}
+
+		var latest []*domain.AuditRecord
+		if err := tx.Order("sequence DESC").Limit(1).Find(&latest).Error; err != nil {
+			return fmt.Errorf("unable to get the latest audit record: %v", err)
+		}
+
+		record.Sequence = 1
+		record.PreviousHash = ""
+		if len(latest) > 0 {
+			record.Sequence = latest[0].Sequence + 1
+			record.PreviousHash = latest[0].Hash
+		}
+		// Stored timestamps have microsecond precision, hash what will be read back
+		record.OccurredAt = record.OccurredAt.Truncate(time.Microsecond)
+		record.Hash = record.ComputeHash()
+
+		if err := tx.Create(record).Error; err != nil {
+			return fmt.Errorf("unable to append audit record: %v", err)
+		}
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	return record, nil
+}
+
+// AuditRecords retrieves audit records matching a filter, newest first
+func (p PostgreSQL) AuditRecords(ctx context.Context, filter application.AuditFilter) ([]*domain.AuditRecord, error) {
+	query := p.ORM.WithContext(ctx).Model(&domain.AuditRecord{})
+	if filter.Actor != "" {
+		query = query.Where("actor = ?", filter.Actor)
+	}
+	if filter.Route != "" {
+		query = query.Where("route = ?", filter.Route)
+	}
+	if filter.Resource != "" {
+		query = query.Where("? = ANY(string_to_array(resource_ids, ','))", filter.Resource)
+	}
+	if filter.From != nil {
+		query = query.Where("occurred_at >= ?", *filter.From)
+	}
+	if filter.To != nil {
+		query = query.Where("occurred_at <= ?", *filter.To)
+	}
+
+	var records []*domain.AuditRecord
+	if err := query.Order("sequence DESC").Limit(filter.Limit).Find(&records).Error; err != nil {
+		return nil, fmt.Errorf("unable to get audit records: %v", err)
+	}
+
+	return records, nil
+}
+
+// AuditChain retrieves audit records in chain order, starting after a sequence number
+func (p PostgreSQL) AuditChain(ctx context.Context, afterSequence int64, limit int) ([]*domain.AuditRecord, error) {
+	var records []*domain.AuditRecord
+	if err := p.ORM.WithContext(ctx).Where("sequence > ?", afterSequence).Order("sequence").Limit(limit).Find(&records).Error; err != nil {
+		return nil, fmt.Errorf("unable to get audit records: %v", err)
+	}
+
+	return records, nil
+}
@@ -0,0 +1,46 @@
+package postgresql_test
+
+import (
+	"testing"
+
+	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/config"
+	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/infrastructure/database/postgresql"
+)
+
+func TestDSN(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  config.Database
+		want string
+	}{
+		{
+			name: "configured connection",
+			cfg: config.Database{
+				Host:     "db.internal",
+				Port:     "5432",
+				User:     "ledger",
+				Password: "s3cret",
+				Name:     "ledger",
+				SSLMode:  "verify-full",
+				TimeZone: "Africa/Nairobi",
+			},
+			want: `host='db.internal' port='5432' user='ledger' password='s3cret' dbname='ledger' sslmode='verify-full' TimeZone='Africa/Nairobi'`,
+		},
+		{
+			name: "values quoted",
+			cfg: config.Database{
+				Host:        "localhost",
+				Password:    `it's a \ pass`,
+				SSLRootCert: "/etc/ssl/root ca.pem",
+			},
+			want: `host='localhost' password='it\'s a \\ pass' sslrootcert='/etc/ssl/root ca.pem'`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := postgresql.DSN(tt.cfg); got != tt.want {
+				t.Errorf("DSN() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,156 @@
+package postgresql
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/application"
+	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/domain"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// TransfersFrom lists the transfers out of an account posted since a time, oldest first. Reversals are left out,
+// they return money rather than move it
+func (p PostgreSQL) TransfersFrom(ctx context.Context, accountID string, since time.Time) ([]*application.TransferRecord, error) {
+	var transfers []*application.TransferRecord
+	if err := p.ORM.WithContext(ctx).Raw(`SELECT credit.transaction_id,
+			debit.account_id AS destination_account_id,
+			credit.credit_amount::numeric AS amount,
+			credit.created_at AS posted_at
+		FROM account_entries credit
+		JOIN account_entries debit ON debit.transaction_id = credit.transaction_id AND debit.uuid <> credit.uuid
+		JOIN transactions ON transactions.uuid = credit.transaction_id
+		WHERE credit.account_id = ?
+			AND credit.created_at >= ?
+			AND credit.credit_amount::numeric > 0
+			AND transactions.reversal_of IS NULL
+			AND credit.deleted_at IS NULL
+			AND debit.deleted_at IS NULL
+		ORDER BY credit.created_at`, accountID, since).Scan(&transfers).Error; err != nil {
+		return nil, fmt.Errorf("unable to get the transfers out of account %s: %v", accountID, err)
+	}
+
+	return transfers, nil
+}
+
+// HoldTransfer queues a transfer for review
+func (p PostgreSQL) HoldTransfer(ctx context.Context, held *domain.HeldTransfer) (*domain.HeldTransfer, error) {
+	if held == nil {
+		return nil, domain.NewValidationError("missing_held_transfer", "missing held transfer information")
+	}
+
+	held.Status = domain.HoldPending
+	if err := p.ORM.WithContext(ctx).Create(held).Error; err != nil {
+		return nil, fmt.Errorf("unable to hold the transfer: %v", err)
+	}
+
+	return held, nil
+}
+
+// HeldTransfer retrieves a held transfer given it's ID(UUID)
+func (p PostgreSQL) HeldTransfer(ctx context.Context, heldTransferID string) (*domain.HeldTransfer, error) {
+	return heldTransfer(p.ORM.WithContext(ctx), heldTransferID)
+}
+
+func heldTransfer(db *gorm.DB, heldTransferID string) (*domain.HeldTransfer, error) {
+	var held domain.HeldTransfer
+
+	filter := domain.HeldTransfer{
+		AbstractBase: domain.AbstractBase{
+			UUID: heldTransferID,
+		},
+	}
+	if err := db.Where(&filter).First(&held).Error; err != nil {
+		return nil, lookupError(err, "held_transfer_not_found", "held transfer %s", heldTransferID)
+	}
+
+	return &held, nil
+}
+
+// HeldTransfers lists the held transfers with a status, oldest first so that the queue is reviewed in order
+func (p PostgreSQL) HeldTransfers(ctx context.Context, status domain.HoldStatus) ([]*domain.HeldTransfer, error) {
+	var held []*domain.HeldTransfer
+	if err := p.ORM.WithContext(ctx).Where("status = ?", status).Order("created_at, uuid").Find(&held).Error; err != nil {
+		return nil, fmt.Errorf("unable to list held transfers: %v", err)
+	}
+
+	return held, nil
+}
+
+// ReleaseHeldTransfer posts a pending held transfer and marks it released
+func (p PostgreSQL) ReleaseHeldTransfer(
+	ctx context.Context,
+	heldTransferID string,
+	actor string,
+	description string,
+	drEntry *domain.AccountEntry,
+	crEntry *domain.AccountEntry,
+) (*domain.HeldTransfer, error) {
+	if drEntry == nil || crEntry == nil {
+		return nil, domain.NewValidationError("missing_entry", "DR and CR entries should be provided to release a transfer")
+	}
+
+	if err := drEntry.ValidateDebitAmount(); err != nil {
+		return nil, err
+	}
+
+	if err := crEntry.ValidateCreditAmount(); err != nil {
+		return nil, err
+	}
+
+	return p.reviewHeldTransfer(ctx, heldTransferID, func(tx *gorm.DB, held *domain.HeldTransfer) error {
+		transaction, err := postTransaction(tx, description, nil, true, drEntry, crEntry)
+		if err != nil {
+			return err
+		}
+
+		held.Status = domain.HoldReleased
+		held.ReviewedBy = actor
+		held.TransactionID = &transaction.UUID
+		return nil
+	})
+}
+
+// RejectHeldTransfer marks a pending held transfer rejected, it is never posted
+func (p PostgreSQL) RejectHeldTransfer(ctx context.Context, heldTransferID string, actor string, reason string) (*domain.HeldTransfer, error) {
+	return p.reviewHeldTransfer(ctx, heldTransferID, func(tx *gorm.DB, held *domain.HeldTransfer) error {
+		held.Status = domain.HoldRejected
+		held.ReviewedBy = actor
+		held.ReviewReason = reason
+		return nil
+	})
+}
+
+// reviewHeldTransfer locks a pending held transfer while review changes it, so that it is reviewed only once
+func (p PostgreSQL) reviewHeldTransfer(ctx context.Context, heldTransferID string, review func(tx *gorm.DB, held *domain.HeldTransfer) error) (*domain.HeldTransfer, error) {
+	var reviewed *domain.HeldTransfer
+	if err := p.ORM.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		held, err := heldTransfer(tx.Clauses(clause.Locking{Strength: "UPDATE"}), heldTransferID)
+		if err != nil {
+			return err
+		}
+
+		if held.Status != domain.HoldPending {
+			return domain.NewConflictError("not_pending_review", "held transfer %s has already been reviewed, it is %s", heldTransferID, held.Status)
+		}
+
+		if err := review(tx, held); err != nil {
+			return err
+		}
+
+		reviewedAt := time.Now()
+		held.ReviewedAt = &reviewedAt
+		if err := tx.Save(held).Error; err != nil {
+			return fmt.Errorf("unable to update the held transfer: %v", err)
+		}
+
+		reviewed = held
+		return nil
+	}); err != nil {
+		return nil, fmt.Errorf("unable to commit the held transfer review: %w", err)
+	}
+
+	return reviewed, nil
+}
@@ -0,0 +1,58 @@
+package postgresql
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/domain"
+)
+
+// Ping checks that the database accepts connections
+func (p PostgreSQL) Ping(ctx context.Context) error {
+	sqlDB, err := p.ORM.DB()
+	if err != nil {
+		return err
+	}
+	return sqlDB.PingContext(ctx)
+}
+
+// VerifyMigrations checks that every known migration is applied unchanged, without waiting for the migration lock
+func (p PostgreSQL) VerifyMigrations(ctx context.Context) error {
+	migrations, err := Migrations()
+	if err != nil {
+		return err
+	}
+
+	applied, err := appliedMigrations(p.ORM.WithContext(ctx))
+	if err != nil {
+		return err
+	}
+
+	for _, state := range MigrationStates(migrations, applied) {
+		if state.Status != MIGRATION_APPLIED {
+			return fmt.Errorf("migration %04d_%s is %s", state.Version, state.Name, state.Status)
+		}
+	}
+	return nil
+}
+
+// VerifySystemAccounts checks that the system accounts of every currency have been created
+func (p PostgreSQL) VerifySystemAccounts(ctx context.Context) error {
+	accounts := p.Chart.SeedAccounts()
+	accountIDs := make([]string, 0, len(accounts))
+	for _, account := range accounts {
+		accountIDs = append(accountIDs, account.UUID)
+	}
+
+	var count int64
+	if err := p.ORM.WithContext(ctx).Model(&domain.Account{}).
+		Where("uuid IN ? AND is_system_account = ?", accountIDs, true).
+		Count(&count).Error; err != nil {
+		return err
+	}
+
+	if missing := int64(len(accountIDs)) - count; missing > 0 {
+		return fmt.Errorf("%d of the %d system accounts are missing", missing, len(accountIDs))
+	}
+	return nil
+}
@@ -0,0 +1,37 @@
+package postgresql_test
+
+import (
+	"context"
+	"testing"
+)
+
+func TestPostgreSQL_Readiness(t *testing.T) {
+	p := newTestPostgreSQL()
+	if err := p.CreateSystemAccount(context.Background()); err != nil {
+		t.Fatalf("unable to create the system accounts: %v", err)
+	}
+
+	tests := []struct {
+		name  string
+		check func(ctx context.Context) error
+	}{
+		{name: "database reachable", check: p.Ping},
+		{name: "migrations applied", check: p.VerifyMigrations},
+		{name: "system accounts created", check: p.VerifySystemAccounts},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.check(context.Background()); err != nil {
+				t.Errorf("expected the check to pass, got %v", err)
+			}
+		})
+	}
+
+	t.Run("cancelled context", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		if err := p.VerifySystemAccounts(ctx); err == nil {
+			t.Errorf("expected a check cut short to fail")
+		}
+	})
+}
@@ -0,0 +1,108 @@
+package postgresql
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/metrics"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.17.0"
+	"go.opentelemetry.io/otel/trace"
+	"gorm.io/gorm"
+)
+
+// QUERY_STARTED_KEY is the statement setting holding when a query started
+var QUERY_STARTED_KEY = "metrics:query_started"
+
+// QUERY_SPAN_KEY is the statement setting holding a query's span
+var QUERY_SPAN_KEY = "tracing:query_span"
+
+// tracer traces the database queries
+var tracer = otel.Tracer("github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/infrastructure/database/postgresql")
+
+// aroundQueries registers callbacks running before and after every kind of query the ORM runs
+func aroundQueries(db *gorm.DB, name string, before func(operation string) func(*gorm.DB), after func(operation string) func(*gorm.DB)) error {
+	callbacks := db.Callback()
+	registrations := []error{
+		callbacks.Create().Before("gorm:create").Register(name+":before_create", before("create")),
+		callbacks.Create().After("gorm:create").Register(name+":after_create", after("create")),
+		callbacks.Query().Before("gorm:query").Register(name+":before_query", before("query")),
+		callbacks.Query().After("gorm:query").Register(name+":after_query", after("query")),
+		callbacks.Update().Before("gorm:update").Register(name+":before_update", before("update")),
+		callbacks.Update().After("gorm:update").Register(name+":after_update", after("update")),
+		callbacks.Delete().Before("gorm:delete").Register(name+":before_delete", before("delete")),
+		callbacks.Delete().After("gorm:delete").Register(name+":after_delete", after("delete")),
+		callbacks.Row().Before("gorm:row").Register(name+":before_row", before("row")),
+		callbacks.Row().After("gorm:row").Register(name+":after_row", after("row")),
+		callbacks.Raw().Before("gorm:raw").Register(name+":before_raw", before("raw")),
+		callbacks.Raw().After("gorm:raw").Register(name+":after_raw", after("raw")),
+	}
+	for _, err := range registrations {
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Instrument times every query run through the ORM, labelled by operation and table
+func Instrument(db *gorm.DB, recorder metrics.QueryRecorder) error {
+	start := func(string) func(*gorm.DB) {
+		return func(tx *gorm.DB) {
+			tx.InstanceSet(QUERY_STARTED_KEY, time.Now())
+		}
+	}
+	finish := func(operation string) func(*gorm.DB) {
+		return func(tx *gorm.DB) {
+			started, ok := tx.InstanceGet(QUERY_STARTED_KEY)
+			if !ok {
+				return
+			}
+			recorder.ObserveQuery(operation, tx.Statement.Table, time.Since(started.(time.Time)))
+		}
+	}
+
+	if err := aroundQueries(db, "metrics", start, finish); err != nil {
+		return fmt.Errorf("unable to instrument the database queries: %v", err)
+	}
+	return nil
+}
+
+// Trace runs every query of the ORM in a span, a child of the span in the query's context
+func Trace(db *gorm.DB) error {
+	start := func(operation string) func(*gorm.DB) {
+		return func(tx *gorm.DB) {
+			_, span := tracer.Start(tx.Statement.Context, "gorm."+operation, trace.WithSpanKind(trace.SpanKindClient))
+			tx.InstanceSet(QUERY_SPAN_KEY, span)
+		}
+	}
+	finish := func(string) func(*gorm.DB) {
+		return func(tx *gorm.DB) {
+			value, ok := tx.InstanceGet(QUERY_SPAN_KEY)
+			if !ok {
+				return
+			}
+			span := value.(trace.Span)
+			span.SetAttributes(
+				semconv.DBSystemPostgreSQL,
+				semconv.DBSQLTable(tx.Statement.Table),
+				semconv.DBStatement(tx.Statement.SQL.String()),
+				attribute.Int64("db.rows_affected", tx.Statement.RowsAffected),
+			)
+			if tx.Error != nil && !errors.Is(tx.Error, gorm.ErrRecordNotFound) {
+				span.RecordError(tx.Error)
+				span.SetStatus(codes.Error, tx.Error.Error())
+			}
+			span.End()
+		}
+	}
+
+	if err := aroundQueries(db, "tracing", start, finish); err != nil {
+		return fmt.Errorf("unable to trace the database queries: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,79 @@
+package postgresql
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/logging"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	gormlogger "gorm.io/gorm/logger"
+)
+
+// SLOW_QUERY_THRESHOLD is how long a query may run before it is logged as slow
+var SLOW_QUERY_THRESHOLD = 200 * time.Millisecond
+
+// QueryLogger logs the ORM's queries with the request they were run for: failures as errors, slow queries as
+// warnings and the others at debug level. Query parameters are left out as they may carry secrets
+type QueryLogger struct {
+	Logger        *zap.Logger
+	SlowThreshold time.Duration
+}
+
+// NewQueryLogger initializes the logger of the ORM's queries
+func NewQueryLogger(logger *zap.Logger) *QueryLogger {
+	return &QueryLogger{
+		Logger:        logger.Named("gorm"),
+		SlowThreshold: SLOW_QUERY_THRESHOLD,
+	}
+}
+
+// LogMode is a no-op, the level is the structured logger's
+func (l *QueryLogger) LogMode(gormlogger.LogLevel) gormlogger.Interface {
+	return l
+}
+
+// Info logs an informational message of the ORM
+func (l *QueryLogger) Info(ctx context.Context, msg string, data ...interface{}) {
+	logging.For(ctx, l.Logger).Info(fmt.Sprintf(msg, data...))
+}
+
+// Warn logs a warning of the ORM
+func (l *QueryLogger) Warn(ctx context.Context, msg string, data ...interface{}) {
+	logging.For(ctx, l.Logger).Warn(fmt.Sprintf(msg, data...))
+}
+
+// Error logs an error of the ORM
+func (l *QueryLogger) Error(ctx context.Context, msg string, data ...interface{}) {
+	logging.For(ctx, l.Logger).Error(fmt.Sprintf(msg, data...))
+}
+
+// Trace logs a query once it has run. Missing records are expected and not logged as failures
+func (l *QueryLogger) Trace(ctx context.Context, begin time.Time, query func() (string, int64), err error) {
+	elapsed := time.Since(begin)
+	logger := logging.For(ctx, l.Logger)
+
+	level, msg := zap.DebugLevel, "query"
+	var fields []zap.Field
+	switch {
+	case err != nil && !errors.Is(err, gorm.ErrRecordNotFound):
+		level, msg = zap.ErrorLevel, "query failed"
+		fields = append(fields, zap.Error(err))
+	case l.SlowThreshold > 0 && elapsed > l.SlowThreshold:
+		level, msg = zap.WarnLevel, "slow query"
+	}
+
+	checked := logger.Check(level, msg)
+	if checked == nil {
+		return
+	}
+	sql, rows := query()
+	checked.Write(append(fields, zap.String("sql", sql), zap.Int64("rows", rows), zap.Duration("elapsed", elapsed))...)
+}
+
+// ParamsFilter keeps the parameters out of the logged statements
+func (l *QueryLogger) ParamsFilter(ctx context.Context, sql string, params ...interface{}) (string, []interface{}) {
+	return sql, nil
+}
@@ -0,0 +1,301 @@
+package postgresql
+
+import (
+	"crypto/sha256"
+	"embed"
+	"encoding/hex"
+	"fmt"
+	"io/fs"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// MIGRATIONS holds the versioned schema migrations, each as a NNNN_name.up.sql and NNNN_name.down.sql pair
+//
+//go:embed migrations/*.sql
+var MIGRATIONS embed.FS
+
+// MIGRATION_LOCK_KEY is the advisory lock keeping concurrent runners from migrating the same database
+var MIGRATION_LOCK_KEY = 2023100201
+
+// MIGRATION_FILE_PATTERN matches a migration's file name, capturing its version, name and direction
+var MIGRATION_FILE_PATTERN = regexp.MustCompile(`^(\d+)_(\w+)\.(up|down)\.sql$`)
+
+// Migration status values
+var (
+	MIGRATION_APPLIED  = "applied"
+	MIGRATION_PENDING  = "pending"
+	MIGRATION_MODIFIED = "modified"
+	MIGRATION_MISSING  = "missing"
+)
+
+// schemaMigrationsTable records the migrations applied to a database
+var schemaMigrationsTable = `CREATE TABLE IF NOT EXISTS schema_migrations (
+	version bigint PRIMARY KEY,
+	name text NOT NULL,
+	checksum text NOT NULL,
+	applied_at timestamptz NOT NULL
+)`
+
+// Migration is a versioned schema change and the statements reverting it
+type Migration struct {
+	Version int
+	Name    string
+	Up      string
+	Down    string
+}
+
+// Checksum fingerprints the migration's statements so that edits to an applied migration are caught
+func (m Migration) Checksum() string {
+	sum := sha256.Sum256([]byte(m.Up + "\x00" + m.Down))
+	return hex.EncodeToString(sum[:])
+}
+
+// SchemaMigration is a migration applied to the database
+type SchemaMigration struct {
+	Version   int `gorm:"primaryKey;autoIncrement:false"`
+	Name      string
+	Checksum  string
+	AppliedAt time.Time
+}
+
+// MigrationState reports whether a migration is applied, pending, modified since it was applied or missing from the code
+type MigrationState struct {
+	Version   int        `json:"version"`
+	Name      string     `json:"name"`
+	Status    string     `json:"status"`
+	AppliedAt *time.Time `json:"applied_at,omitempty"`
+}
+
+// LoadMigrations reads the migrations in a directory ordered by version, every version needs both an up and a down file
+func LoadMigrations(fsys fs.FS) ([]Migration, error) {
+	files, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return nil, fmt.Errorf("unable to list the migrations: %v", err)
+	}
+
+	byVersion := map[int]*Migration{}
+	for _, file := range files {
+		if file.IsDir() || path.Ext(file.Name()) != ".sql" {
+			continue
+		}
+
+		match := MIGRATION_FILE_PATTERN.FindStringSubmatch(file.Name())
+		if match == nil {
+			return nil, fmt.Errorf("migration %s should be named NNNN_name.up.sql or NNNN_name.down.sql", file.Name())
+		}
+		version, err := strconv.Atoi(match[1])
+		if err != nil || version == 0 {
+			return nil, fmt.Errorf("migration %s should have a positive version", file.Name())
+		}
+
+		content, err := fs.ReadFile(fsys, file.Name())
+		if err != nil {
+			return nil, fmt.Errorf("unable to read migration %s: %v", file.Name(), err)
+		}
+
+		migration, ok := byVersion[version]
+		if !ok {
+			migration = &Migration{Version: version, Name: match[2]}
+			byVersion[version] = migration
+		}
+		if migration.Name != match[2] {
+			return nil, fmt.Errorf("migration version %d is used by both %s and %s", version, migration.Name, match[2])
+		}
+
+		statements := &migration.Up
+		if match[3] == "down" {
+			statements = &migration.Down
+		}
+		if *statements != "" {
+			return nil, fmt.Errorf("migration %s is duplicated", file.Name())
+		}
+		*statements = string(content)
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, migration := range byVersion {
+		if migration.Up == "" || migration.Down == "" {
+			return nil, fmt.Errorf("migration %04d_%s needs both an up and a down file", migration.Version, migration.Name)
+		}
+		migrations = append(migrations, *migration)
+	}
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].Version < migrations[j].Version })
+
+	return migrations, nil
+}
+
+// Migrations loads the service's schema migrations
+func Migrations() ([]Migration, error) {
+	dir, err := fs.Sub(MIGRATIONS, "migrations")
+	if err != nil {
+		return nil, fmt.Errorf("unable to open the migrations: %v", err)
+	}
+	return LoadMigrations(dir)
+}
+
+// withMigrationLock runs fn in a transaction holding the migration lock, with the schema_migrations table in place
+func withMigrationLock(db *gorm.DB, fn func(tx *gorm.DB, migrations []Migration, applied map[int]SchemaMigration) error) error {
+	migrations, err := Migrations()
+	if err != nil {
+		return err
+	}
+
+	return db.Transaction(func(tx *gorm.DB) error {
+		if err := advisoryLock(tx, "pg_advisory_xact_lock", MIGRATION_LOCK_KEY); err != nil {
+			return fmt.Errorf("unable to lock the database migrations: %v", err)
+		}
+		if err := tx.Exec(schemaMigrationsTable).Error; err != nil {
+			return fmt.Errorf("unable to create the schema_migrations table: %v", err)
+		}
+
+		applied, err := appliedMigrations(tx)
+		if err != nil {
+			return err
+		}
+
+		return fn(tx, migrations, applied)
+	})
+}
+
+// appliedMigrations reads the schema_migrations table, keyed by version
+func appliedMigrations(db *gorm.DB) (map[int]SchemaMigration, error) {
+	var rows []SchemaMigration
+	if err := db.Order("version").Find(&rows).Error; err != nil {
+		return nil, fmt.Errorf("unable to get the applied migrations: %v", err)
+	}
+	applied := make(map[int]SchemaMigration, len(rows))
+	for _, row := range rows {
+		applied[row.Version] = row
+	}
+
+	return applied, nil
+}
+
+// verifyApplied ensures every applied migration still exists unchanged
+func verifyApplied(migrations []Migration, applied map[int]SchemaMigration) error {
+	known := make(map[int]Migration, len(migrations))
+	for _, migration := range migrations {
+		known[migration.Version] = migration
+	}
+
+	for version, row := range applied {
+		migration, ok := known[version]
+		if !ok {
+			return fmt.Errorf("migration %04d_%s is applied but missing from the code", version, row.Name)
+		}
+		if migration.Checksum() != row.Checksum {
+			return fmt.Errorf("migration %04d_%s was modified after it was applied", version, migration.Name)
+		}
+	}
+
+	return nil
+}
+
+// Migrate applies the pending migrations in order, all of them or none
+func Migrate(db *gorm.DB) error {
+	return withMigrationLock(db, func(tx *gorm.DB, migrations []Migration, applied map[int]SchemaMigration) error {
+		if err := verifyApplied(migrations, applied); err != nil {
+			return err
+		}
+
+		for _, migration := range migrations {
+			if _, ok := applied[migration.Version]; ok {
+				continue
+			}
+
+			if err := tx.Exec(migration.Up).Error; err != nil {
+				return fmt.Errorf("unable to apply migration %04d_%s: %v", migration.Version, migration.Name, err)
+			}
+			if err := tx.Create(&SchemaMigration{
+				Version:   migration.Version,
+				Name:      migration.Name,
+				Checksum:  migration.Checksum(),
+				AppliedAt: time.Now(),
+			}).Error; err != nil {
+				return fmt.Errorf("unable to record migration %04d_%s: %v", migration.Version, migration.Name, err)
+			}
+		}
+
+		return nil
+	})
+}
+
+// MigrateDown reverts the latest applied migrations, newest first
+func MigrateDown(db *gorm.DB, steps int) error {
+	if steps < 1 {
+		return fmt.Errorf("the number of migrations to revert should be at least 1")
+	}
+
+	return withMigrationLock(db, func(tx *gorm.DB, migrations []Migration, applied map[int]SchemaMigration) error {
+		if err := verifyApplied(migrations, applied); err != nil {
+			return err
+		}
+
+		for i := len(migrations) - 1; i >= 0 && steps > 0; i-- {
+			migration := migrations[i]
+			if _, ok := applied[migration.Version]; !ok {
+				continue
+			}
+
+			if err := tx.Exec(migration.Down).Error; err != nil {
+				return fmt.Errorf("unable to revert migration %04d_%s: %v", migration.Version, migration.Name, err)
+			}
+			if err := tx.Delete(&SchemaMigration{}, migration.Version).Error; err != nil {
+				return fmt.Errorf("unable to record reverting migration %04d_%s: %v", migration.Version, migration.Name, err)
+			}
+			steps--
+		}
+
+		return nil
+	})
+}
+
+// MigrationStatus lists every known or applied migration with its state
+func MigrationStatus(db *gorm.DB) ([]MigrationState, error) {
+	var states []MigrationState
+	err := withMigrationLock(db, func(tx *gorm.DB, migrations []Migration, applied map[int]SchemaMigration) error {
+		states = MigrationStates(migrations, applied)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return states, nil
+}
+
+// MigrationStates compares the known migrations with the applied ones
+func MigrationStates(migrations []Migration, applied map[int]SchemaMigration) []MigrationState {
+	states := make([]MigrationState, 0, len(migrations))
+	known := make(map[int]bool, len(migrations))
+	for _, migration := range migrations {
+		known[migration.Version] = true
+		state := MigrationState{Version: migration.Version, Name: migration.Name, Status: MIGRATION_PENDING}
+		if row, ok := applied[migration.Version]; ok {
+			appliedAt := row.AppliedAt
+			state.AppliedAt = &appliedAt
+			state.Status = MIGRATION_APPLIED
+			if row.Checksum != migration.Checksum() {
+				state.Status = MIGRATION_MODIFIED
+			}
+		}
+		states = append(states, state)
+	}
+
+	for version, row := range applied {
+		if known[version] {
+			continue
+		}
+		appliedAt := row.AppliedAt
+		states = append(states, MigrationState{Version: version, Name: row.Name, Status: MIGRATION_MISSING, AppliedAt: &appliedAt})
+	}
+	sort.Slice(states, func(i, j int) bool { return states[i].Version < states[j].Version })
+
+	return states
+}
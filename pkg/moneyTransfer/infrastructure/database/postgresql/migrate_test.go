@@ -0,0 +1,169 @@
+package postgresql_test
+
+import (
+	"strings"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/infrastructure/database/postgresql"
+)
+
+func TestLoadMigrations(t *testing.T) {
+	tests := []struct {
+		name         string
+		files        fstest.MapFS
+		wantVersions []int
+		wantErr      string
+	}{
+		{
+			name: "happy case - ordered by version",
+			files: fstest.MapFS{
+				"0002_add_index.up.sql":        {Data: []byte("CREATE INDEX idx ON t (c);")},
+				"0002_add_index.down.sql":      {Data: []byte("DROP INDEX idx;")},
+				"0001_initial_schema.up.sql":   {Data: []byte("CREATE TABLE t (c text);")},
+				"0001_initial_schema.down.sql": {Data: []byte("DROP TABLE t;")},
+				"README.md":                    {Data: []byte("not a migration")},
+			},
+			wantVersions: []int{1, 2},
+		},
+		{
+			name: "sad case - missing down migration",
+			files: fstest.MapFS{
+				"0001_initial_schema.up.sql": {Data: []byte("CREATE TABLE t (c text);")},
+			},
+			wantErr: "needs both an up and a down file",
+		},
+		{
+			name: "sad case - badly named migration",
+			files: fstest.MapFS{
+				"initial_schema.sql": {Data: []byte("CREATE TABLE t (c text);")},
+			},
+			wantErr: "should be named",
+		},
+		{
+			name: "sad case - version used twice",
+			files: fstest.MapFS{
+				"0001_initial_schema.up.sql": {Data: []byte("CREATE TABLE t (c text);")},
+				"0001_add_index.up.sql":      {Data: []byte("CREATE INDEX idx ON t (c);")},
+			},
+			wantErr: "migration version 1 is used by both",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			migrations, err := postgresql.LoadMigrations(tt.files)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("LoadMigrations() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("LoadMigrations() error = %v", err)
+			}
+
+			if len(migrations) != len(tt.wantVersions) {
+				t.Fatalf("expected %d migrations, got %d", len(tt.wantVersions), len(migrations))
+			}
+			for i, version := range tt.wantVersions {
+				if migrations[i].Version != version {
+					t.Errorf("expected migration %d to be version %d, got %d", i, version, migrations[i].Version)
+				}
+			}
+		})
+	}
+}
+
+func TestMigrations(t *testing.T) {
+	migrations, err := postgresql.Migrations()
+	if err != nil {
+		t.Fatalf("Migrations() error = %v", err)
+	}
+
+	for i, migration := range migrations {
+		if migration.Version != i+1 {
+			t.Errorf("expected migration versions to be consecutive, %s is version %d", migration.Name, migration.Version)
+		}
+	}
+}
+
+func TestMigration_Checksum(t *testing.T) {
+	migration := postgresql.Migration{Version: 1, Name: "initial_schema", Up: "CREATE TABLE t (c text);", Down: "DROP TABLE t;"}
+	if migration.Checksum() != migration.Checksum() {
+		t.Errorf("expected the checksum to be stable")
+	}
+
+	edited := migration
+	edited.Up = "CREATE TABLE t (c text, d text);"
+	if edited.Checksum() == migration.Checksum() {
+		t.Errorf("expected editing a migration to change its checksum")
+	}
+}
+
+func TestMigrationStates(t *testing.T) {
+	migrations := []postgresql.Migration{
+		{Version: 1, Name: "initial_schema", Up: "CREATE TABLE t (c text);", Down: "DROP TABLE t;"},
+		{Version: 2, Name: "add_index", Up: "CREATE INDEX idx ON t (c);", Down: "DROP INDEX idx;"},
+		{Version: 3, Name: "add_column", Up: "ALTER TABLE t ADD d text;", Down: "ALTER TABLE t DROP d;"},
+	}
+	appliedAt := time.Now()
+	applied := map[int]postgresql.SchemaMigration{
+		1: {Version: 1, Name: "initial_schema", Checksum: migrations[0].Checksum(), AppliedAt: appliedAt},
+		2: {Version: 2, Name: "add_index", Checksum: "edited", AppliedAt: appliedAt},
+		4: {Version: 4, Name: "removed", Checksum: "removed", AppliedAt: appliedAt},
+	}
+
+	states := postgresql.MigrationStates(migrations, applied)
+	want := []string{
+		postgresql.MIGRATION_APPLIED,
+		postgresql.MIGRATION_MODIFIED,
+		postgresql.MIGRATION_PENDING,
+		postgresql.MIGRATION_MISSING,
+	}
+	if len(states) != len(want) {
+		t.Fatalf("expected %d states, got %v", len(want), states)
+	}
+	for i, status := range want {
+		if states[i].Status != status {
+			t.Errorf("expected migration %d to be %s, got %s", states[i].Version, status, states[i].Status)
+		}
+	}
+	if states[2].AppliedAt != nil {
+		t.Errorf("expected a pending migration to have no applied time")
+	}
+}
+
+func TestPostgreSQL_AdoptAutoMigrateSchema(t *testing.T) {
+	p := newTestPostgreSQL()
+	migrations, err := postgresql.Migrations()
+	if err != nil {
+		t.Fatalf("Migrations() error = %v", err)
+	}
+
+	// The tables as the baseline's AutoMigrate created them, in a schema of their own that is rolled back
+	tx := p.ORM.Begin()
+	defer tx.Rollback()
+	for _, statement := range []string{
+		"CREATE SCHEMA automigrate_baseline",
+		"SET LOCAL search_path TO automigrate_baseline",
+		`CREATE TABLE accounts (uuid text, active boolean DEFAULT true, created_at timestamptz, updated_at timestamptz,
+			deleted_at timestamptz, name text, description text, number text, currency text DEFAULT 'KSH',
+			balance_type text, header text DEFAULT 'DEPOSIT', is_system_account boolean DEFAULT false, PRIMARY KEY (uuid))`,
+		`CREATE TABLE transactions (uuid text, active boolean DEFAULT true, created_at timestamptz,
+			updated_at timestamptz, deleted_at timestamptz, description text, PRIMARY KEY (uuid))`,
+		migrations[0].Up,
+	} {
+		if err := tx.Exec(statement).Error; err != nil {
+			t.Fatalf("unable to migrate the baseline schema: %v", err)
+		}
+	}
+
+	for table, columns := range map[string][]string{"accounts": {"gl_code", "role"}, "transactions": {"reversal_of"}} {
+		for _, column := range columns {
+			if !tx.Migrator().HasColumn(table, column) {
+				t.Errorf("expected %s.%s to be added to the baseline schema", table, column)
+			}
+		}
+	}
+}
@@ -0,0 +1,11 @@
+DROP TABLE IF EXISTS audit_records;
+DROP TABLE IF EXISTS webhook_deliveries;
+DROP TABLE IF EXISTS webhook_subscriptions;
+DROP TABLE IF EXISTS outbox_events;
+DROP TABLE IF EXISTS period_audit_entries;
+DROP TABLE IF EXISTS balance_snapshots;
+DROP TABLE IF EXISTS accounting_periods;
+DROP TABLE IF EXISTS account_entries;
+DROP TABLE IF EXISTS transactions;
+DROP TABLE IF EXISTS accounts;
+DROP TABLE IF EXISTS ledger_codes;
@@ -0,0 +1,199 @@
+-- The schema gorm's AutoMigrate created before migrations were versioned.
+-- Every statement is guarded so that databases created by AutoMigrate adopt it unchanged, and columns added to a table
+-- after AutoMigrate may have created it are added when missing
+
+CREATE TABLE IF NOT EXISTS ledger_codes (
+    code text,
+    name text,
+    category text,
+    parent_code text,
+    created_at timestamptz,
+    updated_at timestamptz,
+    PRIMARY KEY (code)
+);
+CREATE INDEX IF NOT EXISTS idx_ledger_codes_parent_code ON ledger_codes (parent_code);
+
+CREATE TABLE IF NOT EXISTS accounts (
+    uuid text,
+    active boolean DEFAULT true,
+    created_at timestamptz,
+    updated_at timestamptz,
+    deleted_at timestamptz,
+    name text,
+    description text,
+    number text,
+    currency text DEFAULT 'KSH',
+    balance_type text,
+    header text DEFAULT 'DEPOSIT',
+    is_system_account boolean DEFAULT false,
+    gl_code text,
+    role text,
+    PRIMARY KEY (uuid)
+);
+ALTER TABLE accounts ADD COLUMN IF NOT EXISTS gl_code text;
+ALTER TABLE accounts ADD COLUMN IF NOT EXISTS role text;
+CREATE INDEX IF NOT EXISTS idx_accounts_deleted_at ON accounts (deleted_at);
+CREATE INDEX IF NOT EXISTS idx_accounts_gl_code ON accounts (gl_code);
+CREATE INDEX IF NOT EXISTS idx_accounts_role ON accounts (role);
+
+CREATE TABLE IF NOT EXISTS transactions (
+    uuid text,
+    active boolean DEFAULT true,
+    created_at timestamptz,
+    updated_at timestamptz,
+    deleted_at timestamptz,
+    description text,
+    reversal_of text,
+    PRIMARY KEY (uuid)
+);
+ALTER TABLE transactions ADD COLUMN IF NOT EXISTS reversal_of text;
+CREATE INDEX IF NOT EXISTS idx_transactions_deleted_at ON transactions (deleted_at);
+CREATE INDEX IF NOT EXISTS idx_transactions_reversal_of ON transactions (reversal_of);
+
+CREATE TABLE IF NOT EXISTS account_entries (
+    uuid text,
+    active boolean DEFAULT true,
+    created_at timestamptz,
+    updated_at timestamptz,
+    deleted_at timestamptz,
+    debit_amount text,
+    credit_amount text,
+    effective_date timestamptz,
+    account_id text,
+    transaction_id text,
+    PRIMARY KEY (uuid),
+    CONSTRAINT fk_account_entries_account FOREIGN KEY (account_id) REFERENCES accounts (uuid),
+    CONSTRAINT fk_account_entries_transaction FOREIGN KEY (transaction_id) REFERENCES transactions (uuid)
+);
+CREATE INDEX IF NOT EXISTS idx_account_entries_deleted_at ON account_entries (deleted_at);
+
+CREATE TABLE IF NOT EXISTS accounting_periods (
+    uuid text,
+    active boolean DEFAULT true,
+    created_at timestamptz,
+    updated_at timestamptz,
+    deleted_at timestamptz,
+    type text,
+    start_date timestamptz,
+    end_date timestamptz,
+    status text,
+    closed_by text,
+    closed_at timestamptz,
+    reopen_requested_by text,
+    reopen_reason text,
+    PRIMARY KEY (uuid)
+);
+CREATE INDEX IF NOT EXISTS idx_accounting_periods_deleted_at ON accounting_periods (deleted_at);
+CREATE INDEX IF NOT EXISTS idx_accounting_periods_start_date ON accounting_periods (start_date);
+CREATE INDEX IF NOT EXISTS idx_accounting_periods_end_date ON accounting_periods (end_date);
+CREATE INDEX IF NOT EXISTS idx_accounting_periods_status ON accounting_periods (status);
+
+CREATE TABLE IF NOT EXISTS balance_snapshots (
+    uuid text,
+    active boolean DEFAULT true,
+    created_at timestamptz,
+    updated_at timestamptz,
+    deleted_at timestamptz,
+    period_id text,
+    account_id text,
+    currency text,
+    total_debit text,
+    total_credit text,
+    balance text,
+    PRIMARY KEY (uuid)
+);
+CREATE INDEX IF NOT EXISTS idx_balance_snapshots_deleted_at ON balance_snapshots (deleted_at);
+CREATE INDEX IF NOT EXISTS idx_balance_snapshots_period_id ON balance_snapshots (period_id);
+CREATE INDEX IF NOT EXISTS idx_balance_snapshots_account_id ON balance_snapshots (account_id);
+
+CREATE TABLE IF NOT EXISTS period_audit_entries (
+    uuid text,
+    active boolean DEFAULT true,
+    created_at timestamptz,
+    updated_at timestamptz,
+    deleted_at timestamptz,
+    period_id text,
+    action text,
+    actor text,
+    reason text,
+    PRIMARY KEY (uuid)
+);
+CREATE INDEX IF NOT EXISTS idx_period_audit_entries_period_id ON period_audit_entries (period_id);
+CREATE INDEX IF NOT EXISTS idx_period_audit_entries_deleted_at ON period_audit_entries (deleted_at);
+
+CREATE TABLE IF NOT EXISTS outbox_events (
+    sequence bigserial,
+    event_id text,
+    type text,
+    aggregate_id text,
+    payload jsonb,
+    occurred_at timestamptz,
+    published_at timestamptz,
+    attempts bigint,
+    last_error text,
+    PRIMARY KEY (sequence)
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_outbox_events_event_id ON outbox_events (event_id);
+CREATE INDEX IF NOT EXISTS idx_outbox_events_type ON outbox_events (type);
+CREATE INDEX IF NOT EXISTS idx_outbox_events_aggregate_id ON outbox_events (aggregate_id);
+CREATE INDEX IF NOT EXISTS idx_outbox_events_published_at ON outbox_events (published_at);
+
+CREATE TABLE IF NOT EXISTS webhook_subscriptions (
+    uuid text,
+    active boolean DEFAULT true,
+    created_at timestamptz,
+    updated_at timestamptz,
+    deleted_at timestamptz,
+    owner text,
+    url text,
+    event_types text,
+    secret text,
+    PRIMARY KEY (uuid)
+);
+CREATE INDEX IF NOT EXISTS idx_webhook_subscriptions_deleted_at ON webhook_subscriptions (deleted_at);
+CREATE INDEX IF NOT EXISTS idx_webhook_subscriptions_owner ON webhook_subscriptions (owner);
+
+CREATE TABLE IF NOT EXISTS webhook_deliveries (
+    uuid text,
+    active boolean DEFAULT true,
+    created_at timestamptz,
+    updated_at timestamptz,
+    deleted_at timestamptz,
+    subscription_id text,
+    event_id text,
+    event_type text,
+    payload jsonb,
+    status text,
+    attempts bigint,
+    next_attempt_at timestamptz,
+    last_status_code bigint,
+    last_error text,
+    delivered_at timestamptz,
+    PRIMARY KEY (uuid)
+);
+CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_deleted_at ON webhook_deliveries (deleted_at);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_webhook_delivery_event ON webhook_deliveries (subscription_id, event_id);
+CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_status ON webhook_deliveries (status);
+CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_next_attempt_at ON webhook_deliveries (next_attempt_at);
+
+CREATE TABLE IF NOT EXISTS audit_records (
+    sequence bigint,
+    actor text,
+    client_ip text,
+    method text,
+    route text,
+    path text,
+    payload_hash text,
+    resource_ids text,
+    status_code bigint,
+    outcome text,
+    occurred_at timestamptz,
+    previous_hash text,
+    hash text,
+    PRIMARY KEY (sequence)
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_audit_records_hash ON audit_records (hash);
+CREATE INDEX IF NOT EXISTS idx_audit_records_actor ON audit_records (actor);
+CREATE INDEX IF NOT EXISTS idx_audit_records_route ON audit_records (route);
+CREATE INDEX IF NOT EXISTS idx_audit_records_outcome ON audit_records (outcome);
+CREATE INDEX IF NOT EXISTS idx_audit_records_occurred_at ON audit_records (occurred_at);
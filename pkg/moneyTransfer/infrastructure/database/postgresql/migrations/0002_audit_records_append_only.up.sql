@@ -0,0 +1,15 @@
+-- Reject updates, deletes and truncation of the audit log at the database level
+
+CREATE OR REPLACE FUNCTION audit_records_append_only() RETURNS trigger AS $$
+BEGIN
+    RAISE EXCEPTION 'audit records are append only';
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS audit_records_no_change ON audit_records;
+CREATE TRIGGER audit_records_no_change BEFORE UPDATE OR DELETE ON audit_records
+    FOR EACH ROW EXECUTE FUNCTION audit_records_append_only();
+
+DROP TRIGGER IF EXISTS audit_records_no_truncate ON audit_records;
+CREATE TRIGGER audit_records_no_truncate BEFORE TRUNCATE ON audit_records
+    FOR EACH STATEMENT EXECUTE FUNCTION audit_records_append_only();
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_account_entries_account_id_created_at;
+DROP TABLE IF EXISTS held_transfers;
@@ -0,0 +1,27 @@
+-- Transfers fraud screening held for an analyst's review
+
+CREATE TABLE IF NOT EXISTS held_transfers (
+    uuid text,
+    active boolean DEFAULT true,
+    created_at timestamptz,
+    updated_at timestamptz,
+    deleted_at timestamptz,
+    source_account_id text,
+    destination_account_id text,
+    amount text,
+    currency text,
+    rules text,
+    reasons text,
+    status text,
+    reviewed_by text,
+    reviewed_at timestamptz,
+    review_reason text,
+    transaction_id text,
+    PRIMARY KEY (uuid)
+);
+CREATE INDEX IF NOT EXISTS idx_held_transfers_deleted_at ON held_transfers (deleted_at);
+CREATE INDEX IF NOT EXISTS idx_held_transfers_source_account_id ON held_transfers (source_account_id);
+CREATE INDEX IF NOT EXISTS idx_held_transfers_status ON held_transfers (status);
+
+-- Screening looks back on the transfers out of an account
+CREATE INDEX IF NOT EXISTS idx_account_entries_account_id_created_at ON account_entries (account_id, created_at);
@@ -0,0 +1,31 @@
+-- Payment initiation messages and the instructions executed from them, so that a message sent again is not executed again
+
+CREATE TABLE IF NOT EXISTS payment_messages (
+    uuid text,
+    active boolean DEFAULT true,
+    created_at timestamptz,
+    updated_at timestamptz,
+    deleted_at timestamptz,
+    initiator text,
+    message_id text,
+    PRIMARY KEY (uuid)
+);
+CREATE INDEX IF NOT EXISTS idx_payment_messages_deleted_at ON payment_messages (deleted_at);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_payment_messages_initiator_message_id ON payment_messages (initiator, message_id);
+
+CREATE TABLE IF NOT EXISTS payment_instructions (
+    uuid text,
+    active boolean DEFAULT true,
+    created_at timestamptz,
+    updated_at timestamptz,
+    deleted_at timestamptz,
+    initiator text,
+    message_id text,
+    payment_information_id text,
+    end_to_end_id text,
+    transaction_id text,
+    held_transfer_id text,
+    PRIMARY KEY (uuid)
+);
+CREATE INDEX IF NOT EXISTS idx_payment_instructions_deleted_at ON payment_instructions (deleted_at);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_payment_instructions_initiator_end_to_end_id ON payment_instructions (initiator, end_to_end_id);
@@ -0,0 +1,5 @@
+-- Webhook subscriptions name the accounts whose events they are delivered. The subscriptions made before were
+-- delivered every account's events, they are deactivated until their owners subscribe again
+
+ALTER TABLE webhook_subscriptions ADD COLUMN IF NOT EXISTS account_ids text;
+UPDATE webhook_subscriptions SET active = false WHERE account_ids IS NULL;
@@ -0,0 +1,64 @@
+package postgresql
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/domain"
+	"gorm.io/gorm"
+)
+
+// writeEvent stores a domain event in the outbox as part of the database transaction that raised it
+func writeEvent(tx *gorm.DB, eventType domain.EventType, aggregateID string, payload interface{}) error {
+	event, err := domain.NewOutboxEvent(eventType, aggregateID, payload)
+	if err != nil {
+		return err
+	}
+
+	if err := tx.Create(event).Error; err != nil {
+		return fmt.Errorf("unable to record %s event: %v", eventType, err)
+	}
+
+	return nil
+}
+
+// PendingEvents retrieves the oldest events that have not been published yet
+func (p PostgreSQL) PendingEvents(ctx context.Context, limit int) ([]*domain.OutboxEvent, error) {
+	var events []*domain.OutboxEvent
+	if err := p.ORM.WithContext(ctx).Where("published_at IS NULL").Order("sequence").Limit(limit).Find(&events).Error; err != nil {
+		return nil, fmt.Errorf("unable to get pending events: %v", err)
+	}
+
+	return events, nil
+}
+
+// MarkPublished records that an event was delivered to every sink
+func (p PostgreSQL) MarkPublished(ctx context.Context, sequence int64) error {
+	now := time.Now()
+	if err := p.ORM.WithContext(ctx).Model(&domain.OutboxEvent{}).
+		Where("sequence = ?", sequence).
+		Updates(map[string]interface{}{
+			"published_at": now,
+			"attempts":     gorm.Expr("attempts + 1"),
+			"last_error":   "",
+		}).Error; err != nil {
+		return fmt.Errorf("unable to mark event %d as published: %v", sequence, err)
+	}
+
+	return nil
+}
+
+// MarkFailed records a failed attempt to publish an event
+func (p PostgreSQL) MarkFailed(ctx context.Context, sequence int64, reason string) error {
+	if err := p.ORM.WithContext(ctx).Model(&domain.OutboxEvent{}).
+		Where("sequence = ?", sequence).
+		Updates(map[string]interface{}{
+			"attempts":   gorm.Expr("attempts + 1"),
+			"last_error": reason,
+		}).Error; err != nil {
+		return fmt.Errorf("unable to record failure of event %d: %v", sequence, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,109 @@
+package postgresql_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/domain"
+	"github.com/brianvoe/gofakeit"
+	"github.com/shopspring/decimal"
+)
+
+// eventsFor returns the outbox events recorded about an aggregate in sequence order
+func eventsFor(t *testing.T, aggregateID string) []*domain.OutboxEvent {
+	p := newTestPostgreSQL()
+
+	var events []*domain.OutboxEvent
+	if err := p.ORM.Where(&domain.OutboxEvent{AggregateID: aggregateID}).Order("sequence").Find(&events).Error; err != nil {
+		t.Fatalf("unable to get events of %s: %v", aggregateID, err)
+	}
+	return events
+}
+
+func TestPostgreSQL_OutboxEvents(t *testing.T) {
+	p := newTestPostgreSQL()
+
+	source, err := p.CreateAccount(context.Background(), &domain.Account{Name: gofakeit.Name(), BalanceType: domain.Credit})
+	if err != nil {
+		t.Errorf("unable to create test source account: %v", err)
+		return
+	}
+	destination, err := p.CreateAccount(context.Background(), &domain.Account{Name: gofakeit.Name(), BalanceType: domain.Credit})
+	if err != nil {
+		t.Errorf("unable to create test destination account: %v", err)
+		return
+	}
+
+	opened := eventsFor(t, source.UUID)
+	if len(opened) != 1 || opened[0].Type != domain.AccountOpened {
+		t.Errorf("expected an account opened event, got %v", opened)
+		return
+	}
+
+	amount := decimal.NewFromInt(25)
+	transaction, err := p.CreateTransaction(context.Background(),
+		"Outbox test transfer",
+		&domain.AccountEntry{DebitAmount: amount, AccountID: destination.UUID},
+		&domain.AccountEntry{CreditAmount: amount, AccountID: source.UUID},
+	)
+	if err != nil {
+		t.Errorf("PostgreSQL.CreateTransaction() error = %v", err)
+		return
+	}
+
+	posted := eventsFor(t, transaction.UUID)
+	if len(posted) != 1 || posted[0].Type != domain.TransferPosted {
+		t.Errorf("expected a transfer posted event, got %v", posted)
+		return
+	}
+	var payload domain.TransferPayload
+	if err := json.Unmarshal([]byte(posted[0].Payload), &payload); err != nil {
+		t.Errorf("unable to decode transfer payload: %v", err)
+		return
+	}
+	if payload.SourceAccountID != source.UUID || payload.DestinationAccountID != destination.UUID || !payload.Amount.Equal(amount) {
+		t.Errorf("unexpected transfer payload %+v", payload)
+		return
+	}
+	if posted[0].Sequence <= opened[0].Sequence {
+		t.Errorf("expected events to be sequenced in commit order")
+		return
+	}
+
+	reversal, err := p.ReverseTransaction(context.Background(), transaction.UUID, "")
+	if err != nil {
+		t.Errorf("PostgreSQL.ReverseTransaction() error = %v", err)
+		return
+	}
+	if reversal.ReversalOf == nil || *reversal.ReversalOf != transaction.UUID {
+		t.Errorf("expected the reversal to reference the reversed transaction")
+		return
+	}
+
+	reversed := eventsFor(t, reversal.UUID)
+	if len(reversed) != 1 || reversed[0].Type != domain.TransferReversed {
+		t.Errorf("expected a transfer reversed event, got %v", reversed)
+		return
+	}
+
+	if _, err := p.ReverseTransaction(context.Background(), transaction.UUID, ""); err == nil {
+		t.Errorf("expected a transaction to be reversed only once")
+		return
+	}
+
+	pending, err := p.PendingEvents(context.Background(), 1000)
+	if err != nil {
+		t.Errorf("PostgreSQL.PendingEvents() error = %v", err)
+		return
+	}
+	for _, event := range pending {
+		if err := p.MarkPublished(context.Background(), event.Sequence); err != nil {
+			t.Errorf("PostgreSQL.MarkPublished() error = %v", err)
+			return
+		}
+	}
+	if published := eventsFor(t, reversal.UUID); published[0].PublishedAt == nil {
+		t.Errorf("expected the event to be marked published")
+	}
+}
@@ -0,0 +1,68 @@
+package postgresql
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/domain"
+	"gorm.io/gorm/clause"
+)
+
+// RecordPaymentMessage records a payment initiation message as received. A message its initiator sent before
+// is refused with a conflict
+func (p PostgreSQL) RecordPaymentMessage(ctx context.Context, message *domain.PaymentMessage) (*domain.PaymentMessage, error) {
+	if message == nil || message.Initiator == "" || message.MessageID == "" {
+		return nil, domain.NewValidationError("missing_payment_message", "a payment message should name its initiator and identification")
+	}
+
+	result := p.ORM.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(message)
+	if result.Error != nil {
+		return nil, fmt.Errorf("unable to record payment message %s: %v", message.MessageID, result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return nil, domain.NewConflictError("duplicate_message", "payment initiation %s has already been received", message.MessageID)
+	}
+
+	return message, nil
+}
+
+// ClaimPaymentInstruction records an instruction before it is executed, so that it is executed once. An
+// instruction its initiator sent before is refused with a conflict
+func (p PostgreSQL) ClaimPaymentInstruction(ctx context.Context, instruction *domain.PaymentInstruction) (*domain.PaymentInstruction, error) {
+	if instruction == nil || instruction.Initiator == "" || instruction.EndToEndID == "" {
+		return nil, domain.NewValidationError("missing_payment_instruction", "a payment instruction should name its initiator and end to end identification")
+	}
+
+	result := p.ORM.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(instruction)
+	if result.Error != nil {
+		return nil, fmt.Errorf("unable to claim payment instruction %s: %v", instruction.EndToEndID, result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return nil, domain.NewConflictError("duplicate_instruction", "end to end identification %s has already been executed", instruction.EndToEndID)
+	}
+
+	return instruction, nil
+}
+
+// CompletePaymentInstruction records the transaction or held transfer a claimed instruction was executed as
+func (p PostgreSQL) CompletePaymentInstruction(ctx context.Context, instruction *domain.PaymentInstruction) error {
+	if err := p.ORM.WithContext(ctx).Model(instruction).Select("transaction_id", "held_transfer_id").Updates(instruction).Error; err != nil {
+		return fmt.Errorf("unable to complete payment instruction %s: %v", instruction.EndToEndID, err)
+	}
+
+	return nil
+}
+
+// ReleasePaymentInstruction forgets a claimed instruction that was not executed, so that it can be sent again
+func (p PostgreSQL) ReleasePaymentInstruction(ctx context.Context, instructionID string) error {
+	filter := domain.PaymentInstruction{
+		AbstractBase: domain.AbstractBase{
+			UUID: instructionID,
+		},
+	}
+	if err := p.ORM.WithContext(ctx).Unscoped().Where(&filter).Delete(&domain.PaymentInstruction{}).Error; err != nil {
+		return fmt.Errorf("unable to release payment instruction %s: %v", instructionID, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,49 @@
+package postgresql_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/domain"
+	"github.com/google/uuid"
+)
+
+func TestPostgreSQL_PaymentInitiationSentAgain(t *testing.T) {
+	p := newTestPostgreSQL()
+	initiator := uuid.NewString()
+
+	message := func() *domain.PaymentMessage {
+		return &domain.PaymentMessage{Initiator: initiator, MessageID: "MSG-1"}
+	}
+	if _, err := p.RecordPaymentMessage(context.Background(), message()); err != nil {
+		t.Fatalf("PostgreSQL.RecordPaymentMessage() error = %v", err)
+	}
+	if _, err := p.RecordPaymentMessage(context.Background(), message()); !domain.IsKind(err, domain.Conflict) {
+		t.Errorf("expected a message received again to conflict, got %v", err)
+	}
+
+	instruction := func() *domain.PaymentInstruction {
+		return &domain.PaymentInstruction{Initiator: initiator, MessageID: "MSG-1", EndToEndID: "E2E-1"}
+	}
+	claim, err := p.ClaimPaymentInstruction(context.Background(), instruction())
+	if err != nil {
+		t.Fatalf("PostgreSQL.ClaimPaymentInstruction() error = %v", err)
+	}
+	if _, err := p.ClaimPaymentInstruction(context.Background(), instruction()); !domain.IsKind(err, domain.Conflict) {
+		t.Errorf("expected an instruction claimed again to conflict, got %v", err)
+	}
+
+	if err := p.ReleasePaymentInstruction(context.Background(), claim.UUID); err != nil {
+		t.Fatalf("PostgreSQL.ReleasePaymentInstruction() error = %v", err)
+	}
+	claim, err = p.ClaimPaymentInstruction(context.Background(), instruction())
+	if err != nil {
+		t.Fatalf("expected a released instruction to be claimed again, got %v", err)
+	}
+
+	transactionID := uuid.NewString()
+	claim.TransactionID = &transactionID
+	if err := p.CompletePaymentInstruction(context.Background(), claim); err != nil {
+		t.Errorf("PostgreSQL.CompletePaymentInstruction() error = %v", err)
+	}
+}
@@ -0,0 +1,231 @@
+package postgresql
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/domain"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// PERIOD_LOCK_KEY is the advisory lock that serializes closing a period against postings into it.
+// Postings take it shared so they do not block each other, closing takes it exclusively
+var PERIOD_LOCK_KEY = 2023092601
+
+var lockedStatuses = []domain.PeriodStatus{domain.PeriodClosed, domain.PeriodReopenRequested}
+
+// lockedPeriod returns the closed period containing a date, or nil when the date is open for posting
+func lockedPeriod(db *gorm.DB, date time.Time) (*domain.AccountingPeriod, error) {
+	var periods []*domain.AccountingPeriod
+	if err := db.Where("status IN ? AND start_date <= ? AND end_date > ?", lockedStatuses, date, date).
+		Order("end_date DESC").
+		Limit(1).
+		Find(&periods).Error; err != nil {
+		return nil, fmt.Errorf("unable to check the accounting period of %v: %v", date, err)
+	}
+
+	if len(periods) == 0 {
+		return nil, nil
+	}
+	return periods[0], nil
+}
+
+// nextOpenDate moves a date forward past any closed periods
+func nextOpenDate(db *gorm.DB, date time.Time) (time.Time, error) {
+	for {
+		period, err := lockedPeriod(db, date)
+		if err != nil {
+			return time.Time{}, err
+		}
+		if period == nil {
+			return date, nil
+		}
+		date = period.EndDate
+	}
+}
+
+// ClosePeriod snapshots every account's balance at the end of a period and marks it closed
+func (p PostgreSQL) ClosePeriod(ctx context.Context, period *domain.AccountingPeriod, actor string) (*domain.AccountingPeriod, error) {
+	if period == nil {
+		return nil, domain.NewValidationError("missing_period", "missing accounting period information")
+	}
+
+	if !period.StartDate.Before(period.EndDate) {
+		return nil, domain.NewValidationError("invalid_period", "an accounting period should start before it ends")
+	}
+
+	var closed domain.AccountingPeriod
+	if err := p.ORM.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := advisoryLock(tx, "pg_advisory_xact_lock", PERIOD_LOCK_KEY); err != nil {
+			return fmt.Errorf("unable to lock accounting periods: %v", err)
+		}
+
+		var existing []*domain.AccountingPeriod
+		if err := tx.Where("type = ? AND start_date = ? AND end_date = ?", period.Type, period.StartDate, period.EndDate).
+			Limit(1).
+			Find(&existing).Error; err != nil {
+			return fmt.Errorf("unable to get the accounting period: %v", err)
+		}
+
+		closed = *period
+		if len(existing) > 0 {
+			if existing[0].Status.IsLocked() {
+				return domain.NewConflictError("period_closed", "the %s period starting %s is already closed",
+					period.Type,
+					period.StartDate.Format("2006-01-02"),
+				)
+			}
+			// A reopened period is closed again under the same ID
+			closed = *existing[0]
+		}
+
+		closedAt := time.Now()
+		closed.Status = domain.PeriodClosed
+		closed.ClosedBy = actor
+		closed.ClosedAt = &closedAt
+		closed.ReopenRequestedBy = ""
+		closed.ReopenReason = ""
+		if err := tx.Save(&closed).Error; err != nil {
+			return fmt.Errorf("unable to close the accounting period: %v", err)
+		}
+
+		lines, err := trialBalance(tx, closed.EndDate.Add(-time.Nanosecond))
+		if err != nil {
+			return err
+		}
+
+		if err := tx.Unscoped().Where("period_id = ?", closed.UUID).Delete(&domain.BalanceSnapshot{}).Error; err != nil {
+			return fmt.Errorf("unable to clear previous balance snapshots: %v", err)
+		}
+
+		for _, line := range lines {
+			snapshot := domain.BalanceSnapshot{
+				PeriodID:    closed.UUID,
+				AccountID:   line.AccountID,
+				Currency:    line.Currency,
+				TotalDebit:  line.TotalDebit,
+				TotalCredit: line.TotalCredit,
+				Balance:     line.Balance,
+			}
+			if err := tx.Create(&snapshot).Error; err != nil {
+				return fmt.Errorf("unable to snapshot account %s: %v", line.AccountID, err)
+			}
+		}
+
+		audit := domain.PeriodAuditEntry{
+			PeriodID: closed.UUID,
+			Action:   domain.PeriodClosedAction,
+			Actor:    actor,
+		}
+		if err := tx.Create(&audit).Error; err != nil {
+			return fmt.Errorf("unable to audit the period closure: %v", err)
+		}
+
+		return nil
+	}); err != nil {
+		return nil, fmt.Errorf("unable to commit period closure: %w", err)
+	}
+
+	return &closed, nil
+}
+
+// UpdatePeriod changes an accounting period's status and records the change in its audit trail.
+// The period is locked while update checks and changes it, so that two changes can not both start from the same status
+func (p PostgreSQL) UpdatePeriod(
+	ctx context.Context,
+	periodID string,
+	audit *domain.PeriodAuditEntry,
+	update func(period *domain.AccountingPeriod) error,
+) (*domain.AccountingPeriod, error) {
+	if audit == nil || update == nil {
+		return nil, domain.NewValidationError("missing_period", "missing accounting period information")
+	}
+
+	var period domain.AccountingPeriod
+	if err := p.ORM.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := advisoryLock(tx, "pg_advisory_xact_lock", PERIOD_LOCK_KEY); err != nil {
+			return fmt.Errorf("unable to lock accounting periods: %v", err)
+		}
+
+		filter := domain.AccountingPeriod{AbstractBase: domain.AbstractBase{UUID: periodID}}
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where(&filter).First(&period).Error; err != nil {
+			return lookupError(err, "period_not_found", "accounting period %s", periodID)
+		}
+
+		if err := update(&period); err != nil {
+			return err
+		}
+
+		if err := tx.Save(&period).Error; err != nil {
+			return fmt.Errorf("unable to update the accounting period: %v", err)
+		}
+
+		audit.PeriodID = period.UUID
+		if err := tx.Create(audit).Error; err != nil {
+			return fmt.Errorf("unable to audit the accounting period: %v", err)
+		}
+		return nil
+	}); err != nil {
+		return nil, fmt.Errorf("unable to commit accounting period update: %w", err)
+	}
+
+	return &period, nil
+}
+
+// Period retrieves an accounting period given it's ID(UUID)
+func (p PostgreSQL) Period(ctx context.Context, periodID string) (*domain.AccountingPeriod, error) {
+	var period domain.AccountingPeriod
+
+	filter := domain.AccountingPeriod{
+		AbstractBase: domain.AbstractBase{
+			UUID: periodID,
+		},
+	}
+	if err := p.ORM.WithContext(ctx).Where(&filter).First(&period).Error; err != nil {
+		return nil, lookupError(err, "period_not_found", "accounting period %s", periodID)
+	}
+
+	return &period, nil
+}
+
+// Periods lists all accounting periods, most recent first
+func (p PostgreSQL) Periods(ctx context.Context) ([]*domain.AccountingPeriod, error) {
+	var periods []*domain.AccountingPeriod
+	if err := p.ORM.WithContext(ctx).Order("start_date DESC, end_date DESC").Find(&periods).Error; err != nil {
+		return nil, fmt.Errorf("unable to list accounting periods: %v", err)
+	}
+
+	return periods, nil
+}
+
+// LockedPeriod returns the closed period containing a date, or nil when the date is open for posting
+func (p PostgreSQL) LockedPeriod(ctx context.Context, date time.Time) (*domain.AccountingPeriod, error) {
+	return lockedPeriod(p.ORM.WithContext(ctx), date)
+}
+
+// NextOpenDate returns the earliest date, on or after the given one, that is open for posting
+func (p PostgreSQL) NextOpenDate(ctx context.Context, date time.Time) (time.Time, error) {
+	return nextOpenDate(p.ORM.WithContext(ctx), date)
+}
+
+// BalanceSnapshots lists the balances captured when a period was closed
+func (p PostgreSQL) BalanceSnapshots(ctx context.Context, periodID string) ([]*domain.BalanceSnapshot, error) {
+	var snapshots []*domain.BalanceSnapshot
+	if err := p.ORM.WithContext(ctx).Where("period_id = ?", periodID).Order("currency, account_id").Find(&snapshots).Error; err != nil {
+		return nil, fmt.Errorf("unable to get the period's balance snapshots: %v", err)
+	}
+
+	return snapshots, nil
+}
+
+// PeriodAudit lists the audit trail of an accounting period, oldest first
+func (p PostgreSQL) PeriodAudit(ctx context.Context, periodID string) ([]*domain.PeriodAuditEntry, error) {
+	var entries []*domain.PeriodAuditEntry
+	if err := p.ORM.WithContext(ctx).Where("period_id = ?", periodID).Order("created_at").Find(&entries).Error; err != nil {
+		return nil, fmt.Errorf("unable to get the period's audit trail: %v", err)
+	}
+
+	return entries, nil
+}
@@ -1,28 +1,63 @@
 package postgresql
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"log"
-	"os"
 	"strings"
 	"time"
 
 	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/application"
+	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/config"
 	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/domain"
 	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/domain/data"
 	"github.com/google/uuid"
 	"github.com/shopspring/decimal"
+	"go.uber.org/zap"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 
 	_ "github.com/GoogleCloudPlatform/cloudsql-proxy/proxy/dialers/postgres"
 )
 
 var DUPLICATE_KEY_MSG = "duplicate key value violates unique constraint"
 
+var INVALID_UUID_MSG = "invalid input syntax for type uuid"
+
+// lookupError classifies a failed lookup, a missing record or a malformed ID is not found while anything else is internal
+func lookupError(err error, code string, format string, args ...interface{}) error {
+	if errors.Is(err, gorm.ErrRecordNotFound) || strings.Contains(err.Error(), INVALID_UUID_MSG) {
+		return domain.NewNotFoundError(code, format+" was not found", args...)
+	}
+	return domain.NewInternalError(err, "unable to get "+format, args...)
+}
+
+// advisoryLock takes a transaction level advisory lock with function, such as pg_advisory_xact_lock. Besides the
+// driver cancelling the wait once the transaction's context is done, the context's deadline is set as the
+// transaction's lock_timeout, so that the database gives up the wait, and the row locks after it, on its own
+func advisoryLock(tx *gorm.DB, function string, key int) error {
+	ctx := tx.Statement.Context
+	if deadline, ok := ctx.Deadline(); ok {
+		timeout := time.Until(deadline)
+		if timeout <= 0 {
+			return context.DeadlineExceeded
+		}
+		// SET does not take parameters, a zero lock_timeout would wait without limit
+		if err := tx.Exec(fmt.Sprintf("SET LOCAL lock_timeout = %d", timeout.Milliseconds()+1)).Error; err != nil {
+			return err
+		}
+	}
+	return tx.Exec("SELECT "+function+"(?)", key).Error
+}
+
 // PostgreSQL sets up the PostgreSQL database layer with all the necessary dependencies
 type PostgreSQL struct {
 	ORM *gorm.DB
+
+	// Chart is the chart of accounts system accounts are seeded from and accounts are booked by
+	Chart *data.ChartOfAccounts
 }
 
 // CheckPreconditions ensures PostgreSQL's contract is adhered to
@@ -30,73 +65,83 @@ func (p PostgreSQL) CheckPreconditions() {
 	if p.ORM == nil {
 		log.Panicf("PostgreSQL's ORM driver has not been initialized")
 	}
+
+	if p.Chart == nil {
+		log.Panicf("PostgreSQL's chart of accounts has not been initialized")
+	}
 }
 
-// NewPostgreSQLDatabase initializes a new PostgreSQL database instance
-func NewPostgreSQLDatabase(gorm *gorm.DB) *PostgreSQL {
-	db := &PostgreSQL{ORM: gorm}
+// NewPostgreSQLDatabase initializes a new PostgreSQL database instance booking accounts by a chart of accounts,
+// the bundled one when none is given
+func NewPostgreSQLDatabase(gorm *gorm.DB, chart *data.ChartOfAccounts) *PostgreSQL {
+	if chart == nil {
+		var err error
+		if chart, err = data.DefaultChartOfAccounts(); err != nil {
+			log.Panicf("the bundled chart of accounts is invalid: %v", err)
+		}
+	}
+
+	db := &PostgreSQL{ORM: gorm, Chart: chart}
 	db.CheckPreconditions()
 	return db
 }
 
-// ConnectToDatabase opens a connection to a given database
-func ConnectToDatabase() (*gorm.DB, error) {
-	dsn := fmt.Sprintf(
-		"host=%s user=%s password=%s dbname=%s port=%s sslmode=disable TimeZone=Africa/Nairobi",
-		os.Getenv("DB_HOST"),
-		os.Getenv("DB_USER"),
-		os.Getenv("DB_PASS"),
-		os.Getenv("DB_NAME"),
-		os.Getenv("DB_PORT"),
-	)
-
-	if os.Getenv("DB_CONNECTION") != "cloud" {
-		db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{})
-		if err != nil {
-			log.Fatalf("can't open connection to the local database: %v", err)
-		}
-		if err := Migrate(db); err != nil {
-			return nil, err
-		}
+// DSN is the connection string of a configured database, quoting its values as libpq expects
+func DSN(cfg config.Database) string {
+	settings := []struct{ key, value string }{
+		{"host", cfg.Host},
+		{"port", cfg.Port},
+		{"user", cfg.User},
+		{"password", cfg.Password},
+		{"dbname", cfg.Name},
+		{"sslmode", cfg.SSLMode},
+		{"sslrootcert", cfg.SSLRootCert},
+		{"TimeZone", cfg.TimeZone},
+	}
 
-		return db, nil
+	var dsn []string
+	for _, setting := range settings {
+		if setting.value == "" {
+			continue
+		}
+		quoted := strings.NewReplacer(`\`, `\\`, `'`, `\'`).Replace(setting.value)
+		dsn = append(dsn, fmt.Sprintf("%s='%s'", setting.key, quoted))
 	}
+	return strings.Join(dsn, " ")
+}
 
-	db, err := gorm.Open(postgres.New(postgres.Config{
-		DriverName: "cloudsqlpostgres",
-		DSN:        dsn,
-	}))
+// ConnectToDatabase opens a pool of connections to the configured database, logging its queries with logger.
+// Its schema is migrated separately by Migrate
+func ConnectToDatabase(cfg config.Database, logger *zap.Logger) (*gorm.DB, error) {
+	dialector := postgres.Open(DSN(cfg))
+	if cfg.Connection == config.DB_CLOUD {
+		dialector = postgres.New(postgres.Config{
+			DriverName: "cloudsqlpostgres",
+			DSN:        DSN(cfg),
+		})
+	}
 
+	db, err := gorm.Open(dialector, &gorm.Config{Logger: NewQueryLogger(logger)})
 	if err != nil {
-		return nil, fmt.Errorf("server is unable to connect to the database: %v", err)
+		return nil, fmt.Errorf("unable to connect to the %s database: %v", cfg.Connection, err)
 	}
 
-	if err := Migrate(db); err != nil {
+	sqlDB, err := db.DB()
+	if err != nil {
 		return nil, err
 	}
+	sqlDB.SetMaxOpenConns(cfg.MaxOpenConns)
+	sqlDB.SetMaxIdleConns(cfg.MaxIdleConns)
+	sqlDB.SetConnMaxLifetime(cfg.ConnMaxLifetime)
+	sqlDB.SetConnMaxIdleTime(cfg.ConnMaxIdleTime)
 
 	return db, nil
 }
 
-func Migrate(db *gorm.DB) error {
-	tables := []interface{}{
-		&domain.Account{},
-		&domain.Transaction{},
-		&domain.AccountEntry{},
-	}
-	for _, table := range tables {
-		if err := db.AutoMigrate(table); err != nil {
-			return fmt.Errorf("server is unable to run database migrations: %v", err)
-		}
-	}
-
-	return nil
-}
-
-// CreateSystemAccount created default system accounts
-func (p PostgreSQL) CreateSystemAccount() error {
-	for _, account := range data.SystemAccounts() {
-		err := p.ORM.Create(&account).Error
+// CreateSystemAccount seeds the chart of accounts and the default system accounts of every currency
+func (p PostgreSQL) CreateSystemAccount(ctx context.Context) error {
+	for _, code := range p.Chart.LedgerCodes {
+		err := p.ORM.WithContext(ctx).Create(code).Error
 		if err != nil {
 			if strings.Contains(err.Error(), DUPLICATE_KEY_MSG) {
 				continue
@@ -105,34 +150,87 @@ func (p PostgreSQL) CreateSystemAccount() error {
 			}
 		}
 	}
+
+	// An account seeded before roles and GL codes existed, such as the legacy funding account, is given them
+	for _, account := range p.Chart.SeedAccounts() {
+		if err := p.ORM.WithContext(ctx).Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "uuid"}},
+			DoUpdates: clause.AssignmentColumns([]string{"role", "gl_code"}),
+		}).Create(account).Error; err != nil {
+			return fmt.Errorf("unable to seed system account %s: %v", account.Number, err)
+		}
+	}
 	return nil
 }
 
 // CreateAccount does a database call to create a account
-func (p PostgreSQL) CreateAccount(account *domain.Account) (*application.AccountInformationOutput, error) {
+func (p PostgreSQL) CreateAccount(ctx context.Context, account *domain.Account) (*application.AccountInformationOutput, error) {
 	if account == nil {
-		return nil, fmt.Errorf("missing account creation information")
+		return nil, domain.NewValidationError("missing_account", "missing account creation information")
 	}
 
-	if err := p.ORM.Create(&account).Error; err != nil {
-		return nil, fmt.Errorf("unable to create account: %v", err)
+	if account.GLCode == "" && account.Header != "" {
+		glCode, err := p.Chart.LedgerCodeForHeader(account.Header)
+		if err != nil {
+			return nil, err
+		}
+		account.GLCode = glCode
 	}
 
-	return p.Account(account.UUID)
+	if err := p.ORM.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&account).Error; err != nil {
+			return fmt.Errorf("unable to create account: %v", err)
+		}
+
+		return writeEvent(tx, domain.AccountOpened, account.UUID, domain.AccountOpenedPayload{
+			AccountID: account.UUID,
+			Number:    account.Number,
+			Name:      account.Name,
+			Currency:  account.Currency,
+			Header:    account.Header,
+		})
+	}); err != nil {
+		return nil, err
+	}
+
+	return p.Account(ctx, account.UUID)
 }
 
 // CreateTransaction does a database call to create a transaction with account entries
 func (p PostgreSQL) CreateTransaction(
+	ctx context.Context,
 	description string,
 	drEntry *domain.AccountEntry,
 	crEntry *domain.AccountEntry,
+) (*domain.Transaction, error) {
+	return p.createTransaction(ctx, description, false, drEntry, crEntry)
+}
+
+// CreateTransfer creates a transaction moving money out of the credited account, refusing it when the
+// account's balance does not cover it
+func (p PostgreSQL) CreateTransfer(
+	ctx context.Context,
+	description string,
+	drEntry *domain.AccountEntry,
+	crEntry *domain.AccountEntry,
+) (*domain.Transaction, error) {
+	return p.createTransaction(ctx, description, true, drEntry, crEntry)
+}
+
+// createTransaction validates and posts a transaction, covered tells whether the credited account should cover it
+func (p PostgreSQL) createTransaction(
+	ctx context.Context,
+	description string,
+	covered bool,
+	drEntry *domain.AccountEntry,
+	crEntry *domain.AccountEntry,
 ) (*domain.Transaction, error) {
 	if drEntry == nil {
-		return nil, fmt.Errorf("DR entry should be provided for a transaction")
+		return nil, domain.NewValidationError("missing_entry", "DR entry should be provided for a transaction")
 	}
 
 	if crEntry == nil {
-		return nil, fmt.Errorf("CR entry should be provided for a transaction")
+		return nil, domain.NewValidationError("missing_entry", "CR entry should be provided for a transaction")
 	}
 
 	if err := drEntry.ValidateDebitAmount(); err != nil {
@@ -144,34 +242,216 @@ func (p PostgreSQL) CreateTransaction(
 	}
 
 	if drEntry.DebitAmount != crEntry.CreditAmount {
-		return nil, fmt.Errorf("transaction does not observe double entry")
+		return nil, domain.NewValidationError("unbalanced_transaction", "transaction does not observe double entry")
 	}
 
-	var transaction domain.Transaction
-	if err := p.ORM.Transaction(func(tx *gorm.DB) error {
-		transaction = domain.Transaction{Description: description}
-		if err := tx.Create(&transaction).Error; err != nil {
-			return fmt.Errorf("unable to create an accounting transaction: %v", err)
+	var transaction *domain.Transaction
+	if err := p.ORM.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var err error
+		transaction, err = postTransaction(tx, description, nil, covered, drEntry, crEntry)
+		return err
+	}); err != nil {
+		return nil, fmt.Errorf("unable to commit transaction: %w", err)
+	}
+
+	return transaction, nil
+}
+
+// ReverseTransaction posts a transaction that offsets the entries of a posted transaction
+func (p PostgreSQL) ReverseTransaction(ctx context.Context, transactionID string, description string) (*domain.Transaction, error) {
+	var reversal *domain.Transaction
+	if err := p.ORM.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var original domain.Transaction
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where(&domain.Transaction{AbstractBase: domain.AbstractBase{UUID: transactionID}}).
+			First(&original).Error; err != nil {
+			return lookupError(err, "transaction_not_found", "transaction %s", transactionID)
+		}
+
+		if original.ReversalOf != nil {
+			return domain.NewConflictError("reversal_not_reversible", "transaction %s is a reversal and can not be reversed", transactionID)
+		}
+
+		var count int64
+		if err := tx.Model(&domain.Transaction{}).Where("reversal_of = ?", transactionID).Count(&count).Error; err != nil {
+			return fmt.Errorf("unable to check reversals of transaction %s: %v", transactionID, err)
+		}
+		if count > 0 {
+			return domain.NewConflictError("already_reversed", "transaction %s has already been reversed", transactionID)
 		}
 
 		var entries []*domain.AccountEntry
-		entries = append(entries, drEntry, crEntry)
+		if err := tx.Where(&domain.AccountEntry{TransactionID: transactionID}).Find(&entries).Error; err != nil {
+			return fmt.Errorf("unable to get entries of transaction %s: %v", transactionID, err)
+		}
+
+		var drEntry, crEntry *domain.AccountEntry
 		for _, entry := range entries {
-			entry.TransactionID = transaction.UUID
-			if err := tx.Create(&entry).Error; err != nil {
-				return fmt.Errorf("unable to create an account entry: %v", err)
+			if entry.DebitAmount.IsPositive() {
+				crEntry = &domain.AccountEntry{CreditAmount: entry.DebitAmount, AccountID: entry.AccountID}
+			}
+			if entry.CreditAmount.IsPositive() {
+				drEntry = &domain.AccountEntry{DebitAmount: entry.CreditAmount, AccountID: entry.AccountID}
 			}
 		}
-		return nil
+		if drEntry == nil || crEntry == nil || len(entries) != 2 {
+			return domain.NewConflictError("not_reversible", "transaction %s does not have a debit and a credit entry to reverse", transactionID)
+		}
+
+		// The money may have been spent since, posting the reversal checks that it does not overdraw
+		// the account it was paid into
+		if description == "" {
+			description = fmt.Sprintf("Reversal of %s", original.Description)
+		}
+
+		var err error
+		reversal, err = postTransaction(tx, description, &original.UUID, true, drEntry, crEntry)
+		return err
 	}); err != nil {
-		return nil, fmt.Errorf("unable to commit transaction: %v", err)
+		return nil, fmt.Errorf("unable to reverse transaction: %w", err)
+	}
+
+	return reversal, nil
+}
+
+// postTransaction records a balanced transaction and its domain event within a database transaction.
+// When covered is set, the transaction is refused if it would overdraw the customer account it credits
+func postTransaction(
+	tx *gorm.DB,
+	description string,
+	reversalOf *string,
+	covered bool,
+	drEntry *domain.AccountEntry,
+	crEntry *domain.AccountEntry,
+) (*domain.Transaction, error) {
+	// Hold off period closure until this transaction is committed
+	if err := advisoryLock(tx, "pg_advisory_xact_lock_shared", PERIOD_LOCK_KEY); err != nil {
+		return nil, fmt.Errorf("unable to lock accounting periods: %v", err)
+	}
+
+	accounts, err := lockAccounts(tx, drEntry.AccountID, crEntry.AccountID)
+	if err != nil {
+		return nil, err
+	}
+	if covered {
+		if err := checkCovered(tx, accounts[crEntry.AccountID], crEntry.CreditAmount); err != nil {
+			return nil, err
+		}
+	}
+
+	effectiveDate := time.Now()
+	entries := []*domain.AccountEntry{drEntry, crEntry}
+	for _, entry := range entries {
+		if entry.EffectiveDate == nil {
+			entry.EffectiveDate = &effectiveDate
+		}
+
+		period, err := lockedPeriod(tx, *entry.EffectiveDate)
+		if err != nil {
+			return nil, err
+		}
+		if period != nil {
+			return nil, domain.NewValidationError(
+				"period_closed",
+				"effective date %s falls in the closed %s period starting %s, post adjustments on or after %s",
+				entry.EffectiveDate.Format(time.RFC3339),
+				period.Type,
+				period.StartDate.Format("2006-01-02"),
+				period.EndDate.Format(time.RFC3339),
+			)
+		}
+	}
+
+	transaction := domain.Transaction{Description: description, ReversalOf: reversalOf}
+	if err := tx.Create(&transaction).Error; err != nil {
+		return nil, fmt.Errorf("unable to create an accounting transaction: %v", err)
+	}
+
+	for _, entry := range entries {
+		entry.TransactionID = transaction.UUID
+		if err := tx.Create(&entry).Error; err != nil {
+			return nil, fmt.Errorf("unable to create an account entry: %v", err)
+		}
+	}
+
+	destination := accounts[drEntry.AccountID]
+	eventType := domain.TransferPosted
+	payload := domain.TransferPayload{
+		TransactionID:        transaction.UUID,
+		Description:          description,
+		SourceAccountID:      crEntry.AccountID,
+		DestinationAccountID: drEntry.AccountID,
+		Amount:               drEntry.DebitAmount,
+		Currency:             destination.Currency,
+		EffectiveDate:        *drEntry.EffectiveDate,
+	}
+	if reversalOf != nil {
+		eventType = domain.TransferReversed
+		payload.ReversedTransactionID = *reversalOf
+	}
+	if err := writeEvent(tx, eventType, transaction.UUID, payload); err != nil {
+		return nil, err
 	}
 
 	return &transaction, nil
 }
 
+// lockAccounts locks the rows of the accounts a transaction posts to until it is committed, so that postings to
+// an account are serialized across every process moving money. Rows are locked in the same order by every
+// transaction, keeping two transfers between the same accounts from deadlocking
+func lockAccounts(tx *gorm.DB, accountIDs ...string) (map[string]*domain.Account, error) {
+	var accounts []*domain.Account
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("uuid IN ?", accountIDs).
+		Order("uuid").
+		Find(&accounts).Error; err != nil {
+		return nil, fmt.Errorf("unable to lock accounts: %v", err)
+	}
+
+	locked := make(map[string]*domain.Account, len(accounts))
+	for _, account := range accounts {
+		locked[account.UUID] = account
+	}
+	for _, accountID := range accountIDs {
+		if locked[accountID] == nil {
+			return nil, domain.NewNotFoundError("account_not_found", "account %s was not found", accountID)
+		}
+	}
+	return locked, nil
+}
+
+// checkCovered ensures a locked customer account's balance covers the amount credited from it. The balance is
+// read under the account's lock, so it can not be spent by another transaction before this one is committed
+func checkCovered(tx *gorm.DB, account *domain.Account, amount decimal.Decimal) error {
+	if account.IsSystemAccount {
+		return nil
+	}
+
+	var totals struct {
+		Debits  decimal.Decimal
+		Credits decimal.Decimal
+	}
+	if err := tx.Raw(`SELECT COALESCE(SUM(debit_amount::numeric), 0) AS debits,
+			COALESCE(SUM(credit_amount::numeric), 0) AS credits
+		FROM account_entries
+		WHERE account_id = ? AND deleted_at IS NULL`, account.UUID).
+		Scan(&totals).Error; err != nil {
+		return fmt.Errorf("unable to get the balance of account %s: %v", account.UUID, err)
+	}
+
+	balance := account.ComputeBalance(totals.Debits, totals.Credits)
+	if amount.GreaterThan(balance) {
+		return domain.NewInsufficientFundsError("%v is more than %s current account's balance of %v",
+			amount,
+			account.Name,
+			balance,
+		)
+	}
+	return nil
+}
+
 // Account retrieves an account given it's ID(UUID)
-func (p PostgreSQL) Account(accountID string) (*application.AccountInformationOutput, error) {
+func (p PostgreSQL) Account(ctx context.Context, accountID string) (*application.AccountInformationOutput, error) {
 	var account domain.Account
 
 	filter := domain.Account{
@@ -179,13 +459,56 @@ func (p PostgreSQL) Account(accountID string) (*application.AccountInformationOu
 			UUID: accountID,
 		},
 	}
-	if err := p.ORM.Where(&filter).First(&account).Error; err != nil {
-		return nil, fmt.Errorf("unable to get account %s: %v", accountID, err)
+	if err := p.ORM.WithContext(ctx).Where(&filter).First(&account).Error; err != nil {
+		return nil, lookupError(err, "account_not_found", "account %s", accountID)
+	}
+
+	return p.accountOutput(ctx, &account)
+}
+
+// AccountByNumber retrieves an account given it's account number
+func (p PostgreSQL) AccountByNumber(ctx context.Context, number string) (*application.AccountInformationOutput, error) {
+	var accounts []*domain.Account
+
+	filter := domain.Account{
+		Number: number,
+	}
+	if err := p.ORM.WithContext(ctx).Where(&filter).Limit(2).Find(&accounts).Error; err != nil {
+		return nil, fmt.Errorf("unable to get account %s: %v", number, err)
 	}
 
-	balance, err := p.AccountBalance(&account)
+	if len(accounts) == 0 {
+		return nil, domain.NewNotFoundError("account_not_found", "account %s was not found", number)
+	}
+
+	if len(accounts) > 1 {
+		return nil, domain.NewConflictError("ambiguous_account_number", "account number %s is shared by more than one account", number)
+	}
+
+	return p.accountOutput(ctx, accounts[0])
+}
+
+// SystemAccount retrieves the system account playing the given role for a currency
+func (p PostgreSQL) SystemAccount(ctx context.Context, role domain.AccountRole, currency domain.CurrencyType) (*application.AccountInformationOutput, error) {
+	var account domain.Account
+
+	filter := domain.Account{
+		IsSystemAccount: true,
+		Role:            role,
+		Currency:        currency,
+	}
+	if err := p.ORM.WithContext(ctx).Where(&filter).First(&account).Error; err != nil {
+		return nil, lookupError(err, "account_not_found", "the %s system account for %s", role, currency)
+	}
+
+	return p.accountOutput(ctx, &account)
+}
+
+// accountOutput enriches an account with its current balance
+func (p PostgreSQL) accountOutput(ctx context.Context, account *domain.Account) (*application.AccountInformationOutput, error) {
+	balance, err := p.AccountBalance(ctx, account)
 	if err != nil {
-		return nil, fmt.Errorf("unable to get account's balance: %v", err)
+		return nil, fmt.Errorf("unable to get account's balance: %w", err)
 	}
 
 	effectiveDate := time.Now()
@@ -200,6 +523,8 @@ func (p PostgreSQL) Account(accountID string) (*application.AccountInformationOu
 		BalanceType:     account.BalanceType,
 		Header:          account.Header,
 		IsSystemAccount: account.IsSystemAccount,
+		GLCode:          account.GLCode,
+		Role:            account.Role,
 		Number:          account.Number,
 		Balance:         balance,
 		BalanceAsOf:     &effectiveDate,
@@ -209,57 +534,61 @@ func (p PostgreSQL) Account(accountID string) (*application.AccountInformationOu
 }
 
 // AccountDebitTotal aggregates all the debits done to an account
-func (p PostgreSQL) AccountDebitTotal(account *domain.Account) (*decimal.Decimal, error) {
+func (p PostgreSQL) AccountDebitTotal(ctx context.Context, account *domain.Account) (*decimal.Decimal, error) {
 	accountID := account.UUID
 	if _, err := uuid.Parse(accountID); err != nil {
-		return nil, fmt.Errorf("%s is not a valid uuid: %v", accountID, err)
+		return nil, domain.NewValidationError("invalid_uuid", "%s is not a valid uuid", accountID)
 	}
 
-	var total decimal.Decimal
-	if err := p.ORM.Raw("SELECT COALESCE(SUM(debit_amount::float), 0) AS totalDebit FROM account_entries WHERE account_id = ?", accountID).Scan(&total).Error; err != nil {
+	var totals struct {
+		TotalDebit decimal.Decimal
+	}
+	if err := p.ORM.WithContext(ctx).Raw(
+		"SELECT COALESCE(SUM(debit_amount::numeric), 0) AS total_debit FROM account_entries WHERE account_id = ? AND deleted_at IS NULL",
+		accountID,
+	).Scan(&totals).Error; err != nil {
 		return nil, fmt.Errorf("unable to get the account's total debits: %v", err)
 	}
 
-	return &total, nil
+	return &totals.TotalDebit, nil
 }
 
 // AccountCreditTotal aggregates all the credits done to an account
-func (p PostgreSQL) AccountCreditTotal(account *domain.Account) (*decimal.Decimal, error) {
+func (p PostgreSQL) AccountCreditTotal(ctx context.Context, account *domain.Account) (*decimal.Decimal, error) {
 	accountID := account.UUID
 	if _, err := uuid.Parse(accountID); err != nil {
-		return nil, fmt.Errorf("%s is not a valid uuid: %v", accountID, err)
+		return nil, domain.NewValidationError("invalid_uuid", "%s is not a valid uuid", accountID)
 	}
 
-	var total decimal.Decimal
-	if err := p.ORM.Raw("SELECT COALESCE(SUM(credit_amount::float), 0) AS totalCredit FROM account_entries WHERE account_id = ?", accountID).Scan(&total).Error; err != nil {
+	var totals struct {
+		TotalCredit decimal.Decimal
+	}
+	if err := p.ORM.WithContext(ctx).Raw(
+		"SELECT COALESCE(SUM(credit_amount::numeric), 0) AS total_credit FROM account_entries WHERE account_id = ? AND deleted_at IS NULL",
+		accountID,
+	).Scan(&totals).Error; err != nil {
 		return nil, fmt.Errorf("unable to get the account's total credits: %v", err)
 	}
 
-	return &total, nil
+	return &totals.TotalCredit, nil
 }
 
 // AccountBalance computes the balance of an account from it's entries
-func (p PostgreSQL) AccountBalance(account *domain.Account) (*decimal.Decimal, error) {
+func (p PostgreSQL) AccountBalance(ctx context.Context, account *domain.Account) (*decimal.Decimal, error) {
 	if account == nil {
-		return nil, fmt.Errorf("account has not been supplied")
+		return nil, domain.NewValidationError("missing_account", "account has not been supplied")
 	}
 
-	debits, err := p.AccountDebitTotal(account)
+	debits, err := p.AccountDebitTotal(ctx, account)
 	if err != nil {
 		return nil, err
 	}
 
-	credits, err := p.AccountCreditTotal(account)
+	credits, err := p.AccountCreditTotal(ctx, account)
 	if err != nil {
 		return nil, err
 	}
 
-	var balance decimal.Decimal
-	if account.BalanceType == domain.Credit {
-		balance = debits.Sub(*credits)
-	} else {
-		balance = credits.Sub(*debits)
-	}
-
+	balance := account.ComputeBalance(*debits, *credits)
 	return &balance, nil
 }
@@ -1,22 +1,35 @@
 package postgresql_test
 
 import (
+	"context"
+	"errors"
 	"log"
+	"sync"
 	"testing"
+	"time"
 
+	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/application"
+	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/config"
 	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/domain"
 	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/infrastructure/database/postgresql"
 	"github.com/brianvoe/gofakeit"
 	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
 )
 
 func newTestPostgreSQL() *postgresql.PostgreSQL {
-	db, err := postgresql.ConnectToDatabase()
+	cfg, err := config.Read("")
+	if err != nil {
+		log.Panicf("error reading the testing database configuration: %v", err)
+	}
+	db, err := postgresql.ConnectToDatabase(cfg.Database, zap.NewNop())
 	if err != nil {
 		log.Panicf("error connecting to the testing database: %v", err)
 	}
 
-	return postgresql.NewPostgreSQLDatabase((db))
+	return postgresql.NewPostgreSQLDatabase(db, nil)
 }
 
 func TestPostgreSQL_CreateAccount(t *testing.T) {
@@ -49,7 +62,7 @@ func TestPostgreSQL_CreateAccount(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			account, err := p.CreateAccount(tt.args.account)
+			account, err := p.CreateAccount(context.Background(), tt.args.account)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("PostgreSQL.CreateAccount() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -79,7 +92,7 @@ func TestPostgreSQL_CreateAccount(t *testing.T) {
 func TestPostgreSQL_Account(t *testing.T) {
 	p := newTestPostgreSQL()
 
-	newAccount, err := p.CreateAccount(&domain.Account{
+	newAccount, err := p.CreateAccount(context.Background(), &domain.Account{
 		Name:        gofakeit.Name(),
 		Description: "Customer's deposit account",
 		BalanceType: domain.Credit,
@@ -115,7 +128,7 @@ func TestPostgreSQL_Account(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			account, err := p.Account(tt.args.accountID)
+			account, err := p.Account(context.Background(), tt.args.accountID)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("PostgreSQL.Account() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -141,3 +154,210 @@ func TestPostgreSQL_Account(t *testing.T) {
 		})
 	}
 }
+
+func TestPostgreSQL_SystemAccount(t *testing.T) {
+	p := newTestPostgreSQL()
+	if err := p.CreateSystemAccount(context.Background()); err != nil {
+		t.Errorf("unable to create system accounts: %v", err)
+		return
+	}
+
+	type args struct {
+		role     domain.AccountRole
+		currency domain.CurrencyType
+	}
+	tests := []struct {
+		name    string
+		args    args
+		wantErr bool
+	}{
+		{
+			name: "happy case - KSH funding account",
+			args: args{
+				role:     domain.FundingRole,
+				currency: domain.Kenyan,
+			},
+			wantErr: false,
+		},
+		{
+			name: "happy case - UGX suspense account",
+			args: args{
+				role:     domain.SuspenseRole,
+				currency: domain.Ugandan,
+			},
+			wantErr: false,
+		},
+		{
+			name: "sad case - unknown currency",
+			args: args{
+				role:     domain.FundingRole,
+				currency: "USD",
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			account, err := p.SystemAccount(context.Background(), tt.args.role, tt.args.currency)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("PostgreSQL.SystemAccount() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !tt.wantErr {
+				if !account.IsSystemAccount {
+					t.Errorf("expected a system account")
+					return
+				}
+				if account.Role != tt.args.role || account.Currency != tt.args.currency {
+					t.Errorf("expected a %s %s account, got %s %s", tt.args.currency, tt.args.role, account.Currency, account.Role)
+					return
+				}
+				if account.GLCode == "" {
+					t.Errorf("expected the system account to have a GL code")
+					return
+				}
+			}
+		})
+	}
+}
+
+func TestPostgreSQL_CreateSystemAccount_LegacyFundingAccount(t *testing.T) {
+	p := newTestPostgreSQL()
+	if err := p.CreateSystemAccount(context.Background()); err != nil {
+		t.Fatalf("unable to create system accounts: %v", err)
+	}
+
+	// A database created before the chart of accounts holds the funding account without a role or GL code
+	legacy := p.ORM.Model(&domain.Account{}).Where("number = ?", "AC-0123456789").Updates(map[string]interface{}{"role": "", "gl_code": ""})
+	if legacy.Error != nil || legacy.RowsAffected != 1 {
+		t.Fatalf("unable to strip the funding account's role: %v", legacy.Error)
+	}
+
+	if err := p.CreateSystemAccount(context.Background()); err != nil {
+		t.Fatalf("unable to seed the system accounts again: %v", err)
+	}
+	account, err := p.SystemAccount(context.Background(), domain.FundingRole, domain.Kenyan)
+	if err != nil {
+		t.Fatalf("expected the legacy funding account to be given its role, got %v", err)
+	}
+	if account.Number != "AC-0123456789" || account.GLCode == "" {
+		t.Errorf("expected the legacy funding account with a GL code, got %s %q", account.Number, account.GLCode)
+	}
+}
+
+func TestPostgreSQL_ReverseTransaction_SpentFunds(t *testing.T) {
+	p := newTestPostgreSQL()
+	amount := decimal.NewFromInt(25)
+	source, destination := postTransfer(t, p, amount)
+
+	transactions, err := p.Transactions(context.Background(), application.TransactionFilter{AccountID: source.UUID, Limit: 1})
+	if err != nil || len(transactions) != 1 {
+		t.Fatalf("unable to find the transfer to reverse: %v", err)
+	}
+
+	// The destination spends most of the transfer before it is reversed
+	payee, err := p.CreateAccount(context.Background(), &domain.Account{Name: gofakeit.Name(), BalanceType: domain.Credit})
+	if err != nil {
+		t.Fatalf("unable to create an account: %v", err)
+	}
+	spent := decimal.NewFromInt(20)
+	if _, err := p.CreateTransaction(context.Background(), "spending the transfer",
+		&domain.AccountEntry{DebitAmount: spent, AccountID: payee.UUID},
+		&domain.AccountEntry{CreditAmount: spent, AccountID: destination.UUID},
+	); err != nil {
+		t.Fatalf("unable to spend the transfer: %v", err)
+	}
+
+	_, err = p.ReverseTransaction(context.Background(), transactions[0].UUID, "")
+	var failure *domain.Error
+	if !errors.As(err, &failure) || failure.Kind != domain.InsufficientFunds {
+		t.Fatalf("expected the reversal to be refused for insufficient funds, got %v", err)
+	}
+
+	balance, err := p.AccountBalance(context.Background(), destination)
+	if err != nil {
+		t.Fatalf("unable to get the destination's balance: %v", err)
+	}
+	if !balance.Equal(amount.Sub(spent)) {
+		t.Errorf("expected the destination's balance to be left at %v, got %v", amount.Sub(spent), balance)
+	}
+}
+
+func TestPostgreSQL_CreateTransfer_Concurrent(t *testing.T) {
+	p := newTestPostgreSQL()
+	funding := decimal.NewFromInt(100)
+	_, source := postTransfer(t, p, funding)
+	destination, err := p.CreateAccount(context.Background(), &domain.Account{Name: gofakeit.Name(), Currency: domain.Kenyan, BalanceType: domain.Credit})
+	if err != nil {
+		t.Fatalf("unable to create an account: %v", err)
+	}
+
+	// Transfers racing each other out of the same account, only three of them are covered
+	amount := decimal.NewFromInt(30)
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	posted := 0
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := p.CreateTransfer(context.Background(), "racing transfer",
+				&domain.AccountEntry{DebitAmount: amount, AccountID: destination.UUID},
+				&domain.AccountEntry{CreditAmount: amount, AccountID: source.UUID},
+			)
+			if err == nil {
+				mu.Lock()
+				posted++
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	if posted != 3 {
+		t.Errorf("expected 3 transfers to be posted, got %d", posted)
+	}
+	balance, err := p.AccountBalance(context.Background(), source)
+	if err != nil {
+		t.Fatalf("unable to get the source's balance: %v", err)
+	}
+	if !balance.Equal(decimal.NewFromInt(10)) {
+		t.Errorf("expected the source's balance to be left at 10, got %v", balance)
+	}
+}
+
+func TestPostgreSQL_UpdatePeriod_LockWaitDeadline(t *testing.T) {
+	p := newTestPostgreSQL()
+
+	// Another transaction holds the period lock for longer than the caller can wait
+	held := make(chan struct{})
+	release := make(chan struct{})
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		_ = p.ORM.Transaction(func(tx *gorm.DB) error {
+			if err := tx.Exec("SELECT pg_advisory_xact_lock(?)", postgresql.PERIOD_LOCK_KEY).Error; err != nil {
+				close(held)
+				return err
+			}
+			close(held)
+			<-release
+			return nil
+		})
+	}()
+	<-held
+	defer wg.Wait()
+	defer close(release)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	_, err := p.UpdatePeriod(ctx, uuid.NewString(), &domain.PeriodAuditEntry{}, func(*domain.AccountingPeriod) error { return nil })
+	if err == nil {
+		t.Fatalf("expected the update to give up waiting for the period lock")
+	}
+	if waited := time.Since(start); waited > 2*time.Second {
+		t.Errorf("expected the wait to end at the caller's deadline, waited %v", waited)
+	}
+}
@@ -0,0 +1,163 @@
+package postgresql
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/application"
+	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/domain"
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+)
+
+// afterCursor restricts a listing ordered by creation time to the records following a cursor
+func afterCursor(query *gorm.DB, table string, after *application.Cursor) *gorm.DB {
+	query = query.Order(fmt.Sprintf("%s.created_at, %s.uuid", table, table))
+	if after == nil {
+		return query
+	}
+	return query.Where(fmt.Sprintf("(%s.created_at, %s.uuid) > (?, ?)", table, table), after.CreatedAt, after.ID)
+}
+
+// validIDs drops the IDs that are not uuids, they match no record
+func validIDs(ids []string) []string {
+	valid := make([]string, 0, len(ids))
+	for _, id := range ids {
+		if _, err := uuid.Parse(id); err == nil {
+			valid = append(valid, id)
+		}
+	}
+	return valid
+}
+
+// Accounts lists the accounts matching a filter, oldest first
+func (p PostgreSQL) Accounts(ctx context.Context, filter application.AccountFilter) ([]*domain.Account, error) {
+	query := p.ORM.WithContext(ctx).Model(&domain.Account{})
+	if filter.Currency != nil {
+		query = query.Where("currency = ?", *filter.Currency)
+	}
+	if filter.Header != nil {
+		query = query.Where("header = ?", *filter.Header)
+	}
+	if filter.IsSystemAccount != nil {
+		query = query.Where("is_system_account = ?", *filter.IsSystemAccount)
+	}
+	if filter.Name != "" {
+		query = query.Where("name ILIKE ?", "%"+filter.Name+"%")
+	}
+
+	var accounts []*domain.Account
+	if err := afterCursor(query, "accounts", filter.After).Limit(filter.Limit).Find(&accounts).Error; err != nil {
+		return nil, fmt.Errorf("unable to get accounts: %v", err)
+	}
+
+	return accounts, nil
+}
+
+// AccountsByID retrieves the accounts with the given IDs
+func (p PostgreSQL) AccountsByID(ctx context.Context, accountIDs []string) ([]*domain.Account, error) {
+	var accounts []*domain.Account
+	if err := p.ORM.WithContext(ctx).Where("uuid IN ?", validIDs(accountIDs)).Find(&accounts).Error; err != nil {
+		return nil, fmt.Errorf("unable to get accounts: %v", err)
+	}
+
+	return accounts, nil
+}
+
+// AccountBalances computes the balances of the given accounts in a single query
+func (p PostgreSQL) AccountBalances(ctx context.Context, accountIDs []string) (map[string]decimal.Decimal, error) {
+	var totals []struct {
+		UUID        string
+		BalanceType domain.BalanceType
+		Debits      decimal.Decimal
+		Credits     decimal.Decimal
+	}
+	if err := p.ORM.WithContext(ctx).Raw(`SELECT accounts.uuid, accounts.balance_type,
+			COALESCE(SUM(account_entries.debit_amount::numeric), 0) AS debits,
+			COALESCE(SUM(account_entries.credit_amount::numeric), 0) AS credits
+		FROM accounts
+		LEFT JOIN account_entries ON account_entries.account_id = accounts.uuid AND account_entries.deleted_at IS NULL
+		WHERE accounts.uuid IN ? AND accounts.deleted_at IS NULL
+		GROUP BY accounts.uuid, accounts.balance_type`, validIDs(accountIDs)).
+		Scan(&totals).Error; err != nil {
+		return nil, fmt.Errorf("unable to get the accounts' balances: %v", err)
+	}
+
+	balances := make(map[string]decimal.Decimal, len(totals))
+	for _, total := range totals {
+		balances[total.UUID] = domain.Account{BalanceType: total.BalanceType}.ComputeBalance(total.Debits, total.Credits)
+	}
+
+	return balances, nil
+}
+
+// Transactions lists the transactions matching a filter, oldest first
+func (p PostgreSQL) Transactions(ctx context.Context, filter application.TransactionFilter) ([]*domain.Transaction, error) {
+	query := p.ORM.WithContext(ctx).Model(&domain.Transaction{})
+	if filter.AccountID != "" {
+		if _, err := uuid.Parse(filter.AccountID); err != nil {
+			return nil, domain.NewValidationError("invalid_uuid", "%s is not a valid uuid", filter.AccountID)
+		}
+		query = query.Where("uuid IN (?)", p.ORM.WithContext(ctx).Model(&domain.AccountEntry{}).Select("transaction_id").Where("account_id = ?", filter.AccountID))
+	}
+	if filter.From != nil {
+		query = query.Where("transactions.created_at >= ?", *filter.From)
+	}
+	if filter.To != nil {
+		query = query.Where("transactions.created_at <= ?", *filter.To)
+	}
+
+	var transactions []*domain.Transaction
+	if err := afterCursor(query, "transactions", filter.After).Limit(filter.Limit).Find(&transactions).Error; err != nil {
+		return nil, fmt.Errorf("unable to get transactions: %v", err)
+	}
+
+	return transactions, nil
+}
+
+// TransactionsByID retrieves the transactions with the given IDs
+func (p PostgreSQL) TransactionsByID(ctx context.Context, transactionIDs []string) ([]*domain.Transaction, error) {
+	var transactions []*domain.Transaction
+	if err := p.ORM.WithContext(ctx).Where("uuid IN ?", validIDs(transactionIDs)).Find(&transactions).Error; err != nil {
+		return nil, fmt.Errorf("unable to get transactions: %v", err)
+	}
+
+	return transactions, nil
+}
+
+// Entries lists the account entries matching a filter, oldest first
+func (p PostgreSQL) Entries(ctx context.Context, filter application.EntryFilter) ([]*domain.AccountEntry, error) {
+	query := p.ORM.WithContext(ctx).Model(&domain.AccountEntry{})
+	if filter.AccountID != "" {
+		query = query.Where("account_id = ?", filter.AccountID)
+	}
+	if filter.TransactionID != "" {
+		query = query.Where("transaction_id = ?", filter.TransactionID)
+	}
+	if filter.From != nil {
+		query = query.Where(fmt.Sprintf("%s >= ?", entryDate), *filter.From)
+	}
+	if filter.To != nil {
+		query = query.Where(fmt.Sprintf("%s <= ?", entryDate), *filter.To)
+	}
+
+	var entries []*domain.AccountEntry
+	if err := afterCursor(query, "account_entries", filter.After).Limit(filter.Limit).Find(&entries).Error; err != nil {
+		return nil, fmt.Errorf("unable to get account entries: %v", err)
+	}
+
+	return entries, nil
+}
+
+// EntriesByTransaction retrieves the entries posted by the given transactions
+func (p PostgreSQL) EntriesByTransaction(ctx context.Context, transactionIDs []string) ([]*domain.AccountEntry, error) {
+	var entries []*domain.AccountEntry
+	if err := p.ORM.WithContext(ctx).Where("transaction_id IN ?", validIDs(transactionIDs)).
+		Order("account_entries.created_at, account_entries.uuid").
+		Find(&entries).Error; err != nil {
+		return nil, fmt.Errorf("unable to get account entries: %v", err)
+	}
+
+	return entries, nil
+}
@@ -0,0 +1,121 @@
+package postgresql
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/application"
+	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/domain"
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+)
+
+// entryDate is the date an entry counts towards; entries posted before effective dates were recorded fall back to their creation date
+var entryDate = "COALESCE(account_entries.effective_date, account_entries.created_at)"
+
+// TrialBalance aggregates the debits and credits of every account as of a given date
+func (p PostgreSQL) TrialBalance(ctx context.Context, asOf time.Time) ([]*application.TrialBalanceLine, error) {
+	return trialBalance(p.ORM.WithContext(ctx), asOf)
+}
+
+// trialBalance aggregates the debits and credits of every account as of a given date using the given connection
+func trialBalance(db *gorm.DB, asOf time.Time) ([]*application.TrialBalanceLine, error) {
+	var lines []*application.TrialBalanceLine
+	query := fmt.Sprintf(`
+		SELECT accounts.uuid AS account_id, accounts.number AS account_number, accounts.name AS account_name,
+			accounts.gl_code, accounts.header, accounts.currency, accounts.balance_type,
+			COALESCE(SUM(account_entries.debit_amount::numeric), 0) AS total_debit,
+			COALESCE(SUM(account_entries.credit_amount::numeric), 0) AS total_credit
+		FROM accounts
+		LEFT JOIN account_entries ON account_entries.account_id = accounts.uuid
+			AND account_entries.deleted_at IS NULL
+			AND %s <= ?
+		WHERE accounts.deleted_at IS NULL
+		GROUP BY accounts.uuid, accounts.number, accounts.name, accounts.gl_code,
+			accounts.header, accounts.currency, accounts.balance_type
+		ORDER BY accounts.currency, accounts.gl_code, accounts.number`, entryDate)
+	if err := db.Raw(query, asOf).Scan(&lines).Error; err != nil {
+		return nil, fmt.Errorf("unable to compute the trial balance: %v", err)
+	}
+
+	for _, line := range lines {
+		account := domain.Account{BalanceType: line.BalanceType}
+		line.Balance = account.ComputeBalance(line.TotalDebit, line.TotalCredit)
+	}
+
+	return lines, nil
+}
+
+// AccountEntries lists the entries posted to an account within a period, oldest first
+func (p PostgreSQL) AccountEntries(ctx context.Context, accountID string, from time.Time, to time.Time) ([]*domain.AccountEntry, error) {
+	if _, err := uuid.Parse(accountID); err != nil {
+		return nil, domain.NewValidationError("invalid_uuid", "%s is not a valid uuid", accountID)
+	}
+
+	var entries []*domain.AccountEntry
+	if err := p.ORM.WithContext(ctx).Preload("Transaction").
+		Where("account_id = ?", accountID).
+		Where(fmt.Sprintf("%s >= ? AND %s <= ?", entryDate, entryDate), from, to).
+		Order(fmt.Sprintf("%s, account_entries.created_at", entryDate)).
+		Find(&entries).Error; err != nil {
+		return nil, fmt.Errorf("unable to get the account's entries: %v", err)
+	}
+
+	return entries, nil
+}
+
+// AccountBalanceAsOf computes the balance of an account from the entries effective up to a given date
+func (p PostgreSQL) AccountBalanceAsOf(ctx context.Context, account *domain.Account, asOf time.Time) (*decimal.Decimal, error) {
+	if account == nil {
+		return nil, domain.NewValidationError("missing_account", "account has not been supplied")
+	}
+
+	var totals struct {
+		TotalDebit  decimal.Decimal
+		TotalCredit decimal.Decimal
+	}
+	query := fmt.Sprintf(`
+		SELECT COALESCE(SUM(debit_amount::numeric), 0) AS total_debit, COALESCE(SUM(credit_amount::numeric), 0) AS total_credit
+		FROM account_entries
+		WHERE account_id = ? AND deleted_at IS NULL AND %s <= ?`, entryDate)
+	if err := p.ORM.WithContext(ctx).Raw(query, account.UUID, asOf).Scan(&totals).Error; err != nil {
+		return nil, fmt.Errorf("unable to get the account's balance as of %v: %v", asOf, err)
+	}
+
+	balance := account.ComputeBalance(totals.TotalDebit, totals.TotalCredit)
+	return &balance, nil
+}
+
+// LedgerTotals aggregates all the debits and credits ever posted
+func (p PostgreSQL) LedgerTotals(ctx context.Context) (*decimal.Decimal, *decimal.Decimal, error) {
+	var totals struct {
+		TotalDebit  decimal.Decimal
+		TotalCredit decimal.Decimal
+	}
+	if err := p.ORM.WithContext(ctx).Raw(`
+		SELECT COALESCE(SUM(debit_amount::numeric), 0) AS total_debit, COALESCE(SUM(credit_amount::numeric), 0) AS total_credit
+		FROM account_entries
+		WHERE deleted_at IS NULL`).Scan(&totals).Error; err != nil {
+		return nil, nil, fmt.Errorf("unable to get the ledger's totals: %v", err)
+	}
+
+	return &totals.TotalDebit, &totals.TotalCredit, nil
+}
+
+// UnbalancedTransactions lists transactions whose entries do not observe double entry
+func (p PostgreSQL) UnbalancedTransactions(ctx context.Context) ([]string, error) {
+	var transactions []string
+	if err := p.ORM.WithContext(ctx).Raw(`
+		SELECT transaction_id
+		FROM account_entries
+		WHERE deleted_at IS NULL
+		GROUP BY transaction_id
+		HAVING COALESCE(SUM(debit_amount::numeric), 0) <> COALESCE(SUM(credit_amount::numeric), 0)
+		ORDER BY transaction_id`).Scan(&transactions).Error; err != nil {
+		return nil, fmt.Errorf("unable to check the ledger's transactions: %v", err)
+	}
+
+	return transactions, nil
+}
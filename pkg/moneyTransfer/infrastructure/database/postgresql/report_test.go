@@ -0,0 +1,170 @@
+package postgresql_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/domain"
+	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/infrastructure/database/postgresql"
+	"github.com/brianvoe/gofakeit"
+	"github.com/shopspring/decimal"
+)
+
+// postTransfer moves an amount between two new accounts, returning the accounts credited and debited
+func postTransfer(t *testing.T, p *postgresql.PostgreSQL, amount decimal.Decimal) (*domain.Account, *domain.Account) {
+	var accounts []*domain.Account
+	for i := 0; i < 2; i++ {
+		created, err := p.CreateAccount(context.Background(), &domain.Account{
+			Name:        gofakeit.Name(),
+			Currency:    domain.Kenyan,
+			Header:      domain.Deposit,
+			BalanceType: domain.Credit,
+		})
+		if err != nil {
+			t.Fatalf("unable to create an account: %v", err)
+		}
+		accounts = append(accounts, &domain.Account{AbstractBase: domain.AbstractBase{UUID: created.UUID}, BalanceType: domain.Credit})
+	}
+
+	credited, debited := accounts[0], accounts[1]
+	if _, err := p.CreateTransaction(context.Background(), "report test transfer",
+		&domain.AccountEntry{DebitAmount: amount, AccountID: debited.UUID},
+		&domain.AccountEntry{CreditAmount: amount, AccountID: credited.UUID},
+	); err != nil {
+		t.Fatalf("unable to post a transfer: %v", err)
+	}
+	return credited, debited
+}
+
+func TestPostgreSQL_TrialBalance(t *testing.T) {
+	p := newTestPostgreSQL()
+	amount := decimal.RequireFromString("25.50")
+	credited, debited := postTransfer(t, p, amount)
+
+	lines, err := p.TrialBalance(context.Background(), time.Now())
+	if err != nil {
+		t.Fatalf("PostgreSQL.TrialBalance() error = %v", err)
+	}
+
+	found := 0
+	for _, line := range lines {
+		switch line.AccountID {
+		case credited.UUID:
+			found++
+			if !line.TotalCredit.Equal(amount) || !line.TotalDebit.IsZero() {
+				t.Errorf("expected the credited account to total %v in credits, got %+v", amount, line)
+			}
+		case debited.UUID:
+			found++
+			if !line.TotalDebit.Equal(amount) || !line.TotalCredit.IsZero() {
+				t.Errorf("expected the debited account to total %v in debits, got %+v", amount, line)
+			}
+		}
+	}
+	if found != 2 {
+		t.Errorf("expected both accounts in the trial balance, found %d", found)
+	}
+}
+
+func TestPostgreSQL_AccountBalanceAsOf(t *testing.T) {
+	p := newTestPostgreSQL()
+	amount := decimal.RequireFromString("40.25")
+	credited, debited := postTransfer(t, p, amount)
+
+	tests := []struct {
+		name    string
+		account *domain.Account
+		asOf    time.Time
+		want    decimal.Decimal
+		wantErr bool
+	}{
+		{name: "happy case - credited account", account: credited, asOf: time.Now(), want: amount.Neg()},
+		{name: "happy case - debited account", account: debited, asOf: time.Now(), want: amount},
+		{name: "happy case - before the transfer", account: debited, asOf: time.Now().AddDate(-1, 0, 0), want: decimal.Zero},
+		{name: "sad case - no account", asOf: time.Now(), wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			balance, err := p.AccountBalanceAsOf(context.Background(), tt.account, tt.asOf)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("PostgreSQL.AccountBalanceAsOf() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !balance.Equal(tt.want) {
+				t.Errorf("PostgreSQL.AccountBalanceAsOf() = %v, want %v", balance, tt.want)
+			}
+		})
+	}
+}
+
+func TestPostgreSQL_LedgerTotals(t *testing.T) {
+	p := newTestPostgreSQL()
+	amount := decimal.RequireFromString("12.75")
+	postTransfer(t, p, amount)
+
+	debits, credits, err := p.LedgerTotals(context.Background())
+	if err != nil {
+		t.Fatalf("PostgreSQL.LedgerTotals() error = %v", err)
+	}
+	if debits.LessThan(amount) || credits.LessThan(amount) {
+		t.Errorf("expected the totals to include the posted transfer, got %v DR and %v CR", debits, credits)
+	}
+}
+
+func TestPostgreSQL_UnbalancedTransactions(t *testing.T) {
+	p := newTestPostgreSQL()
+	credited, _ := postTransfer(t, p, decimal.NewFromInt(5))
+
+	// A single sided transaction can only be written past CreateTransaction's checks, it is rolled back so that
+	// other tests checking the ledger never see it
+	tx := p.ORM.Begin()
+	defer tx.Rollback()
+	unbalanced := domain.Transaction{Description: "unbalanced report test transaction"}
+	if err := tx.Create(&unbalanced).Error; err != nil {
+		t.Fatalf("unable to create a transaction: %v", err)
+	}
+	entry := domain.AccountEntry{CreditAmount: decimal.NewFromInt(5), AccountID: credited.UUID, TransactionID: unbalanced.UUID}
+	if err := tx.Omit("Account", "Transaction").Create(&entry).Error; err != nil {
+		t.Fatalf("unable to create an entry: %v", err)
+	}
+
+	transactions, err := postgresql.NewPostgreSQLDatabase(tx, nil).UnbalancedTransactions(context.Background())
+	if err != nil {
+		t.Fatalf("PostgreSQL.UnbalancedTransactions() error = %v", err)
+	}
+	for _, transaction := range transactions {
+		if transaction == unbalanced.UUID {
+			return
+		}
+	}
+	t.Errorf("expected transaction %s to be reported unbalanced, got %v", unbalanced.UUID, transactions)
+}
+
+func TestPostgreSQL_AccountBalance_ExactSums(t *testing.T) {
+	p := newTestPostgreSQL()
+	credited, debited := postTransfer(t, p, decimal.RequireFromString("0.10"))
+	if _, err := p.CreateTransaction(context.Background(), "report test transfer",
+		&domain.AccountEntry{DebitAmount: decimal.RequireFromString("0.20"), AccountID: debited.UUID},
+		&domain.AccountEntry{CreditAmount: decimal.RequireFromString("0.20"), AccountID: credited.UUID},
+	); err != nil {
+		t.Fatalf("unable to post a transfer: %v", err)
+	}
+
+	// Summed as floats, 0.10 and 0.20 come to 0.30000000000000004
+	want := decimal.RequireFromString("0.30")
+	balance, err := p.AccountBalance(context.Background(), debited)
+	if err != nil {
+		t.Fatalf("PostgreSQL.AccountBalance() error = %v", err)
+	}
+	if !balance.Equal(want) {
+		t.Errorf("PostgreSQL.AccountBalance() = %v, want %v", balance, want)
+	}
+
+	asOf, err := p.AccountBalanceAsOf(context.Background(), debited, time.Now())
+	if err != nil {
+		t.Fatalf("PostgreSQL.AccountBalanceAsOf() error = %v", err)
+	}
+	if !asOf.Equal(*balance) {
+		t.Errorf("expected the balance and the balance as of now to agree, got %v and %v", balance, asOf)
+	}
+}
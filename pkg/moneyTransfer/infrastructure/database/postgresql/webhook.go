@@ -0,0 +1,166 @@
+package postgresql
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/domain"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// CreateSubscription registers a webhook subscription
+func (p PostgreSQL) CreateSubscription(ctx context.Context, subscription *domain.WebhookSubscription) (*domain.WebhookSubscription, error) {
+	if subscription == nil {
+		return nil, domain.NewValidationError("missing_subscription", "missing webhook subscription information")
+	}
+
+	if err := p.ORM.WithContext(ctx).Create(subscription).Error; err != nil {
+		return nil, fmt.Errorf("unable to create webhook subscription: %v", err)
+	}
+
+	return subscription, nil
+}
+
+// Subscription retrieves a webhook subscription given it's ID(UUID)
+func (p PostgreSQL) Subscription(ctx context.Context, subscriptionID string) (*domain.WebhookSubscription, error) {
+	var subscription domain.WebhookSubscription
+
+	filter := domain.WebhookSubscription{
+		AbstractBase: domain.AbstractBase{
+			UUID: subscriptionID,
+		},
+	}
+	if err := p.ORM.WithContext(ctx).Where(&filter).First(&subscription).Error; err != nil {
+		return nil, lookupError(err, "subscription_not_found", "webhook subscription %s", subscriptionID)
+	}
+
+	return &subscription, nil
+}
+
+// Subscriptions retrieves the webhook subscriptions of an owner
+func (p PostgreSQL) Subscriptions(ctx context.Context, owner string) ([]*domain.WebhookSubscription, error) {
+	var subscriptions []*domain.WebhookSubscription
+	if err := p.ORM.WithContext(ctx).Where(&domain.WebhookSubscription{Owner: owner}).Order("created_at").Find(&subscriptions).Error; err != nil {
+		return nil, fmt.Errorf("unable to get webhook subscriptions: %v", err)
+	}
+
+	return subscriptions, nil
+}
+
+// DeleteSubscription removes a webhook subscription, its pending deliveries are no longer attempted
+func (p PostgreSQL) DeleteSubscription(ctx context.Context, subscriptionID string) error {
+	if err := p.ORM.WithContext(ctx).Delete(&domain.WebhookSubscription{}, "uuid = ?", subscriptionID).Error; err != nil {
+		return fmt.Errorf("unable to delete webhook subscription %s: %v", subscriptionID, err)
+	}
+
+	return nil
+}
+
+// SubscriptionsFor retrieves the active subscriptions to an event type about any of the accounts
+func (p PostgreSQL) SubscriptionsFor(ctx context.Context, eventType domain.EventType, accountIDs []string) ([]*domain.WebhookSubscription, error) {
+	var subscriptions []*domain.WebhookSubscription
+	if err := p.ORM.WithContext(ctx).Where("active = ?", true).Find(&subscriptions).Error; err != nil {
+		return nil, fmt.Errorf("unable to get webhook subscriptions: %v", err)
+	}
+
+	var subscribed []*domain.WebhookSubscription
+	for _, subscription := range subscriptions {
+		if subscription.Subscribes(eventType) && subscription.Covers(accountIDs) {
+			subscribed = append(subscribed, subscription)
+		}
+	}
+
+	return subscribed, nil
+}
+
+// CreateDeliveries queues webhook deliveries, ignoring events already queued for a subscription
+func (p PostgreSQL) CreateDeliveries(ctx context.Context, deliveries []*domain.WebhookDelivery) error {
+	if len(deliveries) == 0 {
+		return nil
+	}
+
+	if err := p.ORM.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(&deliveries).Error; err != nil {
+		return fmt.Errorf("unable to queue webhook deliveries: %v", err)
+	}
+
+	return nil
+}
+
+// Delivery retrieves a webhook delivery given it's ID(UUID)
+func (p PostgreSQL) Delivery(ctx context.Context, deliveryID string) (*domain.WebhookDelivery, error) {
+	var delivery domain.WebhookDelivery
+
+	filter := domain.WebhookDelivery{
+		AbstractBase: domain.AbstractBase{
+			UUID: deliveryID,
+		},
+	}
+	if err := p.ORM.WithContext(ctx).Where(&filter).First(&delivery).Error; err != nil {
+		return nil, lookupError(err, "delivery_not_found", "webhook delivery %s", deliveryID)
+	}
+
+	return &delivery, nil
+}
+
+// Deliveries retrieves a subscription's webhook deliveries with a given status
+func (p PostgreSQL) Deliveries(ctx context.Context, subscriptionID string, status domain.DeliveryStatus) ([]*domain.WebhookDelivery, error) {
+	var deliveries []*domain.WebhookDelivery
+
+	filter := domain.WebhookDelivery{
+		SubscriptionID: subscriptionID,
+		Status:         status,
+	}
+	if err := p.ORM.WithContext(ctx).Where(&filter).Order("created_at").Find(&deliveries).Error; err != nil {
+		return nil, fmt.Errorf("unable to get webhook deliveries: %v", err)
+	}
+
+	return deliveries, nil
+}
+
+// ClaimDueDeliveries retrieves pending deliveries whose next attempt is due, oldest first, and pushes their next
+// attempt back by the lease so that workers on other replicas skip them while they are being posted
+func (p PostgreSQL) ClaimDueDeliveries(ctx context.Context, asOf time.Time, lease time.Duration, limit int) ([]*domain.WebhookDelivery, error) {
+	var deliveries []*domain.WebhookDelivery
+	err := p.ORM.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("status = ? AND next_attempt_at <= ?", domain.DeliveryPending, asOf).
+			Order("next_attempt_at").
+			Limit(limit).
+			Find(&deliveries).Error; err != nil {
+			return err
+		}
+		if len(deliveries) == 0 {
+			return nil
+		}
+
+		deliveryIDs := make([]string, 0, len(deliveries))
+		leasedUntil := asOf.Add(lease)
+		for _, delivery := range deliveries {
+			deliveryIDs = append(deliveryIDs, delivery.UUID)
+			delivery.NextAttemptAt = leasedUntil
+		}
+		return tx.Model(&domain.WebhookDelivery{}).
+			Where("uuid IN ?", deliveryIDs).
+			Update("next_attempt_at", leasedUntil).Error
+	})
+	if err != nil {
+		return nil, fmt.Errorf("unable to claim due webhook deliveries: %v", err)
+	}
+
+	return deliveries, nil
+}
+
+// UpdateDelivery saves the outcome of a webhook delivery attempt
+func (p PostgreSQL) UpdateDelivery(ctx context.Context, delivery *domain.WebhookDelivery) error {
+	if delivery == nil {
+		return domain.NewValidationError("missing_delivery", "missing webhook delivery information")
+	}
+
+	if err := p.ORM.WithContext(ctx).Save(delivery).Error; err != nil {
+		return fmt.Errorf("unable to update webhook delivery %s: %v", delivery.UUID, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,244 @@
+package iso20022
+
+import (
+	"encoding/xml"
+	"fmt"
+
+	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/application"
+)
+
+// CAMT053_NAMESPACE is the namespace of the camt.053 version generated
+var CAMT053_NAMESPACE = "urn:iso:std:iso:20022:tech:xsd:camt.053.001.02"
+
+// Camt053Document is a BankToCustomerStatement (camt.053) message
+type Camt053Document struct {
+	XMLName   xml.Name                `xml:"Document"`
+	Namespace string                  `xml:"xmlns,attr"`
+	Statement BankToCustomerStatement `xml:"BkToCstmrStmt"`
+}
+
+// BankToCustomerStatement holds the statement of one account
+type BankToCustomerStatement struct {
+	GroupHeader GroupHeader      `xml:"GrpHdr"`
+	Statement   AccountStatement `xml:"Stmt"`
+}
+
+// GroupHeader identifies a message
+type GroupHeader struct {
+	MessageID        string `xml:"MsgId"`
+	CreationDateTime string `xml:"CreDtTm"`
+}
+
+// AccountStatement is the statement of an account over a period
+type AccountStatement struct {
+	ID               string            `xml:"Id"`
+	CreationDateTime string            `xml:"CreDtTm"`
+	Period           FromToDate        `xml:"FrToDt"`
+	Account          CashAccount       `xml:"Acct"`
+	Balances         []CashBalance     `xml:"Bal"`
+	Summary          TotalTransactions `xml:"TxsSummry"`
+	Entries          []ReportEntry     `xml:"Ntry"`
+}
+
+// FromToDate is the period a statement covers
+type FromToDate struct {
+	From string `xml:"FrDtTm"`
+	To   string `xml:"ToDtTm"`
+}
+
+// CashAccount identifies an account by its number
+type CashAccount struct {
+	ID       AccountIdentification `xml:"Id"`
+	Currency string                `xml:"Ccy,omitempty"`
+	Name     string                `xml:"Nm,omitempty"`
+}
+
+// AccountIdentification identifies an account with a proprietary (non IBAN) identifier
+type AccountIdentification struct {
+	Other GenericIdentification `xml:"Othr"`
+}
+
+// GenericIdentification is a proprietary identifier
+type GenericIdentification struct {
+	ID string `xml:"Id"`
+}
+
+// CashBalance is an opening or closing balance
+type CashBalance struct {
+	Type                 BalanceType `xml:"Tp"`
+	Amount               Amount      `xml:"Amt"`
+	CreditDebitIndicator string      `xml:"CdtDbtInd"`
+	Date                 DateChoice  `xml:"Dt"`
+}
+
+// BalanceType names a balance e.g OPBD (opening booked) or CLBD (closing booked)
+type BalanceType struct {
+	CodeOrProprietary CodeOrProprietary `xml:"CdOrPrtry"`
+}
+
+// CodeOrProprietary holds an ISO code
+type CodeOrProprietary struct {
+	Code string `xml:"Cd"`
+}
+
+// DateChoice holds either a date or a date time
+type DateChoice struct {
+	Date     string `xml:"Dt,omitempty"`
+	DateTime string `xml:"DtTm,omitempty"`
+}
+
+// TotalTransactions summarizes the entries of a statement
+type TotalTransactions struct {
+	TotalEntries       NumberAndSumOfTransactions `xml:"TtlNtries"`
+	TotalCreditEntries NumberAndSum               `xml:"TtlCdtNtries"`
+	TotalDebitEntries  NumberAndSum               `xml:"TtlDbtNtries"`
+}
+
+// NumberAndSumOfTransactions counts entries and nets their amounts
+type NumberAndSumOfTransactions struct {
+	NumberOfEntries int                `xml:"NbOfNtries"`
+	Sum             string             `xml:"Sum"`
+	TotalNetEntry   AmountAndDirection `xml:"TtlNetNtry"`
+}
+
+// AmountAndDirection is an unsigned amount with its credit/debit indicator
+type AmountAndDirection struct {
+	Amount               string `xml:"Amt"`
+	CreditDebitIndicator string `xml:"CdtDbtInd"`
+}
+
+// NumberAndSum counts entries and sums their amounts
+type NumberAndSum struct {
+	NumberOfEntries int    `xml:"NbOfNtries"`
+	Sum             string `xml:"Sum"`
+}
+
+// ReportEntry is a single booked entry of a statement
+type ReportEntry struct {
+	Reference            string              `xml:"NtryRef"`
+	Amount               Amount              `xml:"Amt"`
+	CreditDebitIndicator string              `xml:"CdtDbtInd"`
+	Status               string              `xml:"Sts"`
+	BookingDate          DateChoice          `xml:"BookgDt"`
+	ValueDate            DateChoice          `xml:"ValDt"`
+	AccountServicerRef   string              `xml:"AcctSvcrRef"`
+	BankTransactionCode  BankTransactionCode `xml:"BkTxCd"`
+	Details              EntryDetails        `xml:"NtryDtls"`
+}
+
+// BankTransactionCode classifies an entry
+type BankTransactionCode struct {
+	Proprietary ProprietaryCode `xml:"Prtry"`
+}
+
+// ProprietaryCode is a bank specific code
+type ProprietaryCode struct {
+	Code string `xml:"Cd"`
+}
+
+// EntryDetails holds the details of the transaction behind an entry
+type EntryDetails struct {
+	TransactionDetails TransactionDetails `xml:"TxDtls"`
+}
+
+// TransactionDetails references a transaction and describes it
+type TransactionDetails struct {
+	References            TransactionReferences `xml:"Refs"`
+	RemittanceInformation RemittanceInformation `xml:"RmtInf"`
+}
+
+// TransactionReferences references the transaction an entry belongs to
+type TransactionReferences struct {
+	TransactionID string `xml:"TxId"`
+}
+
+// RemittanceInformation is free text describing a payment
+type RemittanceInformation struct {
+	Unstructured string `xml:"Ustrd"`
+}
+
+// NewCamt053 maps an account statement to a camt.053 message
+func NewCamt053(statement *application.Statement) *Camt053Document {
+	account := statement.Account
+	currency := account.Currency
+	created := statement.GeneratedAt.Format(ISODateTime)
+	statementID := fmt.Sprintf("%s-%s-%s", account.Number, statement.From.Format("20060102"), statement.To.Format("20060102"))
+
+	var netTotal = statement.ClosingBalance.Sub(statement.OpeningBalance)
+	var credits, debits int
+	var entries []ReportEntry
+	for _, line := range statement.Lines {
+		if line.Amount.IsNegative() {
+			debits++
+		} else {
+			credits++
+		}
+		entries = append(entries, ReportEntry{
+			Reference:            line.EntryID,
+			Amount:               NewAmount(line.Amount, currency),
+			CreditDebitIndicator: CreditDebitIndicator(line.Amount),
+			Status:               "BOOK",
+			BookingDate:          DateChoice{DateTime: line.BookingDate.Format(ISODateTime)},
+			ValueDate:            DateChoice{Date: line.BookingDate.Format(ISODate)},
+			AccountServicerRef:   line.TransactionID,
+			BankTransactionCode:  BankTransactionCode{Proprietary: ProprietaryCode{Code: "NTRF"}},
+			Details: EntryDetails{
+				TransactionDetails: TransactionDetails{
+					References:            TransactionReferences{TransactionID: line.TransactionID},
+					RemittanceInformation: RemittanceInformation{Unstructured: line.Description},
+				},
+			},
+		})
+	}
+
+	minorUnits := currency.MinorUnits()
+	return &Camt053Document{
+		Namespace: CAMT053_NAMESPACE,
+		Statement: BankToCustomerStatement{
+			GroupHeader: GroupHeader{
+				MessageID:        statementID,
+				CreationDateTime: created,
+			},
+			Statement: AccountStatement{
+				ID:               statementID,
+				CreationDateTime: created,
+				Period: FromToDate{
+					From: statement.From.Format(ISODateTime),
+					To:   statement.To.Format(ISODateTime),
+				},
+				Account: CashAccount{
+					ID:       AccountIdentification{Other: GenericIdentification{ID: account.Number}},
+					Currency: currency.ISOCode(),
+					Name:     account.Name,
+				},
+				Balances: []CashBalance{
+					{
+						Type:                 BalanceType{CodeOrProprietary: CodeOrProprietary{Code: "OPBD"}},
+						Amount:               NewAmount(statement.OpeningBalance, currency),
+						CreditDebitIndicator: CreditDebitIndicator(statement.OpeningBalance),
+						Date:                 DateChoice{Date: statement.From.Format(ISODate)},
+					},
+					{
+						Type:                 BalanceType{CodeOrProprietary: CodeOrProprietary{Code: "CLBD"}},
+						Amount:               NewAmount(statement.ClosingBalance, currency),
+						CreditDebitIndicator: CreditDebitIndicator(statement.ClosingBalance),
+						Date:                 DateChoice{Date: statement.To.Format(ISODate)},
+					},
+				},
+				Summary: TotalTransactions{
+					TotalEntries: NumberAndSumOfTransactions{
+						NumberOfEntries: len(statement.Lines),
+						Sum:             statement.TotalIn.Add(statement.TotalOut).StringFixed(minorUnits),
+						TotalNetEntry: AmountAndDirection{
+							Amount:               netTotal.Abs().StringFixed(minorUnits),
+							CreditDebitIndicator: CreditDebitIndicator(netTotal),
+						},
+					},
+					TotalCreditEntries: NumberAndSum{NumberOfEntries: credits, Sum: statement.TotalIn.StringFixed(minorUnits)},
+					TotalDebitEntries:  NumberAndSum{NumberOfEntries: debits, Sum: statement.TotalOut.StringFixed(minorUnits)},
+				},
+				Entries: entries,
+			},
+		},
+	}
+}
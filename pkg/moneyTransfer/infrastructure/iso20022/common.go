@@ -0,0 +1,53 @@
+package iso20022
+
+import (
+	"encoding/xml"
+	"fmt"
+
+	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/domain"
+	"github.com/shopspring/decimal"
+)
+
+// ISO 20022 dates and date times as they appear in messages
+var (
+	ISODate     = "2006-01-02"
+	ISODateTime = "2006-01-02T15:04:05.000Z07:00"
+)
+
+// Credit and debit indicators
+var (
+	Credit = "CRDT"
+	Debit  = "DBIT"
+)
+
+// Amount is an ISO 20022 ActiveOrHistoricCurrencyAndAmount
+type Amount struct {
+	Currency string `xml:"Ccy,attr"`
+	Value    string `xml:",chardata"`
+}
+
+// NewAmount formats an absolute amount in the minor units of its currency
+func NewAmount(amount decimal.Decimal, currency domain.CurrencyType) Amount {
+	return Amount{
+		Currency: currency.ISOCode(),
+		Value:    amount.Abs().StringFixed(currency.MinorUnits()),
+	}
+}
+
+// CreditDebitIndicator returns CRDT for money in (or zero) and DBIT for money out
+func CreditDebitIndicator(amount decimal.Decimal) string {
+	if amount.IsNegative() {
+		return Debit
+	}
+	return Credit
+}
+
+// Marshal encodes an ISO 20022 document with an XML declaration
+func Marshal(document interface{}) ([]byte, error) {
+	content, err := xml.MarshalIndent(document, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("unable to encode ISO 20022 message: %v", err)
+	}
+
+	return append([]byte(xml.Header), content...), nil
+}
@@ -0,0 +1,196 @@
+package iso20022
+
+import (
+	"encoding/xml"
+	"fmt"
+	"strconv"
+
+	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/application"
+	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/domain"
+	"github.com/shopspring/decimal"
+)
+
+// PAIN001_MESSAGE_NAME identifies the payment initiation messages accepted
+var PAIN001_MESSAGE_NAME = "pain.001.001.03"
+
+// Group status reason codes reported when a whole pain.001 message is rejected
+var (
+	// InvalidFileFormat is reported for messages that can not be decoded
+	InvalidFileFormat = "FF01"
+
+	// InvalidNumberOfTransactions is reported when NbOfTxs does not match the instructions
+	InvalidNumberOfTransactions = "AM18"
+
+	// InvalidControlSum is reported when CtrlSum does not match the instructed amounts
+	InvalidControlSum = "AM10"
+)
+
+// Pain001Document is a CustomerCreditTransferInitiation (pain.001) message.
+// Elements are matched by local name so that both version 03 and 09 messages can be read
+type Pain001Document struct {
+	XMLName    xml.Name                         `xml:"Document"`
+	Initiation CustomerCreditTransferInitiation `xml:"CstmrCdtTrfInitn"`
+}
+
+// CustomerCreditTransferInitiation holds the payment instructions of a message
+type CustomerCreditTransferInitiation struct {
+	GroupHeader        InitiationGroupHeader `xml:"GrpHdr"`
+	PaymentInformation []PaymentInformation  `xml:"PmtInf"`
+}
+
+// InitiationGroupHeader identifies a pain.001 message and summarizes its instructions
+type InitiationGroupHeader struct {
+	MessageID            string `xml:"MsgId"`
+	CreationDateTime     string `xml:"CreDtTm"`
+	NumberOfTransactions string `xml:"NbOfTxs"`
+	ControlSum           string `xml:"CtrlSum"`
+	InitiatingParty      string `xml:"InitgPty>Nm"`
+}
+
+// PaymentInformation groups the credit transfers debited from one account
+type PaymentInformation struct {
+	PaymentInformationID string                      `xml:"PmtInfId"`
+	PaymentMethod        string                      `xml:"PmtMtd"`
+	Debtor               string                      `xml:"Dbtr>Nm"`
+	DebtorAccount        InitiationCashAccount       `xml:"DbtrAcct"`
+	Transactions         []CreditTransferTransaction `xml:"CdtTrfTxInf"`
+}
+
+// InitiationCashAccount identifies an account by IBAN or by a proprietary identifier
+type InitiationCashAccount struct {
+	IBAN     string `xml:"Id>IBAN"`
+	Other    string `xml:"Id>Othr>Id"`
+	Currency string `xml:"Ccy"`
+}
+
+// Number returns the account number an account is identified by
+func (a InitiationCashAccount) Number() string {
+	if a.Other != "" {
+		return a.Other
+	}
+	return a.IBAN
+}
+
+// CreditTransferTransaction is a single credit transfer instruction
+type CreditTransferTransaction struct {
+	InstructionID         string                `xml:"PmtId>InstrId"`
+	EndToEndID            string                `xml:"PmtId>EndToEndId"`
+	InstructedAmount      Amount                `xml:"Amt>InstdAmt"`
+	Creditor              string                `xml:"Cdtr>Nm"`
+	CreditorAccount       InitiationCashAccount `xml:"CdtrAcct"`
+	RemittanceInformation string                `xml:"RmtInf>Ustrd"`
+}
+
+// GroupError is a pain.001 message level validation failure with its ISO reason code
+type GroupError struct {
+	ReasonCode string
+	Reason     string
+}
+
+func (e GroupError) Error() string {
+	return fmt.Sprintf("%s: %s", e.ReasonCode, e.Reason)
+}
+
+// currencyFromISO maps an ISO 4217 code to the currency used by accounts
+func currencyFromISO(code string) domain.CurrencyType {
+	if code == domain.Kenyan.ISOCode() {
+		return domain.Kenyan
+	}
+	return domain.CurrencyType(code)
+}
+
+// ParsePain001 decodes a pain.001 message and validates its group header against its instructions.
+// The returned error is a GroupError when the message as a whole should be rejected
+func ParsePain001(content []byte) (*Pain001Document, error) {
+	var document Pain001Document
+	if err := xml.Unmarshal(content, &document); err != nil {
+		return nil, GroupError{ReasonCode: InvalidFileFormat, Reason: fmt.Sprintf("unable to decode pain.001 message: %v", err)}
+	}
+
+	if err := document.Validate(); err != nil {
+		return &document, err
+	}
+
+	return &document, nil
+}
+
+// Validate ensures the group header identifies the message and matches its instructions
+func (d Pain001Document) Validate() error {
+	header := d.Initiation.GroupHeader
+	if header.MessageID == "" {
+		return GroupError{ReasonCode: InvalidFileFormat, Reason: "the message has no MsgId"}
+	}
+
+	if len(d.Initiation.PaymentInformation) == 0 {
+		return GroupError{ReasonCode: InvalidFileFormat, Reason: "the message has no payment information"}
+	}
+
+	count := 0
+	sum := decimal.Zero
+	for _, payment := range d.Initiation.PaymentInformation {
+		if payment.PaymentMethod != "TRF" {
+			return GroupError{
+				ReasonCode: InvalidFileFormat,
+				Reason:     fmt.Sprintf("payment information %s uses unsupported payment method %q", payment.PaymentInformationID, payment.PaymentMethod),
+			}
+		}
+
+		for _, transaction := range payment.Transactions {
+			amount, err := decimal.NewFromString(transaction.InstructedAmount.Value)
+			if err != nil {
+				return GroupError{
+					ReasonCode: InvalidFileFormat,
+					Reason:     fmt.Sprintf("instruction %s has an invalid amount %q", transaction.EndToEndID, transaction.InstructedAmount.Value),
+				}
+			}
+			count++
+			sum = sum.Add(amount)
+		}
+	}
+
+	declaredCount, err := strconv.Atoi(header.NumberOfTransactions)
+	if err != nil || declaredCount != count {
+		return GroupError{
+			ReasonCode: InvalidNumberOfTransactions,
+			Reason:     fmt.Sprintf("NbOfTxs %q does not match the %d instructions in the message", header.NumberOfTransactions, count),
+		}
+	}
+
+	if header.ControlSum != "" {
+		controlSum, err := decimal.NewFromString(header.ControlSum)
+		if err != nil || !controlSum.Equal(sum) {
+			return GroupError{
+				ReasonCode: InvalidControlSum,
+				Reason:     fmt.Sprintf("CtrlSum %q does not match the instructed total of %v", header.ControlSum, sum),
+			}
+		}
+	}
+
+	return nil
+}
+
+// Instructions maps the message's credit transfers to internal transfer instructions
+func (d Pain001Document) Instructions() *application.PaymentInitiationInput {
+	input := application.PaymentInitiationInput{
+		MessageID: d.Initiation.GroupHeader.MessageID,
+	}
+
+	for _, payment := range d.Initiation.PaymentInformation {
+		for _, transaction := range payment.Transactions {
+			// Amounts were checked when the message was validated
+			amount, _ := decimal.NewFromString(transaction.InstructedAmount.Value)
+			input.Instructions = append(input.Instructions, &application.CreditTransferInstruction{
+				PaymentInformationID:  payment.PaymentInformationID,
+				InstructionID:         transaction.InstructionID,
+				EndToEndID:            transaction.EndToEndID,
+				DebtorAccountNumber:   payment.DebtorAccount.Number(),
+				CreditorAccountNumber: transaction.CreditorAccount.Number(),
+				Amount:                amount,
+				Currency:              currencyFromISO(transaction.InstructedAmount.Currency),
+				RemittanceInformation: transaction.RemittanceInformation,
+			})
+		}
+	}
+
+	return &input
+}
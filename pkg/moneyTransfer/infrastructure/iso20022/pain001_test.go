@@ -0,0 +1,234 @@
+package iso20022_test
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/application"
+	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/domain"
+	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/infrastructure/iso20022"
+	"github.com/shopspring/decimal"
+)
+
+func pain001Message(numberOfTransactions string, controlSum string) []byte {
+	return []byte(fmt.Sprintf(`<?xml version="1.0" encoding="UTF-8"?>
+<Document xmlns="urn:iso:std:iso:20022:tech:xsd:pain.001.001.03">
+  <CstmrCdtTrfInitn>
+    <GrpHdr>
+      <MsgId>MSG-1</MsgId>
+      <CreDtTm>2023-01-01T10:00:00</CreDtTm>
+      <NbOfTxs>%s</NbOfTxs>
+      <CtrlSum>%s</CtrlSum>
+      <InitgPty><Nm>Acme Ltd</Nm></InitgPty>
+    </GrpHdr>
+    <PmtInf>
+      <PmtInfId>PMT-1</PmtInfId>
+      <PmtMtd>TRF</PmtMtd>
+      <Dbtr><Nm>Acme Ltd</Nm></Dbtr>
+      <DbtrAcct><Id><Othr><Id>AC-0000000001</Id></Othr></Id></DbtrAcct>
+      <CdtTrfTxInf>
+        <PmtId><InstrId>INSTR-1</InstrId><EndToEndId>E2E-1</EndToEndId></PmtId>
+        <Amt><InstdAmt Ccy="KES">10.50</InstdAmt></Amt>
+        <Cdtr><Nm>John Doe</Nm></Cdtr>
+        <CdtrAcct><Id><Othr><Id>AC-0000000002</Id></Othr></Id></CdtrAcct>
+        <RmtInf><Ustrd>Invoice 1</Ustrd></RmtInf>
+      </CdtTrfTxInf>
+      <CdtTrfTxInf>
+        <PmtId><EndToEndId>E2E-2</EndToEndId></PmtId>
+        <Amt><InstdAmt Ccy="UGX">200</InstdAmt></Amt>
+        <CdtrAcct><Id><IBAN>AC-0000000003</IBAN></Id></CdtrAcct>
+      </CdtTrfTxInf>
+    </PmtInf>
+  </CstmrCdtTrfInitn>
+</Document>`, numberOfTransactions, controlSum))
+}
+
+func TestParsePain001(t *testing.T) {
+	tests := []struct {
+		name           string
+		content        []byte
+		wantReasonCode string
+	}{
+		{
+			name:    "valid message",
+			content: pain001Message("2", "210.50"),
+		},
+		{
+			name:    "valid message without a control sum",
+			content: pain001Message("2", ""),
+		},
+		{
+			name:           "invalid xml",
+			content:        []byte("<Document><CstmrCdtTrfInitn>"),
+			wantReasonCode: iso20022.InvalidFileFormat,
+		},
+		{
+			name:           "wrong number of transactions",
+			content:        pain001Message("3", "210.50"),
+			wantReasonCode: iso20022.InvalidNumberOfTransactions,
+		},
+		{
+			name:           "wrong control sum",
+			content:        pain001Message("2", "100"),
+			wantReasonCode: iso20022.InvalidControlSum,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			document, err := iso20022.ParsePain001(tt.content)
+			if tt.wantReasonCode == "" {
+				if err != nil {
+					t.Errorf("ParsePain001() error = %v", err)
+				}
+				return
+			}
+
+			var groupErr iso20022.GroupError
+			if !errors.As(err, &groupErr) {
+				t.Errorf("ParsePain001() expected a group error, got %v", err)
+				return
+			}
+			if groupErr.ReasonCode != tt.wantReasonCode {
+				t.Errorf("ParsePain001() reason code = %s, want %s", groupErr.ReasonCode, tt.wantReasonCode)
+			}
+			if tt.wantReasonCode != iso20022.InvalidFileFormat && document == nil {
+				t.Errorf("ParsePain001() expected the decoded message alongside the group error")
+			}
+		})
+	}
+}
+
+func TestPain001Document_Instructions(t *testing.T) {
+	document, err := iso20022.ParsePain001(pain001Message("2", "210.50"))
+	if err != nil {
+		t.Errorf("ParsePain001() error = %v", err)
+		return
+	}
+
+	input := document.Instructions()
+	if input.MessageID != "MSG-1" {
+		t.Errorf("expected message MSG-1, got %s", input.MessageID)
+	}
+	if len(input.Instructions) != 2 {
+		t.Errorf("expected 2 instructions, got %d", len(input.Instructions))
+		return
+	}
+
+	first := input.Instructions[0]
+	if first.DebtorAccountNumber != "AC-0000000001" || first.CreditorAccountNumber != "AC-0000000002" {
+		t.Errorf("unexpected accounts %s and %s", first.DebtorAccountNumber, first.CreditorAccountNumber)
+	}
+	if first.Currency != domain.Kenyan {
+		t.Errorf("expected KES to map to %s, got %s", domain.Kenyan, first.Currency)
+	}
+	if !first.Amount.Equal(decimal.RequireFromString("10.50")) {
+		t.Errorf("expected an amount of 10.50, got %v", first.Amount)
+	}
+	if first.PaymentInformationID != "PMT-1" || first.InstructionID != "INSTR-1" || first.RemittanceInformation != "Invoice 1" {
+		t.Errorf("unexpected instruction references %+v", first)
+	}
+
+	second := input.Instructions[1]
+	if second.CreditorAccountNumber != "AC-0000000003" {
+		t.Errorf("expected the creditor to be identified by IBAN, got %s", second.CreditorAccountNumber)
+	}
+	if second.Currency != domain.Ugandan {
+		t.Errorf("expected %s, got %s", domain.Ugandan, second.Currency)
+	}
+}
+
+func TestNewPain002(t *testing.T) {
+	accepted := &application.CreditTransferResult{
+		Instruction:   &application.CreditTransferInstruction{PaymentInformationID: "PMT-1", EndToEndID: "E2E-1"},
+		Accepted:      true,
+		TransactionID: "TX-1",
+	}
+	rejected := &application.CreditTransferResult{
+		Instruction: &application.CreditTransferInstruction{PaymentInformationID: "PMT-2", EndToEndID: "E2E-2"},
+		ReasonCode:  "AM04",
+		Reason:      "insufficient funds",
+	}
+	held := &application.CreditTransferResult{
+		Instruction:    &application.CreditTransferInstruction{PaymentInformationID: "PMT-3", EndToEndID: "E2E-3"},
+		HeldTransferID: "HELD-1",
+	}
+
+	tests := []struct {
+		name            string
+		results         []*application.CreditTransferResult
+		wantGroupStatus string
+	}{
+		{
+			name:            "all accepted",
+			results:         []*application.CreditTransferResult{accepted},
+			wantGroupStatus: iso20022.AcceptedSettlementCompleted,
+		},
+		{
+			name:            "partially accepted",
+			results:         []*application.CreditTransferResult{accepted, rejected},
+			wantGroupStatus: iso20022.PartiallyAccepted,
+		},
+		{
+			name:            "all rejected",
+			results:         []*application.CreditTransferResult{rejected},
+			wantGroupStatus: iso20022.Rejected,
+		},
+		{
+			name:            "accepted and held for review",
+			results:         []*application.CreditTransferResult{accepted, held},
+			wantGroupStatus: iso20022.Pending,
+		},
+		{
+			name:            "held for review and rejected",
+			results:         []*application.CreditTransferResult{held, rejected},
+			wantGroupStatus: iso20022.PartiallyAccepted,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			document := iso20022.NewPain002("MSG-1", tt.results)
+			if document.Report.OriginalGroup.GroupStatus != tt.wantGroupStatus {
+				t.Errorf("NewPain002() group status = %s, want %s", document.Report.OriginalGroup.GroupStatus, tt.wantGroupStatus)
+			}
+			if len(document.Report.OriginalPayments) != len(tt.results) {
+				t.Errorf("expected a payment information status per payment information block")
+			}
+
+			content, err := iso20022.Marshal(document)
+			if err != nil {
+				t.Errorf("Marshal() error = %v", err)
+				return
+			}
+			if !bytes.Contains(content, []byte("<CstmrPmtStsRpt>")) {
+				t.Errorf("expected a customer payment status report")
+			}
+			for _, result := range tt.results {
+				switch {
+				case result.HeldTransferID != "" && !bytes.Contains(content, []byte("<StsId>HELD-1</StsId>")):
+					t.Errorf("expected the held transfer to be reported pending as HELD-1")
+				case result.ReasonCode != "" && !bytes.Contains(content, []byte("<Cd>AM04</Cd>")):
+					t.Errorf("expected the rejection reason code in the report")
+				}
+			}
+			if bytes.Contains(content, []byte("<StsId>HELD-1</StsId>")) && !bytes.Contains(content, []byte("<TxSts>PDNG</TxSts>")) {
+				t.Errorf("expected the held transfer's instruction to be pending")
+			}
+		})
+	}
+}
+
+func TestNewPain002Rejection(t *testing.T) {
+	document := iso20022.NewPain002Rejection("", iso20022.GroupError{ReasonCode: iso20022.InvalidControlSum, Reason: "wrong sum"})
+	group := document.Report.OriginalGroup
+	if group.GroupStatus != iso20022.Rejected {
+		t.Errorf("expected the message to be rejected, got %s", group.GroupStatus)
+	}
+	if len(group.StatusReasons) != 1 || group.StatusReasons[0].Reason.Code != iso20022.InvalidControlSum {
+		t.Errorf("expected the group reason code %s", iso20022.InvalidControlSum)
+	}
+	if group.OriginalMessageID == "" {
+		t.Errorf("expected a placeholder original message identification")
+	}
+}
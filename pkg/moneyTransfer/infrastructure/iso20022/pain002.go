@@ -0,0 +1,194 @@
+package iso20022
+
+import (
+	"encoding/xml"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/application"
+)
+
+// PAIN002_NAMESPACE is the namespace of the pain.002 version generated
+var PAIN002_NAMESPACE = "urn:iso:std:iso:20022:tech:xsd:pain.002.001.03"
+
+// Payment status codes
+var (
+	// AcceptedSettlementCompleted is reported for instructions executed on the ledger
+	AcceptedSettlementCompleted = "ACSC"
+
+	// PartiallyAccepted is reported for messages with both executed and rejected instructions
+	PartiallyAccepted = "PART"
+
+	// Rejected is reported for instructions, or whole messages, that were not executed
+	Rejected = "RJCT"
+
+	// Pending is reported for instructions held for fraud review, and for messages with held but no rejected instructions
+	Pending = "PDNG"
+)
+
+// Pain002Document is a CustomerPaymentStatusReport (pain.002) message
+type Pain002Document struct {
+	XMLName   xml.Name                    `xml:"Document"`
+	Namespace string                      `xml:"xmlns,attr"`
+	Report    CustomerPaymentStatusReport `xml:"CstmrPmtStsRpt"`
+}
+
+// CustomerPaymentStatusReport reports the status of a pain.001 message and its instructions
+type CustomerPaymentStatusReport struct {
+	GroupHeader      GroupHeader                  `xml:"GrpHdr"`
+	OriginalGroup    OriginalGroupInformation     `xml:"OrgnlGrpInfAndSts"`
+	OriginalPayments []OriginalPaymentInformation `xml:"OrgnlPmtInfAndSts,omitempty"`
+}
+
+// OriginalGroupInformation reports the status of the original message as a whole
+type OriginalGroupInformation struct {
+	OriginalMessageID   string                    `xml:"OrgnlMsgId"`
+	OriginalMessageName string                    `xml:"OrgnlMsgNmId"`
+	OriginalNbOfTxs     string                    `xml:"OrgnlNbOfTxs,omitempty"`
+	GroupStatus         string                    `xml:"GrpSts"`
+	StatusReasons       []StatusReasonInformation `xml:"StsRsnInf,omitempty"`
+}
+
+// StatusReasonInformation explains a status with an ISO reason code
+type StatusReasonInformation struct {
+	Reason                StatusReason `xml:"Rsn"`
+	AdditionalInformation string       `xml:"AddtlInf,omitempty"`
+}
+
+// StatusReason holds an ISO external status reason code
+type StatusReason struct {
+	Code string `xml:"Cd"`
+}
+
+// OriginalPaymentInformation reports the status of the instructions of a payment information block
+type OriginalPaymentInformation struct {
+	OriginalPaymentInformationID string              `xml:"OrgnlPmtInfId"`
+	PaymentInformationStatus     string              `xml:"PmtInfSts"`
+	Transactions                 []TransactionStatus `xml:"TxInfAndSts"`
+}
+
+// TransactionStatus reports the status of a single instruction
+type TransactionStatus struct {
+	StatusID              string                    `xml:"StsId,omitempty"`
+	OriginalInstructionID string                    `xml:"OrgnlInstrId,omitempty"`
+	OriginalEndToEndID    string                    `xml:"OrgnlEndToEndId"`
+	TransactionStatus     string                    `xml:"TxSts"`
+	StatusReasons         []StatusReasonInformation `xml:"StsRsnInf,omitempty"`
+}
+
+// aggregateStatus derives a group or payment information status from the number of executed and pending instructions
+func aggregateStatus(accepted int, pending int, total int) string {
+	rejected := total - accepted - pending
+	switch {
+	case rejected == total:
+		return Rejected
+	case accepted == total:
+		return AcceptedSettlementCompleted
+	case rejected == 0:
+		return Pending
+	}
+	return PartiallyAccepted
+}
+
+// NewPain002 reports the per instruction outcome of executing a pain.001 message
+func NewPain002(originalMessageID string, results []*application.CreditTransferResult) *Pain002Document {
+	now := time.Now()
+	document := Pain002Document{
+		Namespace: PAIN002_NAMESPACE,
+		Report: CustomerPaymentStatusReport{
+			GroupHeader: GroupHeader{
+				MessageID:        fmt.Sprintf("STS-%s-%d", originalMessageID, now.Unix()),
+				CreationDateTime: now.Format(ISODateTime),
+			},
+			OriginalGroup: OriginalGroupInformation{
+				OriginalMessageID:   originalMessageID,
+				OriginalMessageName: PAIN001_MESSAGE_NAME,
+				OriginalNbOfTxs:     strconv.Itoa(len(results)),
+			},
+		},
+	}
+
+	accepted, pending := 0, 0
+	payments := map[string]*OriginalPaymentInformation{}
+	paymentsAccepted, paymentsPending := map[string]int{}, map[string]int{}
+	var order []string
+	for _, result := range results {
+		instruction := result.Instruction
+		payment, ok := payments[instruction.PaymentInformationID]
+		if !ok {
+			payment = &OriginalPaymentInformation{OriginalPaymentInformationID: instruction.PaymentInformationID}
+			payments[instruction.PaymentInformationID] = payment
+			order = append(order, instruction.PaymentInformationID)
+		}
+
+		status := TransactionStatus{
+			StatusID:              result.TransactionID,
+			OriginalInstructionID: instruction.InstructionID,
+			OriginalEndToEndID:    instruction.EndToEndID,
+			TransactionStatus:     AcceptedSettlementCompleted,
+		}
+		switch {
+		case result.Accepted:
+			accepted++
+			paymentsAccepted[instruction.PaymentInformationID]++
+		case result.HeldTransferID != "":
+			pending++
+			paymentsPending[instruction.PaymentInformationID]++
+			status.StatusID = result.HeldTransferID
+			status.TransactionStatus = Pending
+		default:
+			status.TransactionStatus = Rejected
+			status.StatusReasons = []StatusReasonInformation{{
+				Reason:                StatusReason{Code: result.ReasonCode},
+				AdditionalInformation: truncate(result.Reason, 105),
+			}}
+		}
+		payment.Transactions = append(payment.Transactions, status)
+	}
+
+	for _, paymentID := range order {
+		payment := payments[paymentID]
+		payment.PaymentInformationStatus = aggregateStatus(paymentsAccepted[paymentID], paymentsPending[paymentID], len(payment.Transactions))
+		document.Report.OriginalPayments = append(document.Report.OriginalPayments, *payment)
+	}
+	document.Report.OriginalGroup.GroupStatus = aggregateStatus(accepted, pending, len(results))
+
+	return &document
+}
+
+// NewPain002Rejection reports that a pain.001 message was rejected as a whole
+func NewPain002Rejection(originalMessageID string, groupErr GroupError) *Pain002Document {
+	now := time.Now()
+	if originalMessageID == "" {
+		originalMessageID = "NOTPROVIDED"
+	}
+
+	return &Pain002Document{
+		Namespace: PAIN002_NAMESPACE,
+		Report: CustomerPaymentStatusReport{
+			GroupHeader: GroupHeader{
+				MessageID:        fmt.Sprintf("STS-%s-%d", originalMessageID, now.Unix()),
+				CreationDateTime: now.Format(ISODateTime),
+			},
+			OriginalGroup: OriginalGroupInformation{
+				OriginalMessageID:   originalMessageID,
+				OriginalMessageName: PAIN001_MESSAGE_NAME,
+				GroupStatus:         Rejected,
+				StatusReasons: []StatusReasonInformation{{
+					Reason:                StatusReason{Code: groupErr.ReasonCode},
+					AdditionalInformation: truncate(groupErr.Reason, 105),
+				}},
+			},
+		},
+	}
+}
+
+// truncate shortens free text to the length allowed by an ISO 20022 element
+func truncate(text string, length int) string {
+	runes := []rune(text)
+	if len(runes) > length {
+		return string(runes[:length])
+	}
+	return text
+}
@@ -0,0 +1,129 @@
+// Package monitoring exposes the service's metrics to Prometheus
+package monitoring
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/domain"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/shopspring/decimal"
+)
+
+// METRICS_NAMESPACE prefixes every metric name
+var METRICS_NAMESPACE = "money_transfer"
+
+// LATENCY_BUCKETS are the histogram buckets, in seconds, of request and query latencies
+var LATENCY_BUCKETS = []float64{.001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5}
+
+// LAG_BUCKETS are the histogram buckets, in seconds, of how long events wait in the outbox
+var LAG_BUCKETS = []float64{.1, .25, .5, 1, 2.5, 5, 10, 30, 60, 300, 900}
+
+// Prometheus records the service's metrics in a Prometheus registry
+type Prometheus struct {
+	Registry *prometheus.Registry
+
+	requests        *prometheus.CounterVec
+	requestDuration *prometheus.HistogramVec
+	transfers       *prometheus.CounterVec
+	transferAmounts *prometheus.CounterVec
+	queryDuration   *prometheus.HistogramVec
+	lockWait        *prometheus.HistogramVec
+	outboxLag       *prometheus.HistogramVec
+}
+
+// NewPrometheus registers the service's metrics, along with the Go runtime and process metrics, in a new registry
+func NewPrometheus() *Prometheus {
+	p := &Prometheus{
+		Registry: prometheus.NewRegistry(),
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: METRICS_NAMESPACE,
+			Name:      "http_requests_total",
+			Help:      "API requests served, by method, route and status code.",
+		}, []string{"method", "route", "status"}),
+		requestDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: METRICS_NAMESPACE,
+			Name:      "http_request_duration_seconds",
+			Help:      "API request latencies, by method and route.",
+			Buckets:   LATENCY_BUCKETS,
+		}, []string{"method", "route"}),
+		transfers: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: METRICS_NAMESPACE,
+			Name:      "transfers_total",
+			Help:      "Transfers attempted, by currency and outcome.",
+		}, []string{"currency", "outcome"}),
+		transferAmounts: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: METRICS_NAMESPACE,
+			Name:      "transfer_amount_total",
+			Help:      "Sum of the amounts of the transfers attempted, by currency and outcome.",
+		}, []string{"currency", "outcome"}),
+		queryDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: METRICS_NAMESPACE,
+			Name:      "db_query_duration_seconds",
+			Help:      "Database query latencies, by operation and table.",
+			Buckets:   LATENCY_BUCKETS,
+		}, []string{"operation", "table"}),
+		lockWait: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: METRICS_NAMESPACE,
+			Name:      "lock_wait_seconds",
+			Help:      "Time spent waiting to acquire a lock, by lock.",
+			Buckets:   LATENCY_BUCKETS,
+		}, []string{"lock"}),
+		outboxLag: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: METRICS_NAMESPACE,
+			Name:      "outbox_lag_seconds",
+			Help:      "Time between a domain event occurring and its publication, by event type.",
+			Buckets:   LAG_BUCKETS,
+		}, []string{"type"}),
+	}
+
+	p.Registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		p.requests,
+		p.requestDuration,
+		p.transfers,
+		p.transferAmounts,
+		p.queryDuration,
+		p.lockWait,
+		p.outboxLag,
+	)
+	return p
+}
+
+// Handler serves the registry's metrics in the Prometheus exposition format
+func (p *Prometheus) Handler() http.Handler {
+	return promhttp.HandlerFor(p.Registry, promhttp.HandlerOpts{Registry: p.Registry})
+}
+
+// ObserveRequest records an API request
+func (p *Prometheus) ObserveRequest(method string, route string, status int, duration time.Duration) {
+	p.requests.WithLabelValues(method, route, strconv.Itoa(status)).Inc()
+	p.requestDuration.WithLabelValues(method, route).Observe(duration.Seconds())
+}
+
+// ObserveTransfer records a transfer attempt
+func (p *Prometheus) ObserveTransfer(currency domain.CurrencyType, outcome string, amount decimal.Decimal) {
+	p.transfers.WithLabelValues(string(currency), outcome).Inc()
+	if amount.IsPositive() {
+		p.transferAmounts.WithLabelValues(string(currency), outcome).Add(amount.InexactFloat64())
+	}
+}
+
+// ObserveQuery records a database query
+func (p *Prometheus) ObserveQuery(operation string, table string, duration time.Duration) {
+	p.queryDuration.WithLabelValues(operation, table).Observe(duration.Seconds())
+}
+
+// ObserveLockWait records a wait for a lock
+func (p *Prometheus) ObserveLockWait(lock string, wait time.Duration) {
+	p.lockWait.WithLabelValues(lock).Observe(wait.Seconds())
+}
+
+// ObserveOutboxLag records how long a published event waited in the outbox
+func (p *Prometheus) ObserveOutboxLag(eventType domain.EventType, lag time.Duration) {
+	p.outboxLag.WithLabelValues(string(eventType)).Observe(lag.Seconds())
+}
@@ -0,0 +1,47 @@
+package monitoring_test
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/domain"
+	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/infrastructure/monitoring"
+	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/metrics"
+	"github.com/shopspring/decimal"
+)
+
+func TestPrometheus_Handler(t *testing.T) {
+	recorder := monitoring.NewPrometheus()
+	recorder.ObserveRequest(http.MethodPost, "/api/v1/transfer", http.StatusOK, 30*time.Millisecond)
+	recorder.ObserveTransfer(domain.Kenyan, metrics.SUCCESS, decimal.RequireFromString("100.50"))
+	recorder.ObserveTransfer(domain.Kenyan, metrics.SUCCESS, decimal.NewFromInt(20))
+	recorder.ObserveTransfer(domain.Kenyan, "insufficient_funds", decimal.NewFromInt(5000))
+	recorder.ObserveQuery("query", "accounts", 2*time.Millisecond)
+	recorder.ObserveLockWait("money_movement", time.Millisecond)
+	recorder.ObserveOutboxLag(domain.TransferPosted, 3*time.Second)
+
+	response := httptest.NewRecorder()
+	recorder.Handler().ServeHTTP(response, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	body, _ := io.ReadAll(response.Body)
+
+	for _, want := range []string{
+		`money_transfer_http_requests_total{method="POST",route="/api/v1/transfer",status="200"} 1`,
+		`money_transfer_http_request_duration_seconds_count{method="POST",route="/api/v1/transfer"} 1`,
+		`money_transfer_transfers_total{currency="KSH",outcome="success"} 2`,
+		`money_transfer_transfers_total{currency="KSH",outcome="insufficient_funds"} 1`,
+		`money_transfer_transfer_amount_total{currency="KSH",outcome="success"} 120.5`,
+		`money_transfer_db_query_duration_seconds_count{operation="query",table="accounts"} 1`,
+		`money_transfer_lock_wait_seconds_count{lock="money_movement"} 1`,
+		fmt.Sprintf(`money_transfer_outbox_lag_seconds_count{type=%q} 1`, domain.TransferPosted),
+		"go_goroutines",
+	} {
+		if !strings.Contains(string(body), want) {
+			t.Errorf("expected %s in the metrics", want)
+		}
+	}
+}
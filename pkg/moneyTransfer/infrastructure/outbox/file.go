@@ -0,0 +1,50 @@
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+
+	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/domain"
+)
+
+// FileSink appends events to a newline delimited JSON (NDJSON) file
+type FileSink struct {
+	Path string
+
+	mu sync.Mutex
+}
+
+// NewFileSink initializes a sink appending to the file at path
+func NewFileSink(path string) *FileSink {
+	return &FileSink{Path: path}
+}
+
+// Publish appends an event as a single JSON line, syncing it to disk before returning
+func (f *FileSink) Publish(ctx context.Context, event domain.EventEnvelope) error {
+	line, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("unable to encode event %s: %v", event.ID, err)
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	file, err := os.OpenFile(f.Path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return fmt.Errorf("unable to open event file %s: %v", f.Path, err)
+	}
+	defer file.Close()
+
+	if _, err := file.Write(append(line, '\n')); err != nil {
+		return fmt.Errorf("unable to write event %s: %v", event.ID, err)
+	}
+
+	if err := file.Sync(); err != nil {
+		return fmt.Errorf("unable to sync event file %s: %v", f.Path, err)
+	}
+
+	return nil
+}
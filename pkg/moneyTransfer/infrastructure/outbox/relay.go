@@ -0,0 +1,117 @@
+package outbox
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/metrics"
+	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/repository"
+	"go.uber.org/zap"
+)
+
+// RELAY_BATCH_SIZE is the number of pending events read from the outbox at a time
+var RELAY_BATCH_SIZE = 100
+
+// RELAY_INTERVAL is how long the relay waits before polling an empty or failing outbox again
+var RELAY_INTERVAL = time.Second
+
+// Relay publishes outbox events to its sinks in sequence order.
+// An event is only marked published once every sink accepted it, so delivery is at least once
+type Relay struct {
+	Store     repository.OutboxRepository
+	Metrics   metrics.OutboxRecorder
+	Logger    *zap.Logger
+	Sinks     []Sink
+	BatchSize int
+	Interval  time.Duration
+}
+
+// CheckPreconditions ensures a correct Relay struct is initialized
+func (r Relay) CheckPreconditions() {
+	if r.Store == nil {
+		log.Panic("outbox relay has not initialized the outbox repository")
+	}
+
+	if r.Metrics == nil {
+		log.Panic("outbox relay has not initialized the outbox metrics")
+	}
+
+	if r.Logger == nil {
+		log.Panic("outbox relay has not initialized the logger")
+	}
+
+	if len(r.Sinks) == 0 {
+		log.Panic("outbox relay has no sinks to publish to")
+	}
+}
+
+// NewRelay initializes a relay publishing the outbox to the given sinks
+func NewRelay(store repository.OutboxRepository, outboxMetrics metrics.OutboxRecorder, logger *zap.Logger, sinks ...Sink) *Relay {
+	r := &Relay{
+		Store:     store,
+		Metrics:   outboxMetrics,
+		Logger:    logger,
+		Sinks:     sinks,
+		BatchSize: RELAY_BATCH_SIZE,
+		Interval:  RELAY_INTERVAL,
+	}
+	r.CheckPreconditions()
+	return r
+}
+
+// PublishPending publishes a batch of pending events, returning how many were published.
+// Publishing stops at the first failure so that later events are not delivered ahead of it
+func (r Relay) PublishPending(ctx context.Context) (int, error) {
+	events, err := r.Store.PendingEvents(ctx, r.BatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	published := 0
+	for _, event := range events {
+		envelope := event.Envelope()
+		for _, sink := range r.Sinks {
+			if err := sink.Publish(ctx, envelope); err != nil {
+				if markErr := r.Store.MarkFailed(ctx, event.Sequence, err.Error()); markErr != nil {
+					r.Logger.Error("unable to record a failed publication", zap.Int64("sequence", event.Sequence), zap.Error(markErr))
+				}
+				return published, fmt.Errorf("unable to publish event %d: %v", event.Sequence, err)
+			}
+		}
+
+		if err := r.Store.MarkPublished(ctx, event.Sequence); err != nil {
+			return published, err
+		}
+		r.Metrics.ObserveOutboxLag(event.Type, time.Since(event.OccurredAt))
+		published++
+	}
+
+	return published, nil
+}
+
+// Run publishes pending events until ctx is done, draining full batches without waiting
+func (r Relay) Run(ctx context.Context) {
+	for {
+		published, err := r.PublishPending(ctx)
+		if err != nil && ctx.Err() == nil {
+			r.Logger.Warn("unable to publish the outbox", zap.Error(err))
+		}
+
+		if err == nil && published == r.BatchSize {
+			select {
+			case <-ctx.Done():
+				return
+			default:
+				continue
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(r.Interval):
+		}
+	}
+}
@@ -0,0 +1,224 @@
+package outbox_test
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/domain"
+	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/infrastructure/outbox"
+	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/metrics"
+	"go.uber.org/zap"
+)
+
+type memoryOutbox struct {
+	events map[int64]*domain.OutboxEvent
+}
+
+func newMemoryOutbox(count int) *memoryOutbox {
+	m := &memoryOutbox{events: map[int64]*domain.OutboxEvent{}}
+	for i := 1; i <= count; i++ {
+		event, _ := domain.NewOutboxEvent(domain.TransferPosted, fmt.Sprintf("tx-%d", i), map[string]int{"n": i})
+		event.Sequence = int64(i)
+		m.events[event.Sequence] = event
+	}
+	return m
+}
+
+func (m *memoryOutbox) PendingEvents(ctx context.Context, limit int) ([]*domain.OutboxEvent, error) {
+	var pending []*domain.OutboxEvent
+	for _, event := range m.events {
+		if event.PublishedAt == nil {
+			pending = append(pending, event)
+		}
+	}
+	sort.Slice(pending, func(i, j int) bool { return pending[i].Sequence < pending[j].Sequence })
+	if len(pending) > limit {
+		pending = pending[:limit]
+	}
+	return pending, nil
+}
+
+func (m *memoryOutbox) MarkPublished(ctx context.Context, sequence int64) error {
+	now := time.Now()
+	m.events[sequence].PublishedAt = &now
+	m.events[sequence].Attempts++
+	return nil
+}
+
+func (m *memoryOutbox) MarkFailed(ctx context.Context, sequence int64, reason string) error {
+	m.events[sequence].Attempts++
+	m.events[sequence].LastError = reason
+	return nil
+}
+
+type flakySink struct {
+	failOn    int64
+	failures  int
+	delivered []int64
+}
+
+func (f *flakySink) Publish(ctx context.Context, event domain.EventEnvelope) error {
+	if event.Sequence == f.failOn && f.failures > 0 {
+		f.failures--
+		return fmt.Errorf("sink unavailable")
+	}
+	f.delivered = append(f.delivered, event.Sequence)
+	return nil
+}
+
+func TestRelay_PublishPending(t *testing.T) {
+	store := newMemoryOutbox(5)
+	sink := &flakySink{failOn: 3, failures: 1}
+	relay := outbox.NewRelay(store, metrics.Noop{}, zap.NewNop(), sink)
+
+	published, err := relay.PublishPending(context.Background())
+	if err == nil {
+		t.Errorf("expected the failing sink to stop the batch")
+	}
+	if published != 2 {
+		t.Errorf("expected the events before the failure to be published, got %d", published)
+	}
+	if store.events[3].Attempts != 1 || store.events[3].LastError == "" {
+		t.Errorf("expected the failed attempt to be recorded")
+	}
+	if store.events[4].PublishedAt != nil {
+		t.Errorf("expected events after the failure to wait for it")
+	}
+
+	published, err = relay.PublishPending(context.Background())
+	if err != nil {
+		t.Errorf("Relay.PublishPending() error = %v", err)
+		return
+	}
+	if published != 3 {
+		t.Errorf("expected the remaining events to be published, got %d", published)
+	}
+
+	want := []int64{1, 2, 3, 4, 5}
+	if fmt.Sprint(sink.delivered) != fmt.Sprint(want) {
+		t.Errorf("expected delivery in sequence order %v, got %v", want, sink.delivered)
+	}
+}
+
+type lagRecorder struct {
+	lags []time.Duration
+}
+
+func (r *lagRecorder) ObserveOutboxLag(eventType domain.EventType, lag time.Duration) {
+	r.lags = append(r.lags, lag)
+}
+
+func TestRelay_OutboxLag(t *testing.T) {
+	store := newMemoryOutbox(3)
+	store.events[2].OccurredAt = time.Now().Add(-time.Minute)
+	recorder := &lagRecorder{}
+	relay := outbox.NewRelay(store, recorder, zap.NewNop(), &flakySink{failOn: 3, failures: 1})
+
+	if _, err := relay.PublishPending(context.Background()); err == nil {
+		t.Errorf("expected the failing sink to stop the batch")
+	}
+	if len(recorder.lags) != 2 {
+		t.Fatalf("expected only the published events' lag to be recorded, got %v", recorder.lags)
+	}
+	if recorder.lags[1] < time.Minute {
+		t.Errorf("expected the lag to run from when the event occurred, got %v", recorder.lags[1])
+	}
+}
+
+func TestRelay_Redelivery(t *testing.T) {
+	store := newMemoryOutbox(2)
+	delivered := &flakySink{}
+	failing := &flakySink{failOn: 1, failures: 1}
+	relay := outbox.NewRelay(store, metrics.Noop{}, zap.NewNop(), delivered, failing)
+
+	if _, err := relay.PublishPending(context.Background()); err == nil {
+		t.Errorf("expected the second sink to fail")
+	}
+	if _, err := relay.PublishPending(context.Background()); err != nil {
+		t.Errorf("Relay.PublishPending() error = %v", err)
+	}
+
+	// The first sink accepted event 1 before the second failed, so it receives it again
+	want := []int64{1, 1, 2}
+	if fmt.Sprint(delivered.delivered) != fmt.Sprint(want) {
+		t.Errorf("expected at least once delivery %v, got %v", want, delivered.delivered)
+	}
+}
+
+func TestRelay_Run(t *testing.T) {
+	store := newMemoryOutbox(3)
+	sink := outbox.NewChannelSink(10)
+	relay := outbox.NewRelay(store, metrics.Noop{}, zap.NewNop(), sink)
+	relay.Interval = 10 * time.Millisecond
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go relay.Run(ctx)
+	defer cancel()
+
+	for i := int64(1); i <= 3; i++ {
+		select {
+		case event := <-sink.Events:
+			if event.Sequence != i {
+				t.Errorf("expected event %d, got %d", i, event.Sequence)
+			}
+		case <-time.After(time.Second):
+			t.Errorf("timed out waiting for event %d", i)
+			return
+		}
+	}
+}
+
+func TestChannelSink_Publish(t *testing.T) {
+	sink := outbox.NewChannelSink(1)
+	if err := sink.Publish(context.Background(), domain.EventEnvelope{ID: "1"}); err != nil {
+		t.Errorf("ChannelSink.Publish() error = %v", err)
+	}
+	if err := sink.Publish(context.Background(), domain.EventEnvelope{ID: "2"}); err == nil {
+		t.Errorf("expected a full channel to fail rather than block")
+	}
+}
+
+func TestFileSink_Publish(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "events.ndjson")
+	sink := outbox.NewFileSink(path)
+
+	store := newMemoryOutbox(2)
+	for _, sequence := range []int64{1, 2} {
+		if err := sink.Publish(context.Background(), store.events[sequence].Envelope()); err != nil {
+			t.Errorf("FileSink.Publish() error = %v", err)
+			return
+		}
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		t.Errorf("unable to open event file: %v", err)
+		return
+	}
+	defer file.Close()
+
+	var lines []domain.EventEnvelope
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var envelope domain.EventEnvelope
+		if err := json.Unmarshal(scanner.Bytes(), &envelope); err != nil {
+			t.Errorf("expected a JSON event per line: %v", err)
+			return
+		}
+		lines = append(lines, envelope)
+	}
+	if len(lines) != 2 {
+		t.Errorf("expected 2 events, got %d", len(lines))
+		return
+	}
+	if lines[1].Sequence != 2 || lines[1].Type != domain.TransferPosted || string(lines[1].Data) != `{"n":2}` {
+		t.Errorf("unexpected event %+v", lines[1])
+	}
+}
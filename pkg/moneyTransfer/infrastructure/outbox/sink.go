@@ -0,0 +1,84 @@
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+
+	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/domain"
+)
+
+// Sink receives the domain events published by the relay.
+// An event may be delivered more than once, consumers should deduplicate on its ID or sequence
+type Sink interface {
+	Publish(ctx context.Context, event domain.EventEnvelope) error
+}
+
+// Broker abstracts a message broker client (Kafka, NATS, Pub/Sub...) events can be published to
+type Broker interface {
+	Send(topic string, key string, message []byte) error
+}
+
+// BrokerSink publishes events to a message broker topic, keyed by the aggregate they concern
+type BrokerSink struct {
+	Broker Broker
+	Topic  string
+}
+
+// CheckPreconditions ensures a correct BrokerSink struct is initialized
+func (b BrokerSink) CheckPreconditions() {
+	if b.Broker == nil {
+		log.Panic("broker sink has not initialized the broker client")
+	}
+
+	if b.Topic == "" {
+		log.Panic("broker sink has not been given a topic")
+	}
+}
+
+// NewBrokerSink initializes a sink publishing to a broker topic
+func NewBrokerSink(broker Broker, topic string) *BrokerSink {
+	b := &BrokerSink{
+		Broker: broker,
+		Topic:  topic,
+	}
+	b.CheckPreconditions()
+	return b
+}
+
+// Publish sends an event to the broker. Keying by aggregate keeps an account's events in order
+func (b BrokerSink) Publish(ctx context.Context, event domain.EventEnvelope) error {
+	message, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("unable to encode event %s: %v", event.ID, err)
+	}
+
+	if err := b.Broker.Send(b.Topic, event.AggregateID, message); err != nil {
+		return fmt.Errorf("unable to send event %s to %s: %v", event.ID, b.Topic, err)
+	}
+
+	return nil
+}
+
+// ChannelSink hands events to in-process consumers over a buffered channel
+type ChannelSink struct {
+	Events chan domain.EventEnvelope
+}
+
+// NewChannelSink initializes an in-process sink buffering up to size events
+func NewChannelSink(size int) *ChannelSink {
+	return &ChannelSink{
+		Events: make(chan domain.EventEnvelope, size),
+	}
+}
+
+// Publish queues an event, failing rather than blocking the relay when consumers fall behind
+func (c ChannelSink) Publish(ctx context.Context, event domain.EventEnvelope) error {
+	select {
+	case c.Events <- event:
+		return nil
+	default:
+		return fmt.Errorf("channel sink is full, event %s will be retried", event.ID)
+	}
+}
@@ -0,0 +1,54 @@
+package statement
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"time"
+
+	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/application"
+)
+
+// renderCSV writes the opening balance, each movement and the closing balance as rows
+func renderCSV(statement *application.Statement) ([]byte, error) {
+	currency := statement.Account.Currency
+	minorUnits := currency.MinorUnits()
+
+	rows := [][]string{
+		{"date", "transaction_id", "description", "amount", "balance", "currency"},
+		{
+			statement.From.Format(time.RFC3339),
+			"",
+			"Opening balance",
+			"",
+			statement.OpeningBalance.StringFixed(minorUnits),
+			string(currency),
+		},
+	}
+	for _, line := range statement.Lines {
+		rows = append(rows, []string{
+			line.BookingDate.Format(time.RFC3339),
+			line.TransactionID,
+			line.Description,
+			line.Amount.StringFixed(minorUnits),
+			line.RunningBalance.StringFixed(minorUnits),
+			string(currency),
+		})
+	}
+	rows = append(rows, []string{
+		statement.To.Format(time.RFC3339),
+		"",
+		"Closing balance",
+		"",
+		statement.ClosingBalance.StringFixed(minorUnits),
+		string(currency),
+	})
+
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	if err := w.WriteAll(rows); err != nil {
+		return nil, fmt.Errorf("unable to write CSV statement: %v", err)
+	}
+
+	return buf.Bytes(), nil
+}
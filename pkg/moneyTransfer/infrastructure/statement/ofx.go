@@ -0,0 +1,125 @@
+package statement
+
+import (
+	"encoding/xml"
+	"fmt"
+	"time"
+
+	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/application"
+)
+
+var ofxHeader = `<?OFX OFXHEADER="200" VERSION="211" SECURITY="NONE" OLDFILEUID="NONE" NEWFILEUID="NONE"?>` + "\n"
+
+type ofxDocument struct {
+	XMLName xml.Name      `xml:"OFX"`
+	SignOn  ofxSignOn     `xml:"SIGNONMSGSRSV1>SONRS"`
+	Bank    ofxStatements `xml:"BANKMSGSRSV1>STMTTRNRS"`
+}
+
+type ofxStatus struct {
+	Code     int    `xml:"CODE"`
+	Severity string `xml:"SEVERITY"`
+}
+
+type ofxSignOn struct {
+	Status     ofxStatus `xml:"STATUS"`
+	ServerDate string    `xml:"DTSERVER"`
+	Language   string    `xml:"LANGUAGE"`
+	Org        string    `xml:"FI>ORG"`
+}
+
+type ofxStatements struct {
+	TransactionUID string       `xml:"TRNUID"`
+	Status         ofxStatus    `xml:"STATUS"`
+	Statement      ofxStatement `xml:"STMTRS"`
+}
+
+type ofxStatement struct {
+	Currency          string           `xml:"CURDEF"`
+	BankID            string           `xml:"BANKACCTFROM>BANKID"`
+	AccountID         string           `xml:"BANKACCTFROM>ACCTID"`
+	AccountType       string           `xml:"BANKACCTFROM>ACCTTYPE"`
+	Start             string           `xml:"BANKTRANLIST>DTSTART"`
+	End               string           `xml:"BANKTRANLIST>DTEND"`
+	Transactions      []ofxTransaction `xml:"BANKTRANLIST>STMTTRN"`
+	LedgerBalance     string           `xml:"LEDGERBAL>BALAMT"`
+	LedgerBalanceAsOf string           `xml:"LEDGERBAL>DTASOF"`
+}
+
+type ofxTransaction struct {
+	Type   string `xml:"TRNTYPE"`
+	Posted string `xml:"DTPOSTED"`
+	Amount string `xml:"TRNAMT"`
+	FitID  string `xml:"FITID"`
+	Name   string `xml:"NAME"`
+	Memo   string `xml:"MEMO,omitempty"`
+}
+
+// ofxDate formats a time as an OFX date time in UTC
+func ofxDate(t time.Time) string {
+	return t.UTC().Format("20060102150405.000") + "[0:GMT]"
+}
+
+// ofxName truncates a transaction name to the 32 characters OFX allows
+func ofxName(description string) string {
+	runes := []rune(description)
+	if len(runes) > 32 {
+		return string(runes[:32])
+	}
+	return description
+}
+
+// renderOFX writes a statement as an OFX 2.1.1 bank statement response
+func renderOFX(statement *application.Statement) ([]byte, error) {
+	account := statement.Account
+	minorUnits := account.Currency.MinorUnits()
+	ok := ofxStatus{Code: 0, Severity: "INFO"}
+
+	var transactions []ofxTransaction
+	for _, line := range statement.Lines {
+		transactionType := "CREDIT"
+		if line.Amount.IsNegative() {
+			transactionType = "DEBIT"
+		}
+		transactions = append(transactions, ofxTransaction{
+			Type:   transactionType,
+			Posted: ofxDate(line.BookingDate),
+			Amount: line.Amount.StringFixed(minorUnits),
+			FitID:  line.EntryID,
+			Name:   ofxName(line.Description),
+			Memo:   line.Description,
+		})
+	}
+
+	document := ofxDocument{
+		SignOn: ofxSignOn{
+			Status:     ok,
+			ServerDate: ofxDate(statement.GeneratedAt),
+			Language:   "ENG",
+			Org:        INSTITUTION_ID,
+		},
+		Bank: ofxStatements{
+			TransactionUID: fmt.Sprintf("%s-%d", account.Number, statement.GeneratedAt.Unix()),
+			Status:         ok,
+			Statement: ofxStatement{
+				Currency:          account.Currency.ISOCode(),
+				BankID:            INSTITUTION_ID,
+				AccountID:         account.Number,
+				AccountType:       "CHECKING",
+				Start:             ofxDate(statement.From),
+				End:               ofxDate(statement.To),
+				Transactions:      transactions,
+				LedgerBalance:     statement.ClosingBalance.StringFixed(minorUnits),
+				LedgerBalanceAsOf: ofxDate(statement.To),
+			},
+		},
+	}
+
+	content, err := xml.MarshalIndent(document, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("unable to encode OFX statement: %v", err)
+	}
+
+	output := []byte(`<?xml version="1.0" encoding="UTF-8" standalone="no"?>` + "\n" + ofxHeader)
+	return append(output, content...), nil
+}
@@ -0,0 +1,63 @@
+package statement
+
+import (
+	"fmt"
+
+	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/application"
+	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/infrastructure/iso20022"
+)
+
+// Format is a file format a statement can be exported to
+type Format string
+
+const (
+	// CSV renders a statement as comma separated values
+	CSV Format = "csv"
+
+	// OFX renders a statement as an Open Financial Exchange 2.x document
+	OFX Format = "ofx"
+
+	// Camt053 renders a statement as an ISO 20022 camt.053 message
+	Camt053 Format = "camt053"
+)
+
+// INSTITUTION_ID identifies this service as the account servicer in exported statements
+var INSTITUTION_ID = "SIMPLEMONEYTRANSFER"
+
+// Render exports a statement in the given format, returning its content and content type
+func Render(format Format, statement *application.Statement) ([]byte, string, error) {
+	if statement == nil || statement.Account == nil {
+		return nil, "", fmt.Errorf("missing statement information")
+	}
+
+	switch format {
+	case CSV:
+		content, err := renderCSV(statement)
+		return content, "text/csv", err
+
+	case OFX:
+		content, err := renderOFX(statement)
+		return content, "application/x-ofx", err
+
+	case Camt053:
+		content, err := iso20022.Marshal(iso20022.NewCamt053(statement))
+		return content, "application/xml", err
+	}
+
+	return nil, "", fmt.Errorf("%q is not a supported statement format", format)
+}
+
+// Filename suggests a file name for a statement exported in the given format
+func Filename(format Format, statement *application.Statement) string {
+	extension := string(format)
+	if format == Camt053 {
+		extension = "xml"
+	}
+
+	return fmt.Sprintf("statement_%s_%s_%s.%s",
+		statement.Account.Number,
+		statement.From.Format("20060102"),
+		statement.To.Format("20060102"),
+		extension,
+	)
+}
@@ -0,0 +1,162 @@
+package statement_test
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/xml"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/application"
+	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/domain"
+	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/infrastructure/iso20022"
+	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/infrastructure/statement"
+	"github.com/shopspring/decimal"
+)
+
+func newTestStatement() *application.Statement {
+	from := time.Date(2023, 9, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2023, 9, 30, 23, 59, 59, 0, time.UTC)
+
+	return &application.Statement{
+		Account: &application.AccountInformationOutput{
+			UUID:     "ddff1ec2-edb2-4d8e-90f0-115766cace6b",
+			Name:     "John Doe DEPOSIT account",
+			Number:   "AC-1694000000",
+			Currency: domain.Kenyan,
+		},
+		From:           from,
+		To:             to,
+		GeneratedAt:    to,
+		OpeningBalance: decimal.NewFromInt(100),
+		ClosingBalance: decimal.NewFromInt(70),
+		TotalIn:        decimal.NewFromInt(50),
+		TotalOut:       decimal.NewFromInt(80),
+		Lines: []*application.StatementLine{
+			{
+				EntryID:        "entry-1",
+				TransactionID:  "transaction-1",
+				Description:    "Deposit of 50 from account AC-0123456789 to account AC-1694000000",
+				BookingDate:    from.AddDate(0, 0, 1),
+				Amount:         decimal.NewFromInt(50),
+				RunningBalance: decimal.NewFromInt(150),
+			},
+			{
+				EntryID:        "entry-2",
+				TransactionID:  "transaction-2",
+				Description:    "Deposit of 80 from account AC-1694000000 to account AC-1694000001",
+				BookingDate:    from.AddDate(0, 0, 2),
+				Amount:         decimal.NewFromInt(-80),
+				RunningBalance: decimal.NewFromInt(70),
+			},
+		},
+	}
+}
+
+func TestRender_CSV(t *testing.T) {
+	content, contentType, err := statement.Render(statement.CSV, newTestStatement())
+	if err != nil {
+		t.Errorf("Render() error = %v", err)
+		return
+	}
+	if contentType != "text/csv" {
+		t.Errorf("expected a CSV content type, got %s", contentType)
+		return
+	}
+
+	rows, err := csv.NewReader(bytes.NewReader(content)).ReadAll()
+	if err != nil {
+		t.Errorf("expected valid CSV: %v", err)
+		return
+	}
+	// header, opening balance, two movements and closing balance
+	if len(rows) != 5 {
+		t.Errorf("expected 5 rows, got %d", len(rows))
+		return
+	}
+	if rows[3][3] != "-80.00" {
+		t.Errorf("expected money out to be negative, got %s", rows[3][3])
+		return
+	}
+	if rows[4][4] != "70.00" {
+		t.Errorf("expected a closing balance of 70.00, got %s", rows[4][4])
+		return
+	}
+}
+
+func TestRender_OFX(t *testing.T) {
+	content, _, err := statement.Render(statement.OFX, newTestStatement())
+	if err != nil {
+		t.Errorf("Render() error = %v", err)
+		return
+	}
+	if !strings.Contains(string(content), `OFXHEADER="200"`) {
+		t.Errorf("expected an OFX 2.x header")
+		return
+	}
+
+	var document struct {
+		Currency      string   `xml:"BANKMSGSRSV1>STMTTRNRS>STMTRS>CURDEF"`
+		Types         []string `xml:"BANKMSGSRSV1>STMTTRNRS>STMTRS>BANKTRANLIST>STMTTRN>TRNTYPE"`
+		LedgerBalance string   `xml:"BANKMSGSRSV1>STMTTRNRS>STMTRS>LEDGERBAL>BALAMT"`
+	}
+	if err := xml.Unmarshal(content, &document); err != nil {
+		t.Errorf("expected well formed OFX: %v", err)
+		return
+	}
+	if document.Currency != "KES" {
+		t.Errorf("expected the ISO currency code KES, got %s", document.Currency)
+		return
+	}
+	if len(document.Types) != 2 || document.Types[0] != "CREDIT" || document.Types[1] != "DEBIT" {
+		t.Errorf("expected a CREDIT then a DEBIT, got %v", document.Types)
+		return
+	}
+	if document.LedgerBalance != "70.00" {
+		t.Errorf("expected a ledger balance of 70.00, got %s", document.LedgerBalance)
+		return
+	}
+}
+
+func TestRender_Camt053(t *testing.T) {
+	content, _, err := statement.Render(statement.Camt053, newTestStatement())
+	if err != nil {
+		t.Errorf("Render() error = %v", err)
+		return
+	}
+
+	var document iso20022.Camt053Document
+	if err := xml.Unmarshal(content, &document); err != nil {
+		t.Errorf("expected well formed camt.053: %v", err)
+		return
+	}
+
+	stmt := document.Statement.Statement
+	if len(stmt.Balances) != 2 {
+		t.Errorf("expected opening and closing balances, got %d", len(stmt.Balances))
+		return
+	}
+	if stmt.Balances[0].Amount.Value != "100.00" || stmt.Balances[1].Amount.Value != "70.00" {
+		t.Errorf("unexpected balances %v", stmt.Balances)
+		return
+	}
+	if len(stmt.Entries) != 2 || stmt.Entries[1].CreditDebitIndicator != iso20022.Debit {
+		t.Errorf("expected the second entry to be a debit")
+		return
+	}
+	if stmt.Summary.TotalEntries.TotalNetEntry.Amount != "30.00" ||
+		stmt.Summary.TotalEntries.TotalNetEntry.CreditDebitIndicator != iso20022.Debit {
+		t.Errorf("expected a net debit of 30.00, got %v", stmt.Summary.TotalEntries.TotalNetEntry)
+		return
+	}
+}
+
+func TestRender_UnsupportedFormat(t *testing.T) {
+	if _, _, err := statement.Render("pdf", newTestStatement()); err == nil {
+		t.Errorf("expected an unsupported format to be rejected")
+	}
+	if _, _, err := statement.Render(statement.CSV, nil); err == nil {
+		t.Errorf("expected a missing statement to be rejected")
+	}
+}
@@ -0,0 +1,65 @@
+// Package tracing exports the service's OpenTelemetry spans
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.17.0"
+)
+
+// SERVICE_NAME identifies the service's spans
+var SERVICE_NAME = "simple-money-transfer"
+
+// Span exporters, chosen with the OTEL_TRACES_EXPORTER environment variable
+var (
+	EXPORTER_NONE   = "none"
+	EXPORTER_STDOUT = "stdout"
+	EXPORTER_OTLP   = "otlp"
+)
+
+// NewExporter builds a span exporter by name, OTLP is configured by the standard OTEL_EXPORTER_OTLP_* variables
+func NewExporter(ctx context.Context, name string, stdout io.Writer) (sdktrace.SpanExporter, error) {
+	switch name {
+	case EXPORTER_STDOUT:
+		return stdouttrace.New(stdouttrace.WithWriter(stdout))
+	case EXPORTER_OTLP:
+		return otlptracegrpc.New(ctx)
+	}
+	return nil, fmt.Errorf("unknown span exporter %q, use one of %s, %s or %s", name, EXPORTER_NONE, EXPORTER_STDOUT, EXPORTER_OTLP)
+}
+
+// NewTracerProvider batches the spans of the service to an exporter
+func NewTracerProvider(exporter sdktrace.SpanExporter) *sdktrace.TracerProvider {
+	return sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(resource.NewSchemaless(semconv.ServiceName(SERVICE_NAME))),
+	)
+}
+
+// Setup installs the tracer provider of the named exporter, tracing is off when it is empty or none.
+// The returned function flushes the pending spans and should be called before exiting
+func Setup(ctx context.Context, name string) (func(context.Context) error, error) {
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+
+	if name == "" || name == EXPORTER_NONE {
+		return func(context.Context) error { return nil }, nil
+	}
+
+	exporter, err := NewExporter(ctx, name, os.Stdout)
+	if err != nil {
+		return nil, err
+	}
+
+	provider := NewTracerProvider(exporter)
+	otel.SetTracerProvider(provider)
+	return provider.Shutdown, nil
+}
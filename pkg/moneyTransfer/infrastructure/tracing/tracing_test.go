@@ -0,0 +1,53 @@
+package tracing_test
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/infrastructure/tracing"
+)
+
+func TestNewExporter(t *testing.T) {
+	tests := []struct {
+		name    string
+		wantErr bool
+	}{
+		{name: tracing.EXPORTER_STDOUT},
+		{name: tracing.EXPORTER_OTLP},
+		{name: "zipkin", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			exporter, err := tracing.NewExporter(context.Background(), tt.name, &bytes.Buffer{})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("NewExporter() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if exporter != nil {
+				_ = exporter.Shutdown(context.Background())
+			}
+		})
+	}
+}
+
+func TestNewTracerProvider(t *testing.T) {
+	var out bytes.Buffer
+	exporter, err := tracing.NewExporter(context.Background(), tracing.EXPORTER_STDOUT, &out)
+	if err != nil {
+		t.Fatalf("NewExporter() error = %v", err)
+	}
+
+	provider := tracing.NewTracerProvider(exporter)
+	_, span := provider.Tracer("test").Start(context.Background(), "MoneyTransfer.Transfer")
+	span.End()
+	if err := provider.Shutdown(context.Background()); err != nil {
+		t.Fatalf("TracerProvider.Shutdown() error = %v", err)
+	}
+
+	for _, want := range []string{`"Name":"MoneyTransfer.Transfer"`, tracing.SERVICE_NAME} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("expected %s in the exported spans, got %s", want, out.String())
+		}
+	}
+}
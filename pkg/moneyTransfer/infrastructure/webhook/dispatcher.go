@@ -0,0 +1,66 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/domain"
+	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/repository"
+)
+
+// Dispatcher is an outbox sink queueing a delivery of each event to every subscription interested in it
+type Dispatcher struct {
+	Store repository.WebhookRepository
+}
+
+// CheckPreconditions ensures a correct Dispatcher struct is initialized
+func (d Dispatcher) CheckPreconditions() {
+	if d.Store == nil {
+		log.Panic("webhook dispatcher has not initialized the webhook repository")
+	}
+}
+
+// NewDispatcher initializes a new webhook dispatcher
+func NewDispatcher(store repository.WebhookRepository) *Dispatcher {
+	d := &Dispatcher{
+		Store: store,
+	}
+	d.CheckPreconditions()
+	return d
+}
+
+// Publish fans an event out to the subscriptions about its accounts. Redelivered events are only queued once
+// per subscription
+func (d Dispatcher) Publish(ctx context.Context, event domain.EventEnvelope) error {
+	subscriptions, err := d.Store.SubscriptionsFor(ctx, event.Type, event.AccountIDs())
+	if err != nil {
+		return err
+	}
+
+	if len(subscriptions) == 0 {
+		return nil
+	}
+
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("unable to encode event %s: %v", event.ID, err)
+	}
+
+	now := time.Now()
+	var deliveries []*domain.WebhookDelivery
+	for _, subscription := range subscriptions {
+		deliveries = append(deliveries, &domain.WebhookDelivery{
+			SubscriptionID: subscription.UUID,
+			EventID:        event.ID,
+			EventType:      event.Type,
+			Payload:        string(payload),
+			Status:         domain.DeliveryPending,
+			NextAttemptAt:  now,
+		})
+	}
+
+	return d.Store.CreateDeliveries(ctx, deliveries)
+}
@@ -0,0 +1,71 @@
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SIGNATURE_HEADER carries the timestamp and HMAC-SHA256 signature of a delivery as t=<unix>,v1=<hex>
+var SIGNATURE_HEADER = "X-Webhook-Signature"
+
+// SIGNATURE_TOLERANCE is how old a signed timestamp may be before receivers should reject it as a replay
+var SIGNATURE_TOLERANCE = 5 * time.Minute
+
+// computeSignature signs the timestamp and body, so that a captured body can not be replayed with a new timestamp
+func computeSignature(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	fmt.Fprintf(mac, "%d.", timestamp)
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// Sign returns the signature header value for a body sent at a given time
+func Sign(secret string, timestamp time.Time, body []byte) string {
+	return fmt.Sprintf("t=%d,v1=%s", timestamp.Unix(), computeSignature(secret, timestamp.Unix(), body))
+}
+
+// Verify checks a signature header against a received body, as a subscriber would
+func Verify(secret string, header string, body []byte, now time.Time) error {
+	var timestamp int64
+	var signatures []string
+	for _, part := range strings.Split(header, ",") {
+		key, value, found := strings.Cut(strings.TrimSpace(part), "=")
+		if !found {
+			continue
+		}
+
+		switch key {
+		case "t":
+			parsed, err := strconv.ParseInt(value, 10, 64)
+			if err != nil {
+				return fmt.Errorf("invalid signature timestamp %q", value)
+			}
+			timestamp = parsed
+		case "v1":
+			signatures = append(signatures, value)
+		}
+	}
+
+	if timestamp == 0 || len(signatures) == 0 {
+		return fmt.Errorf("signature header should have a timestamp and a v1 signature")
+	}
+
+	age := now.Sub(time.Unix(timestamp, 0))
+	if age > SIGNATURE_TOLERANCE || age < -SIGNATURE_TOLERANCE {
+		return fmt.Errorf("signature timestamp is outside the %v tolerance", SIGNATURE_TOLERANCE)
+	}
+
+	expected := computeSignature(secret, timestamp, body)
+	for _, signature := range signatures {
+		if hmac.Equal([]byte(signature), []byte(expected)) {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("signature does not match the body")
+}
@@ -0,0 +1,452 @@
+package webhook_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/domain"
+	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/infrastructure/webhook"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+type memoryWebhooks struct {
+	mu            sync.Mutex
+	subscriptions map[string]*domain.WebhookSubscription
+	deliveries    map[string]*domain.WebhookDelivery
+}
+
+func newMemoryWebhooks() *memoryWebhooks {
+	return &memoryWebhooks{
+		subscriptions: map[string]*domain.WebhookSubscription{},
+		deliveries:    map[string]*domain.WebhookDelivery{},
+	}
+}
+
+func (m *memoryWebhooks) CreateSubscription(ctx context.Context, subscription *domain.WebhookSubscription) (*domain.WebhookSubscription, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	subscription.UUID = uuid.New().String()
+	subscription.Active = true
+	m.subscriptions[subscription.UUID] = subscription
+	return subscription, nil
+}
+
+func (m *memoryWebhooks) Subscription(ctx context.Context, subscriptionID string) (*domain.WebhookSubscription, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	subscription, ok := m.subscriptions[subscriptionID]
+	if !ok {
+		return nil, domain.NewNotFoundError("subscription_not_found", "webhook subscription %s was not found", subscriptionID)
+	}
+	return subscription, nil
+}
+
+func (m *memoryWebhooks) Subscriptions(ctx context.Context, owner string) ([]*domain.WebhookSubscription, error) {
+	return nil, nil
+}
+
+func (m *memoryWebhooks) DeleteSubscription(ctx context.Context, subscriptionID string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.subscriptions, subscriptionID)
+	return nil
+}
+
+func (m *memoryWebhooks) SubscriptionsFor(ctx context.Context, eventType domain.EventType, accountIDs []string) ([]*domain.WebhookSubscription, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var subscribed []*domain.WebhookSubscription
+	for _, subscription := range m.subscriptions {
+		if subscription.Active && subscription.Subscribes(eventType) && subscription.Covers(accountIDs) {
+			subscribed = append(subscribed, subscription)
+		}
+	}
+	return subscribed, nil
+}
+
+func (m *memoryWebhooks) CreateDeliveries(ctx context.Context, deliveries []*domain.WebhookDelivery) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, delivery := range deliveries {
+		duplicate := false
+		for _, existing := range m.deliveries {
+			if existing.SubscriptionID == delivery.SubscriptionID && existing.EventID == delivery.EventID {
+				duplicate = true
+			}
+		}
+		if !duplicate {
+			delivery.UUID = uuid.New().String()
+			m.deliveries[delivery.UUID] = delivery
+		}
+	}
+	return nil
+}
+
+func (m *memoryWebhooks) Delivery(ctx context.Context, deliveryID string) (*domain.WebhookDelivery, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.deliveries[deliveryID], nil
+}
+
+func (m *memoryWebhooks) Deliveries(ctx context.Context, subscriptionID string, status domain.DeliveryStatus) ([]*domain.WebhookDelivery, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var deliveries []*domain.WebhookDelivery
+	for _, delivery := range m.deliveries {
+		if delivery.SubscriptionID == subscriptionID && delivery.Status == status {
+			deliveries = append(deliveries, delivery)
+		}
+	}
+	return deliveries, nil
+}
+
+func (m *memoryWebhooks) ClaimDueDeliveries(ctx context.Context, asOf time.Time, lease time.Duration, limit int) ([]*domain.WebhookDelivery, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var due []*domain.WebhookDelivery
+	for _, delivery := range m.deliveries {
+		if delivery.Status == domain.DeliveryPending && !delivery.NextAttemptAt.After(asOf) {
+			delivery.NextAttemptAt = asOf.Add(lease)
+			due = append(due, delivery)
+		}
+	}
+	return due, nil
+}
+
+func (m *memoryWebhooks) UpdateDelivery(ctx context.Context, delivery *domain.WebhookDelivery) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.deliveries[delivery.UUID] = delivery
+	return nil
+}
+
+// receiver is a subscriber endpoint failing a number of requests before accepting them
+type receiver struct {
+	mu       sync.Mutex
+	secret   string
+	failures int
+	bodies   [][]byte
+	errors   []error
+}
+
+func (r *receiver) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	body, _ := io.ReadAll(req.Body)
+	if err := webhook.Verify(r.secret, req.Header.Get(webhook.SIGNATURE_HEADER), body, time.Now()); err != nil {
+		r.errors = append(r.errors, err)
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	if r.failures > 0 {
+		r.failures--
+		w.WriteHeader(http.StatusServiceUnavailable)
+		return
+	}
+	r.bodies = append(r.bodies, body)
+	w.WriteHeader(http.StatusOK)
+}
+
+// newTestWorker returns a worker allowed to deliver to the loopback test servers
+func newTestWorker(store *memoryWebhooks) *webhook.Worker {
+	worker := webhook.NewWorker(store, zap.NewNop())
+	worker.Client = &http.Client{Timeout: webhook.REQUEST_TIMEOUT}
+	worker.BaseBackoff = 0
+	worker.MaxAttempts = 3
+	return worker
+}
+
+func publishTestEvent(t *testing.T, store *memoryWebhooks, eventType domain.EventType) domain.EventEnvelope {
+	event, err := domain.NewOutboxEvent(eventType, "account-1", map[string]string{"account_id": "account-1"})
+	if err != nil {
+		t.Fatalf("unable to create event: %v", err)
+	}
+	event.Sequence = 1
+	if err := webhook.NewDispatcher(store).Publish(context.Background(), event.Envelope()); err != nil {
+		t.Fatalf("Dispatcher.Publish() error = %v", err)
+	}
+	return event.Envelope()
+}
+
+func TestWorker_DeliversSignedEvents(t *testing.T) {
+	secret := "s3cret"
+	rcv := &receiver{secret: secret, failures: 1}
+	server := httptest.NewServer(rcv)
+	defer server.Close()
+
+	store := newMemoryWebhooks()
+	_, _ = store.CreateSubscription(context.Background(), &domain.WebhookSubscription{
+		URL:        server.URL,
+		EventTypes: string(domain.TransferPosted),
+		Secret:     secret,
+	})
+	_, _ = store.CreateSubscription(context.Background(), &domain.WebhookSubscription{
+		URL:        server.URL,
+		EventTypes: string(domain.AccountOpened),
+		Secret:     secret,
+	})
+
+	event := publishTestEvent(t, store, domain.TransferPosted)
+	// The relay delivers at least once, a repeated event is not queued twice
+	if err := webhook.NewDispatcher(store).Publish(context.Background(), event); err != nil {
+		t.Errorf("Dispatcher.Publish() error = %v", err)
+		return
+	}
+	if len(store.deliveries) != 1 {
+		t.Errorf("expected a single delivery for the subscribed event type, got %d", len(store.deliveries))
+		return
+	}
+
+	worker := newTestWorker(store)
+	delivered, err := worker.DeliverDue(context.Background())
+	if err != nil || delivered != 0 {
+		t.Errorf("expected the first attempt to fail, delivered %d, error %v", delivered, err)
+		return
+	}
+	delivered, err = worker.DeliverDue(context.Background())
+	if err != nil || delivered != 1 {
+		t.Errorf("expected the retry to be delivered, delivered %d, error %v", delivered, err)
+		return
+	}
+
+	if len(rcv.errors) != 0 {
+		t.Errorf("expected valid signatures, got %v", rcv.errors)
+	}
+	if len(rcv.bodies) != 1 {
+		t.Errorf("expected the event to be received once, got %d", len(rcv.bodies))
+	}
+	for _, delivery := range store.deliveries {
+		if delivery.Status != domain.DeliveryDelivered || delivery.Attempts != 2 {
+			t.Errorf("expected a delivered event after 2 attempts, got %s after %d", delivery.Status, delivery.Attempts)
+		}
+	}
+}
+
+func TestDispatcher_OwnersAccounts(t *testing.T) {
+	store := newMemoryWebhooks()
+	own, _ := store.CreateSubscription(context.Background(), &domain.WebhookSubscription{
+		Owner:      "owner-1",
+		URL:        "https://partner-1.example.com/hooks",
+		EventTypes: string(domain.TransferPosted),
+		AccountIDs: "account-1",
+	})
+	_, _ = store.CreateSubscription(context.Background(), &domain.WebhookSubscription{
+		Owner:      "owner-2",
+		URL:        "https://partner-2.example.com/hooks",
+		EventTypes: string(domain.TransferPosted),
+		AccountIDs: "account-2,account-3",
+	})
+
+	event, err := domain.NewOutboxEvent(domain.TransferPosted, "transaction-1", domain.TransferPayload{
+		TransactionID:        "transaction-1",
+		SourceAccountID:      "account-4",
+		DestinationAccountID: "account-1",
+	})
+	if err != nil {
+		t.Fatalf("unable to create event: %v", err)
+	}
+	if err := webhook.NewDispatcher(store).Publish(context.Background(), event.Envelope()); err != nil {
+		t.Fatalf("Dispatcher.Publish() error = %v", err)
+	}
+
+	if len(store.deliveries) != 1 {
+		t.Fatalf("expected a single delivery, to the subscription about the event's account, got %d", len(store.deliveries))
+	}
+	for _, delivery := range store.deliveries {
+		if delivery.SubscriptionID != own.UUID {
+			t.Errorf("expected another owner's event not to be delivered to %s", delivery.SubscriptionID)
+		}
+	}
+}
+
+func TestWorker_DeadLetters(t *testing.T) {
+	rcv := &receiver{secret: "s3cret", failures: 100}
+	server := httptest.NewServer(rcv)
+	defer server.Close()
+
+	store := newMemoryWebhooks()
+	subscription, _ := store.CreateSubscription(context.Background(), &domain.WebhookSubscription{
+		URL:        server.URL,
+		EventTypes: string(domain.AccountOpened),
+		Secret:     "s3cret",
+	})
+	publishTestEvent(t, store, domain.AccountOpened)
+
+	worker := newTestWorker(store)
+	for i := 0; i < 5; i++ {
+		if _, err := worker.DeliverDue(context.Background()); err != nil {
+			t.Errorf("Worker.DeliverDue() error = %v", err)
+			return
+		}
+	}
+
+	dead, _ := store.Deliveries(context.Background(), subscription.UUID, domain.DeliveryDead)
+	if len(dead) != 1 {
+		t.Errorf("expected the delivery to be dead lettered")
+		return
+	}
+	if dead[0].Attempts != worker.MaxAttempts || dead[0].LastStatusCode != http.StatusServiceUnavailable {
+		t.Errorf("expected %d attempts ending in a 503, got %d ending in %d", worker.MaxAttempts, dead[0].Attempts, dead[0].LastStatusCode)
+	}
+}
+
+// unavailableWebhooks fails every subscription lookup as the database would when unreachable
+type unavailableWebhooks struct {
+	*memoryWebhooks
+}
+
+func (unavailableWebhooks) Subscription(ctx context.Context, subscriptionID string) (*domain.WebhookSubscription, error) {
+	return nil, fmt.Errorf("unable to get webhook subscription %s: connection refused", subscriptionID)
+}
+
+func TestWorker_SubscriptionLookupFails(t *testing.T) {
+	store := newMemoryWebhooks()
+	subscription, _ := store.CreateSubscription(context.Background(), &domain.WebhookSubscription{
+		URL:        "https://partner.example.com/hooks",
+		EventTypes: string(domain.AccountOpened),
+		Secret:     "s3cret",
+	})
+	publishTestEvent(t, store, domain.AccountOpened)
+
+	worker := webhook.NewWorker(unavailableWebhooks{store}, zap.NewNop())
+	if _, err := worker.DeliverDue(context.Background()); err != nil {
+		t.Fatalf("Worker.DeliverDue() error = %v", err)
+	}
+	for _, delivery := range store.deliveries {
+		if delivery.Status != domain.DeliveryPending || delivery.Attempts != 0 {
+			t.Errorf("expected the delivery to stay pending, got %d attempts and status %s", delivery.Attempts, delivery.Status)
+		}
+		if !delivery.NextAttemptAt.After(time.Now()) {
+			t.Errorf("expected the delivery to stay claimed until its lease runs out")
+		}
+		// Let the lease run out
+		delivery.NextAttemptAt = time.Now()
+	}
+
+	// A deleted subscription has nowhere to be delivered to
+	_ = store.DeleteSubscription(context.Background(), subscription.UUID)
+	if _, err := newTestWorker(store).DeliverDue(context.Background()); err != nil {
+		t.Fatalf("Worker.DeliverDue() error = %v", err)
+	}
+	dead, _ := store.Deliveries(context.Background(), subscription.UUID, domain.DeliveryDead)
+	if len(dead) != 1 {
+		t.Errorf("expected the delivery of a deleted subscription to be dead lettered")
+	}
+}
+
+func TestWorker_InternalAddresses(t *testing.T) {
+	rcv := &receiver{secret: "s3cret"}
+	server := httptest.NewServer(rcv)
+	defer server.Close()
+
+	store := newMemoryWebhooks()
+	_, _ = store.CreateSubscription(context.Background(), &domain.WebhookSubscription{
+		URL:        server.URL,
+		EventTypes: string(domain.AccountOpened),
+		Secret:     "s3cret",
+	})
+	publishTestEvent(t, store, domain.AccountOpened)
+
+	if _, err := webhook.NewWorker(store, zap.NewNop()).DeliverDue(context.Background()); err != nil {
+		t.Fatalf("Worker.DeliverDue() error = %v", err)
+	}
+	if len(rcv.bodies) != 0 {
+		t.Errorf("expected nothing to be posted to a loopback address")
+	}
+	for _, delivery := range store.deliveries {
+		if !strings.Contains(delivery.LastError, "not a public address") {
+			t.Errorf("expected the delivery to fail on the loopback address, got %q", delivery.LastError)
+		}
+	}
+}
+
+func TestWorker_Cancelled(t *testing.T) {
+	rcv := &receiver{secret: "s3cret"}
+	server := httptest.NewServer(rcv)
+	defer server.Close()
+
+	store := newMemoryWebhooks()
+	_, _ = store.CreateSubscription(context.Background(), &domain.WebhookSubscription{
+		URL:        server.URL,
+		EventTypes: string(domain.AccountOpened),
+		Secret:     "s3cret",
+	})
+	publishTestEvent(t, store, domain.AccountOpened)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := newTestWorker(store).DeliverDue(ctx); !errors.Is(err, context.Canceled) {
+		t.Errorf("expected a cancelled worker to stop, got %v", err)
+	}
+
+	for _, delivery := range store.deliveries {
+		if delivery.Attempts != 0 || delivery.Status != domain.DeliveryPending {
+			t.Errorf("expected the delivery to be left for the next run, got %d attempts and status %s", delivery.Attempts, delivery.Status)
+		}
+	}
+	if len(rcv.bodies) != 0 {
+		t.Errorf("expected nothing to be posted once cancelled")
+	}
+}
+
+func TestWorker_Backoff(t *testing.T) {
+	worker := webhook.NewWorker(newMemoryWebhooks(), zap.NewNop())
+	worker.BaseBackoff = time.Second
+	worker.MaxBackoff = 10 * time.Second
+
+	tests := []struct {
+		attempts int
+		want     time.Duration
+	}{
+		{attempts: 1, want: time.Second},
+		{attempts: 2, want: 2 * time.Second},
+		{attempts: 4, want: 8 * time.Second},
+		{attempts: 5, want: 10 * time.Second},
+		{attempts: 50, want: 10 * time.Second},
+	}
+	for _, tt := range tests {
+		if got := worker.Backoff(tt.attempts); got != tt.want {
+			t.Errorf("Worker.Backoff(%d) = %v, want %v", tt.attempts, got, tt.want)
+		}
+	}
+}
+
+func TestVerify(t *testing.T) {
+	body := []byte(`{"id":"1"}`)
+	now := time.Now()
+	header := webhook.Sign("s3cret", now, body)
+
+	tests := []struct {
+		name    string
+		secret  string
+		header  string
+		body    []byte
+		now     time.Time
+		wantErr bool
+	}{
+		{name: "valid signature", secret: "s3cret", header: header, body: body, now: now},
+		{name: "wrong secret", secret: "other", header: header, body: body, now: now, wantErr: true},
+		{name: "tampered body", secret: "s3cret", header: header, body: []byte(`{"id":"2"}`), now: now, wantErr: true},
+		{name: "replayed", secret: "s3cret", header: header, body: body, now: now.Add(time.Hour), wantErr: true},
+		{name: "malformed header", secret: "s3cret", header: "v1=abc", body: body, now: now, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := webhook.Verify(tt.secret, tt.header, tt.body, tt.now)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Verify() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
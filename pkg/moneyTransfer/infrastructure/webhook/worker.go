@@ -0,0 +1,230 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"syscall"
+	"time"
+
+	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/domain"
+	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/repository"
+	"go.uber.org/zap"
+)
+
+// Delivery defaults, a delivery is dead lettered after MAX_ATTEMPTS failures
+var (
+	MAX_ATTEMPTS    = 8
+	BASE_BACKOFF    = 30 * time.Second
+	MAX_BACKOFF     = 6 * time.Hour
+	REQUEST_TIMEOUT = 10 * time.Second
+	WORKER_INTERVAL = time.Second
+	WORKER_BATCH    = 50
+
+	// CLAIM_LEASE keeps claimed deliveries from other workers for as long as a batch may take to post
+	CLAIM_LEASE = time.Duration(WORKER_BATCH) * REQUEST_TIMEOUT
+)
+
+// Headers identifying a delivery to subscribers
+var (
+	EVENT_HEADER    = "X-Webhook-Event"
+	DELIVERY_HEADER = "X-Webhook-Delivery"
+)
+
+// Worker posts due webhook deliveries to their subscriptions, retrying failures with exponential backoff
+type Worker struct {
+	Store       repository.WebhookRepository
+	Client      *http.Client
+	Logger      *zap.Logger
+	MaxAttempts int
+	BaseBackoff time.Duration
+	MaxBackoff  time.Duration
+	BatchSize   int
+	Interval    time.Duration
+
+	// Lease is how long claimed deliveries are kept from other workers, a worker that stops
+	// before attempting them leaves them to be claimed again once it runs out
+	Lease time.Duration
+}
+
+// CheckPreconditions ensures a correct Worker struct is initialized
+func (w Worker) CheckPreconditions() {
+	if w.Store == nil {
+		log.Panic("webhook worker has not initialized the webhook repository")
+	}
+
+	if w.Client == nil {
+		log.Panic("webhook worker has not initialized the http client")
+	}
+
+	if w.Logger == nil {
+		log.Panic("webhook worker has not initialized the logger")
+	}
+}
+
+// NewWorker initializes a new webhook delivery worker
+func NewWorker(store repository.WebhookRepository, logger *zap.Logger) *Worker {
+	w := &Worker{
+		Store:       store,
+		Client:      NewClient(REQUEST_TIMEOUT),
+		Logger:      logger,
+		MaxAttempts: MAX_ATTEMPTS,
+		BaseBackoff: BASE_BACKOFF,
+		MaxBackoff:  MAX_BACKOFF,
+		BatchSize:   WORKER_BATCH,
+		Interval:    WORKER_INTERVAL,
+		Lease:       CLAIM_LEASE,
+	}
+	w.CheckPreconditions()
+	return w
+}
+
+// NewClient returns the client deliveries are posted with. It refuses to connect to addresses that are not public,
+// redirects included, so that subscribers can not reach the network the service runs in. Deliveries are not
+// posted through a proxy, the proxy would be the address checked
+func NewClient(timeout time.Duration) *http.Client {
+	dialer := &net.Dialer{
+		Timeout: timeout,
+		Control: func(network string, address string, _ syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			if ip := net.ParseIP(host); ip == nil || !domain.IsPublicAddress(ip) {
+				return fmt.Errorf("webhooks can not be delivered to %s, it is not a public address", host)
+			}
+			return nil
+		},
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+	return &http.Client{Timeout: timeout, Transport: transport}
+}
+
+// Backoff returns the wait before the next attempt of a delivery that failed attempts times
+func (w Worker) Backoff(attempts int) time.Duration {
+	backoff := w.BaseBackoff
+	for i := 1; i < attempts; i++ {
+		backoff *= 2
+		if backoff >= w.MaxBackoff {
+			return w.MaxBackoff
+		}
+	}
+	return backoff
+}
+
+// DeliverDue attempts the deliveries that are due, returning how many were delivered
+func (w Worker) DeliverDue(ctx context.Context) (int, error) {
+	deliveries, err := w.Store.ClaimDueDeliveries(ctx, time.Now(), w.Lease, w.BatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	delivered := 0
+	for _, delivery := range deliveries {
+		// Stop between deliveries rather than record attempts cut short by the cancellation as failures
+		if err := ctx.Err(); err != nil {
+			return delivered, err
+		}
+		if err := w.Deliver(ctx, delivery); err != nil {
+			w.Logger.Error("unable to attempt a webhook delivery", zap.String("delivery_id", delivery.UUID), zap.Error(err))
+			continue
+		}
+		if delivery.Status == domain.DeliveryDelivered {
+			delivered++
+		}
+	}
+
+	return delivered, nil
+}
+
+// Deliver makes one attempt at a delivery and records its outcome.
+// The returned error is set when the subscription could not be looked up, the delivery is then left pending
+// without counting an attempt, or when the outcome could not be recorded
+func (w Worker) Deliver(ctx context.Context, delivery *domain.WebhookDelivery) error {
+	subscription, err := w.Store.Subscription(ctx, delivery.SubscriptionID)
+	if domain.IsKind(err, domain.NotFound) {
+		// The subscription was deleted, there is nowhere to deliver to
+		delivery.Attempts++
+		delivery.Status = domain.DeliveryDead
+		delivery.LastError = err.Error()
+		return w.Store.UpdateDelivery(ctx, delivery)
+	}
+	if err != nil {
+		return err
+	}
+
+	delivery.Attempts++
+
+	statusCode, err := w.post(ctx, subscription, delivery)
+	delivery.LastStatusCode = statusCode
+	if err == nil {
+		now := time.Now()
+		delivery.Status = domain.DeliveryDelivered
+		delivery.DeliveredAt = &now
+		delivery.LastError = ""
+		return w.Store.UpdateDelivery(ctx, delivery)
+	}
+
+	delivery.LastError = err.Error()
+	if delivery.Attempts >= w.MaxAttempts {
+		delivery.Status = domain.DeliveryDead
+		w.Logger.Warn("webhook delivery dead lettered",
+			zap.String("delivery_id", delivery.UUID),
+			zap.String("subscription_id", delivery.SubscriptionID),
+			zap.Int("attempts", delivery.Attempts),
+			zap.Error(err),
+		)
+	} else {
+		delivery.NextAttemptAt = time.Now().Add(w.Backoff(delivery.Attempts))
+	}
+
+	return w.Store.UpdateDelivery(ctx, delivery)
+}
+
+// post sends a signed delivery, succeeding only on a 2xx response
+func (w Worker) post(ctx context.Context, subscription *domain.WebhookSubscription, delivery *domain.WebhookDelivery) (int, error) {
+	body := []byte(delivery.Payload)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, subscription.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, fmt.Errorf("unable to create webhook request: %v", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(EVENT_HEADER, string(delivery.EventType))
+	req.Header.Set(DELIVERY_HEADER, delivery.UUID)
+	req.Header.Set(SIGNATURE_HEADER, Sign(subscription.Secret, time.Now(), body))
+
+	resp, err := w.Client.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("unable to post webhook: %v", err)
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 1<<16))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.StatusCode, fmt.Errorf("subscriber responded with status %d", resp.StatusCode)
+	}
+
+	return resp.StatusCode, nil
+}
+
+// Run delivers due webhooks until ctx is done
+func (w Worker) Run(ctx context.Context) {
+	for {
+		if _, err := w.DeliverDue(ctx); err != nil && ctx.Err() == nil {
+			w.Logger.Warn("unable to deliver the due webhooks", zap.Error(err))
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(w.Interval):
+		}
+	}
+}
@@ -0,0 +1,174 @@
+// Package logging sets up the service's structured logs, redacting credentials and tying each entry to the
+// request it was written for
+package logging
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// DEFAULT_LEVEL is the minimum level logged when none is configured
+var DEFAULT_LEVEL = "info"
+
+// REQUEST_ID_HEADER carries a request's ID, received from clients and proxies or generated, and sent back in responses
+var REQUEST_ID_HEADER = "X-Request-ID"
+
+// MAX_REQUEST_ID_LENGTH bounds the request IDs accepted from clients
+var MAX_REQUEST_ID_LENGTH = 128
+
+// REDACTED replaces the credentials found in log entries
+var REDACTED = "[REDACTED]"
+
+// SENSITIVE_KEYS are the field names, matched case insensitively anywhere in a key, whose values are never logged
+var SENSITIVE_KEYS = []string{"authorization", "token", "secret", "password", "passwd", "dsn", "api_key", "apikey", "cookie"}
+
+// sensitiveValues matches credentials wherever they appear in a message or value: bearer tokens, JWTs and
+// key=value pairs naming a secret
+var sensitiveValues = regexp.MustCompile(
+	`(?i)(bearer\s+)[^\s"',]+` +
+		`|eyJ[\w-]+\.[\w-]+\.[\w-]*` +
+		`|((?:password|passwd|secret|token|api_key|apikey)\s*[=:]\s*)[^\s"',&]+`,
+)
+
+// requestIDPattern is the alphabet of the request IDs accepted from clients
+var requestIDPattern = regexp.MustCompile(`^[\w.:-]+$`)
+
+type requestIDKey struct{}
+
+type loggerKey struct{}
+
+// New builds a JSON logger writing entries of level and above to w, with credentials redacted
+func New(level string, w io.Writer) (*zap.Logger, error) {
+	if level == "" {
+		level = DEFAULT_LEVEL
+	}
+	var minLevel zapcore.Level
+	if err := minLevel.UnmarshalText([]byte(level)); err != nil {
+		return nil, fmt.Errorf("unknown log level %q: %v", level, err)
+	}
+
+	config := zap.NewProductionEncoderConfig()
+	config.TimeKey = "time"
+	config.MessageKey = "message"
+	config.EncodeTime = zapcore.RFC3339NanoTimeEncoder
+	config.EncodeDuration = zapcore.MillisDurationEncoder
+
+	core := zapcore.NewCore(zapcore.NewJSONEncoder(config), zapcore.Lock(zapcore.AddSync(w)), minLevel)
+	return zap.New(redactingCore{core}, zap.AddCaller()), nil
+}
+
+// Sensitive reports whether a field's value should never be logged, judging by its name
+func Sensitive(key string) bool {
+	key = strings.ToLower(key)
+	for _, sensitive := range SENSITIVE_KEYS {
+		if strings.Contains(key, sensitive) {
+			return true
+		}
+	}
+	return false
+}
+
+// Redact masks the credentials found in a message or value
+func Redact(s string) string {
+	return sensitiveValues.ReplaceAllStringFunc(s, func(match string) string {
+		groups := sensitiveValues.FindStringSubmatch(match)
+		for _, prefix := range groups[1:] {
+			if prefix != "" {
+				return prefix + REDACTED
+			}
+		}
+		return REDACTED
+	})
+}
+
+// redactingCore masks credentials in the entries and fields it is given before they are encoded
+type redactingCore struct {
+	zapcore.Core
+}
+
+func (c redactingCore) With(fields []zapcore.Field) zapcore.Core {
+	return redactingCore{c.Core.With(redact(fields))}
+}
+
+func (c redactingCore) Check(entry zapcore.Entry, checked *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(entry.Level) {
+		return checked.AddCore(entry, c)
+	}
+	return checked
+}
+
+func (c redactingCore) Write(entry zapcore.Entry, fields []zapcore.Field) error {
+	entry.Message = Redact(entry.Message)
+	return c.Core.Write(entry, redact(fields))
+}
+
+// redact masks sensitive fields and the credentials in string and error fields
+func redact(fields []zapcore.Field) []zapcore.Field {
+	redacted := make([]zapcore.Field, len(fields))
+	for i, field := range fields {
+		switch {
+		case Sensitive(field.Key):
+			redacted[i] = zap.String(field.Key, REDACTED)
+		case field.Type == zapcore.StringType:
+			redacted[i] = zap.String(field.Key, Redact(field.String))
+		case field.Type == zapcore.ErrorType:
+			redacted[i] = zap.String(field.Key, Redact(field.Interface.(error).Error()))
+		default:
+			redacted[i] = field
+		}
+	}
+	return redacted
+}
+
+// NewRequestID returns the ID a request was received with when it is well formed, or a new one
+func NewRequestID(received string) string {
+	if received != "" && len(received) <= MAX_REQUEST_ID_LENGTH && requestIDPattern.MatchString(received) {
+		return received
+	}
+	return uuid.NewString()
+}
+
+// WithRequestID returns a copy of ctx carrying the ID of the request it serves
+func WithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, requestID)
+}
+
+// RequestID returns the ID of the request ctx serves, if any
+func RequestID(ctx context.Context) string {
+	requestID, _ := ctx.Value(requestIDKey{}).(string)
+	return requestID
+}
+
+// For returns logger with the IDs of the request and trace ctx belongs to
+func For(ctx context.Context, logger *zap.Logger) *zap.Logger {
+	var fields []zap.Field
+	if requestID := RequestID(ctx); requestID != "" {
+		fields = append(fields, zap.String("request_id", requestID))
+	}
+	if span := trace.SpanContextFromContext(ctx); span.IsValid() {
+		fields = append(fields, zap.String("trace_id", span.TraceID().String()), zap.String("span_id", span.SpanID().String()))
+	}
+	return logger.With(fields...)
+}
+
+// NewContext returns a copy of ctx carrying the logger of the layers that are not given one
+func NewContext(ctx context.Context, logger *zap.Logger) context.Context {
+	return context.WithValue(ctx, loggerKey{}, logger)
+}
+
+// FromContext returns the logger carried by ctx, tied to ctx's request, or a logger discarding everything
+func FromContext(ctx context.Context) *zap.Logger {
+	logger, ok := ctx.Value(loggerKey{}).(*zap.Logger)
+	if !ok {
+		return zap.NewNop()
+	}
+	return For(ctx, logger)
+}
@@ -0,0 +1,140 @@
+// Package metrics defines the operational metrics the service records, so that the layers recording them do not
+// depend on the monitoring system collecting them
+package metrics
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/domain"
+	"github.com/shopspring/decimal"
+)
+
+// SUCCESS is the outcome of an operation that did not fail
+var SUCCESS = "success"
+
+// FAILURE is the outcome of an operation that failed with an untyped error
+var FAILURE = "failure"
+
+// RequestRecorder records the API requests served
+type RequestRecorder interface {
+	ObserveRequest(method string, route string, status int, duration time.Duration)
+}
+
+// TransferRecorder records the transfers attempted
+type TransferRecorder interface {
+	ObserveTransfer(currency domain.CurrencyType, outcome string, amount decimal.Decimal)
+}
+
+// QueryRecorder records the database queries run
+type QueryRecorder interface {
+	ObserveQuery(operation string, table string, duration time.Duration)
+}
+
+// LockRecorder records how long callers waited for a lock
+type LockRecorder interface {
+	ObserveLockWait(lock string, wait time.Duration)
+}
+
+// OutboxRecorder records how long domain events waited in the outbox before being published
+type OutboxRecorder interface {
+	ObserveOutboxLag(eventType domain.EventType, lag time.Duration)
+}
+
+// Recorder records every metric of the service
+type Recorder interface {
+	RequestRecorder
+	TransferRecorder
+	QueryRecorder
+	LockRecorder
+	OutboxRecorder
+}
+
+// Noop discards every metric, for code running without monitoring
+type Noop struct{}
+
+// ObserveRequest discards a request
+func (Noop) ObserveRequest(string, string, int, time.Duration) {}
+
+// ObserveTransfer discards a transfer
+func (Noop) ObserveTransfer(domain.CurrencyType, string, decimal.Decimal) {}
+
+// ObserveQuery discards a query
+func (Noop) ObserveQuery(string, string, time.Duration) {}
+
+// ObserveLockWait discards a lock wait
+func (Noop) ObserveLockWait(string, time.Duration) {}
+
+// ObserveOutboxLag discards an outbox lag
+func (Noop) ObserveOutboxLag(domain.EventType, time.Duration) {}
+
+// Outcome labels the result of an operation with the kind of error it failed with
+func Outcome(err error) string {
+	if err == nil {
+		return SUCCESS
+	}
+
+	var typed *domain.Error
+	if errors.As(err, &typed) {
+		return strings.ToLower(string(typed.Kind))
+	}
+	return FAILURE
+}
+
+// ContextLocker is a lock whose wait is given up once a context is done
+type ContextLocker interface {
+	sync.Locker
+	LockContext(ctx context.Context) error
+}
+
+// LockContext locks l, giving up once ctx is done when l is a ContextLocker
+func LockContext(ctx context.Context, l sync.Locker) error {
+	if locker, ok := l.(ContextLocker); ok {
+		return locker.LockContext(ctx)
+	}
+	l.Lock()
+	return nil
+}
+
+// Mutex is a mutual exclusion lock recording how long Lock waited, once a Recorder is set
+type Mutex struct {
+	Name     string
+	Recorder LockRecorder
+
+	once   sync.Once
+	holder chan struct{}
+}
+
+// Lock locks the mutex, recording the wait
+func (m *Mutex) Lock() {
+	_ = m.LockContext(context.Background())
+}
+
+// LockContext locks the mutex unless ctx is done first, recording the wait either way
+func (m *Mutex) LockContext(ctx context.Context) error {
+	m.once.Do(func() { m.holder = make(chan struct{}, 1) })
+
+	start := time.Now()
+	var err error
+	select {
+	case m.holder <- struct{}{}:
+	case <-ctx.Done():
+		err = ctx.Err()
+	}
+	if m.Recorder != nil {
+		m.Recorder.ObserveLockWait(m.Name, time.Since(start))
+	}
+	return err
+}
+
+// Unlock unlocks the mutex
+func (m *Mutex) Unlock() {
+	select {
+	case <-m.holder:
+	default:
+		panic("metrics: unlock of unlocked mutex " + m.Name)
+	}
+}
@@ -0,0 +1,105 @@
+package metrics_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/domain"
+	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/metrics"
+)
+
+func TestOutcome(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want string
+	}{
+		{
+			name: "no error",
+			want: metrics.SUCCESS,
+		},
+		{
+			name: "typed error",
+			err:  domain.NewInsufficientFundsError("not enough"),
+			want: "insufficient_funds",
+		},
+		{
+			name: "wrapped typed error",
+			err:  fmt.Errorf("transfer: %w", domain.NewValidationError("invalid_amount", "invalid amount")),
+			want: "validation",
+		},
+		{
+			name: "untyped error",
+			err:  fmt.Errorf("connection refused"),
+			want: metrics.FAILURE,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := metrics.Outcome(tt.err); got != tt.want {
+				t.Errorf("Outcome() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+type lockRecorder struct {
+	mu    sync.Mutex
+	waits map[string][]time.Duration
+}
+
+func (r *lockRecorder) ObserveLockWait(lock string, wait time.Duration) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.waits[lock] = append(r.waits[lock], wait)
+}
+
+func TestMutex(t *testing.T) {
+	recorder := &lockRecorder{waits: map[string][]time.Duration{}}
+	mutex := &metrics.Mutex{Name: "test", Recorder: recorder}
+
+	mutex.Lock()
+	acquired := make(chan struct{})
+	go func() {
+		mutex.Lock()
+		close(acquired)
+		mutex.Unlock()
+	}()
+	time.Sleep(20 * time.Millisecond)
+	mutex.Unlock()
+	<-acquired
+
+	waits := recorder.waits["test"]
+	if len(waits) != 2 {
+		t.Fatalf("expected both locks to be recorded, got %v", waits)
+	}
+	if waits[1] < 20*time.Millisecond {
+		t.Errorf("expected the second lock to wait for the first to be released, waited %v", waits[1])
+	}
+}
+
+func TestMutex_LockContext(t *testing.T) {
+	recorder := &lockRecorder{waits: map[string][]time.Duration{}}
+	mutex := &metrics.Mutex{Name: "test", Recorder: recorder}
+
+	mutex.Lock()
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if err := metrics.LockContext(ctx, mutex); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected the wait to be given up at the deadline, got %v", err)
+	}
+	mutex.Unlock()
+
+	if err := metrics.LockContext(context.Background(), mutex); err != nil {
+		t.Fatalf("expected the released mutex to be locked, got %v", err)
+	}
+	mutex.Unlock()
+
+	if waits := recorder.waits["test"]; len(waits) != 3 || waits[1] < 20*time.Millisecond {
+		t.Errorf("expected the abandoned wait to be recorded too, got %v", waits)
+	}
+}
@@ -0,0 +1,45 @@
+package graph
+
+import (
+	"context"
+
+	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/domain"
+	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/logging"
+	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/presentation/validation"
+	"go.uber.org/zap"
+)
+
+// fieldError reports a failure in a GraphQL response, with its stable code in the error's extensions
+type fieldError struct {
+	message string
+	code    string
+	fields  map[string]string
+}
+
+func (e fieldError) Error() string {
+	return e.message
+}
+
+// Extensions are added to the error's entry in the response
+func (e fieldError) Extensions() map[string]interface{} {
+	extensions := map[string]interface{}{"code": e.code}
+	if len(e.fields) > 0 {
+		extensions["fields"] = e.fields
+	}
+	return extensions
+}
+
+// resolverError reports a usecase error with its code.
+// Failed validations are reported per field, internal errors are logged and reported without their cause
+func resolverError(ctx context.Context, err error) error {
+	if fields, ok := validation.FieldErrors(err); ok {
+		return fieldError{message: "the request has invalid fields", code: "invalid_request", fields: fields}
+	}
+
+	typed := domain.AsError(err)
+	if typed.Kind == domain.Internal {
+		logging.FromContext(ctx).Error("GraphQL field failed", zap.Error(err))
+	}
+
+	return fieldError{message: typed.Message, code: typed.Code}
+}
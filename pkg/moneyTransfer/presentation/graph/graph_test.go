@@ -0,0 +1,301 @@
+package graph_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/application"
+	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/domain"
+	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/presentation/graph"
+	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/presentation/validation"
+	"github.com/gin-gonic/gin"
+	"github.com/shopspring/decimal"
+)
+
+var (
+	sourceID      = "0b0e4f5c-6f5e-4a4e-9b3a-4a1f2d3c4b5a"
+	destinationID = "9f1c2d3e-4b5a-4c6d-8e7f-0a1b2c3d4e5f"
+)
+
+// stubQueries serves a small ledger of two accounts and a transaction between them, counting its calls
+type stubQueries struct {
+	mu    sync.Mutex
+	calls map[string]int
+	pages []application.PageInput
+}
+
+func (s *stubQueries) record(call string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.calls[call]++
+}
+
+func (s *stubQueries) account(id string) *domain.Account {
+	return &domain.Account{AbstractBase: domain.AbstractBase{UUID: id}, Name: "account " + id, Currency: domain.Kenyan, Header: domain.Deposit, BalanceType: domain.Credit}
+}
+
+func (s *stubQueries) Accounts(ctx context.Context, filter application.AccountFilter, page application.PageInput) (*application.Page[*domain.Account], error) {
+	s.record("Accounts")
+	s.mu.Lock()
+	s.pages = append(s.pages, page)
+	s.mu.Unlock()
+	return &application.Page[*domain.Account]{
+		Items:       []*domain.Account{s.account(sourceID), s.account(destinationID)},
+		HasNextPage: true,
+		EndCursor:   "next",
+	}, nil
+}
+
+func (s *stubQueries) AccountsByID(ctx context.Context, ids []string) ([]*domain.Account, error) {
+	s.record("AccountsByID")
+	var accounts []*domain.Account
+	for _, id := range ids {
+		if id == sourceID || id == destinationID {
+			accounts = append(accounts, s.account(id))
+		}
+	}
+	return accounts, nil
+}
+
+func (s *stubQueries) AccountBalances(ctx context.Context, ids []string) (map[string]decimal.Decimal, error) {
+	s.record("AccountBalances")
+	balances := map[string]decimal.Decimal{}
+	for _, id := range ids {
+		balances[id] = decimal.NewFromInt(60)
+	}
+	return balances, nil
+}
+
+func (s *stubQueries) Transactions(ctx context.Context, filter application.TransactionFilter, page application.PageInput) (*application.Page[*domain.Transaction], error) {
+	s.record("Transactions")
+	if filter.From != nil && filter.To != nil && filter.To.Before(*filter.From) {
+		return nil, domain.NewValidationError("invalid_period", "the start of the period should be before its end")
+	}
+	return &application.Page[*domain.Transaction]{Items: []*domain.Transaction{{AbstractBase: domain.AbstractBase{UUID: "transaction-1"}}}}, nil
+}
+
+func (s *stubQueries) TransactionsByID(ctx context.Context, ids []string) ([]*domain.Transaction, error) {
+	s.record("TransactionsByID")
+	return []*domain.Transaction{{AbstractBase: domain.AbstractBase{UUID: "transaction-1"}}}, nil
+}
+
+func (s *stubQueries) Entries(ctx context.Context, filter application.EntryFilter, page application.PageInput) (*application.Page[*domain.AccountEntry], error) {
+	s.record("Entries")
+	return &application.Page[*domain.AccountEntry]{}, nil
+}
+
+func (s *stubQueries) EntriesByTransaction(ctx context.Context, ids []string) ([]*domain.AccountEntry, error) {
+	s.record("EntriesByTransaction")
+	return []*domain.AccountEntry{
+		{AbstractBase: domain.AbstractBase{UUID: "entry-1"}, CreditAmount: decimal.NewFromInt(40), AccountID: sourceID, TransactionID: "transaction-1"},
+		{AbstractBase: domain.AbstractBase{UUID: "entry-2"}, DebitAmount: decimal.NewFromInt(40), AccountID: destinationID, TransactionID: "transaction-1"},
+	}, nil
+}
+
+// HELD_AMOUNT is held for review by the stub money transfer usecase
+var HELD_AMOUNT = decimal.NewFromInt(99)
+
+type stubMoneyTransfer struct{}
+
+func (stubMoneyTransfer) CreateCustomerAccount(context.Context, application.AccountCreationInput) (*application.AccountInformationOutput, error) {
+	return nil, fmt.Errorf("not implemented")
+}
+
+func (stubMoneyTransfer) Account(ctx context.Context, accountID string) (*application.AccountInformationOutput, error) {
+	balance := decimal.NewFromInt(100)
+	return &application.AccountInformationOutput{UUID: accountID, Currency: domain.Kenyan, Balance: &balance}, nil
+}
+
+func (stubMoneyTransfer) Transfer(ctx context.Context, input application.TransferInput) (*domain.Transaction, error) {
+	if input.Amount.Equal(HELD_AMOUNT) {
+		return nil, domain.NewHeldError("held-1")
+	}
+	if input.Amount.GreaterThan(*input.SourceAccount.Balance) {
+		return nil, domain.NewInsufficientFundsError("%v is more than the balance", input.Amount)
+	}
+	return &domain.Transaction{AbstractBase: domain.AbstractBase{UUID: "transaction-1"}}, nil
+}
+
+func (stubMoneyTransfer) ReverseTransfer(context.Context, string, string) (*domain.Transaction, error) {
+	return nil, fmt.Errorf("not implemented")
+}
+
+type response struct {
+	Data   map[string]interface{} `json:"data"`
+	Errors []struct {
+		Message    string                 `json:"message"`
+		Extensions map[string]interface{} `json:"extensions"`
+	} `json:"errors"`
+}
+
+func execute(t *testing.T, queries *stubQueries, query string, variables map[string]interface{}) response {
+	if err := validation.RegisterValidators(); err != nil {
+		t.Fatalf("RegisterValidators() error = %v", err)
+	}
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.POST("/graphql", graph.NewHandler(stubMoneyTransfer{}, queries, &sync.Mutex{}).Serve)
+
+	body, _ := json.Marshal(graph.Request{Query: query, Variables: variables})
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "/graphql", strings.NewReader(string(body))))
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("expected the query to be executed, got %v: %s", recorder.Code, recorder.Body)
+	}
+
+	var decoded response
+	if err := json.Unmarshal(recorder.Body.Bytes(), &decoded); err != nil {
+		t.Fatalf("unable to decode the response: %v", err)
+	}
+	return decoded
+}
+
+func TestHandler_BatchesRelationships(t *testing.T) {
+	queries := &stubQueries{calls: map[string]int{}}
+	res := execute(t, queries, `{
+		accounts(first: 2, filter: {currency: KSH}) {
+			nodes { id balance }
+			pageInfo { hasNextPage endCursor }
+		}
+		transactions {
+			nodes {
+				id
+				entries { id creditAmount account { id name balance } transaction { id } }
+			}
+		}
+	}`, nil)
+	if len(res.Errors) != 0 {
+		t.Fatalf("unexpected errors %v", res.Errors)
+	}
+
+	accounts := res.Data["accounts"].(map[string]interface{})
+	if nodes := accounts["nodes"].([]interface{}); len(nodes) != 2 || nodes[0].(map[string]interface{})["balance"] != "60" {
+		t.Errorf("unexpected accounts %v", nodes)
+	}
+	if pageInfo := accounts["pageInfo"].(map[string]interface{}); pageInfo["hasNextPage"] != true || pageInfo["endCursor"] != "next" {
+		t.Errorf("unexpected page info %v", pageInfo)
+	}
+	if queries.pages[0].First != 2 {
+		t.Errorf("expected the requested page size to be passed on, got %v", queries.pages[0])
+	}
+
+	tests := []struct {
+		call string
+		want int
+	}{
+		{call: "AccountBalances", want: 1},
+		{call: "AccountsByID", want: 1},
+		{call: "EntriesByTransaction", want: 1},
+		{call: "TransactionsByID", want: 1},
+	}
+	for _, tt := range tests {
+		if got := queries.calls[tt.call]; got != tt.want {
+			t.Errorf("%s was called %d times, want %d", tt.call, got, tt.want)
+		}
+	}
+}
+
+func TestHandler_Errors(t *testing.T) {
+	to := time.Now()
+	from := to.Add(time.Hour)
+
+	tests := []struct {
+		name      string
+		query     string
+		variables map[string]interface{}
+		wantCode  string
+	}{
+		{
+			name:     "unknown account",
+			query:    `{ account(id: "9f1c2d3e-0000-4c6d-8e7f-0a1b2c3d4e5f") { id } }`,
+			wantCode: "account_not_found",
+		},
+		{
+			name:  "invalid period",
+			query: `query ($from: Time, $to: Time) { transactions(filter: {from: $from, to: $to}) { nodes { id } } }`,
+			variables: map[string]interface{}{
+				"from": from.Format(time.RFC3339),
+				"to":   to.Format(time.RFC3339),
+			},
+			wantCode: "invalid_period",
+		},
+		{
+			name:     "malformed amount",
+			query:    fmt.Sprintf(`mutation { transfer(input: {sourceAccountId: %q, destinationAccountId: %q, amount: "ten"}) { ... on Transaction { id } } }`, sourceID, destinationID),
+			wantCode: "invalid_amount",
+		},
+		{
+			name:     "invalid fields",
+			query:    fmt.Sprintf(`mutation { transfer(input: {sourceAccountId: %q, destinationAccountId: %q, amount: "10"}) { ... on Transaction { id } } }`, sourceID, sourceID),
+			wantCode: "invalid_request",
+		},
+		{
+			name:     "insufficient funds",
+			query:    fmt.Sprintf(`mutation { transfer(input: {sourceAccountId: %q, destinationAccountId: %q, amount: "1000"}) { ... on Transaction { id } } }`, sourceID, destinationID),
+			wantCode: "insufficient_funds",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res := execute(t, &stubQueries{calls: map[string]int{}}, tt.query, tt.variables)
+			if len(res.Errors) != 1 {
+				t.Fatalf("expected a single error, got %v", res.Errors)
+			}
+			if code := res.Errors[0].Extensions["code"]; code != tt.wantCode {
+				t.Errorf("code = %v, want %v", code, tt.wantCode)
+			}
+		})
+	}
+}
+
+func TestHandler_Transfer(t *testing.T) {
+	res := execute(t, &stubQueries{calls: map[string]int{}}, `mutation ($input: TransferInput!) {
+		transfer(input: $input) { ... on Transaction { id entries { id } } }
+	}`, map[string]interface{}{
+		"input": map[string]interface{}{
+			"sourceAccountId":      sourceID,
+			"destinationAccountId": destinationID,
+			"amount":               "40",
+		},
+	})
+	if len(res.Errors) != 0 {
+		t.Fatalf("unexpected errors %v", res.Errors)
+	}
+
+	transfer := res.Data["transfer"].(map[string]interface{})
+	if transfer["id"] != "transaction-1" || len(transfer["entries"].([]interface{})) != 2 {
+		t.Errorf("unexpected transaction %v", transfer)
+	}
+}
+
+func TestHandler_HeldTransfer(t *testing.T) {
+	res := execute(t, &stubQueries{calls: map[string]int{}}, `mutation ($input: TransferInput!) {
+		transfer(input: $input) {
+			__typename
+			... on Transaction { id }
+			... on HeldTransfer { id status }
+		}
+	}`, map[string]interface{}{
+		"input": map[string]interface{}{
+			"sourceAccountId":      sourceID,
+			"destinationAccountId": destinationID,
+			"amount":               HELD_AMOUNT.String(),
+		},
+	})
+	if len(res.Errors) != 0 {
+		t.Fatalf("expected a held transfer rather than an error, got %v", res.Errors)
+	}
+
+	transfer := res.Data["transfer"].(map[string]interface{})
+	if transfer["__typename"] != "HeldTransfer" || transfer["id"] != "held-1" || transfer["status"] != string(domain.HoldPending) {
+		t.Errorf("unexpected held transfer %v", transfer)
+	}
+}
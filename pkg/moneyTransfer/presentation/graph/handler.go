@@ -0,0 +1,57 @@
+package graph
+
+import (
+	"log"
+	"net/http"
+	"sync"
+
+	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/usecases"
+	"github.com/gin-gonic/gin"
+	graphql "github.com/graph-gophers/graphql-go"
+)
+
+// Request is the body of a GraphQL request
+type Request struct {
+	Query         string                 `json:"query" binding:"required"`
+	OperationName string                 `json:"operationName"`
+	Variables     map[string]interface{} `json:"variables"`
+}
+
+// Handler serves GraphQL requests over HTTP
+type Handler struct {
+	Schema  *graphql.Schema
+	Queries usecases.QueryUsecases
+}
+
+// CheckPreconditions ensures a correct Handler struct is initialized
+func (h Handler) CheckPreconditions() {
+	if h.Schema == nil {
+		log.Panic("GraphQL presentation layer has not initialized the schema")
+	}
+
+	if h.Queries == nil {
+		log.Panic("GraphQL presentation layer has not initialized the query business logic")
+	}
+}
+
+// NewHandler initializes a new GraphQL endpoint handler
+func NewHandler(uc usecases.MoneyTransferUsecases, queries usecases.QueryUsecases, lock sync.Locker) *Handler {
+	h := &Handler{
+		Schema:  NewSchema(NewResolver(uc, queries, lock)),
+		Queries: queries,
+	}
+	h.CheckPreconditions()
+	return h
+}
+
+// Serve executes a GraphQL request, each request gets its own loaders
+func (h Handler) Serve(c *gin.Context) {
+	var request Request
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"errors": []gin.H{{"message": err.Error()}}})
+		return
+	}
+
+	ctx := WithLoaders(c.Request.Context(), NewLoaders(h.Queries))
+	c.JSON(http.StatusOK, h.Schema.Exec(ctx, request.Query, request.OperationName, request.Variables))
+}
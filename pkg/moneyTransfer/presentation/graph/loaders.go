@@ -0,0 +1,116 @@
+package graph
+
+import (
+	"context"
+	"time"
+
+	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/domain"
+	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/usecases"
+	"github.com/graph-gophers/dataloader/v7"
+	"github.com/shopspring/decimal"
+)
+
+// LOADER_WAIT is how long a loader collects keys before fetching them in a single batch
+var LOADER_WAIT = 2 * time.Millisecond
+
+type loadersKey struct{}
+
+// Loaders batch the lookups made while resolving a single request so that listing N records
+// costs one query per relationship rather than N
+type Loaders struct {
+	Accounts     *dataloader.Loader[string, *domain.Account]
+	Balances     *dataloader.Loader[string, decimal.Decimal]
+	Transactions *dataloader.Loader[string, *domain.Transaction]
+	Entries      *dataloader.Loader[string, []*domain.AccountEntry]
+}
+
+// NewLoaders initializes the loaders of a single request, their cache lives as long as the request
+func NewLoaders(queries usecases.QueryUsecases) *Loaders {
+	return &Loaders{
+		Accounts: dataloader.NewBatchedLoader(
+			byID(queries.AccountsByID, func(a *domain.Account) string { return a.UUID }, "account_not_found", "account"),
+			dataloader.WithWait[string, *domain.Account](LOADER_WAIT),
+		),
+		Balances: dataloader.NewBatchedLoader(
+			func(ctx context.Context, accountIDs []string) []*dataloader.Result[decimal.Decimal] {
+				balances, err := queries.AccountBalances(ctx, accountIDs)
+				return results(accountIDs, err, func(id string) (decimal.Decimal, error) {
+					balance, ok := balances[id]
+					if !ok {
+						return balance, domain.NewNotFoundError("account_not_found", "account %s was not found", id)
+					}
+					return balance, nil
+				})
+			},
+			dataloader.WithWait[string, decimal.Decimal](LOADER_WAIT),
+		),
+		Transactions: dataloader.NewBatchedLoader(
+			byID(queries.TransactionsByID, func(t *domain.Transaction) string { return t.UUID }, "transaction_not_found", "transaction"),
+			dataloader.WithWait[string, *domain.Transaction](LOADER_WAIT),
+		),
+		Entries: dataloader.NewBatchedLoader(
+			func(ctx context.Context, transactionIDs []string) []*dataloader.Result[[]*domain.AccountEntry] {
+				entries, err := queries.EntriesByTransaction(ctx, transactionIDs)
+				grouped := map[string][]*domain.AccountEntry{}
+				for _, entry := range entries {
+					grouped[entry.TransactionID] = append(grouped[entry.TransactionID], entry)
+				}
+				return results(transactionIDs, err, func(id string) ([]*domain.AccountEntry, error) {
+					return grouped[id], nil
+				})
+			},
+			dataloader.WithWait[string, []*domain.AccountEntry](LOADER_WAIT),
+		),
+	}
+}
+
+// WithLoaders returns a copy of ctx carrying a request's loaders
+func WithLoaders(ctx context.Context, loaders *Loaders) context.Context {
+	return context.WithValue(ctx, loadersKey{}, loaders)
+}
+
+// loadersFrom returns the loaders of the request being resolved
+func loadersFrom(ctx context.Context) *Loaders {
+	loaders, ok := ctx.Value(loadersKey{}).(*Loaders)
+	if !ok {
+		panic("graph: request resolved without loaders")
+	}
+	return loaders
+}
+
+// byID batches the lookup of records by ID, reporting the IDs matching no record as not found
+func byID[V any](
+	lookup func(ctx context.Context, ids []string) ([]V, error),
+	id func(V) string,
+	code string,
+	resource string,
+) dataloader.BatchFunc[string, V] {
+	return func(ctx context.Context, ids []string) []*dataloader.Result[V] {
+		records, err := lookup(ctx, ids)
+		found := make(map[string]V, len(records))
+		for _, record := range records {
+			found[id(record)] = record
+		}
+		return results(ids, err, func(key string) (V, error) {
+			record, ok := found[key]
+			if !ok {
+				return record, domain.NewNotFoundError(code, "%s %s was not found", resource, key)
+			}
+			return record, nil
+		})
+	}
+}
+
+// results answers every key of a batch, in order, with the batch's error or the key's own result
+func results[V any](keys []string, err error, result func(key string) (V, error)) []*dataloader.Result[V] {
+	answers := make([]*dataloader.Result[V], len(keys))
+	for i, key := range keys {
+		if err != nil {
+			answers[i] = &dataloader.Result[V]{Error: err}
+			continue
+		}
+		data, err := result(key)
+		answers[i] = &dataloader.Result[V]{Data: data, Error: err}
+	}
+	return answers
+}
@@ -0,0 +1,241 @@
+// Package graph serves the ledger as a GraphQL API, letting clients fetch accounts, their entries and
+// transactions along with their relationships in a single round trip
+package graph
+
+import (
+	"context"
+	_ "embed"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/application"
+	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/domain"
+	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/metrics"
+	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/presentation/validation"
+	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/usecases"
+	graphql "github.com/graph-gophers/graphql-go"
+	"github.com/shopspring/decimal"
+)
+
+// SCHEMA is the GraphQL schema the resolvers implement
+//
+//go:embed schema.graphql
+var SCHEMA string
+
+// MAX_QUERY_DEPTH bounds how deeply relationships can be nested in a single query
+var MAX_QUERY_DEPTH = 10
+
+// Resolver resolves the root query and mutation fields
+type Resolver struct {
+	Uc      usecases.MoneyTransferUsecases
+	Queries usecases.QueryUsecases
+
+	// Lock serializes the money moved here with the other presentation layers
+	Lock sync.Locker
+}
+
+// CheckPreconditions ensures a correct Resolver struct is initialized
+func (r Resolver) CheckPreconditions() {
+	if r.Uc == nil {
+		log.Panic("GraphQL presentation layer has not initialized the money transfer business logic")
+	}
+
+	if r.Queries == nil {
+		log.Panic("GraphQL presentation layer has not initialized the query business logic")
+	}
+
+	if r.Lock == nil {
+		log.Panic("GraphQL presentation layer has not initialized the money movement lock")
+	}
+}
+
+// NewResolver initializes a new GraphQL root resolver
+func NewResolver(uc usecases.MoneyTransferUsecases, queries usecases.QueryUsecases, lock sync.Locker) *Resolver {
+	r := &Resolver{
+		Uc:      uc,
+		Queries: queries,
+		Lock:    lock,
+	}
+	r.CheckPreconditions()
+	return r
+}
+
+// NewSchema parses the GraphQL schema against its resolvers
+func NewSchema(r *Resolver) *graphql.Schema {
+	return graphql.MustParseSchema(SCHEMA, r, graphql.MaxDepth(MAX_QUERY_DEPTH))
+}
+
+type pageArgs struct {
+	First *int32
+	After *string
+}
+
+// page converts the pagination arguments of a connection field
+func (args pageArgs) page() application.PageInput {
+	var page application.PageInput
+	if args.First != nil {
+		page.First = int(*args.First)
+	}
+	if args.After != nil {
+		page.After = *args.After
+	}
+	return page
+}
+
+type accountFilterInput struct {
+	Currency        *string
+	Header          *string
+	IsSystemAccount *bool
+	Name            *string
+}
+
+type transactionFilterInput struct {
+	AccountID *graphql.ID
+	From      *graphql.Time
+	To        *graphql.Time
+}
+
+// filter converts the transaction filter argument, an unset argument matches every transaction
+func (input *transactionFilterInput) filter() application.TransactionFilter {
+	var filter application.TransactionFilter
+	if input == nil {
+		return filter
+	}
+	if input.AccountID != nil {
+		filter.AccountID = string(*input.AccountID)
+	}
+	filter.From, filter.To = period(input.From, input.To)
+	return filter
+}
+
+type entryFilterInput struct {
+	From *graphql.Time
+	To   *graphql.Time
+}
+
+type transferInput struct {
+	SourceAccountID      graphql.ID
+	DestinationAccountID graphql.ID
+	Amount               string
+}
+
+// period converts optional GraphQL timestamps bounding a period
+func period(from *graphql.Time, to *graphql.Time) (*time.Time, *time.Time) {
+	var start, end *time.Time
+	if from != nil {
+		start = &from.Time
+	}
+	if to != nil {
+		end = &to.Time
+	}
+	return start, end
+}
+
+// Account resolves an account by ID
+func (r *Resolver) Account(ctx context.Context, args struct{ ID graphql.ID }) (*accountResolver, error) {
+	account, err := loadersFrom(ctx).Accounts.Load(ctx, string(args.ID))()
+	if err != nil {
+		return nil, resolverError(ctx, err)
+	}
+	return &accountResolver{r: r, account: account}, nil
+}
+
+// Accounts resolves a page of the accounts matching a filter
+func (r *Resolver) Accounts(ctx context.Context, args struct {
+	Filter *accountFilterInput
+	pageArgs
+}) (*accountConnection, error) {
+	var filter application.AccountFilter
+	if args.Filter != nil {
+		if args.Filter.Currency != nil {
+			currency := domain.CurrencyType(*args.Filter.Currency)
+			filter.Currency = &currency
+		}
+		if args.Filter.Header != nil {
+			header := domain.HeaderType(*args.Filter.Header)
+			filter.Header = &header
+		}
+		if args.Filter.Name != nil {
+			filter.Name = *args.Filter.Name
+		}
+		filter.IsSystemAccount = args.Filter.IsSystemAccount
+	}
+
+	page, err := r.Queries.Accounts(ctx, filter, args.page())
+	if err != nil {
+		return nil, resolverError(ctx, err)
+	}
+	return &accountConnection{r: r, page: page}, nil
+}
+
+// Transaction resolves a transaction by ID
+func (r *Resolver) Transaction(ctx context.Context, args struct{ ID graphql.ID }) (*transactionResolver, error) {
+	transaction, err := loadersFrom(ctx).Transactions.Load(ctx, string(args.ID))()
+	if err != nil {
+		return nil, resolverError(ctx, err)
+	}
+	return &transactionResolver{r: r, transaction: transaction}, nil
+}
+
+// Transactions resolves a page of the transactions matching a filter
+func (r *Resolver) Transactions(ctx context.Context, args struct {
+	Filter *transactionFilterInput
+	pageArgs
+}) (*transactionConnection, error) {
+	page, err := r.Queries.Transactions(ctx, args.Filter.filter(), args.page())
+	if err != nil {
+		return nil, resolverError(ctx, err)
+	}
+	return &transactionConnection{r: r, page: page}, nil
+}
+
+// Transfer moves money from a source to a destination account, answering with the posted transaction or the
+// transfer held for fraud review
+func (r *Resolver) Transfer(ctx context.Context, args struct{ Input transferInput }) (*transferOutcomeResolver, error) {
+	payload := application.TransferPayload{
+		SourceAccountID:      string(args.Input.SourceAccountID),
+		DestinationAccountID: string(args.Input.DestinationAccountID),
+	}
+	amount, err := decimal.NewFromString(args.Input.Amount)
+	if err != nil {
+		return nil, resolverError(ctx, domain.NewValidationError("invalid_amount", "%q is not a decimal amount", args.Input.Amount))
+	}
+	payload.Amount = &amount
+
+	if err := validation.Validate(payload); err != nil {
+		return nil, resolverError(ctx, err)
+	}
+
+	if err := metrics.LockContext(ctx, r.Lock); err != nil {
+		return nil, resolverError(ctx, err)
+	}
+	defer r.Lock.Unlock()
+
+	sourceAccount, err := r.Uc.Account(ctx, payload.SourceAccountID)
+	if err != nil {
+		return nil, resolverError(ctx, err)
+	}
+
+	destinationAccount, err := r.Uc.Account(ctx, payload.DestinationAccountID)
+	if err != nil {
+		return nil, resolverError(ctx, err)
+	}
+
+	transaction, err := r.Uc.Transfer(ctx, application.TransferInput{
+		SourceAccount:      sourceAccount,
+		DestinationAccount: destinationAccount,
+		Amount:             payload.Amount,
+	})
+	if domain.IsKind(err, domain.Held) {
+		return &transferOutcomeResolver{held: &heldTransferResolver{id: domain.AsError(err).HeldTransferID, status: domain.HoldPending}}, nil
+	}
+	if err != nil {
+		return nil, resolverError(ctx, err)
+	}
+
+	// Balances loaded earlier in the request are stale once money has moved
+	loadersFrom(ctx).Balances.ClearAll()
+
+	return &transferOutcomeResolver{transaction: &transactionResolver{r: r, transaction: transaction}}, nil
+}
@@ -0,0 +1,295 @@
+package graph
+
+import (
+	"context"
+	"time"
+
+	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/application"
+	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/domain"
+	graphql "github.com/graph-gophers/graphql-go"
+)
+
+// timestamp converts an optional timestamp to its GraphQL scalar
+func timestamp(t *time.Time) *graphql.Time {
+	if t == nil {
+		return nil
+	}
+	return &graphql.Time{Time: *t}
+}
+
+type pageInfo struct {
+	hasNextPage bool
+	endCursor   string
+}
+
+func (p pageInfo) HasNextPage() bool {
+	return p.hasNextPage
+}
+
+func (p pageInfo) EndCursor() *string {
+	if p.endCursor == "" {
+		return nil
+	}
+	return &p.endCursor
+}
+
+// accountResolver resolves the fields of an account
+type accountResolver struct {
+	r       *Resolver
+	account *domain.Account
+}
+
+func (a *accountResolver) ID() graphql.ID {
+	return graphql.ID(a.account.UUID)
+}
+
+func (a *accountResolver) Active() bool {
+	return a.account.Active
+}
+
+func (a *accountResolver) Name() string {
+	return a.account.Name
+}
+
+func (a *accountResolver) Description() string {
+	return a.account.Description
+}
+
+func (a *accountResolver) Number() string {
+	return a.account.Number
+}
+
+func (a *accountResolver) Currency() string {
+	return string(a.account.Currency)
+}
+
+func (a *accountResolver) BalanceType() string {
+	return string(a.account.BalanceType)
+}
+
+func (a *accountResolver) Header() string {
+	return string(a.account.Header)
+}
+
+func (a *accountResolver) IsSystemAccount() bool {
+	return a.account.IsSystemAccount
+}
+
+func (a *accountResolver) GlCode() string {
+	return a.account.GLCode
+}
+
+func (a *accountResolver) Role() string {
+	return string(a.account.Role)
+}
+
+// Balance is loaded in a batch with the balances of the other accounts in the response
+func (a *accountResolver) Balance(ctx context.Context) (string, error) {
+	balance, err := loadersFrom(ctx).Balances.Load(ctx, a.account.UUID)()
+	if err != nil {
+		return "", resolverError(ctx, err)
+	}
+	return balance.String(), nil
+}
+
+func (a *accountResolver) CreatedAt() *graphql.Time {
+	return timestamp(a.account.CreatedAt)
+}
+
+func (a *accountResolver) Entries(ctx context.Context, args struct {
+	Filter *entryFilterInput
+	pageArgs
+}) (*entryConnection, error) {
+	filter := application.EntryFilter{AccountID: a.account.UUID}
+	if args.Filter != nil {
+		filter.From, filter.To = period(args.Filter.From, args.Filter.To)
+	}
+
+	page, err := a.r.Queries.Entries(ctx, filter, args.page())
+	if err != nil {
+		return nil, resolverError(ctx, err)
+	}
+	return &entryConnection{r: a.r, page: page}, nil
+}
+
+func (a *accountResolver) Transactions(ctx context.Context, args struct {
+	Filter *transactionFilterInput
+	pageArgs
+}) (*transactionConnection, error) {
+	filter := args.Filter.filter()
+	filter.AccountID = a.account.UUID
+
+	page, err := a.r.Queries.Transactions(ctx, filter, args.page())
+	if err != nil {
+		return nil, resolverError(ctx, err)
+	}
+	return &transactionConnection{r: a.r, page: page}, nil
+}
+
+// transactionResolver resolves the fields of a transaction
+type transactionResolver struct {
+	r           *Resolver
+	transaction *domain.Transaction
+}
+
+func (t *transactionResolver) ID() graphql.ID {
+	return graphql.ID(t.transaction.UUID)
+}
+
+func (t *transactionResolver) Description() string {
+	return t.transaction.Description
+}
+
+func (t *transactionResolver) ReversalOf(ctx context.Context) (*transactionResolver, error) {
+	if t.transaction.ReversalOf == nil {
+		return nil, nil
+	}
+
+	reversed, err := loadersFrom(ctx).Transactions.Load(ctx, *t.transaction.ReversalOf)()
+	if err != nil {
+		return nil, resolverError(ctx, err)
+	}
+	return &transactionResolver{r: t.r, transaction: reversed}, nil
+}
+
+func (t *transactionResolver) CreatedAt() *graphql.Time {
+	return timestamp(t.transaction.CreatedAt)
+}
+
+// Entries are loaded in a batch with the entries of the other transactions in the response
+func (t *transactionResolver) Entries(ctx context.Context) ([]*entryResolver, error) {
+	entries, err := loadersFrom(ctx).Entries.Load(ctx, t.transaction.UUID)()
+	if err != nil {
+		return nil, resolverError(ctx, err)
+	}
+
+	resolvers := make([]*entryResolver, len(entries))
+	for i, entry := range entries {
+		resolvers[i] = &entryResolver{r: t.r, entry: entry}
+	}
+	return resolvers, nil
+}
+
+// entryResolver resolves the fields of an account entry
+type entryResolver struct {
+	r     *Resolver
+	entry *domain.AccountEntry
+}
+
+func (e *entryResolver) ID() graphql.ID {
+	return graphql.ID(e.entry.UUID)
+}
+
+func (e *entryResolver) DebitAmount() string {
+	return e.entry.DebitAmount.String()
+}
+
+func (e *entryResolver) CreditAmount() string {
+	return e.entry.CreditAmount.String()
+}
+
+func (e *entryResolver) EffectiveDate() *graphql.Time {
+	return timestamp(e.entry.EffectiveDate)
+}
+
+func (e *entryResolver) CreatedAt() *graphql.Time {
+	return timestamp(e.entry.CreatedAt)
+}
+
+func (e *entryResolver) Account(ctx context.Context) (*accountResolver, error) {
+	account, err := loadersFrom(ctx).Accounts.Load(ctx, e.entry.AccountID)()
+	if err != nil {
+		return nil, resolverError(ctx, err)
+	}
+	return &accountResolver{r: e.r, account: account}, nil
+}
+
+func (e *entryResolver) Transaction(ctx context.Context) (*transactionResolver, error) {
+	transaction, err := loadersFrom(ctx).Transactions.Load(ctx, e.entry.TransactionID)()
+	if err != nil {
+		return nil, resolverError(ctx, err)
+	}
+	return &transactionResolver{r: e.r, transaction: transaction}, nil
+}
+
+// accountConnection resolves a page of accounts
+type accountConnection struct {
+	r    *Resolver
+	page *application.Page[*domain.Account]
+}
+
+func (c *accountConnection) Nodes() []*accountResolver {
+	nodes := make([]*accountResolver, len(c.page.Items))
+	for i, account := range c.page.Items {
+		nodes[i] = &accountResolver{r: c.r, account: account}
+	}
+	return nodes
+}
+
+func (c *accountConnection) PageInfo() pageInfo {
+	return pageInfo{hasNextPage: c.page.HasNextPage, endCursor: c.page.EndCursor}
+}
+
+// transactionConnection resolves a page of transactions
+type transactionConnection struct {
+	r    *Resolver
+	page *application.Page[*domain.Transaction]
+}
+
+func (c *transactionConnection) Nodes() []*transactionResolver {
+	nodes := make([]*transactionResolver, len(c.page.Items))
+	for i, transaction := range c.page.Items {
+		nodes[i] = &transactionResolver{r: c.r, transaction: transaction}
+	}
+	return nodes
+}
+
+func (c *transactionConnection) PageInfo() pageInfo {
+	return pageInfo{hasNextPage: c.page.HasNextPage, endCursor: c.page.EndCursor}
+}
+
+// entryConnection resolves a page of account entries
+type entryConnection struct {
+	r    *Resolver
+	page *application.Page[*domain.AccountEntry]
+}
+
+func (c *entryConnection) Nodes() []*entryResolver {
+	nodes := make([]*entryResolver, len(c.page.Items))
+	for i, entry := range c.page.Items {
+		nodes[i] = &entryResolver{r: c.r, entry: entry}
+	}
+	return nodes
+}
+
+func (c *entryConnection) PageInfo() pageInfo {
+	return pageInfo{hasNextPage: c.page.HasNextPage, endCursor: c.page.EndCursor}
+}
+
+// transferOutcomeResolver resolves a transfer to the transaction it posted or the transfer held for review
+type transferOutcomeResolver struct {
+	transaction *transactionResolver
+	held        *heldTransferResolver
+}
+
+func (t *transferOutcomeResolver) ToTransaction() (*transactionResolver, bool) {
+	return t.transaction, t.transaction != nil
+}
+
+func (t *transferOutcomeResolver) ToHeldTransfer() (*heldTransferResolver, bool) {
+	return t.held, t.held != nil
+}
+
+// heldTransferResolver resolves the fields of a transfer held for fraud review
+type heldTransferResolver struct {
+	id     string
+	status domain.HoldStatus
+}
+
+func (h *heldTransferResolver) ID() graphql.ID {
+	return graphql.ID(h.id)
+}
+
+func (h *heldTransferResolver) Status() string {
+	return string(h.status)
+}
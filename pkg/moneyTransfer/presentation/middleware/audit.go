@@ -0,0 +1,178 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/domain"
+	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/logging"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// MAX_AUDITED_RESPONSE limits how much of a response is buffered to find the resources it created or changed
+var MAX_AUDITED_RESPONSE = 1 << 20
+
+// resourceKeys are the response fields identifying the resources a call created or changed
+var resourceKeys = map[string]bool{"UUID": true, "uuid": true, "id": true, "ID": true}
+
+// AuditRecorder stores audit records
+type AuditRecorder interface {
+	Record(ctx context.Context, record *domain.AuditRecord) error
+}
+
+// auditWriter keeps a copy of the response body while writing it
+type auditWriter struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *auditWriter) Write(data []byte) (int, error) {
+	if remaining := MAX_AUDITED_RESPONSE - w.body.Len(); remaining > 0 {
+		if len(data) < remaining {
+			remaining = len(data)
+		}
+		w.body.Write(data[:remaining])
+	}
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *auditWriter) WriteString(s string) (int, error) {
+	return w.Write([]byte(s))
+}
+
+// isMutating reports whether a request method changes state
+func isMutating(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return false
+	}
+	return true
+}
+
+// collectResourceIDs walks a decoded JSON response for identifier fields
+func collectResourceIDs(value interface{}, depth int, ids map[string]bool) {
+	if depth > 3 {
+		return
+	}
+
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, field := range v {
+			if id, ok := field.(string); ok && resourceKeys[key] && id != "" {
+				ids[id] = true
+				continue
+			}
+			collectResourceIDs(field, depth+1, ids)
+		}
+	case []interface{}:
+		for _, item := range v {
+			collectResourceIDs(item, depth+1, ids)
+		}
+	}
+}
+
+// resourceIDs lists the resources a call addressed in its path or returned in its response
+func resourceIDs(c *gin.Context, response []byte) string {
+	var addressed []string
+	for _, param := range c.Params {
+		addressed = append(addressed, param.Value)
+	}
+	return ResourceIDs(response, addressed...)
+}
+
+// ResourceIDs lists the addressed resources along with the ones identified in a JSON response
+func ResourceIDs(response []byte, addressed ...string) string {
+	ids := map[string]bool{}
+	for _, id := range addressed {
+		ids[id] = true
+	}
+
+	var decoded interface{}
+	if json.Unmarshal(response, &decoded) == nil {
+		collectResourceIDs(decoded, 0, ids)
+	}
+
+	var sorted []string
+	for id := range ids {
+		sorted = append(sorted, id)
+	}
+	sort.Strings(sorted)
+	return strings.Join(sorted, ",")
+}
+
+// detachedContext keeps the values of a request's context, such as its span, but not its deadline or cancellation
+type detachedContext struct {
+	context.Context
+}
+
+func (detachedContext) Deadline() (time.Time, bool) { return time.Time{}, false }
+
+func (detachedContext) Done() <-chan struct{} { return nil }
+
+func (detachedContext) Err() error { return nil }
+
+// Detach keeps the values of a call's context without its deadline or cancellation, so that
+// the audit of a call that timed out or was abandoned can still be recorded
+func Detach(ctx context.Context) context.Context {
+	return detachedContext{ctx}
+}
+
+// Audit is a middleware recording who made each state changing call, what it carried and how it ended.
+// The payload is stored as a SHA-256 hash so the log does not retain customer data
+func Audit(recorder AuditRecorder) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !isMutating(c.Request.Method) {
+			c.Next()
+			return
+		}
+
+		var payload []byte
+		if c.Request.Body != nil {
+			var err error
+			payload, err = io.ReadAll(c.Request.Body)
+			if err != nil {
+				c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "unable to read request body"})
+				return
+			}
+			c.Request.Body = io.NopCloser(bytes.NewReader(payload))
+		}
+		payloadHash := sha256.Sum256(payload)
+
+		writer := &auditWriter{ResponseWriter: c.Writer}
+		c.Writer = writer
+		occurredAt := time.Now()
+
+		c.Next()
+
+		record := domain.AuditRecord{
+			Actor:       Subject(c.Request.Context()),
+			ClientIP:    c.ClientIP(),
+			Method:      c.Request.Method,
+			Route:       c.FullPath(),
+			Path:        c.Request.URL.Path,
+			PayloadHash: hex.EncodeToString(payloadHash[:]),
+			ResourceIDs: resourceIDs(c, writer.body.Bytes()),
+			StatusCode:  writer.Status(),
+			Outcome:     domain.OutcomeOf(writer.Status()),
+			OccurredAt:  occurredAt,
+		}
+		// Calls that timed out or were abandoned by the client are audited too
+		if err := recorder.Record(Detach(c.Request.Context()), &record); err != nil {
+			logging.FromContext(c.Request.Context()).Error("unable to record the audit of a request",
+				zap.String("method", record.Method),
+				zap.String("path", record.Path),
+				zap.String("actor", record.Actor),
+				zap.Error(err),
+			)
+		}
+	}
+}
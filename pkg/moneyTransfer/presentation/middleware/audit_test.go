@@ -0,0 +1,143 @@
+package middleware_test
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/domain"
+	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/presentation/middleware"
+	"github.com/gin-gonic/gin"
+)
+
+type memoryRecorder struct {
+	records []*domain.AuditRecord
+}
+
+func (m *memoryRecorder) Record(ctx context.Context, record *domain.AuditRecord) error {
+	m.records = append(m.records, record)
+	return nil
+}
+
+func newTestAuditRouter(recorder middleware.AuditRecorder) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(middleware.Audit(recorder))
+	router.GET("/account/:id", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"account": gin.H{"UUID": c.Param("id")}})
+	})
+	router.POST("/account", func(c *gin.Context) {
+		var payload map[string]interface{}
+		if err := c.ShouldBindJSON(&payload); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"account": gin.H{"UUID": "account-1", "Entries": []gin.H{{"UUID": "entry-1"}}}})
+	})
+	router.POST("/transfers/:id/reverse", func(c *gin.Context) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "already reversed"})
+	})
+	return router
+}
+
+func TestAudit(t *testing.T) {
+	body := `{"customer_name":"John Doe"}`
+	bodyHash := sha256.Sum256([]byte(body))
+
+	tests := []struct {
+		name            string
+		method          string
+		path            string
+		body            string
+		wantRecorded    bool
+		wantRoute       string
+		wantResources   string
+		wantOutcome     domain.AuditOutcome
+		wantPayloadHash string
+	}{
+		{
+			name:   "reads are not audited",
+			method: http.MethodGet,
+			path:   "/account/account-1",
+		},
+		{
+			name:            "successful creation",
+			method:          http.MethodPost,
+			path:            "/account",
+			body:            body,
+			wantRecorded:    true,
+			wantRoute:       "/account",
+			wantResources:   "account-1,entry-1",
+			wantOutcome:     domain.AuditSuccess,
+			wantPayloadHash: hex.EncodeToString(bodyHash[:]),
+		},
+		{
+			name:          "failed call",
+			method:        http.MethodPost,
+			path:          "/transfers/tx-1/reverse",
+			wantRecorded:  true,
+			wantRoute:     "/transfers/:id/reverse",
+			wantResources: "tx-1",
+			wantOutcome:   domain.AuditFailure,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := &memoryRecorder{}
+			router := newTestAuditRouter(recorder)
+
+			req := httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body))
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+
+			if !tt.wantRecorded {
+				if len(recorder.records) != 0 {
+					t.Errorf("expected no audit record, got %d", len(recorder.records))
+				}
+				return
+			}
+			if len(recorder.records) != 1 {
+				t.Errorf("expected one audit record, got %d", len(recorder.records))
+				return
+			}
+
+			record := recorder.records[0]
+			if record.Route != tt.wantRoute || record.Path != tt.path || record.Method != tt.method {
+				t.Errorf("unexpected call details %s %s (%s)", record.Method, record.Path, record.Route)
+			}
+			if record.ResourceIDs != tt.wantResources {
+				t.Errorf("expected resources %q, got %q", tt.wantResources, record.ResourceIDs)
+			}
+			if record.Outcome != tt.wantOutcome || record.StatusCode != rec.Code {
+				t.Errorf("expected outcome %s, got %s with status %d", tt.wantOutcome, record.Outcome, record.StatusCode)
+			}
+			if tt.wantPayloadHash != "" && record.PayloadHash != tt.wantPayloadHash {
+				t.Errorf("expected the payload hash %s, got %s", tt.wantPayloadHash, record.PayloadHash)
+			}
+			if tt.body != "" && rec.Code != http.StatusOK {
+				t.Errorf("expected the handler to still read the request body, got %d", rec.Code)
+			}
+		})
+	}
+}
+
+func TestAuditRecord_ComputeHash(t *testing.T) {
+	record := domain.AuditRecord{Sequence: 1, Actor: "user", Route: "/account", StatusCode: 200}
+	hash := record.ComputeHash()
+
+	tampered := record
+	tampered.Actor = "someone else"
+	if tampered.ComputeHash() == hash {
+		t.Errorf("expected a changed record to change its hash")
+	}
+
+	rechained := record
+	rechained.PreviousHash = "other"
+	if rechained.ComputeHash() == hash {
+		t.Errorf("expected the hash to cover the previous record's hash")
+	}
+}
@@ -2,17 +2,23 @@ package middleware
 
 import (
 	"context"
-	"log"
+	"fmt"
 	"net/http"
 	"net/url"
-	"os"
-	"time"
+	"strings"
 
+	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/config"
+	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/logging"
 	jwtmiddleware "github.com/auth0/go-jwt-middleware/v2"
 	"github.com/auth0/go-jwt-middleware/v2/jwks"
 	"github.com/auth0/go-jwt-middleware/v2/validator"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
 )
 
+// ADMIN_SCOPE is the access token scope granting administrative operations
+var ADMIN_SCOPE = "admin"
+
 // CustomClaims contains custom data we want from the token.
 type CustomClaims struct {
 	Scope string `json:"scope"`
@@ -24,43 +30,109 @@ func (c CustomClaims) Validate(ctx context.Context) error {
 	return nil
 }
 
-// EnsureValidToken is a middleware that will check the validity of our JWT.
-func EnsureValidToken() func(next http.Handler) http.Handler {
-	issuerURL, err := url.Parse("https://" + os.Getenv("AUTH0_DOMAIN") + "/")
-	if err != nil {
-		log.Fatalf("Failed to parse the issuer url: %v", err)
+// HasScope checks whether the token was granted a given scope
+func (c CustomClaims) HasScope(expectedScope string) bool {
+	for _, scope := range strings.Split(c.Scope, " ") {
+		if scope == expectedScope {
+			return true
+		}
+	}
+	return false
+}
+
+// ValidatedClaims retrieves the claims of the access token validated for a request
+func ValidatedClaims(ctx context.Context) (*validator.ValidatedClaims, bool) {
+	claims, ok := ctx.Value(jwtmiddleware.ContextKey{}).(*validator.ValidatedClaims)
+	return claims, ok
+}
+
+// Subject identifies the client or user an authenticated request was made by
+func Subject(ctx context.Context) string {
+	claims, ok := ValidatedClaims(ctx)
+	if !ok {
+		return ""
+	}
+	return claims.RegisteredClaims.Subject
+}
+
+// HasScope reports whether the access token an authenticated request was made with was granted a given scope
+func HasScope(ctx context.Context, scope string) bool {
+	claims, ok := ValidatedClaims(ctx)
+	if !ok {
+		return false
+	}
+	customClaims, ok := claims.CustomClaims.(*CustomClaims)
+	return ok && customClaims.HasScope(scope)
+}
+
+// RequireScope is a middleware that only lets through requests whose token was granted a given scope
+func RequireScope(scope string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if HasScope(c.Request.Context(), scope) {
+			c.Next()
+			return
+		}
+
+		c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "Insufficient scope."})
 	}
+}
 
-	provider := jwks.NewCachingProvider(issuerURL, 5*time.Minute)
+// NewIssuerKeys fetches the signing keys of the configured Auth0 tenant, caching them for the configured TTL.
+// One cache is shared by every token validator and the readiness probe
+func NewIssuerKeys(auth config.Auth, opts ...jwks.ProviderOption) (*jwks.CachingProvider, error) {
+	issuerURL, err := url.Parse(auth.IssuerURL())
+	if err != nil {
+		return nil, fmt.Errorf("invalid issuer url: %v", err)
+	}
+	return jwks.NewCachingProvider(issuerURL, auth.JWKSCacheTTL, opts...), nil
+}
 
+// NewTokenValidator sets up the validation of access tokens issued by the configured Auth0 tenant and signed
+// with one of keys
+func NewTokenValidator(auth config.Auth, keys *jwks.CachingProvider) (*validator.Validator, error) {
 	jwtValidator, err := validator.New(
-		provider.KeyFunc,
+		keys.KeyFunc,
 		validator.RS256,
-		issuerURL.String(),
-		[]string{os.Getenv("AUTH0_AUDIENCE")},
+		auth.IssuerURL(),
+		[]string{auth.Audience},
 		validator.WithCustomClaims(
 			func() validator.CustomClaims {
 				return &CustomClaims{}
 			},
 		),
-		validator.WithAllowedClockSkew(time.Minute),
+		validator.WithAllowedClockSkew(auth.ClockSkew),
 	)
 	if err != nil {
-		log.Fatalf("Failed to set up the jwt validator")
+		return nil, fmt.Errorf("unable to set up the jwt validator: %v", err)
 	}
 
+	return jwtValidator, nil
+}
+
+// WithValidatedClaims attaches the claims of a validated access token to a context
+func WithValidatedClaims(ctx context.Context, claims *validator.ValidatedClaims) context.Context {
+	return context.WithValue(ctx, jwtmiddleware.ContextKey{}, claims)
+}
+
+// TokenValidator validates an access token, returning its claims
+type TokenValidator interface {
+	ValidateToken(ctx context.Context, token string) (interface{}, error)
+}
+
+// EnsureValidToken is a middleware that will check the validity of our JWT.
+func EnsureValidToken(jwtValidator TokenValidator) func(next http.Handler) http.Handler {
 	errorHandler := func(w http.ResponseWriter, r *http.Request, err error) {
-		log.Printf("Encountered error while validating JWT: %v", err)
+		logging.FromContext(r.Context()).Warn("access token rejected", zap.Error(err))
 
 		w.Header().Set("Content-Type", "application/json")
 		w.WriteHeader(http.StatusUnauthorized)
 		if _, err := w.Write([]byte(`{"error":"Unauthorized."}`)); err != nil {
-			log.Panic(err)
+			logging.FromContext(r.Context()).Warn("unable to answer an unauthorized request", zap.Error(err))
 		}
 	}
 
 	middleware := jwtmiddleware.New(
-		jwtValidator.ValidateToken,
+		TracedTokenValidation(jwtValidator.ValidateToken),
 		jwtmiddleware.WithErrorHandler(errorHandler),
 	)
 
@@ -0,0 +1,70 @@
+package middleware
+
+import (
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/logging"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// RequestID is a middleware tagging every request with the ID it was sent with, or a new one, and
+// sending it back in the response
+func RequestID() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		requestID := logging.NewRequestID(c.GetHeader(logging.REQUEST_ID_HEADER))
+		c.Header(logging.REQUEST_ID_HEADER, requestID)
+		c.Request = c.Request.WithContext(logging.WithRequestID(c.Request.Context(), requestID))
+		c.Next()
+	}
+}
+
+// Logger is a middleware handing logger to the request's handlers and logging every request once served.
+// Server errors are logged as errors and client errors as warnings. The query string is left out as it may
+// carry credentials
+func Logger(logger *zap.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		c.Request = c.Request.WithContext(logging.NewContext(c.Request.Context(), logger))
+		c.Next()
+
+		route := c.FullPath()
+		if route == "" {
+			route = UNMATCHED_ROUTE
+		}
+		status := c.Writer.Status()
+		fields := []zap.Field{
+			zap.String("method", c.Request.Method),
+			zap.String("route", route),
+			zap.String("path", c.Request.URL.Path),
+			zap.Int("status", status),
+			zap.Duration("latency", time.Since(start)),
+			zap.Int("bytes", c.Writer.Size()),
+			zap.String("client_ip", c.ClientIP()),
+			zap.String("user_agent", c.Request.UserAgent()),
+		}
+		if len(c.Errors) > 0 {
+			fields = append(fields, zap.String("errors", c.Errors.String()))
+		}
+
+		entry := logging.FromContext(c.Request.Context())
+		switch {
+		case status >= http.StatusInternalServerError:
+			entry.Error("request served", fields...)
+		case status >= http.StatusBadRequest:
+			entry.Warn("request served", fields...)
+		default:
+			entry.Info("request served", fields...)
+		}
+	}
+}
+
+// Recovery is a middleware answering the requests whose handler panicked with a 500, logging the panic
+func Recovery() gin.HandlerFunc {
+	return gin.CustomRecoveryWithWriter(io.Discard, func(c *gin.Context, recovered interface{}) {
+		logging.FromContext(c.Request.Context()).Error("request handler panicked", zap.Any("panic", recovered), zap.Stack("stack"))
+		c.AbortWithStatus(http.StatusInternalServerError)
+	})
+}
@@ -0,0 +1,144 @@
+package middleware_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/logging"
+	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/presentation/middleware"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+func TestLogger(t *testing.T) {
+	tests := []struct {
+		name          string
+		path          string
+		requestID     string
+		authorization string
+		wantStatus    int
+		wantLevel     string
+		wantRoute     string
+		wantKeptID    bool
+	}{
+		{
+			name:       "request id propagated",
+			path:       "/accounts/account-1",
+			requestID:  "req-42",
+			wantStatus: http.StatusOK,
+			wantLevel:  "info",
+			wantRoute:  "/accounts/:id",
+			wantKeptID: true,
+		},
+		{
+			name:       "request id generated",
+			path:       "/accounts/account-1",
+			wantStatus: http.StatusOK,
+			wantLevel:  "info",
+			wantRoute:  "/accounts/:id",
+		},
+		{
+			name:       "malformed request id replaced",
+			path:       "/accounts/account-1",
+			requestID:  "req 42; drop",
+			wantStatus: http.StatusOK,
+			wantLevel:  "info",
+			wantRoute:  "/accounts/:id",
+		},
+		{
+			name:          "credentials kept out of the logs",
+			path:          "/accounts/account-1?access_token=opaque-token-1",
+			authorization: "Bearer opaque-token-2",
+			wantStatus:    http.StatusOK,
+			wantLevel:     "info",
+			wantRoute:     "/accounts/:id",
+		},
+		{
+			name:       "client error",
+			path:       "/accounts",
+			wantStatus: http.StatusNotFound,
+			wantLevel:  "warn",
+			wantRoute:  middleware.UNMATCHED_ROUTE,
+		},
+		{
+			name:       "panicking handler",
+			path:       "/panic",
+			wantStatus: http.StatusInternalServerError,
+			wantLevel:  "error",
+			wantRoute:  "/panic",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			logger, err := logging.New("debug", &out)
+			if err != nil {
+				t.Fatalf("New() error = %v", err)
+			}
+
+			gin.SetMode(gin.TestMode)
+			router := gin.New()
+			router.Use(middleware.RequestID(), middleware.Logger(logger), middleware.Recovery())
+
+			var handlerID string
+			router.GET("/accounts/:id", func(c *gin.Context) {
+				handlerID = logging.RequestID(c.Request.Context())
+				logging.FromContext(c.Request.Context()).Debug("account read", zap.String("authorization", c.GetHeader("Authorization")))
+				c.Status(http.StatusOK)
+			})
+			router.GET("/panic", func(c *gin.Context) {
+				panic("ledger unavailable")
+			})
+
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			if tt.requestID != "" {
+				req.Header.Set(logging.REQUEST_ID_HEADER, tt.requestID)
+			}
+			if tt.authorization != "" {
+				req.Header.Set("Authorization", tt.authorization)
+			}
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Errorf("expected status %d, got %d", tt.wantStatus, rec.Code)
+			}
+			requestID := rec.Header().Get(logging.REQUEST_ID_HEADER)
+			if requestID == "" {
+				t.Fatalf("expected the response to carry a request id")
+			}
+			if (requestID == tt.requestID) != tt.wantKeptID {
+				t.Errorf("expected the received request id to be kept: %v, got %q", tt.wantKeptID, requestID)
+			}
+			if handlerID != "" && handlerID != requestID {
+				t.Errorf("expected the handler to see request id %q, got %q", requestID, handlerID)
+			}
+			for _, leak := range []string{"opaque-token-1", "opaque-token-2"} {
+				if strings.Contains(out.String(), leak) {
+					t.Errorf("expected %q to be kept out of the logs, got %s", leak, out.String())
+				}
+			}
+
+			lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+			var access map[string]interface{}
+			if err := json.Unmarshal([]byte(lines[len(lines)-1]), &access); err != nil {
+				t.Fatalf("expected a JSON access log entry, got %s", out.String())
+			}
+			for key, want := range map[string]interface{}{
+				"message":    "request served",
+				"level":      tt.wantLevel,
+				"route":      tt.wantRoute,
+				"status":     float64(tt.wantStatus),
+				"request_id": requestID,
+			} {
+				if access[key] != want {
+					t.Errorf("expected the access log's %s to be %v, got %v", key, want, access[key])
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,25 @@
+package middleware
+
+import (
+	"time"
+
+	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/metrics"
+	"github.com/gin-gonic/gin"
+)
+
+// UNMATCHED_ROUTE labels the requests that matched no route, so that unknown paths do not each get their own series
+var UNMATCHED_ROUTE = "unmatched"
+
+// Metrics records every request's status and latency against the route it matched
+func Metrics(recorder metrics.RequestRecorder) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		c.Next()
+
+		route := c.FullPath()
+		if route == "" {
+			route = UNMATCHED_ROUTE
+		}
+		recorder.ObserveRequest(c.Request.Method, route, c.Writer.Status(), time.Since(start))
+	}
+}
@@ -0,0 +1,70 @@
+package middleware_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/presentation/middleware"
+	"github.com/gin-gonic/gin"
+)
+
+type recordedRequest struct {
+	method string
+	route  string
+	status int
+}
+
+type requestRecorder struct {
+	requests []recordedRequest
+}
+
+func (r *requestRecorder) ObserveRequest(method string, route string, status int, duration time.Duration) {
+	r.requests = append(r.requests, recordedRequest{method, route, status})
+}
+
+func TestMetrics(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		want   recordedRequest
+	}{
+		{
+			name:   "labelled by route, not path",
+			method: http.MethodGet,
+			path:   "/account/0b0e4f5c",
+			want:   recordedRequest{http.MethodGet, "/account/:id", http.StatusOK},
+		},
+		{
+			name:   "failed request",
+			method: http.MethodPost,
+			path:   "/transfer",
+			want:   recordedRequest{http.MethodPost, "/transfer", http.StatusBadRequest},
+		},
+		{
+			name:   "unknown path",
+			method: http.MethodGet,
+			path:   "/wp-admin",
+			want:   recordedRequest{http.MethodGet, middleware.UNMATCHED_ROUTE, http.StatusNotFound},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := &requestRecorder{}
+			router := gin.New()
+			router.Use(middleware.Metrics(recorder))
+			router.GET("/account/:id", func(c *gin.Context) { c.Status(http.StatusOK) })
+			router.POST("/transfer", func(c *gin.Context) { c.Status(http.StatusBadRequest) })
+
+			router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(tt.method, tt.path, nil))
+
+			if len(recorder.requests) != 1 || recorder.requests[0] != tt.want {
+				t.Errorf("recorded %v, want %v", recorder.requests, tt.want)
+			}
+		})
+	}
+}
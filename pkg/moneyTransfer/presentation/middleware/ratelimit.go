@@ -0,0 +1,90 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"math"
+	"net/http"
+	"strconv"
+
+	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/logging"
+	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/ratelimit"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// RateLimitKey tells what a request is counted by, an empty key leaves the request out of the limit
+type RateLimitKey func(c *gin.Context) string
+
+// RATE_LIMIT_KEYS are how requests are counted by each of the ratelimit keys
+var RATE_LIMIT_KEYS = map[string]RateLimitKey{
+	ratelimit.KEY_IP:      ClientIPKey,
+	ratelimit.KEY_SUBJECT: SubjectKey,
+	ratelimit.KEY_ACCOUNT: SourceAccountKey,
+}
+
+// ClientIPKey counts requests by the address of the client, as seen through the trusted proxies
+func ClientIPKey(c *gin.Context) string {
+	return c.ClientIP()
+}
+
+// SubjectKey counts requests by the subject of their access token
+func SubjectKey(c *gin.Context) string {
+	return Subject(c.Request.Context())
+}
+
+// SourceAccountKey counts requests by the account they move money from, read from their JSON body
+func SourceAccountKey(c *gin.Context) string {
+	if c.Request.Body == nil {
+		return ""
+	}
+	body, err := io.ReadAll(c.Request.Body)
+	c.Request.Body = io.NopCloser(bytes.NewReader(body))
+	if err != nil {
+		return ""
+	}
+
+	var payload struct {
+		SourceAccountID string
+	}
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return ""
+	}
+	return payload.SourceAccountID
+}
+
+// RateLimitRule limits the requests to a route sharing a key
+type RateLimitRule struct {
+	// Name tells the rules of a route apart in the store
+	Name  string
+	Key   RateLimitKey
+	Limit ratelimit.Limit
+}
+
+// RateLimit rejects the requests going over any of the rules with a 429 and the seconds to wait in Retry-After.
+// Requests are let through when the store cannot be reached, rather than failing the API with it
+func RateLimit(store ratelimit.Store, rules ...RateLimitRule) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		for _, rule := range rules {
+			key := rule.Key(c)
+			if key == "" {
+				continue
+			}
+
+			decision, err := store.Take(c.Request.Context(), c.Request.Method+" "+c.FullPath()+" "+rule.Name+" "+key, rule.Limit)
+			if err != nil {
+				logging.FromContext(c.Request.Context()).Warn("unable to apply the rate limit", zap.String("rule", rule.Name), zap.Error(err))
+				continue
+			}
+			if !decision.Allowed {
+				logging.FromContext(c.Request.Context()).Info("request rate limited", zap.String("rule", rule.Name))
+				c.Header("Retry-After", strconv.Itoa(int(math.Max(1, math.Ceil(decision.RetryAfter.Seconds())))))
+				c.AbortWithStatusJSON(http.StatusTooManyRequests, gin.H{"error": "Too many requests.", "code": "rate_limited"})
+				return
+			}
+		}
+
+		c.Next()
+	}
+}
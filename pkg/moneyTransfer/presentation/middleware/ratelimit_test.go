@@ -0,0 +1,141 @@
+package middleware_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/presentation/middleware"
+	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/ratelimit"
+	"github.com/gin-gonic/gin"
+)
+
+type unavailableStore struct{}
+
+func (unavailableStore) Take(ctx context.Context, key string, limit ratelimit.Limit) (ratelimit.Decision, error) {
+	return ratelimit.Decision{}, errors.New("connection refused")
+}
+
+func newRateLimitedRouter(store ratelimit.Store, rules ...middleware.RateLimitRule) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.POST("/transfers", middleware.RateLimit(store, rules...), func(c *gin.Context) {
+		body, _ := io.ReadAll(c.Request.Body)
+		c.String(http.StatusOK, string(body))
+	})
+	return router
+}
+
+func transfer(router *gin.Engine, clientIP string, body string) *httptest.ResponseRecorder {
+	request := httptest.NewRequest(http.MethodPost, "/transfers", strings.NewReader(body))
+	request.RemoteAddr = clientIP + ":41000"
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+	return recorder
+}
+
+func TestRateLimit(t *testing.T) {
+	perIP := middleware.RateLimitRule{
+		Name:  ratelimit.KEY_IP,
+		Key:   middleware.ClientIPKey,
+		Limit: ratelimit.Limit{Requests: 2, Period: time.Minute},
+	}
+	perAccount := middleware.RateLimitRule{
+		Name:  ratelimit.KEY_ACCOUNT,
+		Key:   middleware.SourceAccountKey,
+		Limit: ratelimit.Limit{Requests: 1, Period: time.Minute},
+	}
+
+	tests := []struct {
+		name     string
+		rules    []middleware.RateLimitRule
+		requests [][2]string
+		want     []int
+	}{
+		{
+			name:     "per client address",
+			rules:    []middleware.RateLimitRule{perIP},
+			requests: [][2]string{{"192.0.2.1", ""}, {"192.0.2.1", ""}, {"192.0.2.1", ""}, {"192.0.2.2", ""}},
+			want:     []int{http.StatusOK, http.StatusOK, http.StatusTooManyRequests, http.StatusOK},
+		},
+		{
+			name:  "per source account",
+			rules: []middleware.RateLimitRule{perAccount},
+			requests: [][2]string{
+				{"192.0.2.1", `{"SourceAccountID": "acc-1"}`},
+				{"192.0.2.2", `{"SourceAccountID": "acc-1"}`},
+				{"192.0.2.1", `{"SourceAccountID": "acc-2"}`},
+			},
+			want: []int{http.StatusOK, http.StatusTooManyRequests, http.StatusOK},
+		},
+		{
+			name:     "without the key",
+			rules:    []middleware.RateLimitRule{perAccount},
+			requests: [][2]string{{"192.0.2.1", `not json`}, {"192.0.2.1", `not json`}},
+			want:     []int{http.StatusOK, http.StatusOK},
+		},
+		{
+			name:  "any rule going over",
+			rules: []middleware.RateLimitRule{perIP, perAccount},
+			requests: [][2]string{
+				{"192.0.2.1", `{"SourceAccountID": "acc-1"}`},
+				{"192.0.2.1", `{"SourceAccountID": "acc-2"}`},
+				{"192.0.2.1", `{"SourceAccountID": "acc-3"}`},
+			},
+			want: []int{http.StatusOK, http.StatusOK, http.StatusTooManyRequests},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := newRateLimitedRouter(ratelimit.NewMemoryStore(), tt.rules...)
+			for i, request := range tt.requests {
+				recorder := transfer(router, request[0], request[1])
+				if recorder.Code != tt.want[i] {
+					t.Fatalf("request %d: expected %v, got %v", i, tt.want[i], recorder.Code)
+				}
+				if recorder.Code == http.StatusOK && recorder.Body.String() != request[1] {
+					t.Errorf("request %d: expected the handler to read the body, got %q", i, recorder.Body.String())
+				}
+			}
+		})
+	}
+}
+
+func TestRateLimit_RetryAfter(t *testing.T) {
+	router := newRateLimitedRouter(ratelimit.NewMemoryStore(), middleware.RateLimitRule{
+		Name:  ratelimit.KEY_IP,
+		Key:   middleware.ClientIPKey,
+		Limit: ratelimit.Limit{Requests: 1, Period: 90 * time.Second},
+	})
+
+	transfer(router, "192.0.2.1", "")
+	recorder := transfer(router, "192.0.2.1", "")
+	if recorder.Code != http.StatusTooManyRequests {
+		t.Fatalf("expected the second request to be rate limited, got %v", recorder.Code)
+	}
+	if retryAfter := recorder.Header().Get("Retry-After"); retryAfter != "90" {
+		t.Errorf("expected to be told to retry after 90 seconds, got %q", retryAfter)
+	}
+	if !strings.Contains(recorder.Body.String(), `"code":"rate_limited"`) {
+		t.Errorf("expected a rate limited error, got %s", recorder.Body.String())
+	}
+}
+
+func TestRateLimit_UnavailableStore(t *testing.T) {
+	router := newRateLimitedRouter(unavailableStore{}, middleware.RateLimitRule{
+		Name:  ratelimit.KEY_IP,
+		Key:   middleware.ClientIPKey,
+		Limit: ratelimit.Limit{Requests: 1, Period: time.Minute},
+	})
+
+	for i := 0; i < 3; i++ {
+		if recorder := transfer(router, "192.0.2.1", ""); recorder.Code != http.StatusOK {
+			t.Errorf("expected requests to be let through without the store, got %v", recorder.Code)
+		}
+	}
+}
@@ -0,0 +1,65 @@
+package middleware_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/presentation/middleware"
+	"github.com/gin-gonic/gin"
+)
+
+func TestTimeout(t *testing.T) {
+	tests := []struct {
+		name    string
+		timeout time.Duration
+		work    time.Duration
+		wantErr error
+	}{
+		{
+			name:    "work finishing within the deadline",
+			timeout: time.Second,
+			work:    time.Millisecond,
+		},
+		{
+			name:    "work outliving the deadline",
+			timeout: 10 * time.Millisecond,
+			work:    time.Second,
+			wantErr: context.DeadlineExceeded,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gin.SetMode(gin.TestMode)
+			router := gin.New()
+
+			var gotErr error
+			var hasDeadline bool
+			router.GET("/work", middleware.Timeout(tt.timeout), func(c *gin.Context) {
+				_, hasDeadline = c.Request.Context().Deadline()
+				select {
+				case <-time.After(tt.work):
+				case <-c.Request.Context().Done():
+				}
+				gotErr = c.Request.Context().Err()
+				c.Status(http.StatusNoContent)
+			})
+
+			start := time.Now()
+			router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/work", nil))
+
+			if !hasDeadline {
+				t.Errorf("expected the request's context to have a deadline")
+			}
+			if !errors.Is(gotErr, tt.wantErr) {
+				t.Errorf("expected the request's context to end with %v, got %v", tt.wantErr, gotErr)
+			}
+			if elapsed := time.Since(start); elapsed > tt.timeout+500*time.Millisecond {
+				t.Errorf("expected the request to be cut short after %v, it took %v", tt.timeout, elapsed)
+			}
+		})
+	}
+}
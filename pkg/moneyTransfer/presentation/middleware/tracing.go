@@ -0,0 +1,27 @@
+package middleware
+
+import (
+	"context"
+
+	jwtmiddleware "github.com/auth0/go-jwt-middleware/v2"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+)
+
+// tracer traces the request processing done by the middleware
+var tracer = otel.Tracer("github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/presentation/middleware")
+
+// TracedTokenValidation runs access token validations in their own span
+func TracedTokenValidation(validate jwtmiddleware.ValidateToken) jwtmiddleware.ValidateToken {
+	return func(ctx context.Context, token string) (interface{}, error) {
+		ctx, span := tracer.Start(ctx, "jwt.validate")
+		defer span.End()
+
+		claims, err := validate(ctx, token)
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+		}
+		return claims, err
+	}
+}
@@ -0,0 +1,48 @@
+package middleware_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/presentation/middleware"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestTracedTokenValidation(t *testing.T) {
+	spans := tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(spans)))
+	defer otel.SetTracerProvider(trace.NewNoopTracerProvider())
+
+	validate := middleware.TracedTokenValidation(func(ctx context.Context, token string) (interface{}, error) {
+		if !trace.SpanFromContext(ctx).SpanContext().IsValid() {
+			t.Errorf("expected the validation to run within its span")
+		}
+		if token != "valid" {
+			return nil, fmt.Errorf("token is expired")
+		}
+		return "claims", nil
+	})
+
+	if _, err := validate(context.Background(), "valid"); err != nil {
+		t.Errorf("expected the token to be valid, got %v", err)
+	}
+	if _, err := validate(context.Background(), "expired"); err == nil {
+		t.Errorf("expected the token to be rejected")
+	}
+
+	ended := spans.Ended()
+	if len(ended) != 2 {
+		t.Fatalf("expected a span per validation, got %d", len(ended))
+	}
+	if ended[0].Name() != "jwt.validate" || ended[0].Status().Code == codes.Error {
+		t.Errorf("expected a successful jwt.validate span, got %s %v", ended[0].Name(), ended[0].Status())
+	}
+	if ended[1].Status().Code != codes.Error {
+		t.Errorf("expected the rejected token to mark its span as failed")
+	}
+}
@@ -0,0 +1,35 @@
+package openapi
+
+import (
+	"bytes"
+	_ "embed"
+	"html/template"
+	"log"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+//go:embed ui/index.html
+var swaggerUI string
+
+var swaggerTemplate = template.Must(template.New("swagger").Parse(swaggerUI))
+
+// SpecHandler serves a document as JSON
+func SpecHandler(document *Document) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.JSON(http.StatusOK, document)
+	}
+}
+
+// SwaggerUIHandler serves a Swagger UI page exploring the document at specURL
+func SwaggerUIHandler(specURL string) gin.HandlerFunc {
+	var page bytes.Buffer
+	if err := swaggerTemplate.Execute(&page, struct{ SpecURL string }{specURL}); err != nil {
+		log.Panicf("unable to render the swagger ui: %v", err)
+	}
+
+	return func(c *gin.Context) {
+		c.Data(http.StatusOK, "text/html; charset=utf-8", page.Bytes())
+	}
+}
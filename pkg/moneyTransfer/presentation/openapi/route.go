@@ -0,0 +1,182 @@
+package openapi
+
+import (
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// BEARER_AUTH is the security scheme authenticated operations require
+var BEARER_AUTH = "bearerAuth"
+
+// Route is an API route together with the documentation of the operation it serves
+type Route struct {
+	Method  string
+	Path    string
+	Handler gin.HandlerFunc
+
+	// Name is the operation's unique identifier
+	Name    string
+	Summary string
+	Tag     string
+
+	// Public routes are served without an access token, Admin routes need the admin scope
+	Public bool
+	Admin  bool
+
+	Query []Parameter
+
+	// Timeout bounds how long serving the operation may take, the API's default when unset
+	Timeout time.Duration
+
+	// Request is a value of the JSON body's type, RequestContentType documents other bodies as text
+	Request            interface{}
+	RequestContentType string
+
+	// Status is the success status, 200 when unset
+	Status int
+
+	// Response is a value of the JSON body's type, wrapped in an object under ResponseKey when set.
+	// Produces lists other content types the operation can respond with
+	Response    interface{}
+	ResponseKey string
+	Produces    []string
+
+	// Accepted is a value of the JSON body's type answering a request accepted for later processing with a 202
+	Accepted interface{}
+}
+
+// QueryParam describes an optional query string parameter
+func QueryParam(name string, description string) Parameter {
+	return Parameter{
+		Name:        name,
+		In:          "query",
+		Description: description,
+		Schema:      &Schema{Type: "string"},
+	}
+}
+
+// Path converts a gin route path to an OpenAPI path template
+func Path(ginPath string) (string, []string) {
+	var params []string
+	segments := strings.Split(ginPath, "/")
+	for i, segment := range segments {
+		if strings.HasPrefix(segment, ":") || strings.HasPrefix(segment, "*") {
+			params = append(params, segment[1:])
+			segments[i] = "{" + segment[1:] + "}"
+		}
+	}
+	return strings.Join(segments, "/"), params
+}
+
+// Build documents routes served under a base path, errors are described by errorBody's type
+func Build(info Info, basePath string, routes []Route, errorBody interface{}, enums ...Enum) *Document {
+	s := newSchemas(enums)
+	document := &Document{
+		OpenAPI: OPENAPI_VERSION,
+		Info:    info,
+		Servers: []Server{{URL: basePath}},
+		Paths:   map[string]PathItem{},
+		Components: Components{
+			SecuritySchemes: map[string]SecurityScheme{
+				BEARER_AUTH: {Type: "http", Scheme: "bearer", BearerFormat: "JWT"},
+			},
+		},
+	}
+
+	errorSchema := s.of(errorBody)
+	tags := map[string]bool{}
+	for _, route := range routes {
+		path, params := Path(route.Path)
+		operation := &Operation{
+			OperationID: route.Name,
+			Summary:     route.Summary,
+			Responses:   map[string]Response{},
+		}
+
+		if route.Tag != "" {
+			operation.Tags = []string{route.Tag}
+			tags[route.Tag] = true
+		}
+
+		for _, param := range params {
+			operation.Parameters = append(operation.Parameters, Parameter{
+				Name:     param,
+				In:       "path",
+				Required: true,
+				Schema:   &Schema{Type: "string"},
+			})
+		}
+		operation.Parameters = append(operation.Parameters, route.Query...)
+
+		if !route.Public {
+			operation.Security = []map[string][]string{{BEARER_AUTH: {}}}
+		}
+		if route.Admin {
+			operation.Description = "Requires an access token with the admin scope."
+		}
+
+		switch {
+		case route.Request != nil:
+			operation.RequestBody = &RequestBody{
+				Required: true,
+				Content:  map[string]MediaType{"application/json": {Schema: s.of(route.Request)}},
+			}
+		case route.RequestContentType != "":
+			operation.RequestBody = &RequestBody{
+				Required: true,
+				Content:  map[string]MediaType{route.RequestContentType: {Schema: &Schema{Type: "string"}}},
+			}
+		}
+
+		status := route.Status
+		if status == 0 {
+			status = http.StatusOK
+		}
+		response := Response{Description: http.StatusText(status)}
+		if route.Response != nil || len(route.Produces) > 0 {
+			response.Content = map[string]MediaType{}
+		}
+		if route.Response != nil {
+			schema := s.of(route.Response)
+			if route.ResponseKey != "" {
+				schema = &Schema{
+					Type:       "object",
+					Properties: map[string]*Schema{route.ResponseKey: schema},
+				}
+			}
+			response.Content["application/json"] = MediaType{Schema: schema}
+		}
+		for _, contentType := range route.Produces {
+			response.Content[contentType] = MediaType{Schema: &Schema{Type: "string"}}
+		}
+		operation.Responses[strconv.Itoa(status)] = response
+		if route.Accepted != nil {
+			operation.Responses[strconv.Itoa(http.StatusAccepted)] = Response{
+				Description: http.StatusText(http.StatusAccepted),
+				Content:     map[string]MediaType{"application/json": {Schema: s.of(route.Accepted)}},
+			}
+		}
+		operation.Responses["default"] = Response{
+			Description: "Error",
+			Content:     map[string]MediaType{"application/json": {Schema: errorSchema}},
+		}
+
+		if document.Paths[path] == nil {
+			document.Paths[path] = PathItem{}
+		}
+		document.Paths[path][strings.ToLower(route.Method)] = operation
+	}
+
+	for tag := range tags {
+		document.Tags = append(document.Tags, Tag{Name: tag})
+	}
+	sort.Slice(document.Tags, func(i, j int) bool { return document.Tags[i].Name < document.Tags[j].Name })
+
+	document.Components.Schemas = s.components
+	return document
+}
@@ -0,0 +1,156 @@
+package openapi
+
+import (
+	"encoding/json"
+	"path"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+)
+
+// Enum lists the values a named string type is restricted to
+type Enum struct {
+	Type   reflect.Type
+	Values []interface{}
+}
+
+// EnumOf describes the values of a named string type
+func EnumOf[T ~string](values []T) Enum {
+	enum := Enum{Type: reflect.TypeOf(values).Elem()}
+	for _, value := range values {
+		enum.Values = append(enum.Values, string(value))
+	}
+	return enum
+}
+
+// formats describes types that are not encoded the way their Go kind suggests
+var formats = map[reflect.Type]Schema{
+	reflect.TypeOf(decimal.Decimal{}): {Type: "string", Format: "decimal"},
+	reflect.TypeOf(time.Time{}):       {Type: "string", Format: "date-time"},
+	reflect.TypeOf(gorm.DeletedAt{}):  {Type: "string", Format: "date-time", Nullable: true},
+	reflect.TypeOf(json.RawMessage{}): {Description: "any JSON value"},
+}
+
+// schemas derives schemas from Go types the way encoding/json encodes them.
+// Structs are described once as components and referred to
+type schemas struct {
+	components map[string]*Schema
+	names      map[reflect.Type]string
+	enums      map[reflect.Type][]interface{}
+}
+
+func newSchemas(enums []Enum) *schemas {
+	s := &schemas{
+		components: map[string]*Schema{},
+		names:      map[reflect.Type]string{},
+		enums:      map[reflect.Type][]interface{}{},
+	}
+	for _, enum := range enums {
+		s.enums[enum.Type] = enum.Values
+	}
+	return s
+}
+
+// of describes the type of a value, nil describes any value
+func (s *schemas) of(value interface{}) *Schema {
+	if value == nil {
+		return &Schema{}
+	}
+	return s.schema(reflect.TypeOf(value))
+}
+
+func (s *schemas) schema(t reflect.Type) *Schema {
+	nullable := false
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+		nullable = true
+	}
+
+	if format, ok := formats[t]; ok {
+		format.Nullable = format.Nullable || nullable
+		return &format
+	}
+
+	if values, ok := s.enums[t]; ok {
+		return &Schema{Type: "string", Enum: values, Nullable: nullable}
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return &Schema{Type: "boolean", Nullable: nullable}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return &Schema{Type: "integer", Format: "int32", Nullable: nullable}
+	case reflect.Int64, reflect.Uint64:
+		return &Schema{Type: "integer", Format: "int64", Nullable: nullable}
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: "number", Nullable: nullable}
+	case reflect.String:
+		return &Schema{Type: "string", Nullable: nullable}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return &Schema{Type: "string", Format: "byte", Nullable: nullable}
+		}
+		return &Schema{Type: "array", Items: s.schema(t.Elem()), Nullable: true}
+	case reflect.Map:
+		return &Schema{Type: "object", AdditionalProperties: s.schema(t.Elem()), Nullable: true}
+	case reflect.Struct:
+		return &Schema{Ref: "#/components/schemas/" + s.component(t)}
+	}
+	return &Schema{}
+}
+
+// component registers a struct's schema, returning the name it is referred to by
+func (s *schemas) component(t reflect.Type) string {
+	if name, ok := s.names[t]; ok {
+		return name
+	}
+
+	name := t.Name()
+	if _, taken := s.components[name]; taken || name == "" {
+		name = path.Base(t.PkgPath()) + "." + name
+	}
+
+	// Register before describing the fields so that recursive types refer to themselves
+	schema := &Schema{Type: "object", Properties: map[string]*Schema{}}
+	s.names[t] = name
+	s.components[name] = schema
+	s.fields(t, schema)
+	return name
+}
+
+// fields describes a struct's encoded fields, promoting the fields of embedded structs
+func (s *schemas) fields(t reflect.Type, schema *Schema) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" {
+			continue
+		}
+
+		fieldType := field.Type
+		if fieldType.Kind() == reflect.Ptr {
+			fieldType = fieldType.Elem()
+		}
+		if field.Anonymous && name == "" && fieldType.Kind() == reflect.Struct {
+			s.fields(fieldType, schema)
+			continue
+		}
+
+		if !field.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+
+		schema.Properties[name] = s.schema(field.Type)
+		for _, rule := range strings.Split(field.Tag.Get("binding"), ",") {
+			if rule == "required" {
+				schema.Required = append(schema.Required, name)
+			}
+		}
+	}
+}
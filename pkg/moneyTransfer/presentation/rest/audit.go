@@ -0,0 +1,100 @@
+package rest
+
+import (
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/application"
+	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/usecases"
+	"github.com/gin-gonic/gin"
+)
+
+// AuditHandlers defines a contract the audit log rest presentation adheres to
+type AuditHandlers interface {
+	AuditTrail(c *gin.Context)
+	VerifyAuditChain(c *gin.Context)
+}
+
+// Audit sets up the audit log REST presentation layer with all it's dependencies
+type Audit struct {
+	Uc usecases.AuditUsecases
+
+	// Location is the business time zone plain dates are read in
+	Location *time.Location
+}
+
+// CheckPreconditions ensures a correct Audit struct is initialized
+func (a Audit) CheckPreconditions() {
+	if a.Uc == nil {
+		log.Panic("audit presentation layer has not initialized the business logic")
+	}
+
+	if a.Location == nil {
+		log.Panic("audit presentation layer has not initialized the business time zone")
+	}
+}
+
+// NewAuditHandlers initializes a new audit log endpoints handler
+func NewAuditHandlers(uc usecases.AuditUsecases, location *time.Location) *Audit {
+	a := &Audit{
+		Uc:       uc,
+		Location: location,
+	}
+	a.CheckPreconditions()
+	return a
+}
+
+// AuditTrail implements the audit log query handler, filtered by actor, route, resource and date
+func (a Audit) AuditTrail(c *gin.Context) {
+	filter := application.AuditFilter{
+		Actor:    c.Query("actor"),
+		Route:    c.Query("route"),
+		Resource: c.Query("resource"),
+	}
+
+	if c.Query("from") != "" {
+		from, err := parseDate(c, "from", a.Location, time.Time{}, false)
+		if err != nil {
+			jsonErrorResponse(c, http.StatusBadRequest, err.Error())
+			return
+		}
+		filter.From = &from
+	}
+	if c.Query("to") != "" {
+		to, err := parseDate(c, "to", a.Location, time.Time{}, true)
+		if err != nil {
+			jsonErrorResponse(c, http.StatusBadRequest, err.Error())
+			return
+		}
+		filter.To = &to
+	}
+	if c.Query("limit") != "" {
+		limit, err := strconv.Atoi(c.Query("limit"))
+		if err != nil {
+			jsonErrorResponse(c, http.StatusBadRequest, "limit should be a number")
+			return
+		}
+		filter.Limit = limit
+	}
+
+	records, err := a.Uc.AuditTrail(c.Request.Context(), filter)
+	if err != nil {
+		errorResponse(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"records": records})
+}
+
+// VerifyAuditChain implements the handler checking the audit log has not been tampered with
+func (a Audit) VerifyAuditChain(c *gin.Context) {
+	verification, err := a.Uc.VerifyChain(c.Request.Context())
+	if err != nil {
+		errorResponse(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"verification": verification})
+}
@@ -0,0 +1,88 @@
+package rest
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/domain"
+	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/logging"
+	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/presentation/validation"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// ErrorResponse is the body of every error response
+type ErrorResponse struct {
+	Error  string            `json:"error"`
+	Code   string            `json:"code,omitempty"`
+	Fields map[string]string `json:"fields,omitempty"`
+}
+
+// HeldTransferResponse is the body answering a transfer held for fraud review, it is followed with its held transfer
+type HeldTransferResponse struct {
+	HeldTransferID string            `json:"held_transfer_id"`
+	Status         domain.HoldStatus `json:"status"`
+}
+
+// ERROR_STATUS_CODES maps each kind of domain error to the HTTP status reporting it
+var ERROR_STATUS_CODES = map[domain.ErrorKind]int{
+	domain.NotFound:          http.StatusNotFound,
+	domain.InsufficientFunds: http.StatusUnprocessableEntity,
+	domain.Validation:        http.StatusUnprocessableEntity,
+	domain.Conflict:          http.StatusConflict,
+	domain.Forbidden:         http.StatusForbidden,
+	domain.Held:              http.StatusAccepted,
+	domain.Internal:          http.StatusInternalServerError,
+}
+
+// CLIENT_CLOSED_REQUEST is the non-standard status recorded for requests abandoned by their client
+var CLIENT_CLOSED_REQUEST = 499
+
+// StatusCode returns the HTTP status reporting an error
+func StatusCode(err error) int {
+	if status, ok := ERROR_STATUS_CODES[domain.AsError(err).Kind]; ok {
+		return status
+	}
+	return http.StatusInternalServerError
+}
+
+// errorResponse reports a usecase error with the status and code of its kind, or a held transfer.
+// Internal errors are logged and reported without their cause, unless the request timed out or was cancelled
+func errorResponse(c *gin.Context, err error) {
+	typed := domain.AsError(err)
+	// A held transfer has not failed, it is accepted for review
+	if typed.Kind == domain.Held {
+		c.JSON(http.StatusAccepted, HeldTransferResponse{HeldTransferID: typed.HeldTransferID, Status: domain.HoldPending})
+		return
+	}
+	if typed.Kind == domain.Internal {
+		// Work cut short by the request's deadline or its client going away fails with whatever error it was at
+		switch c.Request.Context().Err() {
+		case context.DeadlineExceeded:
+			c.JSON(http.StatusGatewayTimeout, ErrorResponse{Error: "the request did not complete in time", Code: "request_timeout"})
+			return
+		case context.Canceled:
+			c.AbortWithStatus(CLIENT_CLOSED_REQUEST)
+			return
+		}
+		logging.FromContext(c.Request.Context()).Error("request failed", zap.String("route", c.FullPath()), zap.Error(err))
+	}
+
+	c.JSON(StatusCode(err), ErrorResponse{Error: typed.Message, Code: typed.Code})
+}
+
+// bindingErrorResponse reports a request body that could not be bound.
+// Failed validations are reported per field, anything else is a malformed request
+func bindingErrorResponse(c *gin.Context, err error) {
+	fields, ok := validation.FieldErrors(err)
+	if !ok {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error(), Code: "malformed_request"})
+		return
+	}
+
+	c.JSON(http.StatusUnprocessableEntity, ErrorResponse{
+		Error:  "the request has invalid fields",
+		Code:   "invalid_request",
+		Fields: fields,
+	})
+}
@@ -0,0 +1,134 @@
+package rest_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/application"
+	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/config"
+	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/domain"
+	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/presentation/middleware"
+	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/presentation/rest"
+	"github.com/gin-gonic/gin"
+)
+
+func TestStatusCode(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want int
+	}{
+		{
+			name: "missing resource",
+			err:  domain.NewNotFoundError("account_not_found", "account %s was not found", "1"),
+			want: http.StatusNotFound,
+		},
+		{
+			name: "insufficient funds",
+			err:  domain.NewInsufficientFundsError("100 is more than the balance"),
+			want: http.StatusUnprocessableEntity,
+		},
+		{
+			name: "broken business rule",
+			err:  domain.NewValidationError("invalid_amount", "amount should be positive"),
+			want: http.StatusUnprocessableEntity,
+		},
+		{
+			name: "clashing state",
+			err:  domain.NewConflictError("already_reversed", "transaction has already been reversed"),
+			want: http.StatusConflict,
+		},
+		{
+			name: "disallowed request",
+			err:  domain.NewForbiddenError("subscription_forbidden", "subscription belongs to someone else"),
+			want: http.StatusForbidden,
+		},
+		{
+			name: "wrapped typed error",
+			err:  fmt.Errorf("unable to commit transaction: %w", domain.NewConflictError("period_closed", "period is closed")),
+			want: http.StatusConflict,
+		},
+		{
+			name: "untyped error",
+			err:  fmt.Errorf("connection refused"),
+			want: http.StatusInternalServerError,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := rest.StatusCode(tt.err); got != tt.want {
+				t.Errorf("StatusCode() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	if typed := domain.AsError(fmt.Errorf("dial tcp: connection refused")); typed.Message != "internal server error" {
+		t.Errorf("expected the cause of an untyped error to be hidden, got %q", typed.Message)
+	}
+}
+
+type blockingMoneyTransfer struct {
+	stubMoneyTransfer
+}
+
+func (blockingMoneyTransfer) Account(ctx context.Context, accountID string) (*application.AccountInformationOutput, error) {
+	<-ctx.Done()
+	return nil, fmt.Errorf("unable to get account %s: %v", accountID, ctx.Err())
+}
+
+func TestErrorResponse_RequestContext(t *testing.T) {
+	tests := []struct {
+		name       string
+		timeout    time.Duration
+		cancelIn   time.Duration
+		wantStatus int
+		wantCode   string
+	}{
+		{
+			name:       "deadline passed",
+			timeout:    10 * time.Millisecond,
+			wantStatus: http.StatusGatewayTimeout,
+			wantCode:   "request_timeout",
+		},
+		{
+			name:       "client went away",
+			timeout:    time.Minute,
+			cancelIn:   10 * time.Millisecond,
+			wantStatus: rest.CLIENT_CLOSED_REQUEST,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gin.SetMode(gin.TestMode)
+			router := gin.New()
+			router.GET("/account/:id", middleware.Timeout(tt.timeout), rest.NewRestHandlers(blockingMoneyTransfer{}, config.Auth{}, &sync.Mutex{}).Account)
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			if tt.cancelIn > 0 {
+				// The client hanging up cancels the request's context
+				time.AfterFunc(tt.cancelIn, cancel)
+			}
+
+			recorder := httptest.NewRecorder()
+			router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/account/1", nil).WithContext(ctx))
+
+			if recorder.Code != tt.wantStatus {
+				t.Errorf("expected status %d, got %d", tt.wantStatus, recorder.Code)
+			}
+			if tt.wantCode == "" {
+				return
+			}
+			var response rest.ErrorResponse
+			if err := json.Unmarshal(recorder.Body.Bytes(), &response); err != nil || response.Code != tt.wantCode {
+				t.Errorf("expected the %q error code, got %s", tt.wantCode, recorder.Body.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,124 @@
+package rest
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/buildinfo"
+	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/logging"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// READINESS_CHECK_TIMEOUT bounds each readiness check, so that a hanging dependency fails the probe instead of stalling it
+var READINESS_CHECK_TIMEOUT = 2 * time.Second
+
+// Probe statuses
+var (
+	STATUS_OK        = "ok"
+	STATUS_READY     = "ready"
+	STATUS_NOT_READY = "not_ready"
+	STATUS_DRAINING  = "draining"
+	STATUS_FAILING   = "failing"
+)
+
+// Check is a dependency the service needs to serve requests, along with how to tell it is available
+type Check struct {
+	Name string
+	Run  func(ctx context.Context) error
+}
+
+// HealthResponse reports whether the service is live or ready, and the state of each dependency it checked
+type HealthResponse struct {
+	Status string            `json:"status"`
+	Checks map[string]string `json:"checks,omitempty"`
+}
+
+// HealthHandlers defines a contract the health probes rest presentation adheres to
+type HealthHandlers interface {
+	Live(c *gin.Context)
+	Ready(c *gin.Context)
+	BuildInfo(c *gin.Context)
+	Drain()
+}
+
+// Health sets up the liveness, readiness and build info endpoints probed by the orchestrator and load balancer
+type Health struct {
+	Checks []Check
+
+	draining atomic.Bool
+}
+
+// CheckPreconditions ensures a correct Health struct is initialized
+func (h *Health) CheckPreconditions() {
+	for _, check := range h.Checks {
+		if check.Name == "" || check.Run == nil {
+			log.Panic("health presentation layer has an incomplete readiness check")
+		}
+	}
+}
+
+// NewHealthHandlers initializes the health probes, ready once every check passes
+func NewHealthHandlers(checks ...Check) *Health {
+	h := &Health{
+		Checks: checks,
+	}
+	h.CheckPreconditions()
+	return h
+}
+
+// Drain marks the service as no longer ready, so that load balancers stop sending it traffic before it shuts down
+func (h *Health) Drain() {
+	h.draining.Store(true)
+}
+
+// Live reports that the process is up and serving requests
+func (h *Health) Live(c *gin.Context) {
+	c.JSON(http.StatusOK, HealthResponse{Status: STATUS_OK})
+}
+
+// Ready runs every check concurrently, reporting the service ready when all of them pass.
+// Failures are logged rather than reported, as the endpoint is public
+func (h *Health) Ready(c *gin.Context) {
+	if h.draining.Load() {
+		c.JSON(http.StatusServiceUnavailable, HealthResponse{Status: STATUS_DRAINING})
+		return
+	}
+
+	results := make([]error, len(h.Checks))
+	var wg sync.WaitGroup
+	for i, check := range h.Checks {
+		wg.Add(1)
+		go func(i int, check Check) {
+			defer wg.Done()
+			ctx, cancel := context.WithTimeout(c.Request.Context(), READINESS_CHECK_TIMEOUT)
+			defer cancel()
+			results[i] = check.Run(ctx)
+		}(i, check)
+	}
+	wg.Wait()
+
+	response := HealthResponse{Status: STATUS_READY, Checks: make(map[string]string, len(h.Checks))}
+	status := http.StatusOK
+	for i, check := range h.Checks {
+		if err := results[i]; err != nil {
+			logging.FromContext(c.Request.Context()).Warn("readiness check failed", zap.String("check", check.Name), zap.Error(err))
+			response.Checks[check.Name] = STATUS_FAILING
+			response.Status = STATUS_NOT_READY
+			status = http.StatusServiceUnavailable
+			continue
+		}
+		response.Checks[check.Name] = STATUS_OK
+	}
+
+	c.JSON(status, response)
+}
+
+// BuildInfo reports the version and commit of the running build
+func (h *Health) BuildInfo(c *gin.Context) {
+	c.JSON(http.StatusOK, buildinfo.Get())
+}
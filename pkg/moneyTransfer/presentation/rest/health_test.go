@@ -0,0 +1,129 @@
+package rest_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/buildinfo"
+	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/presentation/rest"
+	"github.com/gin-gonic/gin"
+)
+
+func passing(ctx context.Context) error { return nil }
+
+func failing(ctx context.Context) error {
+	return fmt.Errorf("dial tcp 10.0.0.5:5432: connection refused")
+}
+
+func hanging(ctx context.Context) error {
+	<-ctx.Done()
+	return ctx.Err()
+}
+
+func TestHealth_Ready(t *testing.T) {
+	tests := []struct {
+		name       string
+		checks     []rest.Check
+		drain      bool
+		wantStatus int
+		wantBody   rest.HealthResponse
+	}{
+		{
+			name:       "every check passing",
+			checks:     []rest.Check{{Name: "database", Run: passing}, {Name: "jwks", Run: passing}},
+			wantStatus: http.StatusOK,
+			wantBody: rest.HealthResponse{
+				Status: rest.STATUS_READY,
+				Checks: map[string]string{"database": rest.STATUS_OK, "jwks": rest.STATUS_OK},
+			},
+		},
+		{
+			name:       "a check failing",
+			checks:     []rest.Check{{Name: "database", Run: failing}, {Name: "jwks", Run: passing}},
+			wantStatus: http.StatusServiceUnavailable,
+			wantBody: rest.HealthResponse{
+				Status: rest.STATUS_NOT_READY,
+				Checks: map[string]string{"database": rest.STATUS_FAILING, "jwks": rest.STATUS_OK},
+			},
+		},
+		{
+			name:       "a check hanging",
+			checks:     []rest.Check{{Name: "migrations", Run: hanging}},
+			wantStatus: http.StatusServiceUnavailable,
+			wantBody: rest.HealthResponse{
+				Status: rest.STATUS_NOT_READY,
+				Checks: map[string]string{"migrations": rest.STATUS_FAILING},
+			},
+		},
+		{
+			name:       "draining",
+			checks:     []rest.Check{{Name: "database", Run: passing}},
+			drain:      true,
+			wantStatus: http.StatusServiceUnavailable,
+			wantBody:   rest.HealthResponse{Status: rest.STATUS_DRAINING},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			timeout := rest.READINESS_CHECK_TIMEOUT
+			defer func() { rest.READINESS_CHECK_TIMEOUT = timeout }()
+			rest.READINESS_CHECK_TIMEOUT = 20 * time.Millisecond
+
+			health := rest.NewHealthHandlers(tt.checks...)
+			if tt.drain {
+				health.Drain()
+			}
+
+			gin.SetMode(gin.TestMode)
+			router := gin.New()
+			router.GET("/readyz", health.Ready)
+			router.GET("/healthz", health.Live)
+
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+			if rec.Code != tt.wantStatus {
+				t.Errorf("expected status %d, got %d", tt.wantStatus, rec.Code)
+			}
+			var body rest.HealthResponse
+			if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+				t.Fatalf("unable to decode the readiness response %s: %v", rec.Body.String(), err)
+			}
+			if body.Status != tt.wantBody.Status || fmt.Sprint(body.Checks) != fmt.Sprint(tt.wantBody.Checks) {
+				t.Errorf("expected %+v, got %+v", tt.wantBody, body)
+			}
+
+			// Liveness does not depend on the checks nor on draining
+			rec = httptest.NewRecorder()
+			router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+			if rec.Code != http.StatusOK {
+				t.Errorf("expected the process to be reported live, got status %d", rec.Code)
+			}
+		})
+	}
+}
+
+func TestHealth_BuildInfo(t *testing.T) {
+	version := buildinfo.VERSION
+	defer func() { buildinfo.VERSION = version }()
+	buildinfo.VERSION = "1.4.0"
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/version", rest.NewHealthHandlers().BuildInfo)
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/version", nil))
+
+	var info buildinfo.Info
+	if err := json.Unmarshal(rec.Body.Bytes(), &info); err != nil {
+		t.Fatalf("unable to decode the build info %s: %v", rec.Body.String(), err)
+	}
+	if rec.Code != http.StatusOK || info.Version != "1.4.0" || info.GoVersion == "" {
+		t.Errorf("expected the running build's information, got %d %+v", rec.Code, info)
+	}
+}
@@ -0,0 +1,96 @@
+package rest
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+
+	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/infrastructure/iso20022"
+	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/presentation/middleware"
+	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/usecases"
+	"github.com/gin-gonic/gin"
+)
+
+// MAX_PAYMENT_FILE_SIZE limits the size of an uploaded payment initiation message, larger ones are refused whole
+var MAX_PAYMENT_FILE_SIZE int64 = 10 << 20
+
+// PaymentHandlers defines a contract the payment initiation rest presentation adheres to
+type PaymentHandlers interface {
+	ImportPain001(c *gin.Context)
+}
+
+// Payments sets up the payment initiation REST presentation layer with all it's dependencies
+type Payments struct {
+	Uc usecases.PaymentInitiationUsecases
+}
+
+// CheckPreconditions ensures a correct Payments struct is initialized
+func (p Payments) CheckPreconditions() {
+	if p.Uc == nil {
+		log.Panic("payments presentation layer has not initialized the business logic")
+	}
+}
+
+// NewPaymentHandlers initializes a new payment initiation endpoints handler
+func NewPaymentHandlers(uc usecases.PaymentInitiationUsecases) *Payments {
+	p := &Payments{
+		Uc: uc,
+	}
+	p.CheckPreconditions()
+	return p
+}
+
+// ImportPain001 executes the credit transfers of a pain.001 message sent as the request body
+// and responds with a pain.002 status report. A message sent again is refused with a conflict
+func (p Payments) ImportPain001(c *gin.Context) {
+	content, err := io.ReadAll(http.MaxBytesReader(c.Writer, c.Request.Body, MAX_PAYMENT_FILE_SIZE))
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			jsonErrorResponse(c, http.StatusRequestEntityTooLarge, fmt.Sprintf("payment initiation should not be larger than %d bytes", tooLarge.Limit))
+			return
+		}
+		jsonErrorResponse(c, http.StatusBadRequest, fmt.Sprintf("unable to read payment initiation: %v", err))
+		return
+	}
+
+	document, err := iso20022.ParsePain001(content)
+	if err != nil {
+		var groupErr iso20022.GroupError
+		if !errors.As(err, &groupErr) {
+			jsonErrorResponse(c, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		var messageID string
+		if document != nil {
+			messageID = document.Initiation.GroupHeader.MessageID
+		}
+		statusReport(c, http.StatusUnprocessableEntity, iso20022.NewPain002Rejection(messageID, groupErr))
+		return
+	}
+
+	input := document.Instructions()
+	input.Initiator = middleware.Subject(c.Request.Context())
+
+	results, err := p.Uc.ExecuteCreditTransfers(c.Request.Context(), input)
+	if err != nil {
+		errorResponse(c, err)
+		return
+	}
+
+	statusReport(c, http.StatusOK, iso20022.NewPain002(input.MessageID, results))
+}
+
+// statusReport writes a pain.002 message as the response
+func statusReport(c *gin.Context, statusCode int, document *iso20022.Pain002Document) {
+	content, err := iso20022.Marshal(document)
+	if err != nil {
+		jsonErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	c.Data(statusCode, "application/xml", content)
+}
@@ -0,0 +1,36 @@
+package rest_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/application"
+	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/presentation/rest"
+	"github.com/gin-gonic/gin"
+)
+
+// unexpectedPayments fails the test when a payment initiation is executed
+type unexpectedPayments struct {
+	t *testing.T
+}
+
+func (u unexpectedPayments) ExecuteCreditTransfers(context.Context, *application.PaymentInitiationInput) ([]*application.CreditTransferResult, error) {
+	u.t.Errorf("expected the payment initiation not to be executed")
+	return nil, nil
+}
+
+func TestPayments_ImportPain001TooLarge(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.POST("/payments/pain001", rest.NewPaymentHandlers(unexpectedPayments{t: t}).ImportPain001)
+
+	body := strings.Repeat(" ", int(rest.MAX_PAYMENT_FILE_SIZE)+1)
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "/payments/pain001", strings.NewReader(body)))
+	if recorder.Code != http.StatusRequestEntityTooLarge {
+		t.Errorf("expected an oversized payment initiation to be refused with %d, got %d", http.StatusRequestEntityTooLarge, recorder.Code)
+	}
+}
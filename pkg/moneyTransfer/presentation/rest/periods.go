@@ -0,0 +1,189 @@
+package rest
+
+import (
+	"log"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/application"
+	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/domain"
+	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/metrics"
+	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/presentation/middleware"
+	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/usecases"
+	"github.com/gin-gonic/gin"
+)
+
+// PeriodHandlers defines a contract the accounting periods rest presentation adheres to
+type PeriodHandlers interface {
+	ClosePeriod(c *gin.Context)
+	Periods(c *gin.Context)
+	Period(c *gin.Context)
+	RequestReopen(c *gin.Context)
+	ApproveReopen(c *gin.Context)
+	RejectReopen(c *gin.Context)
+	Adjustment(c *gin.Context)
+}
+
+// Periods sets up the accounting periods REST presentation layer with all it's dependencies
+type Periods struct {
+	Uc usecases.PeriodUsecases
+
+	// Lock serializes the money moved here with the other presentation layers
+	Lock sync.Locker
+
+	// Location is the business time zone the dates of periods are read in
+	Location *time.Location
+}
+
+// CheckPreconditions ensures a correct Periods struct is initialized
+func (p Periods) CheckPreconditions() {
+	if p.Uc == nil {
+		log.Panic("periods presentation layer has not initialized the business logic")
+	}
+
+	if p.Lock == nil {
+		log.Panic("periods presentation layer has not initialized the money movement lock")
+	}
+
+	if p.Location == nil {
+		log.Panic("periods presentation layer has not initialized the business time zone")
+	}
+}
+
+// NewPeriodHandlers initializes a new accounting periods endpoints handler
+func NewPeriodHandlers(uc usecases.PeriodUsecases, location *time.Location, lock sync.Locker) *Periods {
+	prd := &Periods{
+		Uc:       uc,
+		Lock:     lock,
+		Location: location,
+	}
+	prd.CheckPreconditions()
+	return prd
+}
+
+// ClosePeriod implements the end of day / end of month closing handler.
+// Without a date, the previous day or month is closed
+func (p Periods) ClosePeriod(c *gin.Context) {
+	var input application.ClosePeriodInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		bindingErrorResponse(c, err)
+		return
+	}
+
+	if input.Type == "" {
+		input.Type = domain.Day
+	}
+
+	now := time.Now().In(p.Location)
+	date := now.AddDate(0, 0, -1)
+	if input.Type == domain.Month {
+		date = now.AddDate(0, -1, 0)
+	}
+	if input.Date != "" {
+		var err error
+		date, err = time.ParseInLocation(dateLayout, input.Date, p.Location)
+		if err != nil {
+			jsonErrorResponse(c, http.StatusBadRequest, "Date should be formatted as YYYY-MM-DD")
+			return
+		}
+	}
+
+	period, err := p.Uc.ClosePeriod(c.Request.Context(), input.Type, date, middleware.Subject(c.Request.Context()))
+	if err != nil {
+		errorResponse(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"period": period})
+}
+
+// Periods implements the accounting periods listing handler
+func (p Periods) Periods(c *gin.Context) {
+	periods, err := p.Uc.Periods(c.Request.Context())
+	if err != nil {
+		errorResponse(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"periods": periods})
+}
+
+// Period implements a get accounting period endpoint handler
+func (p Periods) Period(c *gin.Context) {
+	period, err := p.Uc.Period(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		errorResponse(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"period": period})
+}
+
+// RequestReopen implements the handler asking for a closed period to be reopened
+func (p Periods) RequestReopen(c *gin.Context) {
+	var input application.ReopenPeriodInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		bindingErrorResponse(c, err)
+		return
+	}
+
+	period, err := p.Uc.RequestReopen(c.Request.Context(), c.Param("id"), middleware.Subject(c.Request.Context()), input.Reason)
+	if err != nil {
+		errorResponse(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"period": period})
+}
+
+// ApproveReopen implements the admin handler approving a period reopen request
+func (p Periods) ApproveReopen(c *gin.Context) {
+	period, err := p.Uc.ApproveReopen(c.Request.Context(), c.Param("id"), middleware.Subject(c.Request.Context()))
+	if err != nil {
+		errorResponse(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"period": period})
+}
+
+// RejectReopen implements the admin handler rejecting a period reopen request
+func (p Periods) RejectReopen(c *gin.Context) {
+	var input application.ReopenPeriodInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		bindingErrorResponse(c, err)
+		return
+	}
+
+	period, err := p.Uc.RejectReopen(c.Request.Context(), c.Param("id"), middleware.Subject(c.Request.Context()), input.Reason)
+	if err != nil {
+		errorResponse(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"period": period})
+}
+
+// Adjustment implements the manual journal adjustment handler
+func (p Periods) Adjustment(c *gin.Context) {
+	var input application.AdjustmentInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		bindingErrorResponse(c, err)
+		return
+	}
+
+	if err := metrics.LockContext(c.Request.Context(), p.Lock); err != nil {
+		errorResponse(c, err)
+		return
+	}
+	defer p.Lock.Unlock()
+
+	transaction, err := p.Uc.PostAdjustment(c.Request.Context(), input)
+	if err != nil {
+		errorResponse(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"transaction": transaction})
+}
@@ -0,0 +1,214 @@
+package rest
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/usecases"
+	"github.com/gin-gonic/gin"
+)
+
+var dateLayout = "2006-01-02"
+
+// ReportHandlers defines a contract the accounting reports rest presentation adheres to
+type ReportHandlers interface {
+	TrialBalance(c *gin.Context)
+	GeneralLedger(c *gin.Context)
+	LedgerCheck(c *gin.Context)
+}
+
+// Reports sets up the accounting reports REST presentation layer with all it's dependencies
+type Reports struct {
+	Uc usecases.ReportingUsecases
+
+	// Location is the business time zone plain dates are read in
+	Location *time.Location
+}
+
+// CheckPreconditions ensures a correct Reports struct is initialized
+func (r Reports) CheckPreconditions() {
+	if r.Uc == nil {
+		log.Panic("reports presentation layer has not initialized the business logic")
+	}
+
+	if r.Location == nil {
+		log.Panic("reports presentation layer has not initialized the business time zone")
+	}
+}
+
+// NewReportHandlers initializes a new accounting reports endpoints handler
+func NewReportHandlers(uc usecases.ReportingUsecases, location *time.Location) *Reports {
+	rpt := &Reports{
+		Uc:       uc,
+		Location: location,
+	}
+	rpt.CheckPreconditions()
+	return rpt
+}
+
+// parseDate reads an RFC3339 timestamp or a plain date, in the business time zone, from a query parameter.
+// A plain date is read as the end of that day when endOfDay is set
+func parseDate(c *gin.Context, param string, location *time.Location, fallback time.Time, endOfDay bool) (time.Time, error) {
+	value := c.Query(param)
+	if value == "" {
+		return fallback, nil
+	}
+
+	if date, err := time.Parse(time.RFC3339, value); err == nil {
+		return date, nil
+	}
+
+	date, err := time.ParseInLocation(dateLayout, value, location)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%s should be a date (YYYY-MM-DD) or an RFC3339 timestamp", param)
+	}
+	if endOfDay {
+		date = date.AddDate(0, 0, 1).Add(-time.Nanosecond)
+	}
+	return date, nil
+}
+
+// csvResponse writes rows as a downloadable CSV file
+func csvResponse(c *gin.Context, filename string, rows [][]string) {
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	if err := w.WriteAll(rows); err != nil {
+		jsonErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+	c.Data(http.StatusOK, "text/csv", buf.Bytes())
+}
+
+// TrialBalance implements the trial balance report handler
+func (r Reports) TrialBalance(c *gin.Context) {
+	asOf, err := parseDate(c, "as_of", r.Location, time.Now(), true)
+	if err != nil {
+		jsonErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	trialBalance, err := r.Uc.TrialBalance(c.Request.Context(), asOf)
+	if err != nil {
+		errorResponse(c, err)
+		return
+	}
+
+	if c.Query("format") != "csv" {
+		c.JSON(http.StatusOK, gin.H{"trial_balance": trialBalance})
+		return
+	}
+
+	rows := [][]string{{"account_number", "account_name", "gl_code", "header", "currency", "total_debit", "total_credit", "balance"}}
+	for _, line := range trialBalance.Accounts {
+		rows = append(rows, []string{
+			line.AccountNumber,
+			line.AccountName,
+			line.GLCode,
+			string(line.Header),
+			string(line.Currency),
+			line.TotalDebit.String(),
+			line.TotalCredit.String(),
+			line.Balance.String(),
+		})
+	}
+	for _, total := range trialBalance.Totals {
+		rows = append(rows, []string{
+			"TOTAL",
+			"",
+			"",
+			string(total.Header),
+			string(total.Currency),
+			total.TotalDebit.String(),
+			total.TotalCredit.String(),
+			"",
+		})
+	}
+
+	csvResponse(c, fmt.Sprintf("trial_balance_%s.csv", asOf.Format(dateLayout)), rows)
+}
+
+// GeneralLedger implements the general ledger report handler for a single account
+func (r Reports) GeneralLedger(c *gin.Context) {
+	accountID := c.Param("id")
+
+	now := time.Now()
+	to, err := parseDate(c, "to", r.Location, now, true)
+	if err != nil {
+		jsonErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	from, err := parseDate(c, "from", r.Location, to.AddDate(0, -1, 0), false)
+	if err != nil {
+		jsonErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	ledger, err := r.Uc.GeneralLedger(c.Request.Context(), accountID, from, to)
+	if err != nil {
+		errorResponse(c, err)
+		return
+	}
+
+	if c.Query("format") != "csv" {
+		c.JSON(http.StatusOK, gin.H{"general_ledger": ledger})
+		return
+	}
+
+	rows := [][]string{
+		{"effective_date", "transaction_id", "description", "dr_amount", "cr_amount", "running_balance"},
+		{from.Format(time.RFC3339), "", "Opening balance", "", "", ledger.OpeningBalance.String()},
+	}
+	for _, entry := range ledger.Entries {
+		rows = append(rows, []string{
+			entry.EffectiveDate.Format(time.RFC3339),
+			entry.TransactionID,
+			entry.Description,
+			entry.DebitAmount.String(),
+			entry.CreditAmount.String(),
+			entry.RunningBalance.String(),
+		})
+	}
+	rows = append(rows, []string{to.Format(time.RFC3339), "", "Closing balance", "", "", ledger.ClosingBalance.String()})
+
+	csvResponse(c, fmt.Sprintf("general_ledger_%s.csv", ledger.Account.Number), rows)
+}
+
+// LedgerCheck implements the ledger consistency check handler
+func (r Reports) LedgerCheck(c *gin.Context) {
+	check, err := r.Uc.LedgerCheck(c.Request.Context())
+	if err != nil {
+		errorResponse(c, err)
+		return
+	}
+
+	if c.Query("format") != "csv" {
+		c.JSON(http.StatusOK, gin.H{"ledger_check": check})
+		return
+	}
+
+	rows := [][]string{
+		{"checked_at", "total_debit", "total_credit", "difference", "balanced"},
+		{
+			check.CheckedAt.Format(time.RFC3339),
+			check.TotalDebit.String(),
+			check.TotalCredit.String(),
+			check.Difference.String(),
+			strconv.FormatBool(check.Balanced),
+		},
+	}
+	if len(check.UnbalancedTransactions) > 0 {
+		rows = append(rows, []string{"unbalanced_transaction_id"})
+	}
+	for _, transactionID := range check.UnbalancedTransactions {
+		rows = append(rows, []string{transactionID})
+	}
+
+	csvResponse(c, "ledger_check.csv", rows)
+}
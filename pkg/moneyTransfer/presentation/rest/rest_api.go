@@ -2,34 +2,40 @@ package rest
 
 import (
 	"encoding/json"
-	"fmt"
 	"io"
 	"log"
 	"net/http"
 	"net/url"
-	"os"
 	"strings"
 	"sync"
 
 	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/application"
+	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/config"
 	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/domain"
+	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/metrics"
 	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/usecases"
 	"github.com/gin-gonic/gin"
 )
 
-var mutex sync.Mutex
-
 // RestHandlers defines a contract the money transfer rest presentation adheres to
 type RestHandlers interface {
 	Authenticate(c *gin.Context)
 	CreateAccount(c *gin.Context)
 	Account(c *gin.Context)
 	Transfer(c *gin.Context)
+	ReverseTransfer(c *gin.Context)
 }
 
 // Rest sets up REST presentation layer with all it's dependencies
 type Rest struct {
-	Uc usecases.MoneyTransferUsecases
+	Uc   usecases.MoneyTransferUsecases
+	Auth config.Auth
+
+	// Client requests access tokens from the Auth0 tenant
+	Client *http.Client
+
+	// Lock serializes the money moved here with the other presentation layers
+	Lock sync.Locker
 }
 
 // CheckPreconditions ensures a correct Rest struct is initialized
@@ -37,19 +43,30 @@ func (r Rest) CheckPreconditions() {
 	if r.Uc == nil {
 		log.Panic("rest presentation layer has not initialized the business logic")
 	}
+
+	if r.Client == nil {
+		log.Panic("rest presentation layer has not initialized the auth client")
+	}
+
+	if r.Lock == nil {
+		log.Panic("rest presentation layer has not initialized the money movement lock")
+	}
 }
 
 // NewRestHandlers initializes a new Rest API endpoints handler
-func NewRestHandlers(uc usecases.MoneyTransferUsecases) *Rest {
+func NewRestHandlers(uc usecases.MoneyTransferUsecases, auth config.Auth, lock sync.Locker) *Rest {
 	rst := &Rest{
-		Uc: uc,
+		Uc:     uc,
+		Auth:   auth,
+		Client: http.DefaultClient,
+		Lock:   lock,
 	}
 	rst.CheckPreconditions()
 	return rst
 }
 
 func jsonErrorResponse(c *gin.Context, statusCode int, err string) {
-	c.JSON(statusCode, gin.H{"error": err})
+	c.JSON(statusCode, ErrorResponse{Error: err})
 }
 
 // CreateAccount is account creation handler
@@ -59,7 +76,7 @@ func (r Rest) CreateAccount(c *gin.Context) {
 	var accountCreationInput application.AccountCreationInput
 
 	if err = c.ShouldBindJSON(&accountCreationInput); err != nil {
-		jsonErrorResponse(c, http.StatusBadRequest, err.Error())
+		bindingErrorResponse(c, err)
 		return
 	}
 
@@ -70,10 +87,12 @@ func (r Rest) CreateAccount(c *gin.Context) {
 
 	go func() {
 		defer wg.Done()
-		mutex.Lock()
-		defer mutex.Unlock()
+		if err = metrics.LockContext(c.Request.Context(), r.Lock); err != nil {
+			return
+		}
+		defer r.Lock.Unlock()
 
-		account, err = r.Uc.CreateCustomerAccount(accountCreationInput)
+		account, err = r.Uc.CreateCustomerAccount(c.Request.Context(), accountCreationInput)
 		accountChan <- account
 	}()
 
@@ -88,7 +107,7 @@ func (r Rest) CreateAccount(c *gin.Context) {
 	}
 
 	if err != nil {
-		jsonErrorResponse(c, http.StatusBadRequest, err.Error())
+		errorResponse(c, err)
 		return
 	}
 
@@ -109,14 +128,14 @@ func (r Rest) Account(c *gin.Context) {
 	var wg sync.WaitGroup
 	wg.Add(1)
 	go func() {
-		account, err = r.Uc.Account(accountID)
+		account, err = r.Uc.Account(c.Request.Context(), accountID)
 		wg.Done()
 	}()
 
 	wg.Wait()
 
 	if err != nil {
-		jsonErrorResponse(c, http.StatusBadRequest, err.Error())
+		errorResponse(c, err)
 		return
 	}
 
@@ -132,7 +151,7 @@ func (r Rest) Transfer(c *gin.Context) {
 
 	var payload application.TransferPayload
 	if err := c.ShouldBindJSON(&payload); err != nil {
-		jsonErrorResponse(c, http.StatusBadRequest, err.Error())
+		bindingErrorResponse(c, err)
 		return
 	}
 
@@ -146,17 +165,25 @@ func (r Rest) Transfer(c *gin.Context) {
 	go func() {
 		defer wg.Done()
 
-		mutex.Lock()
-		defer mutex.Unlock()
-		sourceAccount, err = r.Uc.Account(payload.SourceAccountID)
-		destinationAccount, err = r.Uc.Account(payload.DestinationAccountID)
+		if err = metrics.LockContext(c.Request.Context(), r.Lock); err != nil {
+			return
+		}
+		defer r.Lock.Unlock()
+		sourceAccount, err = r.Uc.Account(c.Request.Context(), payload.SourceAccountID)
+		if err != nil {
+			return
+		}
+		destinationAccount, err = r.Uc.Account(c.Request.Context(), payload.DestinationAccountID)
+		if err != nil {
+			return
+		}
 
 		transferInput := application.TransferInput{
 			SourceAccount:      sourceAccount,
 			DestinationAccount: destinationAccount,
 			Amount:             payload.Amount,
 		}
-		transaction, err = r.Uc.Transfer(transferInput)
+		transaction, err = r.Uc.Transfer(c.Request.Context(), transferInput)
 
 		transactionChan <- transaction
 	}()
@@ -168,7 +195,7 @@ func (r Rest) Transfer(c *gin.Context) {
 	close(transactionChan)
 
 	if err != nil {
-		jsonErrorResponse(c, http.StatusBadRequest, err.Error())
+		errorResponse(c, err)
 		return
 	}
 
@@ -182,25 +209,49 @@ func (r Rest) Transfer(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"transaction": transaction})
 }
 
+// ReverseTransfer implements the handler offsetting a posted transfer
+func (r Rest) ReverseTransfer(c *gin.Context) {
+	var input application.ReversalInput
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&input); err != nil {
+			bindingErrorResponse(c, err)
+			return
+		}
+	}
+
+	if err := metrics.LockContext(c.Request.Context(), r.Lock); err != nil {
+		errorResponse(c, err)
+		return
+	}
+	transaction, err := r.Uc.ReverseTransfer(c.Request.Context(), c.Param("id"), input.Reason)
+	r.Lock.Unlock()
+	if err != nil {
+		errorResponse(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"transaction": transaction})
+}
+
 // Authenticate provides an authentication endpoint that returns an access token
 // to interact with the other APIs
 func (r Rest) Authenticate(c *gin.Context) {
 	params := url.Values{}
-	params.Add("grant_type", os.Getenv("AUTH0_GRANT_TYPE"))
-	params.Add("client_id", os.Getenv("AUTH0_CLIENT_ID"))
-	params.Add("client_secret", os.Getenv("AUTH0_CLIENT_SECRET"))
-	params.Add("audience", os.Getenv("AUTH0_AUDIENCE"))
+	params.Add("grant_type", r.Auth.GrantType)
+	params.Add("client_id", r.Auth.ClientID)
+	params.Add("client_secret", r.Auth.ClientSecret)
+	params.Add("audience", r.Auth.Audience)
 	payload := strings.NewReader(params.Encode())
 
-	URL := fmt.Sprintf("https://%s/oauth/token", os.Getenv("AUTH0_DOMAIN"))
-	req, err := http.NewRequest(http.MethodPost, URL, payload)
+	URL := r.Auth.IssuerURL() + "oauth/token"
+	req, err := http.NewRequestWithContext(c.Request.Context(), http.MethodPost, URL, payload)
 	if err != nil {
 		jsonErrorResponse(c, http.StatusInternalServerError, err.Error())
 		return
 	}
 
 	req.Header.Set("content-type", "application/x-www-form-urlencoded")
-	res, err := http.DefaultClient.Do(req)
+	res, err := r.Client.Do(req)
 	if err != nil {
 		jsonErrorResponse(c, http.StatusInternalServerError, err.Error())
 		return
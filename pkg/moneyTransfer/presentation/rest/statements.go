@@ -0,0 +1,89 @@
+package rest
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/infrastructure/statement"
+	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/usecases"
+	"github.com/gin-gonic/gin"
+)
+
+// StatementHandlers defines a contract the account statement rest presentation adheres to
+type StatementHandlers interface {
+	Statement(c *gin.Context)
+}
+
+// Statements sets up the account statement REST presentation layer with all it's dependencies
+type Statements struct {
+	Uc usecases.StatementUsecases
+
+	// Location is the business time zone plain dates are read in
+	Location *time.Location
+}
+
+// CheckPreconditions ensures a correct Statements struct is initialized
+func (s Statements) CheckPreconditions() {
+	if s.Uc == nil {
+		log.Panic("statements presentation layer has not initialized the business logic")
+	}
+
+	if s.Location == nil {
+		log.Panic("statements presentation layer has not initialized the business time zone")
+	}
+}
+
+// NewStatementHandlers initializes a new account statement endpoints handler
+func NewStatementHandlers(uc usecases.StatementUsecases, location *time.Location) *Statements {
+	stmt := &Statements{
+		Uc:       uc,
+		Location: location,
+	}
+	stmt.CheckPreconditions()
+	return stmt
+}
+
+// Statement implements the account statement handler. The statement is exported as
+// csv, ofx or camt053 according to the format query parameter and as JSON otherwise
+func (s Statements) Statement(c *gin.Context) {
+	to, err := parseDate(c, "to", s.Location, time.Now(), true)
+	if err != nil {
+		jsonErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	from, err := parseDate(c, "from", s.Location, to.AddDate(0, -1, 0), false)
+	if err != nil {
+		jsonErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	format := statement.Format(c.DefaultQuery("format", "json"))
+	switch format {
+	case "json", statement.CSV, statement.OFX, statement.Camt053:
+	default:
+		jsonErrorResponse(c, http.StatusBadRequest, fmt.Sprintf("%q is not a supported statement format", format))
+		return
+	}
+
+	stmt, err := s.Uc.Statement(c.Request.Context(), c.Param("id"), from, to)
+	if err != nil {
+		errorResponse(c, err)
+		return
+	}
+
+	if format == "json" {
+		c.JSON(http.StatusOK, gin.H{"statement": stmt})
+		return
+	}
+
+	content, contentType, err := statement.Render(format, stmt)
+	if err != nil {
+		jsonErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", statement.Filename(format, stmt)))
+	c.Data(http.StatusOK, contentType, content)
+}
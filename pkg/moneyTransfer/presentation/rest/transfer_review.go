@@ -0,0 +1,109 @@
+package rest
+
+import (
+	"log"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/application"
+	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/domain"
+	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/metrics"
+	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/presentation/middleware"
+	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/usecases"
+	"github.com/gin-gonic/gin"
+)
+
+// TransferReviewHandlers defines a contract the held transfers review rest presentation adheres to
+type TransferReviewHandlers interface {
+	HeldTransfers(c *gin.Context)
+	HeldTransfer(c *gin.Context)
+	ReleaseTransfer(c *gin.Context)
+	RejectTransfer(c *gin.Context)
+}
+
+// TransferReview sets up the held transfers review REST presentation layer with all it's dependencies
+type TransferReview struct {
+	Uc usecases.TransferReviewUsecases
+
+	// Lock serializes the money moved here with the other presentation layers
+	Lock sync.Locker
+}
+
+// CheckPreconditions ensures a correct TransferReview struct is initialized
+func (tr TransferReview) CheckPreconditions() {
+	if tr.Uc == nil {
+		log.Panic("transfer review presentation layer has not initialized the business logic")
+	}
+
+	if tr.Lock == nil {
+		log.Panic("transfer review presentation layer has not initialized the money movement lock")
+	}
+}
+
+// NewTransferReviewHandlers initializes a new held transfers review endpoints handler
+func NewTransferReviewHandlers(uc usecases.TransferReviewUsecases, lock sync.Locker) *TransferReview {
+	tr := &TransferReview{
+		Uc:   uc,
+		Lock: lock,
+	}
+	tr.CheckPreconditions()
+	return tr
+}
+
+// HeldTransfers implements the review queue handler, listing the held transfers with a status
+func (tr TransferReview) HeldTransfers(c *gin.Context) {
+	status := domain.HoldStatus(strings.ToUpper(c.Query("status")))
+	held, err := tr.Uc.HeldTransfers(c.Request.Context(), status)
+	if err != nil {
+		errorResponse(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"held_transfers": held})
+}
+
+// HeldTransfer implements a get held transfer endpoint handler
+func (tr TransferReview) HeldTransfer(c *gin.Context) {
+	held, err := tr.Uc.HeldTransfer(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		errorResponse(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"held_transfer": held})
+}
+
+// ReleaseTransfer implements the analyst handler posting a held transfer
+func (tr TransferReview) ReleaseTransfer(c *gin.Context) {
+	if err := metrics.LockContext(c.Request.Context(), tr.Lock); err != nil {
+		errorResponse(c, err)
+		return
+	}
+	defer tr.Lock.Unlock()
+
+	held, err := tr.Uc.ReleaseTransfer(c.Request.Context(), c.Param("id"), middleware.Subject(c.Request.Context()))
+	if err != nil {
+		errorResponse(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"held_transfer": held})
+}
+
+// RejectTransfer implements the analyst handler declining a held transfer
+func (tr TransferReview) RejectTransfer(c *gin.Context) {
+	var input application.ReviewInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		bindingErrorResponse(c, err)
+		return
+	}
+
+	held, err := tr.Uc.RejectTransfer(c.Request.Context(), c.Param("id"), middleware.Subject(c.Request.Context()), input.Reason)
+	if err != nil {
+		errorResponse(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"held_transfer": held})
+}
@@ -0,0 +1,164 @@
+package rest_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/application"
+	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/config"
+	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/domain"
+	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/presentation/rest"
+	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/presentation/validation"
+	"github.com/gin-gonic/gin"
+)
+
+type stubMoneyTransfer struct{}
+
+func (stubMoneyTransfer) CreateCustomerAccount(context.Context, application.AccountCreationInput) (*application.AccountInformationOutput, error) {
+	return &application.AccountInformationOutput{}, nil
+}
+
+func (stubMoneyTransfer) Account(ctx context.Context, accountID string) (*application.AccountInformationOutput, error) {
+	return &application.AccountInformationOutput{UUID: accountID}, nil
+}
+
+func (stubMoneyTransfer) Transfer(context.Context, application.TransferInput) (*domain.Transaction, error) {
+	return &domain.Transaction{}, nil
+}
+
+func (stubMoneyTransfer) ReverseTransfer(context.Context, string, string) (*domain.Transaction, error) {
+	return &domain.Transaction{}, nil
+}
+
+func newTestValidationRouter(t *testing.T) *gin.Engine {
+	if err := validation.RegisterValidators(); err != nil {
+		t.Fatalf("RegisterValidators() error = %v", err)
+	}
+
+	h := rest.NewRestHandlers(stubMoneyTransfer{}, config.Auth{}, &sync.Mutex{})
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.POST("/account", h.CreateAccount)
+	router.POST("/transfer", h.Transfer)
+	return router
+}
+
+func TestRequestValidation(t *testing.T) {
+	router := newTestValidationRouter(t)
+	sourceID := "0b0e4f5c-6f5e-4a4e-9b3a-4a1f2d3c4b5a"
+	destinationID := "9f1c2d3e-4b5a-4c6d-8e7f-0a1b2c3d4e5f"
+
+	tests := []struct {
+		name       string
+		path       string
+		body       string
+		wantStatus int
+		wantFields []string
+	}{
+		{
+			name:       "valid account",
+			path:       "/account",
+			body:       `{"CustomerName": "Jane", "Amount": "100.50", "Currency": "KSH", "Header": "DEPOSIT"}`,
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "account missing fields",
+			path:       "/account",
+			body:       `{"CustomerName": "Jane"}`,
+			wantStatus: http.StatusUnprocessableEntity,
+			wantFields: []string{"Amount", "Currency", "Header"},
+		},
+		{
+			name:       "account with unknown enums",
+			path:       "/account",
+			body:       `{"CustomerName": "Jane", "Amount": "100", "Currency": "EUR", "Header": "SAVINGS"}`,
+			wantStatus: http.StatusUnprocessableEntity,
+			wantFields: []string{"Currency", "Header"},
+		},
+		{
+			name:       "account under a system account header",
+			path:       "/account",
+			body:       `{"CustomerName": "Jane", "Amount": "100", "Currency": "KSH", "Header": "SUSPENSE"}`,
+			wantStatus: http.StatusUnprocessableEntity,
+			wantFields: []string{"Header"},
+		},
+		{
+			name:       "account with a negative deposit",
+			path:       "/account",
+			body:       `{"CustomerName": "Jane", "Amount": "-100", "Currency": "KSH", "Header": "DEPOSIT"}`,
+			wantStatus: http.StatusUnprocessableEntity,
+			wantFields: []string{"Amount"},
+		},
+		{
+			name:       "account deposit more precise than the currency",
+			path:       "/account",
+			body:       `{"CustomerName": "Jane", "Amount": "100.5", "Currency": "UGX", "Header": "DEPOSIT"}`,
+			wantStatus: http.StatusUnprocessableEntity,
+			wantFields: []string{"Amount"},
+		},
+		{
+			name:       "valid transfer",
+			path:       "/transfer",
+			body:       `{"SourceAccountID": "` + sourceID + `", "DestinationAccountID": "` + destinationID + `", "Amount": "10"}`,
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "transfer to the same account",
+			path:       "/transfer",
+			body:       `{"SourceAccountID": "` + sourceID + `", "DestinationAccountID": "` + sourceID + `", "Amount": "10"}`,
+			wantStatus: http.StatusUnprocessableEntity,
+			wantFields: []string{"DestinationAccountID"},
+		},
+		{
+			name:       "transfer without an amount",
+			path:       "/transfer",
+			body:       `{"SourceAccountID": "not-a-uuid", "DestinationAccountID": "` + destinationID + `"}`,
+			wantStatus: http.StatusUnprocessableEntity,
+			wantFields: []string{"Amount", "SourceAccountID"},
+		},
+		{
+			name:       "malformed body",
+			path:       "/transfer",
+			body:       `{"Amount":`,
+			wantStatus: http.StatusBadRequest,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := httptest.NewRecorder()
+			router.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, tt.path, strings.NewReader(tt.body)))
+			if recorder.Code != tt.wantStatus {
+				t.Errorf("status = %v, want %v: %s", recorder.Code, tt.wantStatus, recorder.Body.String())
+				return
+			}
+
+			if tt.wantFields == nil {
+				return
+			}
+
+			var response struct {
+				Fields map[string]string `json:"fields"`
+			}
+			if err := json.Unmarshal(recorder.Body.Bytes(), &response); err != nil {
+				t.Errorf("unable to decode response: %v", err)
+				return
+			}
+
+			var fields []string
+			for field := range response.Fields {
+				fields = append(fields, field)
+			}
+			sort.Strings(fields)
+			if !reflect.DeepEqual(fields, tt.wantFields) {
+				t.Errorf("fields = %v, want %v", response.Fields, tt.wantFields)
+			}
+		})
+	}
+}
@@ -0,0 +1,108 @@
+package rest
+
+import (
+	"log"
+	"net/http"
+
+	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/application"
+	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/domain"
+	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/presentation/middleware"
+	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/usecases"
+	"github.com/gin-gonic/gin"
+)
+
+// WebhookHandlers defines a contract the webhook subscriptions rest presentation adheres to
+type WebhookHandlers interface {
+	Subscribe(c *gin.Context)
+	Subscriptions(c *gin.Context)
+	Unsubscribe(c *gin.Context)
+	DeadLetters(c *gin.Context)
+	Redeliver(c *gin.Context)
+}
+
+// Webhooks sets up the webhook subscriptions REST presentation layer with all it's dependencies
+type Webhooks struct {
+	Uc usecases.WebhookUsecases
+}
+
+// CheckPreconditions ensures a correct Webhooks struct is initialized
+func (w Webhooks) CheckPreconditions() {
+	if w.Uc == nil {
+		log.Panic("webhooks presentation layer has not initialized the business logic")
+	}
+}
+
+// NewWebhookHandlers initializes a new webhook subscriptions endpoints handler
+func NewWebhookHandlers(uc usecases.WebhookUsecases) *Webhooks {
+	w := &Webhooks{
+		Uc: uc,
+	}
+	w.CheckPreconditions()
+	return w
+}
+
+// Subscribe implements the webhook subscription creation handler. Only admins can subscribe to the events
+// of every account
+func (w Webhooks) Subscribe(c *gin.Context) {
+	var input application.WebhookSubscriptionInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		bindingErrorResponse(c, err)
+		return
+	}
+
+	if len(input.AccountIDs) == 0 && !middleware.HasScope(c.Request.Context(), middleware.ADMIN_SCOPE) {
+		errorResponse(c, domain.NewForbiddenError("missing_account_ids", "the accounts whose events are delivered should be named"))
+		return
+	}
+
+	subscription, err := w.Uc.Subscribe(c.Request.Context(), middleware.Subject(c.Request.Context()), input)
+	if err != nil {
+		errorResponse(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, subscription)
+}
+
+// Subscriptions implements the webhook subscriptions listing handler
+func (w Webhooks) Subscriptions(c *gin.Context) {
+	subscriptions, err := w.Uc.Subscriptions(c.Request.Context(), middleware.Subject(c.Request.Context()))
+	if err != nil {
+		errorResponse(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"subscriptions": subscriptions})
+}
+
+// Unsubscribe implements the webhook subscription deletion handler
+func (w Webhooks) Unsubscribe(c *gin.Context) {
+	if err := w.Uc.Unsubscribe(c.Request.Context(), middleware.Subject(c.Request.Context()), c.Param("id")); err != nil {
+		errorResponse(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// DeadLetters implements the handler listing a subscription's failed deliveries
+func (w Webhooks) DeadLetters(c *gin.Context) {
+	deliveries, err := w.Uc.DeadLetters(c.Request.Context(), middleware.Subject(c.Request.Context()), c.Param("id"))
+	if err != nil {
+		errorResponse(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"deliveries": deliveries})
+}
+
+// Redeliver implements the handler queueing a delivery for another attempt
+func (w Webhooks) Redeliver(c *gin.Context) {
+	delivery, err := w.Uc.Redeliver(c.Request.Context(), middleware.Subject(c.Request.Context()), c.Param("id"))
+	if err != nil {
+		errorResponse(c, err)
+		return
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{"delivery": delivery})
+}
@@ -0,0 +1,491 @@
+package presentation
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/application"
+	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/domain"
+	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/presentation/graph"
+	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/presentation/middleware"
+	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/presentation/openapi"
+	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/presentation/rest"
+	"github.com/gin-gonic/gin"
+)
+
+// API_BASE_PATH is the path every versioned API route is served under
+var API_BASE_PATH = "/api/v1"
+
+// METRICS_PATH is where Prometheus scrapes the service's metrics
+var METRICS_PATH = "/metrics"
+
+// Paths of the probes and build information, served outside the API for the orchestrator and load balancer
+var (
+	LIVENESS_PATH   = "/healthz"
+	READINESS_PATH  = "/readyz"
+	BUILD_INFO_PATH = "/version"
+)
+
+// DEFAULT_REQUEST_TIMEOUT bounds how long serving a route may take, unless the route sets its own timeout
+var DEFAULT_REQUEST_TIMEOUT = 10 * time.Second
+
+// REPORT_REQUEST_TIMEOUT bounds the routes reading or writing a whole ledger or account history
+var REPORT_REQUEST_TIMEOUT = time.Minute
+
+// API_INFO describes the API in its OpenAPI specification
+var API_INFO = openapi.Info{
+	Title:       "Simple Money Transfer API",
+	Description: "Accounts, transfers and the ledger reports behind them.",
+	Version:     "1.0.0",
+}
+
+// Handlers groups the handlers serving the API's routes
+type Handlers struct {
+	Rest       *rest.Rest
+	Reports    *rest.Reports
+	Periods    *rest.Periods
+	Statements *rest.Statements
+	Payments   *rest.Payments
+	Audit      *rest.Audit
+	Review     *rest.TransferReview
+
+	// Webhooks and GraphQL are optional, their routes are left out when they are disabled
+	Webhooks *rest.Webhooks
+	GraphQL  *graph.Handler
+
+	// Authenticated runs before every route that is not public
+	Authenticated []gin.HandlerFunc
+
+	// RateLimits throttle the routes they are keyed by, such as "POST /api/v1/transfers", once the caller
+	// is authenticated
+	RateLimits map[string]gin.HandlerFunc
+
+	// Audited runs on the routes that are not public once the caller is within their rate limits,
+	// throttled requests are refused before they reach the audit log
+	Audited []gin.HandlerFunc
+}
+
+// RegisterRoutes serves the API's routes along with their OpenAPI specification and a Swagger UI exploring it
+func RegisterRoutes(router *gin.Engine, h Handlers) *openapi.Document {
+	document := &openapi.Document{}
+	routes := apiRoutes(h)
+	if h.Webhooks != nil {
+		routes = append(routes, webhookRoutes(h)...)
+	}
+	if h.GraphQL != nil {
+		routes = append(routes, graphQLRoutes(h)...)
+	}
+	routes = append(routes, []openapi.Route{
+		{
+			Method:   http.MethodGet,
+			Path:     "/openapi.json",
+			Handler:  openapi.SpecHandler(document),
+			Name:     "openAPISpecification",
+			Summary:  "This OpenAPI specification",
+			Tag:      "documentation",
+			Public:   true,
+			Response: map[string]interface{}{},
+		},
+		{
+			Method:   http.MethodGet,
+			Path:     "/docs",
+			Handler:  openapi.SwaggerUIHandler(API_BASE_PATH + "/openapi.json"),
+			Name:     "swaggerUI",
+			Summary:  "Swagger UI exploring this specification",
+			Tag:      "documentation",
+			Public:   true,
+			Produces: []string{"text/html"},
+		},
+	}...)
+
+	*document = *openapi.Build(
+		API_INFO,
+		API_BASE_PATH,
+		routes,
+		rest.ErrorResponse{},
+		openapi.EnumOf(domain.CURRENCIES),
+		openapi.EnumOf(domain.HEADERS),
+	)
+
+	v1 := router.Group(API_BASE_PATH)
+	for _, route := range routes {
+		timeout := route.Timeout
+		if timeout == 0 {
+			timeout = DEFAULT_REQUEST_TIMEOUT
+		}
+		handlers := []gin.HandlerFunc{middleware.Timeout(timeout)}
+		if !route.Public {
+			handlers = append(handlers, h.Authenticated...)
+		}
+		if limit, ok := h.RateLimits[route.Method+" "+API_BASE_PATH+route.Path]; ok {
+			handlers = append(handlers, limit)
+		}
+		if !route.Public {
+			handlers = append(handlers, h.Audited...)
+		}
+		if route.Admin {
+			handlers = append(handlers, middleware.RequireScope(middleware.ADMIN_SCOPE))
+		}
+		v1.Handle(route.Method, route.Path, append(handlers, route.Handler)...)
+	}
+
+	return document
+}
+
+// apiRoutes lists the API's routes with the documentation of the operations they serve
+func apiRoutes(h Handlers) []openapi.Route {
+	dateRange := []openapi.Parameter{
+		openapi.QueryParam("from", "Start date, YYYY-MM-DD or RFC3339"),
+		openapi.QueryParam("to", "End date, YYYY-MM-DD or RFC3339"),
+	}
+	csvFormat := openapi.QueryParam("format", "csv to download the report as a CSV file")
+
+	return []openapi.Route{
+		{
+			Method:      http.MethodPost,
+			Path:        "/access_token",
+			Handler:     h.Rest.Authenticate,
+			Name:        "accessToken",
+			Summary:     "Get an access token for the other operations",
+			Tag:         "authentication",
+			Public:      true,
+			Response:    application.AccessToken{},
+			ResponseKey: "response",
+		},
+		{
+			Method:      http.MethodGet,
+			Path:        "/account/:id",
+			Handler:     h.Rest.Account,
+			Name:        "getAccount",
+			Summary:     "Get an account with its balance",
+			Tag:         "accounts",
+			Response:    application.AccountInformationOutput{},
+			ResponseKey: "account",
+		},
+		{
+			Method:      http.MethodGet,
+			Path:        "/account/:id/statement",
+			Handler:     h.Statements.Statement,
+			Name:        "getStatement",
+			Summary:     "Export an account statement",
+			Tag:         "accounts",
+			Timeout:     REPORT_REQUEST_TIMEOUT,
+			Query:       append(dateRange, openapi.QueryParam("format", "json, csv, ofx or camt053")),
+			Response:    application.Statement{},
+			ResponseKey: "statement",
+			Produces:    []string{"text/csv", "application/x-ofx", "application/xml"},
+		},
+		{
+			Method:      http.MethodPost,
+			Path:        "/account",
+			Handler:     h.Rest.CreateAccount,
+			Name:        "createAccount",
+			Summary:     "Open a customer account with an initial deposit",
+			Tag:         "accounts",
+			Request:     application.AccountCreationInput{},
+			Response:    application.AccountInformationOutput{},
+			ResponseKey: "account",
+		},
+		{
+			Method:      http.MethodPost,
+			Path:        "/transfers",
+			Handler:     h.Rest.Transfer,
+			Name:        "transfer",
+			Summary:     "Transfer money between accounts, a transfer held for fraud review is answered with a 202",
+			Tag:         "transfers",
+			Request:     application.TransferPayload{},
+			Response:    domain.Transaction{},
+			ResponseKey: "transaction",
+			Accepted:    rest.HeldTransferResponse{},
+		},
+		{
+			Method:             http.MethodPost,
+			Path:               "/payments/pain001",
+			Handler:            h.Payments.ImportPain001,
+			Name:               "importPain001",
+			Summary:            "Execute the credit transfers of an ISO 20022 pain.001 message",
+			Tag:                "payments",
+			Timeout:            REPORT_REQUEST_TIMEOUT,
+			RequestContentType: "application/xml",
+			Produces:           []string{"application/xml"},
+		},
+		{
+			Method:      http.MethodGet,
+			Path:        "/reports/trial_balance",
+			Handler:     h.Reports.TrialBalance,
+			Name:        "trialBalance",
+			Summary:     "Trial balance as of a date",
+			Tag:         "reports",
+			Timeout:     REPORT_REQUEST_TIMEOUT,
+			Query:       []openapi.Parameter{openapi.QueryParam("as_of", "Date, YYYY-MM-DD or RFC3339"), csvFormat},
+			Response:    application.TrialBalance{},
+			ResponseKey: "trial_balance",
+			Produces:    []string{"text/csv"},
+		},
+		{
+			Method:      http.MethodGet,
+			Path:        "/reports/general_ledger/:id",
+			Handler:     h.Reports.GeneralLedger,
+			Name:        "generalLedger",
+			Summary:     "General ledger of an account",
+			Tag:         "reports",
+			Timeout:     REPORT_REQUEST_TIMEOUT,
+			Query:       append(dateRange, csvFormat),
+			Response:    application.GeneralLedger{},
+			ResponseKey: "general_ledger",
+			Produces:    []string{"text/csv"},
+		},
+		{
+			Method:      http.MethodGet,
+			Path:        "/reports/ledger_check",
+			Handler:     h.Reports.LedgerCheck,
+			Name:        "ledgerCheck",
+			Summary:     "Check that the ledger balances",
+			Tag:         "reports",
+			Timeout:     REPORT_REQUEST_TIMEOUT,
+			Query:       []openapi.Parameter{csvFormat},
+			Response:    application.LedgerCheck{},
+			ResponseKey: "ledger_check",
+			Produces:    []string{"text/csv"},
+		},
+		{
+			Method:      http.MethodGet,
+			Path:        "/periods",
+			Handler:     h.Periods.Periods,
+			Name:        "listPeriods",
+			Summary:     "List accounting periods",
+			Tag:         "periods",
+			Response:    []*domain.AccountingPeriod{},
+			ResponseKey: "periods",
+		},
+		{
+			Method:      http.MethodGet,
+			Path:        "/periods/:id",
+			Handler:     h.Periods.Period,
+			Name:        "getPeriod",
+			Summary:     "Get an accounting period with its closing balances and audit trail",
+			Tag:         "periods",
+			Response:    application.PeriodOutput{},
+			ResponseKey: "period",
+		},
+		{
+			Method:      http.MethodPost,
+			Path:        "/periods/:id/reopen",
+			Handler:     h.Periods.RequestReopen,
+			Name:        "requestPeriodReopen",
+			Summary:     "Request that a closed period is reopened",
+			Tag:         "periods",
+			Request:     application.ReopenPeriodInput{},
+			Response:    domain.AccountingPeriod{},
+			ResponseKey: "period",
+		},
+		{
+			Method:      http.MethodPost,
+			Path:        "/periods/close",
+			Handler:     h.Periods.ClosePeriod,
+			Name:        "closePeriod",
+			Summary:     "Close a business day or month",
+			Tag:         "periods",
+			Timeout:     REPORT_REQUEST_TIMEOUT,
+			Admin:       true,
+			Request:     application.ClosePeriodInput{},
+			Response:    domain.AccountingPeriod{},
+			ResponseKey: "period",
+		},
+		{
+			Method:      http.MethodPost,
+			Path:        "/periods/:id/reopen/approve",
+			Handler:     h.Periods.ApproveReopen,
+			Name:        "approvePeriodReopen",
+			Summary:     "Approve a request to reopen a period",
+			Tag:         "periods",
+			Admin:       true,
+			Response:    domain.AccountingPeriod{},
+			ResponseKey: "period",
+		},
+		{
+			Method:      http.MethodPost,
+			Path:        "/periods/:id/reopen/reject",
+			Handler:     h.Periods.RejectReopen,
+			Name:        "rejectPeriodReopen",
+			Summary:     "Reject a request to reopen a period",
+			Tag:         "periods",
+			Admin:       true,
+			Request:     application.ReopenPeriodInput{},
+			Response:    domain.AccountingPeriod{},
+			ResponseKey: "period",
+		},
+		{
+			Method:      http.MethodPost,
+			Path:        "/adjustments",
+			Handler:     h.Periods.Adjustment,
+			Name:        "postAdjustment",
+			Summary:     "Post a manual journal adjustment",
+			Tag:         "periods",
+			Admin:       true,
+			Request:     application.AdjustmentInput{},
+			Response:    domain.Transaction{},
+			ResponseKey: "transaction",
+		},
+		{
+			Method:      http.MethodPost,
+			Path:        "/transfers/:id/reverse",
+			Handler:     h.Rest.ReverseTransfer,
+			Name:        "reverseTransfer",
+			Summary:     "Reverse a posted transfer",
+			Tag:         "transfers",
+			Admin:       true,
+			Request:     application.ReversalInput{},
+			Response:    domain.Transaction{},
+			ResponseKey: "transaction",
+		},
+		{
+			Method:      http.MethodGet,
+			Path:        "/held_transfers",
+			Handler:     h.Review.HeldTransfers,
+			Name:        "listHeldTransfers",
+			Summary:     "List the transfers held for fraud review, oldest first",
+			Tag:         "fraud",
+			Admin:       true,
+			Query:       []openapi.Parameter{openapi.QueryParam("status", "pending (the default), released or rejected")},
+			Response:    []*domain.HeldTransfer{},
+			ResponseKey: "held_transfers",
+		},
+		{
+			Method:      http.MethodGet,
+			Path:        "/held_transfers/:id",
+			Handler:     h.Review.HeldTransfer,
+			Name:        "getHeldTransfer",
+			Summary:     "Get a held transfer with the rules that flagged it",
+			Tag:         "fraud",
+			Admin:       true,
+			Response:    domain.HeldTransfer{},
+			ResponseKey: "held_transfer",
+		},
+		{
+			Method:      http.MethodPost,
+			Path:        "/held_transfers/:id/release",
+			Handler:     h.Review.ReleaseTransfer,
+			Name:        "releaseHeldTransfer",
+			Summary:     "Release a held transfer, posting it",
+			Tag:         "fraud",
+			Admin:       true,
+			Response:    domain.HeldTransfer{},
+			ResponseKey: "held_transfer",
+		},
+		{
+			Method:      http.MethodPost,
+			Path:        "/held_transfers/:id/reject",
+			Handler:     h.Review.RejectTransfer,
+			Name:        "rejectHeldTransfer",
+			Summary:     "Reject a held transfer",
+			Tag:         "fraud",
+			Admin:       true,
+			Request:     application.ReviewInput{},
+			Response:    domain.HeldTransfer{},
+			ResponseKey: "held_transfer",
+		},
+		{
+			Method:  http.MethodGet,
+			Path:    "/audit",
+			Handler: h.Audit.AuditTrail,
+			Name:    "auditTrail",
+			Summary: "Query the audit log",
+			Tag:     "audit",
+			Admin:   true,
+			Query: append(dateRange,
+				openapi.QueryParam("actor", "Subject of the access token that made the call"),
+				openapi.QueryParam("route", "Route template that was called"),
+				openapi.QueryParam("resource", "ID of a resource the call touched"),
+				openapi.QueryParam("limit", "Maximum number of records"),
+			),
+			Response:    []*domain.AuditRecord{},
+			ResponseKey: "records",
+		},
+		{
+			Method:      http.MethodGet,
+			Path:        "/audit/verify",
+			Handler:     h.Audit.VerifyAuditChain,
+			Name:        "verifyAuditLog",
+			Summary:     "Verify the audit log's hash chain",
+			Tag:         "audit",
+			Timeout:     REPORT_REQUEST_TIMEOUT,
+			Admin:       true,
+			Response:    application.AuditVerification{},
+			ResponseKey: "verification",
+		},
+	}
+}
+
+// webhookRoutes lists the routes managing webhook subscriptions and their deliveries
+func webhookRoutes(h Handlers) []openapi.Route {
+	return []openapi.Route{
+		{
+			Method:   http.MethodPost,
+			Path:     "/webhooks",
+			Handler:  h.Webhooks.Subscribe,
+			Name:     "subscribeWebhook",
+			Summary:  "Subscribe an endpoint to the events about accounts, only admins can leave the accounts out",
+			Tag:      "webhooks",
+			Request:  application.WebhookSubscriptionInput{},
+			Status:   http.StatusCreated,
+			Response: application.WebhookSubscriptionOutput{},
+		},
+		{
+			Method:      http.MethodGet,
+			Path:        "/webhooks",
+			Handler:     h.Webhooks.Subscriptions,
+			Name:        "listWebhooks",
+			Summary:     "List your webhook subscriptions",
+			Tag:         "webhooks",
+			Response:    []*domain.WebhookSubscription{},
+			ResponseKey: "subscriptions",
+		},
+		{
+			Method:  http.MethodDelete,
+			Path:    "/webhooks/:id",
+			Handler: h.Webhooks.Unsubscribe,
+			Name:    "unsubscribeWebhook",
+			Summary: "Delete a webhook subscription",
+			Tag:     "webhooks",
+			Status:  http.StatusNoContent,
+		},
+		{
+			Method:      http.MethodGet,
+			Path:        "/webhooks/:id/dead_letters",
+			Handler:     h.Webhooks.DeadLetters,
+			Name:        "listDeadLetters",
+			Summary:     "List deliveries that exhausted their retries",
+			Tag:         "webhooks",
+			Response:    []*domain.WebhookDelivery{},
+			ResponseKey: "deliveries",
+		},
+		{
+			Method:      http.MethodPost,
+			Path:        "/webhooks/deliveries/:id/redeliver",
+			Handler:     h.Webhooks.Redeliver,
+			Name:        "redeliverWebhook",
+			Summary:     "Queue a webhook delivery for another attempt",
+			Tag:         "webhooks",
+			Status:      http.StatusAccepted,
+			Response:    domain.WebhookDelivery{},
+			ResponseKey: "delivery",
+		},
+	}
+}
+
+// graphQLRoutes lists the route serving the GraphQL API
+func graphQLRoutes(h Handlers) []openapi.Route {
+	return []openapi.Route{
+		{
+			Method:   http.MethodPost,
+			Path:     "/graphql",
+			Handler:  h.GraphQL.Serve,
+			Name:     "graphQL",
+			Summary:  "Query accounts, transactions and entries or transfer money with GraphQL",
+			Tag:      "graphql",
+			Request:  graph.Request{},
+			Response: map[string]interface{}{},
+		},
+	}
+}
@@ -0,0 +1,147 @@
+package presentation_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/presentation"
+	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/presentation/graph"
+	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/presentation/openapi"
+	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/presentation/rest"
+	"github.com/gin-gonic/gin"
+)
+
+func newTestRouter() *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	presentation.RegisterRoutes(router, presentation.Handlers{
+		Rest:       &rest.Rest{},
+		Reports:    &rest.Reports{},
+		Periods:    &rest.Periods{},
+		Statements: &rest.Statements{},
+		Payments:   &rest.Payments{},
+		Webhooks:   &rest.Webhooks{},
+		Audit:      &rest.Audit{},
+		Review:     &rest.TransferReview{},
+		GraphQL:    &graph.Handler{},
+	})
+	return router
+}
+
+func servedSpec(t *testing.T, router *gin.Engine) *openapi.Document {
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, presentation.API_BASE_PATH+"/openapi.json", nil))
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("expected the specification to be served, got %v", recorder.Code)
+	}
+
+	var document openapi.Document
+	if err := json.Unmarshal(recorder.Body.Bytes(), &document); err != nil {
+		t.Fatalf("unable to decode the specification: %v", err)
+	}
+	return &document
+}
+
+func TestRegisterRoutes_EveryRouteIsDocumented(t *testing.T) {
+	router := newTestRouter()
+	document := servedSpec(t, router)
+
+	routes := router.Routes()
+	if len(routes) == 0 {
+		t.Fatalf("expected routes to be registered")
+	}
+
+	documented := 0
+	for _, item := range document.Paths {
+		documented += len(item)
+	}
+	if documented != len(routes) {
+		t.Errorf("expected %d documented operations, got %d", len(routes), documented)
+	}
+
+	for _, route := range routes {
+		path, _ := openapi.Path(strings.TrimPrefix(route.Path, presentation.API_BASE_PATH))
+		operation := document.Paths[path][strings.ToLower(route.Method)]
+		if operation == nil {
+			t.Errorf("%s %s is missing from the OpenAPI specification", route.Method, route.Path)
+			continue
+		}
+		if operation.OperationID == "" || len(operation.Responses) == 0 {
+			t.Errorf("%s %s is documented without an operation ID or responses", route.Method, route.Path)
+		}
+	}
+}
+
+func TestRegisterRoutes_SchemasFromDTOs(t *testing.T) {
+	document := servedSpec(t, newTestRouter())
+
+	createAccount := document.Paths["/account"]["post"]
+	if createAccount == nil || createAccount.RequestBody == nil {
+		t.Fatalf("expected account creation to document its request body")
+	}
+	if ref := createAccount.RequestBody.Content["application/json"].Schema.Ref; ref != "#/components/schemas/AccountCreationInput" {
+		t.Errorf("expected the request body to refer to the account creation input, got %q", ref)
+	}
+
+	input := document.Components.Schemas["AccountCreationInput"]
+	if input == nil {
+		t.Fatalf("expected the account creation input schema")
+	}
+	if want := []string{"CustomerName", "Amount", "Currency", "Header"}; !reflect.DeepEqual(input.Required, want) {
+		t.Errorf("required = %v, want %v", input.Required, want)
+	}
+	if input.Properties["Amount"].Format != "decimal" {
+		t.Errorf("expected amounts to be documented as decimal strings")
+	}
+	if len(input.Properties["Currency"].Enum) == 0 {
+		t.Errorf("expected currencies to be documented as an enum")
+	}
+
+	account := document.Components.Schemas["AccountInformationOutput"]
+	if account == nil || account.Properties["Balance"] == nil {
+		t.Errorf("expected the account output schema to describe the balance")
+	}
+
+	transfer := document.Paths["/transfers"]["post"]
+	if transfer == nil || len(transfer.Security) == 0 {
+		t.Errorf("expected transfers to require an access token")
+	}
+	if held, ok := transfer.Responses["202"]; !ok || held.Content["application/json"].Schema.Ref != "#/components/schemas/HeldTransferResponse" {
+		t.Errorf("expected transfers to document the body answering a held transfer, got %+v", transfer.Responses)
+	}
+
+	if accessToken := document.Paths["/access_token"]["post"]; accessToken == nil || len(accessToken.Security) != 0 {
+		t.Errorf("expected the access token operation to be public")
+	}
+}
+
+func TestRegisterRoutes_DisabledFeatures(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	presentation.RegisterRoutes(router, presentation.Handlers{
+		Rest:       &rest.Rest{},
+		Reports:    &rest.Reports{},
+		Periods:    &rest.Periods{},
+		Statements: &rest.Statements{},
+		Payments:   &rest.Payments{},
+		Audit:      &rest.Audit{},
+		Review:     &rest.TransferReview{},
+	})
+	document := servedSpec(t, router)
+
+	for _, route := range router.Routes() {
+		if strings.Contains(route.Path, "/webhooks") || strings.HasSuffix(route.Path, "/graphql") {
+			t.Errorf("expected %s %s to be left out with its feature disabled", route.Method, route.Path)
+		}
+	}
+	if document.Paths["/webhooks"] != nil || document.Paths["/graphql"] != nil {
+		t.Errorf("expected the disabled features to be left out of the specification")
+	}
+	if document.Paths["/transfers"] == nil {
+		t.Errorf("expected the core routes to be served")
+	}
+}
@@ -0,0 +1,85 @@
+package rpc
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"net"
+	"time"
+
+	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/domain"
+	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/logging"
+	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/presentation/middleware"
+	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/presentation/rpc/pb"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+// AUDITED_METHODS are the methods changing state, their calls are recorded in the audit log
+var AUDITED_METHODS = map[string]bool{
+	pb.MoneyTransfer_CreateAccount_FullMethodName: true,
+	pb.MoneyTransfer_Transfer_FullMethodName:      true,
+}
+
+// clientIP is the address of the peer a call was received from
+func clientIP(ctx context.Context) string {
+	p, ok := peer.FromContext(ctx)
+	if !ok || p.Addr == nil {
+		return ""
+	}
+	host, _, err := net.SplitHostPort(p.Addr.String())
+	if err != nil {
+		return p.Addr.String()
+	}
+	return host
+}
+
+// UnaryAuditInterceptor records who made each state changing call, what it carried and how it ended, in the
+// same audit log as the REST requests. The payload is stored as a SHA-256 hash of its protobuf encoding
+func UnaryAuditInterceptor(recorder middleware.AuditRecorder) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if !AUDITED_METHODS[info.FullMethod] {
+			return handler(ctx, req)
+		}
+
+		var payload []byte
+		if message, ok := req.(proto.Message); ok {
+			payload, _ = proto.MarshalOptions{Deterministic: true}.Marshal(message)
+		}
+		payloadHash := sha256.Sum256(payload)
+		occurredAt := time.Now()
+
+		resp, err := handler(ctx, req)
+
+		var response []byte
+		if message, ok := resp.(proto.Message); ok && err == nil {
+			response, _ = protojson.Marshal(message)
+		}
+		statusCode := HTTPStatus(status.Code(err))
+		record := domain.AuditRecord{
+			Actor:       middleware.Subject(ctx),
+			ClientIP:    clientIP(ctx),
+			Method:      CALL_METHOD,
+			Route:       info.FullMethod,
+			Path:        info.FullMethod,
+			PayloadHash: hex.EncodeToString(payloadHash[:]),
+			ResourceIDs: middleware.ResourceIDs(response),
+			StatusCode:  statusCode,
+			Outcome:     domain.OutcomeOf(statusCode),
+			OccurredAt:  occurredAt,
+		}
+		// Calls that timed out or were abandoned by the client are audited too
+		if recordErr := recorder.Record(middleware.Detach(ctx), &record); recordErr != nil {
+			logging.FromContext(ctx).Error("unable to record the audit of a call",
+				zap.String("method", info.FullMethod),
+				zap.String("actor", record.Actor),
+				zap.Error(recordErr),
+			)
+		}
+		return resp, err
+	}
+}
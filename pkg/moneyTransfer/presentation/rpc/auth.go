@@ -0,0 +1,56 @@
+package rpc
+
+import (
+	"context"
+	"strings"
+
+	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/presentation/middleware"
+	"github.com/auth0/go-jwt-middleware/v2/validator"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// TokenValidator validates an access token, returning its claims
+type TokenValidator interface {
+	ValidateToken(ctx context.Context, token string) (interface{}, error)
+}
+
+// UnaryAuthInterceptor rejects calls without a valid bearer access token in their "authorization" metadata.
+// The token's claims are available to handlers the same way they are to REST handlers
+func UnaryAuthInterceptor(tokens TokenValidator) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		claims, err := authenticate(ctx, tokens)
+		if err != nil {
+			return nil, err
+		}
+
+		return handler(middleware.WithValidatedClaims(ctx, claims), req)
+	}
+}
+
+// authenticate validates the bearer access token a call was made with
+func authenticate(ctx context.Context, tokens TokenValidator) (*validator.ValidatedClaims, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	authorization := md.Get("authorization")
+	if len(authorization) == 0 {
+		return nil, status.Error(codes.Unauthenticated, "an access token is required")
+	}
+
+	token := strings.TrimPrefix(authorization[0], "Bearer ")
+	if token == authorization[0] || token == "" {
+		return nil, status.Error(codes.Unauthenticated, "the access token should be sent as a bearer token")
+	}
+
+	validated, err := tokens.ValidateToken(ctx, token)
+	if err != nil {
+		return nil, status.Error(codes.Unauthenticated, "the access token is not valid")
+	}
+
+	claims, ok := validated.(*validator.ValidatedClaims)
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "the access token is not valid")
+	}
+	return claims, nil
+}
@@ -0,0 +1,61 @@
+package rpc
+
+import (
+	"context"
+
+	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/domain"
+	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/logging"
+	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/presentation/validation"
+	"go.uber.org/zap"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ERROR_DOMAIN qualifies the error codes attached to failed calls
+var ERROR_DOMAIN = "simplemoneytransfer"
+
+// ERROR_STATUS_CODES maps each kind of domain error to the gRPC status code reporting it.
+// A held transfer is not a failure, Transfer answers it with the held transfer
+var ERROR_STATUS_CODES = map[domain.ErrorKind]codes.Code{
+	domain.NotFound:          codes.NotFound,
+	domain.InsufficientFunds: codes.FailedPrecondition,
+	domain.Validation:        codes.InvalidArgument,
+	domain.Conflict:          codes.Aborted,
+	domain.Forbidden:         codes.PermissionDenied,
+	domain.Internal:          codes.Internal,
+}
+
+// statusError reports a failure as a gRPC status carrying the error's stable code.
+// Failed validations carry a violation per field, internal errors are logged and reported without their cause
+func statusError(ctx context.Context, err error) error {
+	if fields, ok := validation.FieldErrors(err); ok {
+		violations := errdetails.BadRequest{}
+		for field, description := range fields {
+			violations.FieldViolations = append(violations.FieldViolations, &errdetails.BadRequest_FieldViolation{
+				Field:       field,
+				Description: description,
+			})
+		}
+		st := status.New(codes.InvalidArgument, "the request has invalid fields")
+		if withViolations, err := st.WithDetails(&violations); err == nil {
+			st = withViolations
+		}
+		return st.Err()
+	}
+
+	typed := domain.AsError(err)
+	if typed.Kind == domain.Internal {
+		logging.FromContext(ctx).Error("gRPC call failed", zap.Error(err))
+	}
+
+	code, ok := ERROR_STATUS_CODES[typed.Kind]
+	if !ok {
+		code = codes.Internal
+	}
+	st := status.New(code, typed.Message)
+	if withInfo, err := st.WithDetails(&errdetails.ErrorInfo{Reason: typed.Code, Domain: ERROR_DOMAIN}); err == nil {
+		st = withInfo
+	}
+	return st.Err()
+}
@@ -0,0 +1,51 @@
+package rpc
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/logging"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// UnaryLoggingInterceptor tags every call with the request ID in its "x-request-id" metadata, or a new one,
+// hands logger to the call's handlers and logs the call once served
+func UnaryLoggingInterceptor(logger *zap.Logger) grpc.UnaryServerInterceptor {
+	key := strings.ToLower(logging.REQUEST_ID_HEADER)
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		start := time.Now()
+
+		var received string
+		md, _ := metadata.FromIncomingContext(ctx)
+		if ids := md.Get(key); len(ids) > 0 {
+			received = ids[0]
+		}
+		requestID := logging.NewRequestID(received)
+		_ = grpc.SetHeader(ctx, metadata.Pairs(key, requestID))
+		ctx = logging.NewContext(logging.WithRequestID(ctx, requestID), logger)
+
+		resp, err := handler(ctx, req)
+
+		code := status.Code(err)
+		fields := []zap.Field{
+			zap.String("method", info.FullMethod),
+			zap.String("code", code.String()),
+			zap.Duration("latency", time.Since(start)),
+		}
+		entry := logging.FromContext(ctx)
+		switch code {
+		case codes.OK:
+			entry.Info("call served", fields...)
+		case codes.Internal, codes.Unknown, codes.DataLoss, codes.Unavailable:
+			entry.Error("call served", fields...)
+		default:
+			entry.Warn("call served", fields...)
+		}
+		return resp, err
+	}
+}
@@ -0,0 +1,56 @@
+package rpc
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/metrics"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// CALL_METHOD labels gRPC calls in request metrics and audit records, where REST requests have their HTTP method
+var CALL_METHOD = "GRPC"
+
+// HTTP_STATUS_CODES are the HTTP equivalents of gRPC status codes, calls are measured and audited with them
+// alongside REST requests
+var HTTP_STATUS_CODES = map[codes.Code]int{
+	codes.OK:                 http.StatusOK,
+	codes.Canceled:           499,
+	codes.Unknown:            http.StatusInternalServerError,
+	codes.InvalidArgument:    http.StatusBadRequest,
+	codes.DeadlineExceeded:   http.StatusGatewayTimeout,
+	codes.NotFound:           http.StatusNotFound,
+	codes.AlreadyExists:      http.StatusConflict,
+	codes.PermissionDenied:   http.StatusForbidden,
+	codes.Unauthenticated:    http.StatusUnauthorized,
+	codes.ResourceExhausted:  http.StatusTooManyRequests,
+	codes.FailedPrecondition: http.StatusBadRequest,
+	codes.Aborted:            http.StatusConflict,
+	codes.OutOfRange:         http.StatusBadRequest,
+	codes.Unimplemented:      http.StatusNotImplemented,
+	codes.Internal:           http.StatusInternalServerError,
+	codes.Unavailable:        http.StatusServiceUnavailable,
+	codes.DataLoss:           http.StatusInternalServerError,
+}
+
+// HTTPStatus returns the HTTP equivalent of a gRPC status code
+func HTTPStatus(code codes.Code) int {
+	if statusCode, ok := HTTP_STATUS_CODES[code]; ok {
+		return statusCode
+	}
+	return http.StatusInternalServerError
+}
+
+// UnaryMetricsInterceptor records every call's status and latency against its method
+func UnaryMetricsInterceptor(recorder metrics.RequestRecorder) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		start := time.Now()
+		resp, err := handler(ctx, req)
+
+		recorder.ObserveRequest(CALL_METHOD, info.FullMethod, HTTPStatus(status.Code(err)), time.Since(start))
+		return resp, err
+	}
+}
@@ -0,0 +1,228 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.3.0
+// - protoc             v4.24.3
+// source: money_transfer.proto
+
+package pb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+const (
+	MoneyTransfer_CreateAccount_FullMethodName = "/moneytransfer.v1.MoneyTransfer/CreateAccount"
+	MoneyTransfer_GetAccount_FullMethodName    = "/moneytransfer.v1.MoneyTransfer/GetAccount"
+	MoneyTransfer_Transfer_FullMethodName      = "/moneytransfer.v1.MoneyTransfer/Transfer"
+	MoneyTransfer_ListEntries_FullMethodName   = "/moneytransfer.v1.MoneyTransfer/ListEntries"
+)
+
+// MoneyTransferClient is the client API for MoneyTransfer service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type MoneyTransferClient interface {
+	// CreateAccount opens a customer account funded with an initial deposit
+	CreateAccount(ctx context.Context, in *CreateAccountRequest, opts ...grpc.CallOption) (*Account, error)
+	// GetAccount retrieves an account with its current balance
+	GetAccount(ctx context.Context, in *GetAccountRequest, opts ...grpc.CallOption) (*Account, error)
+	// Transfer moves money from a source to a destination account, or holds it for fraud review
+	Transfer(ctx context.Context, in *TransferRequest, opts ...grpc.CallOption) (*TransferResponse, error)
+	// ListEntries lists the entries posted to an account within a period, oldest first
+	ListEntries(ctx context.Context, in *ListEntriesRequest, opts ...grpc.CallOption) (*ListEntriesResponse, error)
+}
+
+type moneyTransferClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewMoneyTransferClient(cc grpc.ClientConnInterface) MoneyTransferClient {
+	return &moneyTransferClient{cc}
+}
+
+func (c *moneyTransferClient) CreateAccount(ctx context.Context, in *CreateAccountRequest, opts ...grpc.CallOption) (*Account, error) {
+	out := new(Account)
+	err := c.cc.Invoke(ctx, MoneyTransfer_CreateAccount_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *moneyTransferClient) GetAccount(ctx context.Context, in *GetAccountRequest, opts ...grpc.CallOption) (*Account, error) {
+	out := new(Account)
+	err := c.cc.Invoke(ctx, MoneyTransfer_GetAccount_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *moneyTransferClient) Transfer(ctx context.Context, in *TransferRequest, opts ...grpc.CallOption) (*TransferResponse, error) {
+	out := new(TransferResponse)
+	err := c.cc.Invoke(ctx, MoneyTransfer_Transfer_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *moneyTransferClient) ListEntries(ctx context.Context, in *ListEntriesRequest, opts ...grpc.CallOption) (*ListEntriesResponse, error) {
+	out := new(ListEntriesResponse)
+	err := c.cc.Invoke(ctx, MoneyTransfer_ListEntries_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// MoneyTransferServer is the server API for MoneyTransfer service.
+// All implementations must embed UnimplementedMoneyTransferServer
+// for forward compatibility
+type MoneyTransferServer interface {
+	// CreateAccount opens a customer account funded with an initial deposit
+	CreateAccount(context.Context, *CreateAccountRequest) (*Account, error)
+	// GetAccount retrieves an account with its current balance
+	GetAccount(context.Context, *GetAccountRequest) (*Account, error)
+	// Transfer moves money from a source to a destination account, or holds it for fraud review
+	Transfer(context.Context, *TransferRequest) (*TransferResponse, error)
+	// ListEntries lists the entries posted to an account within a period, oldest first
+	ListEntries(context.Context, *ListEntriesRequest) (*ListEntriesResponse, error)
+	mustEmbedUnimplementedMoneyTransferServer()
+}
+
+// UnimplementedMoneyTransferServer must be embedded to have forward compatible implementations.
+type UnimplementedMoneyTransferServer struct {
+}
+
+func (UnimplementedMoneyTransferServer) CreateAccount(context.Context, *CreateAccountRequest) (*Account, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateAccount not implemented")
+}
+func (UnimplementedMoneyTransferServer) GetAccount(context.Context, *GetAccountRequest) (*Account, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetAccount not implemented")
+}
+func (UnimplementedMoneyTransferServer) Transfer(context.Context, *TransferRequest) (*TransferResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Transfer not implemented")
+}
+func (UnimplementedMoneyTransferServer) ListEntries(context.Context, *ListEntriesRequest) (*ListEntriesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListEntries not implemented")
+}
+func (UnimplementedMoneyTransferServer) mustEmbedUnimplementedMoneyTransferServer() {}
+
+// UnsafeMoneyTransferServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to MoneyTransferServer will
+// result in compilation errors.
+type UnsafeMoneyTransferServer interface {
+	mustEmbedUnimplementedMoneyTransferServer()
+}
+
+func RegisterMoneyTransferServer(s grpc.ServiceRegistrar, srv MoneyTransferServer) {
+	s.RegisterService(&MoneyTransfer_ServiceDesc, srv)
+}
+
+func _MoneyTransfer_CreateAccount_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateAccountRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MoneyTransferServer).CreateAccount(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MoneyTransfer_CreateAccount_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MoneyTransferServer).CreateAccount(ctx, req.(*CreateAccountRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MoneyTransfer_GetAccount_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetAccountRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MoneyTransferServer).GetAccount(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MoneyTransfer_GetAccount_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MoneyTransferServer).GetAccount(ctx, req.(*GetAccountRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MoneyTransfer_Transfer_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TransferRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MoneyTransferServer).Transfer(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MoneyTransfer_Transfer_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MoneyTransferServer).Transfer(ctx, req.(*TransferRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MoneyTransfer_ListEntries_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListEntriesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MoneyTransferServer).ListEntries(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MoneyTransfer_ListEntries_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MoneyTransferServer).ListEntries(ctx, req.(*ListEntriesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// MoneyTransfer_ServiceDesc is the grpc.ServiceDesc for MoneyTransfer service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var MoneyTransfer_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "moneytransfer.v1.MoneyTransfer",
+	HandlerType: (*MoneyTransferServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "CreateAccount",
+			Handler:    _MoneyTransfer_CreateAccount_Handler,
+		},
+		{
+			MethodName: "GetAccount",
+			Handler:    _MoneyTransfer_GetAccount_Handler,
+		},
+		{
+			MethodName: "Transfer",
+			Handler:    _MoneyTransfer_Transfer_Handler,
+		},
+		{
+			MethodName: "ListEntries",
+			Handler:    _MoneyTransfer_ListEntries_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "money_transfer.proto",
+}
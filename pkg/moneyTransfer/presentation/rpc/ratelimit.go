@@ -0,0 +1,108 @@
+package rpc
+
+import (
+	"context"
+	"math"
+	"strconv"
+
+	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/logging"
+	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/presentation/middleware"
+	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/ratelimit"
+	"go.uber.org/zap"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+// RateLimitKey tells what a call is counted by, an empty key leaves the call out of the limit
+type RateLimitKey func(ctx context.Context, req interface{}) string
+
+// RATE_LIMIT_KEYS are how calls are counted by each of the ratelimit keys
+var RATE_LIMIT_KEYS = map[string]RateLimitKey{
+	ratelimit.KEY_IP:      ClientIPKey,
+	ratelimit.KEY_SUBJECT: SubjectKey,
+	ratelimit.KEY_ACCOUNT: SourceAccountKey,
+}
+
+// ClientIPKey counts calls by the address of the peer they were received from
+func ClientIPKey(ctx context.Context, req interface{}) string {
+	return clientIP(ctx)
+}
+
+// SubjectKey counts calls by the subject of their access token
+func SubjectKey(ctx context.Context, req interface{}) string {
+	return middleware.Subject(ctx)
+}
+
+// SourceAccountKey counts calls by the account they move money from
+func SourceAccountKey(ctx context.Context, req interface{}) string {
+	transfer, ok := req.(interface{ GetSourceAccountId() string })
+	if !ok {
+		return ""
+	}
+	return transfer.GetSourceAccountId()
+}
+
+// RateLimitRule limits the calls to a method sharing a key
+type RateLimitRule struct {
+	// Name tells the rules of a method apart in the store
+	Name  string
+	Key   RateLimitKey
+	Limit ratelimit.Limit
+}
+
+// MethodLimits are the rules limiting a method. Route names the REST route serving the same operation,
+// calls are counted in its buckets so that a caller can not double their allowance by switching APIs
+type MethodLimits struct {
+	Route string
+	Rules []RateLimitRule
+}
+
+// RateLimits throttle the methods they are keyed by, keeping their buckets in Store
+type RateLimits struct {
+	Store   ratelimit.Store
+	Methods map[string]MethodLimits
+}
+
+// UnaryRateLimitInterceptor rejects the calls going over any of their method's rules as resource exhausted,
+// telling the caller when to retry. Calls are let through when the store cannot be reached
+func UnaryRateLimitInterceptor(limits RateLimits) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		method, ok := limits.Methods[info.FullMethod]
+		if !ok {
+			return handler(ctx, req)
+		}
+
+		for _, rule := range method.Rules {
+			key := rule.Key(ctx, req)
+			if key == "" {
+				continue
+			}
+
+			decision, err := limits.Store.Take(ctx, method.Route+" "+rule.Name+" "+key, rule.Limit)
+			if err != nil {
+				logging.FromContext(ctx).Warn("unable to apply the rate limit", zap.String("rule", rule.Name), zap.Error(err))
+				continue
+			}
+			if !decision.Allowed {
+				logging.FromContext(ctx).Info("call rate limited", zap.String("rule", rule.Name))
+				retryAfter := math.Max(1, math.Ceil(decision.RetryAfter.Seconds()))
+				_ = grpc.SetHeader(ctx, metadata.Pairs("retry-after", strconv.Itoa(int(retryAfter))))
+
+				st := status.New(codes.ResourceExhausted, "Too many requests.")
+				if withDetails, err := st.WithDetails(
+					&errdetails.ErrorInfo{Reason: "rate_limited", Domain: ERROR_DOMAIN},
+					&errdetails.RetryInfo{RetryDelay: durationpb.New(decision.RetryAfter)},
+				); err == nil {
+					st = withDetails
+				}
+				return nil, st.Err()
+			}
+		}
+
+		return handler(ctx, req)
+	}
+}
@@ -0,0 +1,265 @@
+package rpc
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/application"
+	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/domain"
+	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/metrics"
+	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/presentation/middleware"
+	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/presentation/rpc/pb"
+	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/presentation/validation"
+	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/usecases"
+	"github.com/shopspring/decimal"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// Server implements the money transfer gRPC service over the same business logic as the REST API
+type Server struct {
+	pb.UnimplementedMoneyTransferServer
+
+	Uc         usecases.MoneyTransferUsecases
+	Statements usecases.StatementUsecases
+
+	// Lock serializes the money moved here with the other presentation layers
+	Lock sync.Locker
+}
+
+// CheckPreconditions ensures a correct Server struct is initialized
+func (s Server) CheckPreconditions() {
+	if s.Uc == nil {
+		log.Panic("gRPC presentation layer has not initialized the money transfer business logic")
+	}
+
+	if s.Statements == nil {
+		log.Panic("gRPC presentation layer has not initialized the statements business logic")
+	}
+
+	if s.Lock == nil {
+		log.Panic("gRPC presentation layer has not initialized the money movement lock")
+	}
+}
+
+// NewServer initializes a new money transfer gRPC service
+func NewServer(uc usecases.MoneyTransferUsecases, statements usecases.StatementUsecases, lock sync.Locker) *Server {
+	s := &Server{
+		Uc:         uc,
+		Statements: statements,
+		Lock:       lock,
+	}
+	s.CheckPreconditions()
+	return s
+}
+
+// NewGRPCServer serves the money transfer service to callers presenting a valid access token. Calls go through
+// the same tracing, logging, metrics, deadlines, rate limits and audit as the REST requests, in the same order
+func NewGRPCServer(
+	service pb.MoneyTransferServer,
+	tokens TokenValidator,
+	audit middleware.AuditRecorder,
+	recorder metrics.RequestRecorder,
+	limits RateLimits,
+	logger *zap.Logger,
+	opts ...grpc.ServerOption,
+) *grpc.Server {
+	opts = append(opts, grpc.ChainUnaryInterceptor(
+		otelgrpc.UnaryServerInterceptor(),
+		UnaryLoggingInterceptor(logger),
+		UnaryMetricsInterceptor(recorder),
+		UnaryTimeoutInterceptor(),
+		UnaryAuthInterceptor(tokens),
+		UnaryRateLimitInterceptor(limits),
+		UnaryAuditInterceptor(audit),
+	))
+	server := grpc.NewServer(opts...)
+	pb.RegisterMoneyTransferServer(server, service)
+	return server
+}
+
+// CreateAccount opens a customer account funded with an initial deposit
+func (s Server) CreateAccount(ctx context.Context, req *pb.CreateAccountRequest) (*pb.Account, error) {
+	input := application.AccountCreationInput{
+		CustomerName: req.CustomerName,
+		Header:       domain.HeaderType(req.Header),
+	}
+	if req.Amount != "" {
+		amount, err := parseAmount(req.Amount)
+		if err != nil {
+			return nil, statusError(ctx, err)
+		}
+		input.Amount = &amount
+	}
+	if req.Currency != "" {
+		currency := domain.CurrencyType(req.Currency)
+		input.Currency = &currency
+	}
+
+	if err := validation.Validate(input); err != nil {
+		return nil, statusError(ctx, err)
+	}
+
+	if err := metrics.LockContext(ctx, s.Lock); err != nil {
+		return nil, statusError(ctx, err)
+	}
+	account, err := s.Uc.CreateCustomerAccount(ctx, input)
+	s.Lock.Unlock()
+	if err != nil {
+		return nil, statusError(ctx, err)
+	}
+
+	return accountMessage(account), nil
+}
+
+// GetAccount retrieves an account with its current balance
+func (s Server) GetAccount(ctx context.Context, req *pb.GetAccountRequest) (*pb.Account, error) {
+	account, err := s.Uc.Account(ctx, req.Id)
+	if err != nil {
+		return nil, statusError(ctx, err)
+	}
+
+	return accountMessage(account), nil
+}
+
+// Transfer moves money from a source to a destination account, answering a transfer held for fraud review
+// with the held transfer rather than a failure
+func (s Server) Transfer(ctx context.Context, req *pb.TransferRequest) (*pb.TransferResponse, error) {
+	payload := application.TransferPayload{
+		SourceAccountID:      req.SourceAccountId,
+		DestinationAccountID: req.DestinationAccountId,
+	}
+	if req.Amount != "" {
+		amount, err := parseAmount(req.Amount)
+		if err != nil {
+			return nil, statusError(ctx, err)
+		}
+		payload.Amount = &amount
+	}
+
+	if err := validation.Validate(payload); err != nil {
+		return nil, statusError(ctx, err)
+	}
+
+	if err := metrics.LockContext(ctx, s.Lock); err != nil {
+		return nil, statusError(ctx, err)
+	}
+	defer s.Lock.Unlock()
+
+	sourceAccount, err := s.Uc.Account(ctx, payload.SourceAccountID)
+	if err != nil {
+		return nil, statusError(ctx, err)
+	}
+
+	destinationAccount, err := s.Uc.Account(ctx, payload.DestinationAccountID)
+	if err != nil {
+		return nil, statusError(ctx, err)
+	}
+
+	transaction, err := s.Uc.Transfer(ctx, application.TransferInput{
+		SourceAccount:      sourceAccount,
+		DestinationAccount: destinationAccount,
+		Amount:             payload.Amount,
+	})
+	if domain.IsKind(err, domain.Held) {
+		return &pb.TransferResponse{Outcome: &pb.TransferResponse_HeldTransfer{HeldTransfer: &pb.HeldTransfer{
+			Id:     domain.AsError(err).HeldTransferID,
+			Status: string(domain.HoldPending),
+		}}}, nil
+	}
+	if err != nil {
+		return nil, statusError(ctx, err)
+	}
+
+	return &pb.TransferResponse{Outcome: &pb.TransferResponse_Transaction{Transaction: transactionMessage(transaction)}}, nil
+}
+
+// ListEntries lists the entries posted to an account within a period, oldest first
+func (s Server) ListEntries(ctx context.Context, req *pb.ListEntriesRequest) (*pb.ListEntriesResponse, error) {
+	to := time.Now()
+	if req.To != nil {
+		to = req.To.AsTime()
+	}
+	from := to.AddDate(0, -1, 0)
+	if req.From != nil {
+		from = req.From.AsTime()
+	}
+
+	statement, err := s.Statements.Statement(ctx, req.AccountId, from, to)
+	if err != nil {
+		return nil, statusError(ctx, err)
+	}
+
+	response := pb.ListEntriesResponse{
+		AccountId:      req.AccountId,
+		OpeningBalance: statement.OpeningBalance.String(),
+		ClosingBalance: statement.ClosingBalance.String(),
+	}
+	for _, line := range statement.Lines {
+		response.Entries = append(response.Entries, &pb.Entry{
+			Id:             line.EntryID,
+			TransactionId:  line.TransactionID,
+			Description:    line.Description,
+			BookingDate:    timestamppb.New(line.BookingDate),
+			Amount:         line.Amount.String(),
+			RunningBalance: line.RunningBalance.String(),
+		})
+	}
+
+	return &response, nil
+}
+
+// parseAmount decodes a decimal amount sent as a string
+func parseAmount(amount string) (decimal.Decimal, error) {
+	parsed, err := decimal.NewFromString(amount)
+	if err != nil {
+		return decimal.Decimal{}, domain.NewValidationError("invalid_amount", "%q is not a decimal amount", amount)
+	}
+	return parsed, nil
+}
+
+// accountMessage converts an account to its protobuf message
+func accountMessage(account *application.AccountInformationOutput) *pb.Account {
+	message := pb.Account{
+		Id:              account.UUID,
+		Active:          account.Active,
+		Name:            account.Name,
+		Description:     account.Description,
+		Number:          account.Number,
+		Currency:        string(account.Currency),
+		BalanceType:     string(account.BalanceType),
+		Header:          string(account.Header),
+		IsSystemAccount: account.IsSystemAccount,
+		GlCode:          account.GLCode,
+		Role:            string(account.Role),
+	}
+	if account.Balance != nil {
+		message.Balance = account.Balance.String()
+	}
+	if account.BalanceAsOf != nil {
+		message.BalanceAsOf = timestamppb.New(*account.BalanceAsOf)
+	}
+	if account.CreatedAt != nil {
+		message.CreatedAt = timestamppb.New(*account.CreatedAt)
+	}
+	return &message
+}
+
+// transactionMessage converts a transaction to its protobuf message
+func transactionMessage(transaction *domain.Transaction) *pb.Transaction {
+	message := pb.Transaction{
+		Id:          transaction.UUID,
+		Description: transaction.Description,
+	}
+	if transaction.ReversalOf != nil {
+		message.ReversalOf = *transaction.ReversalOf
+	}
+	if transaction.CreatedAt != nil {
+		message.CreatedAt = timestamppb.New(*transaction.CreatedAt)
+	}
+	return &message
+}
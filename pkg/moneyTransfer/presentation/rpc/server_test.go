@@ -0,0 +1,399 @@
+package rpc_test
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/application"
+	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/domain"
+	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/presentation/middleware"
+	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/presentation/rpc"
+	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/presentation/rpc/pb"
+	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/presentation/validation"
+	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/ratelimit"
+	"github.com/auth0/go-jwt-middleware/v2/validator"
+	"github.com/shopspring/decimal"
+	"go.uber.org/zap"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+var (
+	sourceID      = "0b0e4f5c-6f5e-4a4e-9b3a-4a1f2d3c4b5a"
+	destinationID = "9f1c2d3e-4b5a-4c6d-8e7f-0a1b2c3d4e5f"
+)
+
+type stubTokens struct{}
+
+func (stubTokens) ValidateToken(ctx context.Context, token string) (interface{}, error) {
+	if token != "valid-token" {
+		return nil, fmt.Errorf("token is expired")
+	}
+	return &validator.ValidatedClaims{RegisteredClaims: validator.RegisteredClaims{Subject: "client-1"}}, nil
+}
+
+type stubMoneyTransfer struct{}
+
+func (s *stubMoneyTransfer) CreateCustomerAccount(ctx context.Context, input application.AccountCreationInput) (*application.AccountInformationOutput, error) {
+	return &application.AccountInformationOutput{
+		UUID:     destinationID,
+		Name:     input.CustomerName,
+		Currency: *input.Currency,
+		Header:   input.Header,
+		Balance:  input.Amount,
+	}, nil
+}
+
+func (s *stubMoneyTransfer) Account(ctx context.Context, accountID string) (*application.AccountInformationOutput, error) {
+	if accountID != sourceID && accountID != destinationID {
+		return nil, domain.NewNotFoundError("account_not_found", "account %s was not found", accountID)
+	}
+	balance := decimal.NewFromInt(100)
+	return &application.AccountInformationOutput{UUID: accountID, Currency: domain.Kenyan, Balance: &balance}, nil
+}
+
+func (s *stubMoneyTransfer) Transfer(ctx context.Context, input application.TransferInput) (*domain.Transaction, error) {
+	if input.Amount.GreaterThan(*input.SourceAccount.Balance) {
+		return nil, domain.NewInsufficientFundsError("%v is more than the balance", input.Amount)
+	}
+	if input.Amount.Equal(decimal.NewFromInt(50)) {
+		return nil, domain.NewHeldError("held-1")
+	}
+	return &domain.Transaction{AbstractBase: domain.AbstractBase{UUID: "transaction-1"}, Description: "transfer"}, nil
+}
+
+func (s *stubMoneyTransfer) ReverseTransfer(context.Context, string, string) (*domain.Transaction, error) {
+	return nil, fmt.Errorf("not implemented")
+}
+
+type stubStatements struct{}
+
+func (s stubStatements) Statement(ctx context.Context, accountID string, from time.Time, to time.Time) (*application.Statement, error) {
+	return &application.Statement{
+		OpeningBalance: decimal.NewFromInt(0),
+		ClosingBalance: decimal.NewFromInt(100),
+		Lines: []*application.StatementLine{
+			{EntryID: "entry-1", TransactionID: "transaction-1", BookingDate: from, Amount: decimal.NewFromInt(100), RunningBalance: decimal.NewFromInt(100)},
+		},
+	}, nil
+}
+
+// memoryAudit keeps the audit records of the calls served
+type memoryAudit struct {
+	mu      sync.Mutex
+	records []*domain.AuditRecord
+}
+
+func (m *memoryAudit) Record(ctx context.Context, record *domain.AuditRecord) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.records = append(m.records, record)
+	return nil
+}
+
+// observation is a call measured by memoryRequests
+type observation struct {
+	method string
+	route  string
+	status int
+}
+
+// memoryRequests keeps the request metrics of the calls served
+type memoryRequests struct {
+	mu           sync.Mutex
+	observations []observation
+}
+
+func (m *memoryRequests) ObserveRequest(method string, route string, status int, duration time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.observations = append(m.observations, observation{method: method, route: route, status: status})
+}
+
+func newTestClient(t *testing.T) pb.MoneyTransferClient {
+	return newObservedTestClient(t, &memoryAudit{}, &memoryRequests{}, rpc.RateLimits{})
+}
+
+func newObservedTestClient(t *testing.T, audit *memoryAudit, requests *memoryRequests, limits rpc.RateLimits) pb.MoneyTransferClient {
+	if err := validation.RegisterValidators(); err != nil {
+		t.Fatalf("RegisterValidators() error = %v", err)
+	}
+
+	listener := bufconn.Listen(1024 * 1024)
+	server := rpc.NewGRPCServer(rpc.NewServer(&stubMoneyTransfer{}, stubStatements{}, &sync.Mutex{}), stubTokens{}, audit, requests, limits, zap.NewNop())
+	go func() {
+		_ = server.Serve(listener)
+	}()
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.Dial("bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatalf("unable to dial the test server: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	return pb.NewMoneyTransferClient(conn)
+}
+
+func withToken(token string) context.Context {
+	return metadata.AppendToOutgoingContext(context.Background(), "authorization", "Bearer "+token)
+}
+
+func TestServer_Authentication(t *testing.T) {
+	client := newTestClient(t)
+
+	tests := []struct {
+		name string
+		ctx  context.Context
+		want codes.Code
+	}{
+		{
+			name: "happy case - valid token",
+			ctx:  withToken("valid-token"),
+			want: codes.OK,
+		},
+		{
+			name: "sad case - no token",
+			ctx:  context.Background(),
+			want: codes.Unauthenticated,
+		},
+		{
+			name: "sad case - invalid token",
+			ctx:  withToken("expired-token"),
+			want: codes.Unauthenticated,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := client.GetAccount(tt.ctx, &pb.GetAccountRequest{Id: sourceID})
+			if got := status.Code(err); got != tt.want {
+				t.Errorf("GetAccount() code = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	if _, ok := middleware.ValidatedClaims(context.Background()); ok {
+		t.Errorf("expected no claims outside an authenticated call")
+	}
+}
+
+func TestServer_Calls(t *testing.T) {
+	client := newTestClient(t)
+	ctx := withToken("valid-token")
+
+	account, err := client.CreateAccount(ctx, &pb.CreateAccountRequest{
+		CustomerName: "Jane",
+		Amount:       "100.50",
+		Currency:     "KSH",
+		Header:       "DEPOSIT",
+	})
+	if err != nil {
+		t.Fatalf("CreateAccount() error = %v", err)
+	}
+	if account.Balance != "100.5" || account.Currency != "KSH" {
+		t.Errorf("unexpected account %v", account)
+	}
+
+	transfer, err := client.Transfer(ctx, &pb.TransferRequest{
+		SourceAccountId:      sourceID,
+		DestinationAccountId: destinationID,
+		Amount:               "10",
+	})
+	if err != nil {
+		t.Fatalf("Transfer() error = %v", err)
+	}
+	if transfer.GetTransaction().GetId() != "transaction-1" {
+		t.Errorf("unexpected transfer %v", transfer)
+	}
+
+	held, err := client.Transfer(ctx, &pb.TransferRequest{
+		SourceAccountId:      sourceID,
+		DestinationAccountId: destinationID,
+		Amount:               "50",
+	})
+	if err != nil {
+		t.Fatalf("Transfer() error = %v", err)
+	}
+	if held.GetHeldTransfer().GetId() != "held-1" || held.GetHeldTransfer().GetStatus() != string(domain.HoldPending) {
+		t.Errorf("expected the transfer to be held for review as held-1, got %v", held)
+	}
+
+	entries, err := client.ListEntries(ctx, &pb.ListEntriesRequest{AccountId: sourceID})
+	if err != nil {
+		t.Fatalf("ListEntries() error = %v", err)
+	}
+	if len(entries.Entries) != 1 || entries.ClosingBalance != "100" {
+		t.Errorf("unexpected entries %v", entries)
+	}
+}
+
+func TestServer_Errors(t *testing.T) {
+	client := newTestClient(t)
+	ctx := withToken("valid-token")
+
+	tests := []struct {
+		name       string
+		call       func() error
+		wantCode   codes.Code
+		wantReason string
+	}{
+		{
+			name: "unknown account",
+			call: func() error {
+				_, err := client.GetAccount(ctx, &pb.GetAccountRequest{Id: "9f1c2d3e-0000-4c6d-8e7f-0a1b2c3d4e5f"})
+				return err
+			},
+			wantCode:   codes.NotFound,
+			wantReason: "account_not_found",
+		},
+		{
+			name: "insufficient funds",
+			call: func() error {
+				_, err := client.Transfer(ctx, &pb.TransferRequest{SourceAccountId: sourceID, DestinationAccountId: destinationID, Amount: "1000"})
+				return err
+			},
+			wantCode:   codes.FailedPrecondition,
+			wantReason: "insufficient_funds",
+		},
+		{
+			name: "malformed amount",
+			call: func() error {
+				_, err := client.Transfer(ctx, &pb.TransferRequest{SourceAccountId: sourceID, DestinationAccountId: destinationID, Amount: "ten"})
+				return err
+			},
+			wantCode:   codes.InvalidArgument,
+			wantReason: "invalid_amount",
+		},
+		{
+			name: "invalid fields",
+			call: func() error {
+				_, err := client.CreateAccount(ctx, &pb.CreateAccountRequest{CustomerName: "Jane", Amount: "100", Currency: "EUR"})
+				return err
+			},
+			wantCode: codes.InvalidArgument,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			st := status.Convert(tt.call())
+			if st.Code() != tt.wantCode {
+				t.Errorf("code = %v, want %v: %s", st.Code(), tt.wantCode, st.Message())
+				return
+			}
+
+			for _, detail := range st.Details() {
+				switch detail := detail.(type) {
+				case *errdetails.ErrorInfo:
+					if detail.Reason != tt.wantReason {
+						t.Errorf("reason = %v, want %v", detail.Reason, tt.wantReason)
+					}
+				case *errdetails.BadRequest:
+					if len(detail.FieldViolations) != 2 {
+						t.Errorf("expected the currency and header violations, got %v", detail.FieldViolations)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestServer_Interceptors(t *testing.T) {
+	audit := &memoryAudit{}
+	requests := &memoryRequests{}
+	client := newObservedTestClient(t, audit, requests, rpc.RateLimits{
+		Store: ratelimit.NewMemoryStore(),
+		Methods: map[string]rpc.MethodLimits{
+			pb.MoneyTransfer_Transfer_FullMethodName: {
+				Route: "POST /api/v1/transfers",
+				Rules: []rpc.RateLimitRule{{
+					Name:  ratelimit.KEY_SUBJECT,
+					Key:   rpc.SubjectKey,
+					Limit: ratelimit.Limit{Requests: 1, Period: time.Minute},
+				}},
+			},
+		},
+	})
+	ctx := withToken("valid-token")
+
+	if _, err := client.GetAccount(ctx, &pb.GetAccountRequest{Id: sourceID}); err != nil {
+		t.Fatalf("GetAccount() error = %v", err)
+	}
+	transfer := &pb.TransferRequest{SourceAccountId: sourceID, DestinationAccountId: destinationID, Amount: "10"}
+	if _, err := client.Transfer(ctx, transfer); err != nil {
+		t.Fatalf("Transfer() error = %v", err)
+	}
+	throttled := status.Convert(func() error { _, err := client.Transfer(ctx, transfer); return err }())
+	if throttled.Code() != codes.ResourceExhausted {
+		t.Fatalf("expected the second transfer to be rate limited, got %v", throttled.Code())
+	}
+
+	if len(audit.records) != 1 {
+		t.Fatalf("expected only the transfer let through to be audited, got %d records", len(audit.records))
+	}
+	record := audit.records[0]
+	if record.Actor != "client-1" || record.Route != pb.MoneyTransfer_Transfer_FullMethodName || record.StatusCode != http.StatusOK || record.ResourceIDs != "transaction-1" {
+		t.Errorf("unexpected audit record %+v", record)
+	}
+
+	want := []observation{
+		{method: rpc.CALL_METHOD, route: pb.MoneyTransfer_GetAccount_FullMethodName, status: http.StatusOK},
+		{method: rpc.CALL_METHOD, route: pb.MoneyTransfer_Transfer_FullMethodName, status: http.StatusOK},
+		{method: rpc.CALL_METHOD, route: pb.MoneyTransfer_Transfer_FullMethodName, status: http.StatusTooManyRequests},
+	}
+	if !reflect.DeepEqual(requests.observations, want) {
+		t.Errorf("observations = %+v, want %+v", requests.observations, want)
+	}
+}
+
+func TestUnaryTimeoutInterceptor(t *testing.T) {
+	tests := []struct {
+		method string
+		want   time.Duration
+	}{
+		{method: pb.MoneyTransfer_GetAccount_FullMethodName, want: rpc.DEFAULT_CALL_TIMEOUT},
+		{method: pb.MoneyTransfer_ListEntries_FullMethodName, want: rpc.CALL_TIMEOUTS[pb.MoneyTransfer_ListEntries_FullMethodName]},
+	}
+	for _, tt := range tests {
+		t.Run(tt.method, func(t *testing.T) {
+			var timeout time.Duration
+			handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+				if deadline, ok := ctx.Deadline(); ok {
+					timeout = time.Until(deadline)
+				}
+				return nil, ctx.Err()
+			}
+			if _, err := rpc.UnaryTimeoutInterceptor()(context.Background(), nil, &grpc.UnaryServerInfo{FullMethod: tt.method}, handler); err != nil {
+				t.Fatalf("UnaryTimeoutInterceptor() error = %v", err)
+			}
+			if timeout <= tt.want-time.Second || timeout > tt.want {
+				t.Errorf("expected a deadline within %v, got %v", tt.want, timeout)
+			}
+		})
+	}
+
+	expired, cancel := context.WithDeadline(context.Background(), time.Now().Add(-time.Second))
+	defer cancel()
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return nil, fmt.Errorf("unable to get account: %v", ctx.Err())
+	}
+	_, err := rpc.UnaryTimeoutInterceptor()(expired, nil, &grpc.UnaryServerInfo{FullMethod: pb.MoneyTransfer_GetAccount_FullMethodName}, handler)
+	if status.Code(err) != codes.DeadlineExceeded {
+		t.Errorf("expected a call cut short to report its deadline, got %v", err)
+	}
+}
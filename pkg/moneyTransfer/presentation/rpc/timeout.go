@@ -0,0 +1,40 @@
+package rpc
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/presentation/rpc/pb"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// DEFAULT_CALL_TIMEOUT bounds how long serving a call may take, unless its method sets its own timeout
+var DEFAULT_CALL_TIMEOUT = 10 * time.Second
+
+// CALL_TIMEOUTS bound the methods reading a whole account history, as the REST report routes are
+var CALL_TIMEOUTS = map[string]time.Duration{
+	pb.MoneyTransfer_ListEntries_FullMethodName: time.Minute,
+}
+
+// UnaryTimeoutInterceptor gives a call's context its method's deadline, or the deadline the caller set if it
+// is sooner, so that the work done for the call is cancelled once it passes
+func UnaryTimeoutInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		timeout, ok := CALL_TIMEOUTS[info.FullMethod]
+		if !ok {
+			timeout = DEFAULT_CALL_TIMEOUT
+		}
+		ctx, cancel := context.WithTimeout(ctx, timeout)
+		defer cancel()
+
+		resp, err := handler(ctx, req)
+		// Handlers report the work cut short as failed, the caller is told it ran out of time instead
+		if err != nil && errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return nil, status.Error(codes.DeadlineExceeded, "the call took too long to serve")
+		}
+		return resp, err
+	}
+}
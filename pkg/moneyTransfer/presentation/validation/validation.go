@@ -0,0 +1,107 @@
+// Package validation declares the rules requests are validated against, whichever API they arrive through
+package validation
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/application"
+	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/domain"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+	"github.com/shopspring/decimal"
+)
+
+// RegisterValidators teaches the request binding validator the service's custom tags.
+// It should be called once before any request is bound
+func RegisterValidators() error {
+	validate, ok := binding.Validator.Engine().(*validator.Validate)
+	if !ok {
+		return fmt.Errorf("request binding does not use the expected validator")
+	}
+
+	// Report fields by the name clients send them as
+	validate.RegisterTagNameFunc(func(field reflect.StructField) string {
+		name := strings.SplitN(field.Tag.Get("json"), ",", 2)[0]
+		if name == "" || name == "-" {
+			return field.Name
+		}
+		return name
+	})
+
+	validate.RegisterCustomTypeFunc(func(field reflect.Value) interface{} {
+		if amount, ok := field.Interface().(decimal.Decimal); ok {
+			return amount.String()
+		}
+		return nil
+	}, decimal.Decimal{})
+
+	validations := map[string]validator.Func{
+		"positive_amount": func(fl validator.FieldLevel) bool {
+			amount, err := decimal.NewFromString(fl.Field().String())
+			return err == nil && amount.IsPositive()
+		},
+		"currency": func(fl validator.FieldLevel) bool {
+			return domain.IsKnownCurrency(domain.CurrencyType(fl.Field().String()))
+		},
+		"customer_header": func(fl validator.FieldLevel) bool {
+			return domain.IsCustomerHeader(domain.HeaderType(fl.Field().String()))
+		},
+	}
+	for tag, validation := range validations {
+		if err := validate.RegisterValidation(tag, validation); err != nil {
+			return fmt.Errorf("unable to register the %s validation: %v", tag, err)
+		}
+	}
+
+	validate.RegisterStructValidation(func(sl validator.StructLevel) {
+		input := sl.Current().Interface().(application.AccountCreationInput)
+		if input.Amount != nil && input.Currency != nil && !input.Currency.Fits(*input.Amount) {
+			sl.ReportError(input.Amount, "Amount", "Amount", "precision", string(*input.Currency))
+		}
+	}, application.AccountCreationInput{})
+
+	return nil
+}
+
+// Validate checks a request against the rules declared in its binding tags
+func Validate(request interface{}) error {
+	return binding.Validator.ValidateStruct(request)
+}
+
+// FieldErrors describes each field that failed validation, reporting false for errors that are not failed validations
+func FieldErrors(err error) (map[string]string, bool) {
+	var validationErrs validator.ValidationErrors
+	if !errors.As(err, &validationErrs) {
+		return nil, false
+	}
+
+	fields := map[string]string{}
+	for _, fieldErr := range validationErrs {
+		fields[fieldErr.Field()] = fieldErrorMessage(fieldErr)
+	}
+	return fields, true
+}
+
+// fieldErrorMessage describes a failed validation in terms a client can act on
+func fieldErrorMessage(fieldErr validator.FieldError) string {
+	switch fieldErr.Tag() {
+	case "required":
+		return "is required"
+	case "positive_amount":
+		return "should be more than zero"
+	case "precision":
+		return fmt.Sprintf("has more decimals than %s allows", fieldErr.Param())
+	case "currency":
+		return fmt.Sprintf("should be one of %v", domain.CURRENCIES)
+	case "customer_header":
+		return fmt.Sprintf("should be one of %v", domain.CUSTOMER_HEADERS)
+	case "uuid":
+		return "should be a valid uuid"
+	case "nefield":
+		return fmt.Sprintf("should differ from %s", fieldErr.Param())
+	}
+	return fmt.Sprintf("failed the %s validation", fieldErr.Tag())
+}
@@ -0,0 +1,67 @@
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// SWEEP_INTERVAL is how often the memory store forgets the buckets that have refilled
+var SWEEP_INTERVAL = time.Minute
+
+// MemoryStore keeps the buckets in memory, limiting the requests served by a single instance
+type MemoryStore struct {
+	// Now tells the time, it is overridden by tests
+	Now func() time.Time
+
+	mu      sync.Mutex
+	buckets map[string]memoryBucket
+	swept   time.Time
+}
+
+type memoryBucket struct {
+	Bucket
+	limit Limit
+}
+
+// NewMemoryStore initializes an empty memory store
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		Now:     time.Now,
+		buckets: map[string]memoryBucket{},
+	}
+}
+
+// Take takes a token from the bucket at key
+func (m *MemoryStore) Take(ctx context.Context, key string, limit Limit) (Decision, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := m.Now()
+	m.sweep(now)
+
+	bucket, decision := limit.Take(m.buckets[key].Bucket, now)
+	m.buckets[key] = memoryBucket{Bucket: bucket, limit: limit}
+	return decision, nil
+}
+
+// sweep forgets the buckets that have refilled, so that callers seen once do not take memory forever
+func (m *MemoryStore) sweep(now time.Time) {
+	if now.Sub(m.swept) < SWEEP_INTERVAL {
+		return
+	}
+	m.swept = now
+
+	for key, bucket := range m.buckets {
+		if bucket.limit.Full(bucket.Bucket, now) {
+			delete(m.buckets, key)
+		}
+	}
+}
+
+// Len is the number of buckets kept
+func (m *MemoryStore) Len() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return len(m.buckets)
+}
@@ -0,0 +1,85 @@
+// Package ratelimit throttles callers with token buckets, kept in a store that may be shared by every instance of
+// the service
+package ratelimit
+
+import (
+	"context"
+	"math"
+	"time"
+)
+
+// What a route's requests are counted by
+var (
+	KEY_IP      = "ip"
+	KEY_SUBJECT = "subject"
+	KEY_ACCOUNT = "account"
+)
+
+// KEYS are the keys routes can be limited by
+var KEYS = []string{KEY_IP, KEY_SUBJECT, KEY_ACCOUNT}
+
+// Limit lets Requests through every Period on average, in bursts of up to Burst requests
+type Limit struct {
+	Requests int
+	Period   time.Duration
+	Burst    int
+}
+
+// capacity is how many tokens the bucket holds when full
+func (l Limit) capacity() float64 {
+	if l.Burst > 0 {
+		return float64(l.Burst)
+	}
+	return float64(l.Requests)
+}
+
+// interval is how long refilling a single token takes
+func (l Limit) interval() time.Duration {
+	return l.Period / time.Duration(l.Requests)
+}
+
+// Decision tells whether a request may go through, and otherwise when it may be retried
+type Decision struct {
+	Allowed    bool
+	Remaining  int
+	RetryAfter time.Duration
+}
+
+// Bucket is the state of a token bucket, as a shared store keeps it
+type Bucket struct {
+	Tokens  float64
+	Updated time.Time
+}
+
+// Take refills the bucket for the time elapsed since it was last updated and takes a token from it, when one is
+// left. A zero bucket is a full one
+func (l Limit) Take(bucket Bucket, now time.Time) (Bucket, Decision) {
+	capacity := l.capacity()
+	tokens := capacity
+	if !bucket.Updated.IsZero() {
+		elapsed := now.Sub(bucket.Updated)
+		if elapsed < 0 {
+			elapsed = 0
+		}
+		tokens = math.Min(capacity, bucket.Tokens+float64(elapsed)/float64(l.interval()))
+	}
+
+	if tokens < 1 {
+		wait := time.Duration((1 - tokens) * float64(l.interval()))
+		return Bucket{Tokens: tokens, Updated: now}, Decision{RetryAfter: wait}
+	}
+	tokens--
+	return Bucket{Tokens: tokens, Updated: now}, Decision{Allowed: true, Remaining: int(tokens)}
+}
+
+// Full reports whether the bucket would have refilled by now, so that a store may forget it
+func (l Limit) Full(bucket Bucket, now time.Time) bool {
+	missing := l.capacity() - bucket.Tokens
+	return now.Sub(bucket.Updated) >= time.Duration(missing*float64(l.interval()))
+}
+
+// Store keeps the buckets, taking tokens atomically so that concurrent requests cannot overdraw a bucket.
+// A store shared by the instances of the service, such as Redis, applies the same Limit.Take to its buckets
+type Store interface {
+	Take(ctx context.Context, key string, limit Limit) (Decision, error)
+}
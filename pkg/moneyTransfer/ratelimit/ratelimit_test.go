@@ -0,0 +1,137 @@
+package ratelimit_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/ratelimit"
+)
+
+func TestLimit_Take(t *testing.T) {
+	start := time.Date(2023, 8, 1, 12, 0, 0, 0, time.UTC)
+	limit := ratelimit.Limit{Requests: 60, Period: time.Minute, Burst: 3}
+
+	tests := []struct {
+		name          string
+		bucket        ratelimit.Bucket
+		now           time.Time
+		wantAllowed   bool
+		wantRemaining int
+		wantRetry     time.Duration
+	}{
+		{
+			name:          "new bucket is full",
+			now:           start,
+			wantAllowed:   true,
+			wantRemaining: 2,
+		},
+		{
+			name:      "empty bucket",
+			bucket:    ratelimit.Bucket{Tokens: 0, Updated: start},
+			now:       start,
+			wantRetry: time.Second,
+		},
+		{
+			name:      "partly refilled",
+			bucket:    ratelimit.Bucket{Tokens: 0, Updated: start},
+			now:       start.Add(250 * time.Millisecond),
+			wantRetry: 750 * time.Millisecond,
+		},
+		{
+			name:          "refilled token",
+			bucket:        ratelimit.Bucket{Tokens: 0, Updated: start},
+			now:           start.Add(time.Second),
+			wantAllowed:   true,
+			wantRemaining: 0,
+		},
+		{
+			name:          "refilled up to the burst",
+			bucket:        ratelimit.Bucket{Tokens: 0, Updated: start},
+			now:           start.Add(time.Hour),
+			wantAllowed:   true,
+			wantRemaining: 2,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, decision := limit.Take(tt.bucket, tt.now)
+			if decision.Allowed != tt.wantAllowed || decision.Remaining != tt.wantRemaining || decision.RetryAfter != tt.wantRetry {
+				t.Errorf("Take() = %+v, want allowed %v, %d remaining, retry after %v", decision, tt.wantAllowed, tt.wantRemaining, tt.wantRetry)
+			}
+		})
+	}
+}
+
+func TestLimit_BurstDefaultsToRequests(t *testing.T) {
+	limit := ratelimit.Limit{Requests: 5, Period: time.Minute}
+	now := time.Now()
+
+	var bucket ratelimit.Bucket
+	allowed := 0
+	for i := 0; i < 10; i++ {
+		var decision ratelimit.Decision
+		bucket, decision = limit.Take(bucket, now)
+		if decision.Allowed {
+			allowed++
+		}
+	}
+	if allowed != 5 {
+		t.Errorf("expected a burst of 5 requests, got %d", allowed)
+	}
+}
+
+func TestMemoryStore_Take(t *testing.T) {
+	store := ratelimit.NewMemoryStore()
+	limit := ratelimit.Limit{Requests: 10, Period: time.Hour}
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	allowed := 0
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			decision, err := store.Take(context.Background(), "client-1", limit)
+			if err != nil {
+				t.Errorf("Take() error = %v", err)
+				return
+			}
+			if decision.Allowed {
+				mu.Lock()
+				allowed++
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	if allowed != 10 {
+		t.Errorf("expected 10 concurrent requests to be let through, got %d", allowed)
+	}
+	if decision, _ := store.Take(context.Background(), "client-2", limit); !decision.Allowed {
+		t.Errorf("expected other keys to have their own bucket")
+	}
+}
+
+func TestMemoryStore_ForgetsRefilledBuckets(t *testing.T) {
+	now := time.Date(2023, 8, 1, 12, 0, 0, 0, time.UTC)
+	store := ratelimit.NewMemoryStore()
+	store.Now = func() time.Time { return now }
+	limit := ratelimit.Limit{Requests: 1, Period: time.Second}
+
+	for _, key := range []string{"client-1", "client-2"} {
+		if _, err := store.Take(context.Background(), key, limit); err != nil {
+			t.Fatalf("Take() error = %v", err)
+		}
+	}
+
+	now = now.Add(ratelimit.SWEEP_INTERVAL)
+	if _, err := store.Take(context.Background(), "client-3", limit); err != nil {
+		t.Fatalf("Take() error = %v", err)
+	}
+	if store.Len() != 1 {
+		t.Errorf("expected the refilled buckets to be forgotten, %d buckets kept", store.Len())
+	}
+}
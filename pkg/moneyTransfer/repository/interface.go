@@ -1,6 +1,9 @@
 package repository
 
 import (
+	"context"
+	"time"
+
 	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/application"
 	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/domain"
 	"github.com/shopspring/decimal"
@@ -8,19 +11,116 @@ import (
 
 // CreateRepository abstracts the Create contract that any repository should adhere to
 type CreateRepository interface {
-	CreateAccount(account *domain.Account) (*application.AccountInformationOutput, error)
+	CreateAccount(ctx context.Context, account *domain.Account) (*application.AccountInformationOutput, error)
 	CreateTransaction(
+		ctx context.Context,
+		description string,
+		drEntry *domain.AccountEntry,
+		crEntry *domain.AccountEntry,
+	) (*domain.Transaction, error)
+	CreateTransfer(
+		ctx context.Context,
 		description string,
 		drEntry *domain.AccountEntry,
 		crEntry *domain.AccountEntry,
 	) (*domain.Transaction, error)
-	CreateSystemAccount() error
+	ReverseTransaction(ctx context.Context, transactionID string, description string) (*domain.Transaction, error)
+	CreateSystemAccount(ctx context.Context) error
 }
 
 // GetRepository abstracts the Get contract that any repository should adhere to
 type GetRepository interface {
-	Account(accountID string) (*application.AccountInformationOutput, error)
-	AccountDebitTotal(account *domain.Account) (*decimal.Decimal, error)
-	AccountCreditTotal(account *domain.Account) (*decimal.Decimal, error)
-	AccountBalance(account *domain.Account) (*decimal.Decimal, error)
+	Account(ctx context.Context, accountID string) (*application.AccountInformationOutput, error)
+	AccountByNumber(ctx context.Context, number string) (*application.AccountInformationOutput, error)
+	SystemAccount(ctx context.Context, role domain.AccountRole, currency domain.CurrencyType) (*application.AccountInformationOutput, error)
+	AccountDebitTotal(ctx context.Context, account *domain.Account) (*decimal.Decimal, error)
+	AccountCreditTotal(ctx context.Context, account *domain.Account) (*decimal.Decimal, error)
+	AccountBalance(ctx context.Context, account *domain.Account) (*decimal.Decimal, error)
+}
+
+// ReportRepository abstracts the accounting reports contract that any repository should adhere to
+type ReportRepository interface {
+	TrialBalance(ctx context.Context, asOf time.Time) ([]*application.TrialBalanceLine, error)
+	AccountEntries(ctx context.Context, accountID string, from time.Time, to time.Time) ([]*domain.AccountEntry, error)
+	AccountBalanceAsOf(ctx context.Context, account *domain.Account, asOf time.Time) (*decimal.Decimal, error)
+	LedgerTotals(ctx context.Context) (*decimal.Decimal, *decimal.Decimal, error)
+	UnbalancedTransactions(ctx context.Context) ([]string, error)
+}
+
+// PeriodRepository abstracts the accounting period contract that any repository should adhere to
+type PeriodRepository interface {
+	ClosePeriod(ctx context.Context, period *domain.AccountingPeriod, actor string) (*domain.AccountingPeriod, error)
+	UpdatePeriod(ctx context.Context, periodID string, audit *domain.PeriodAuditEntry, update func(period *domain.AccountingPeriod) error) (*domain.AccountingPeriod, error)
+	Period(ctx context.Context, periodID string) (*domain.AccountingPeriod, error)
+	Periods(ctx context.Context) ([]*domain.AccountingPeriod, error)
+	LockedPeriod(ctx context.Context, date time.Time) (*domain.AccountingPeriod, error)
+	NextOpenDate(ctx context.Context, date time.Time) (time.Time, error)
+	BalanceSnapshots(ctx context.Context, periodID string) ([]*domain.BalanceSnapshot, error)
+	PeriodAudit(ctx context.Context, periodID string) ([]*domain.PeriodAuditEntry, error)
+}
+
+// OutboxRepository abstracts the domain event outbox contract that any repository should adhere to
+type OutboxRepository interface {
+	PendingEvents(ctx context.Context, limit int) ([]*domain.OutboxEvent, error)
+	MarkPublished(ctx context.Context, sequence int64) error
+	MarkFailed(ctx context.Context, sequence int64, reason string) error
+}
+
+// WebhookRepository abstracts the webhook subscriptions and deliveries contract that any repository should adhere to
+type WebhookRepository interface {
+	CreateSubscription(ctx context.Context, subscription *domain.WebhookSubscription) (*domain.WebhookSubscription, error)
+	Subscription(ctx context.Context, subscriptionID string) (*domain.WebhookSubscription, error)
+	Subscriptions(ctx context.Context, owner string) ([]*domain.WebhookSubscription, error)
+	DeleteSubscription(ctx context.Context, subscriptionID string) error
+	SubscriptionsFor(ctx context.Context, eventType domain.EventType, accountIDs []string) ([]*domain.WebhookSubscription, error)
+	CreateDeliveries(ctx context.Context, deliveries []*domain.WebhookDelivery) error
+	Delivery(ctx context.Context, deliveryID string) (*domain.WebhookDelivery, error)
+	Deliveries(ctx context.Context, subscriptionID string, status domain.DeliveryStatus) ([]*domain.WebhookDelivery, error)
+	ClaimDueDeliveries(ctx context.Context, asOf time.Time, lease time.Duration, limit int) ([]*domain.WebhookDelivery, error)
+	UpdateDelivery(ctx context.Context, delivery *domain.WebhookDelivery) error
+}
+
+// PaymentRepository abstracts the payment initiation records contract that any repository should adhere to
+type PaymentRepository interface {
+	RecordPaymentMessage(ctx context.Context, message *domain.PaymentMessage) (*domain.PaymentMessage, error)
+	ClaimPaymentInstruction(ctx context.Context, instruction *domain.PaymentInstruction) (*domain.PaymentInstruction, error)
+	CompletePaymentInstruction(ctx context.Context, instruction *domain.PaymentInstruction) error
+	ReleasePaymentInstruction(ctx context.Context, instructionID string) error
+}
+
+// AuditRepository abstracts the append only audit log contract that any repository should adhere to
+type AuditRepository interface {
+	AppendAudit(ctx context.Context, record *domain.AuditRecord) (*domain.AuditRecord, error)
+	AuditRecords(ctx context.Context, filter application.AuditFilter) ([]*domain.AuditRecord, error)
+	AuditChain(ctx context.Context, afterSequence int64, limit int) ([]*domain.AuditRecord, error)
+}
+
+// QueryRepository abstracts the ledger listing contract that any repository should adhere to.
+// The batch lookups skip IDs that match no record
+type QueryRepository interface {
+	Accounts(ctx context.Context, filter application.AccountFilter) ([]*domain.Account, error)
+	AccountsByID(ctx context.Context, accountIDs []string) ([]*domain.Account, error)
+	AccountBalances(ctx context.Context, accountIDs []string) (map[string]decimal.Decimal, error)
+	Transactions(ctx context.Context, filter application.TransactionFilter) ([]*domain.Transaction, error)
+	TransactionsByID(ctx context.Context, transactionIDs []string) ([]*domain.Transaction, error)
+	Entries(ctx context.Context, filter application.EntryFilter) ([]*domain.AccountEntry, error)
+	EntriesByTransaction(ctx context.Context, transactionIDs []string) ([]*domain.AccountEntry, error)
+}
+
+// FraudRepository abstracts the transfer screening history and review queue contract that any repository should
+// adhere to. Releasing a held transfer posts it in the same database transaction that marks it released
+type FraudRepository interface {
+	TransfersFrom(ctx context.Context, accountID string, since time.Time) ([]*application.TransferRecord, error)
+	HoldTransfer(ctx context.Context, held *domain.HeldTransfer) (*domain.HeldTransfer, error)
+	HeldTransfer(ctx context.Context, heldTransferID string) (*domain.HeldTransfer, error)
+	HeldTransfers(ctx context.Context, status domain.HoldStatus) ([]*domain.HeldTransfer, error)
+	ReleaseHeldTransfer(
+		ctx context.Context,
+		heldTransferID string,
+		actor string,
+		description string,
+		drEntry *domain.AccountEntry,
+		crEntry *domain.AccountEntry,
+	) (*domain.HeldTransfer, error)
+	RejectHeldTransfer(ctx context.Context, heldTransferID string, actor string, reason string) (*domain.HeldTransfer, error)
 }
@@ -0,0 +1,106 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/application"
+	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/domain"
+	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/repository"
+)
+
+// AUDIT_PAGE_SIZE is the default and maximum number of audit records returned by a query
+var AUDIT_PAGE_SIZE = 500
+
+// AuditUsecases defines a contract the audit log usecase adheres to
+type AuditUsecases interface {
+	Record(ctx context.Context, record *domain.AuditRecord) error
+	AuditTrail(ctx context.Context, filter application.AuditFilter) ([]*domain.AuditRecord, error)
+	VerifyChain(ctx context.Context) (*application.AuditVerification, error)
+}
+
+// Audit sets up the audit log business logic and its dependencies
+type Audit struct {
+	Log repository.AuditRepository
+}
+
+// CheckPreconditions ensures all dependencies are injected
+func (a Audit) CheckPreconditions() {
+	if a.Log == nil {
+		log.Panic("audit usecase did not initialize the audit repository")
+	}
+}
+
+// NewAuditUsecases initializes a new audit log usecase
+func NewAuditUsecases(auditRepo repository.AuditRepository) *Audit {
+	a := &Audit{
+		Log: auditRepo,
+	}
+	a.CheckPreconditions()
+	return a
+}
+
+// Record appends a record to the audit log
+func (a Audit) Record(ctx context.Context, record *domain.AuditRecord) error {
+	_, err := a.Log.AppendAudit(ctx, record)
+	return err
+}
+
+// AuditTrail queries the audit log, newest records first
+func (a Audit) AuditTrail(ctx context.Context, filter application.AuditFilter) ([]*domain.AuditRecord, error) {
+	if filter.From != nil && filter.To != nil && filter.To.Before(*filter.From) {
+		return nil, domain.NewValidationError("invalid_period", "the start of the audit period should be before its end")
+	}
+
+	if filter.Limit <= 0 || filter.Limit > AUDIT_PAGE_SIZE {
+		filter.Limit = AUDIT_PAGE_SIZE
+	}
+
+	return a.Log.AuditRecords(ctx, filter)
+}
+
+// VerifyChain recomputes every record's hash, reporting the first record that was altered or is missing
+func (a Audit) VerifyChain(ctx context.Context) (*application.AuditVerification, error) {
+	verification := application.AuditVerification{Verified: true}
+
+	var previous *domain.AuditRecord
+	for {
+		var after int64
+		if previous != nil {
+			after = previous.Sequence
+		}
+
+		records, err := a.Log.AuditChain(ctx, after, AUDIT_PAGE_SIZE)
+		if err != nil {
+			return nil, err
+		}
+		if len(records) == 0 {
+			return &verification, nil
+		}
+
+		for _, record := range records {
+			reason := ""
+			switch {
+			case previous == nil && record.Sequence != 1:
+				reason = "the first audit records are missing"
+			case previous != nil && record.Sequence != previous.Sequence+1:
+				reason = fmt.Sprintf("audit record %d is missing", previous.Sequence+1)
+			case previous != nil && record.PreviousHash != previous.Hash:
+				reason = "the record does not chain onto the record before it"
+			case record.Hash != record.ComputeHash():
+				reason = "the record's content does not match its hash"
+			}
+
+			if reason != "" {
+				verification.Verified = false
+				verification.BrokenAt = record.Sequence
+				verification.Reason = reason
+				return &verification, nil
+			}
+
+			verification.Records++
+			previous = record
+		}
+	}
+}
@@ -0,0 +1,79 @@
+package usecases_test
+
+import (
+	"context"
+	"log"
+	"testing"
+	"time"
+
+	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/application"
+	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/config"
+	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/domain"
+	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/infrastructure/database/postgresql"
+	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/usecases"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+func newTestAuditUsecases() *usecases.Audit {
+	cfg, err := config.Read("")
+	if err != nil {
+		log.Panicf("error reading the testing database configuration: %v", err)
+	}
+	db, err := postgresql.ConnectToDatabase(cfg.Database, zap.NewNop())
+	if err != nil {
+		log.Panicf("error connecting to the testing database: %v", err)
+	}
+
+	return usecases.NewAuditUsecases(postgresql.NewPostgreSQLDatabase(db, nil))
+}
+
+func TestAudit_RecordAndVerify(t *testing.T) {
+	a := newTestAuditUsecases()
+	actor := uuid.New().String()
+	resource := uuid.New().String()
+
+	for i := 0; i < 3; i++ {
+		if err := a.Record(context.Background(), &domain.AuditRecord{
+			Actor:       actor,
+			Method:      "POST",
+			Route:       "/api/v1/transfers",
+			ResourceIDs: resource,
+			StatusCode:  200,
+			Outcome:     domain.AuditSuccess,
+			OccurredAt:  time.Now(),
+		}); err != nil {
+			t.Errorf("Audit.Record() error = %v", err)
+			return
+		}
+	}
+
+	records, err := a.AuditTrail(context.Background(), application.AuditFilter{Actor: actor, Resource: resource})
+	if err != nil {
+		t.Errorf("Audit.AuditTrail() error = %v", err)
+		return
+	}
+	if len(records) != 3 {
+		t.Errorf("expected the actor's 3 records, got %d", len(records))
+		return
+	}
+	if records[0].PreviousHash != records[1].Hash {
+		t.Errorf("expected each record to chain onto the one before it")
+		return
+	}
+
+	verification, err := a.VerifyChain(context.Background())
+	if err != nil {
+		t.Errorf("Audit.VerifyChain() error = %v", err)
+		return
+	}
+	if !verification.Verified {
+		t.Errorf("expected an untouched audit log to verify, broken at %d: %s", verification.BrokenAt, verification.Reason)
+	}
+
+	if err := a.Log.(*postgresql.PostgreSQL).ORM.Model(&domain.AuditRecord{}).
+		Where("sequence = ?", records[0].Sequence).
+		Update("actor", "someone else").Error; err == nil {
+		t.Errorf("expected audit records to be append only")
+	}
+}
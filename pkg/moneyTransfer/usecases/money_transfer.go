@@ -1,26 +1,53 @@
 package usecases
 
 import (
+	"context"
 	"fmt"
 	"log"
+	"time"
 
 	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/application"
 	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/domain"
-	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/domain/data"
+	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/fraud"
+	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/logging"
+	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/metrics"
 	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/repository"
+	"github.com/shopspring/decimal"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
 )
 
+// tracer traces the business operations
+var tracer = otel.Tracer("github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/usecases")
+
+// endSpan marks a span as failed when its operation failed, then ends it
+func endSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
 // MoneyTransferUsecases defines a contract the money transfer usecase adheres to
 type MoneyTransferUsecases interface {
-	CreateCustomerAccount(accountInput application.AccountCreationInput) (*application.AccountInformationOutput, error)
-	Account(accountID string) (*application.AccountInformationOutput, error)
-	Transfer(transferInput application.TransferInput) (*domain.Transaction, error)
+	CreateCustomerAccount(ctx context.Context, accountInput application.AccountCreationInput) (*application.AccountInformationOutput, error)
+	Account(ctx context.Context, accountID string) (*application.AccountInformationOutput, error)
+	Transfer(ctx context.Context, transferInput application.TransferInput) (*domain.Transaction, error)
+	ReverseTransfer(ctx context.Context, transactionID string, reason string) (*domain.Transaction, error)
 }
 
 // MoneyTransfer set up the money transfer business logic and its dependencies
 type MoneyTransfer struct {
-	Create repository.CreateRepository
-	Get    repository.GetRepository
+	Create    repository.CreateRepository
+	Get       repository.GetRepository
+	Fraud     repository.FraudRepository
+	Screening *fraud.Engine
+	Metrics   metrics.TransferRecorder
+	Logger    *zap.Logger
 }
 
 // CheckPreconditions ensures all dependencies are injected
@@ -32,26 +59,63 @@ func (mt MoneyTransfer) CheckPreconditions() {
 	if mt.Get == nil {
 		log.Panic("money transfer usecase did not initialize the get repository")
 	}
+
+	if mt.Fraud == nil {
+		log.Panic("money transfer usecase did not initialize the fraud repository")
+	}
+
+	if mt.Screening == nil {
+		log.Panic("money transfer usecase did not initialize the fraud screening")
+	}
+
+	if mt.Metrics == nil {
+		log.Panic("money transfer usecase did not initialize the transfer metrics")
+	}
+
+	if mt.Logger == nil {
+		log.Panic("money transfer usecase did not initialize the logger")
+	}
 }
 
 // NewMoneyTransferUsecases initializes a new money transfer business usecase
 func NewMoneyTransferUsecases(
 	createRepo repository.CreateRepository,
 	getRepo repository.GetRepository,
+	fraudRepo repository.FraudRepository,
+	screening *fraud.Engine,
+	transferMetrics metrics.TransferRecorder,
+	logger *zap.Logger,
 ) *MoneyTransfer {
 	mt := &MoneyTransfer{
-		Create: createRepo,
-		Get:    getRepo,
+		Create:    createRepo,
+		Get:       getRepo,
+		Fraud:     fraudRepo,
+		Screening: screening,
+		Metrics:   transferMetrics,
+		Logger:    logger,
 	}
 	mt.CheckPreconditions()
 	return mt
 }
 
 // CreateCustomerAccount creates a new customer's account
-func (mt MoneyTransfer) CreateCustomerAccount(accountInput application.AccountCreationInput) (*application.AccountInformationOutput, error) {
+func (mt MoneyTransfer) CreateCustomerAccount(ctx context.Context, accountInput application.AccountCreationInput) (output *application.AccountInformationOutput, err error) {
+	ctx, span := tracer.Start(ctx, "MoneyTransfer.CreateCustomerAccount", trace.WithAttributes(
+		attribute.String("account.header", string(accountInput.Header)),
+	))
+	defer func() { endSpan(span, err) }()
+
 	depositAmount := accountInput.Amount
 	if depositAmount == nil {
-		return nil, fmt.Errorf("a deposit amount should be provided for a new account")
+		return nil, domain.NewValidationError("missing_amount", "a deposit amount should be provided for a new account")
+	}
+
+	if accountInput.Currency == nil || !domain.IsKnownCurrency(*accountInput.Currency) {
+		return nil, domain.NewValidationError("invalid_currency", "a new account should be held in one of %v", domain.CURRENCIES)
+	}
+
+	if !domain.IsCustomerHeader(accountInput.Header) {
+		return nil, domain.NewValidationError("invalid_header", "a new account should be grouped under one of %v", domain.CUSTOMER_HEADERS)
 	}
 
 	accountInfo := domain.Account{
@@ -69,12 +133,12 @@ func (mt MoneyTransfer) CreateCustomerAccount(accountInput application.AccountCr
 		accountInfo.BalanceType = domain.Debit
 	}
 
-	account, err := mt.Create.CreateAccount(&accountInfo)
+	account, err := mt.Create.CreateAccount(ctx, &accountInfo)
 	if err != nil {
 		return nil, err
 	}
 
-	systemAccount, err := mt.Account(data.SYSTEM_CASH_ACCOUNT)
+	systemAccount, err := mt.Get.SystemAccount(ctx, domain.FundingRole, account.Currency)
 	if err != nil {
 		return nil, err
 	}
@@ -85,47 +149,175 @@ func (mt MoneyTransfer) CreateCustomerAccount(accountInput application.AccountCr
 		Amount:             accountInput.Amount,
 	}
 
-	if _, err = mt.Transfer(transferInput); err != nil {
+	if _, err = mt.Transfer(ctx, transferInput); err != nil {
 		return nil, err
 	}
 
-	return mt.Account(account.UUID)
+	logging.For(ctx, mt.Logger).Info("account opened", zap.String("account_id", account.UUID), zap.String("currency", string(account.Currency)))
+	return mt.Account(ctx, account.UUID)
 }
 
 // Account retrieves an account given it identifier
-func (mt MoneyTransfer) Account(accountID string) (*application.AccountInformationOutput, error) {
-	return mt.Get.Account(accountID)
+func (mt MoneyTransfer) Account(ctx context.Context, accountID string) (account *application.AccountInformationOutput, err error) {
+	ctx, span := tracer.Start(ctx, "MoneyTransfer.Account", trace.WithAttributes(attribute.String("account.id", accountID)))
+	defer func() { endSpan(span, err) }()
+
+	return mt.Get.Account(ctx, accountID)
 }
 
-// Transfer handles the movement of money from a source to a destination account
-func (mt MoneyTransfer) Transfer(transferInput application.TransferInput) (*domain.Transaction, error) {
+// Transfer handles the movement of money from a source to a destination account, recording its outcome
+func (mt MoneyTransfer) Transfer(ctx context.Context, transferInput application.TransferInput) (*domain.Transaction, error) {
+	var currency domain.CurrencyType
+	if transferInput.SourceAccount != nil {
+		currency = transferInput.SourceAccount.Currency
+	}
+	var amount decimal.Decimal
+	if transferInput.Amount != nil {
+		amount = *transferInput.Amount
+	}
+
+	ctx, span := tracer.Start(ctx, "MoneyTransfer.Transfer", trace.WithAttributes(
+		attribute.String("transfer.currency", string(currency)),
+		attribute.String("transfer.amount", amount.String()),
+	))
+	transaction, err := mt.transfer(ctx, transferInput)
+	outcome := metrics.Outcome(err)
+	span.SetAttributes(attribute.String("transfer.outcome", outcome))
+	endSpan(span, err)
+
+	mt.Metrics.ObserveTransfer(currency, outcome, amount)
+	logger := logging.For(ctx, mt.Logger).With(
+		zap.String("currency", string(currency)),
+		zap.String("amount", amount.String()),
+		zap.String("outcome", outcome),
+	)
+	switch {
+	case err == nil:
+		logger.Info("transfer posted", zap.String("transaction_id", transaction.UUID))
+	case domain.IsKind(err, domain.Held):
+		logger.Info("transfer held for review", zap.Error(err))
+	default:
+		logger.Warn("transfer failed", zap.Error(err))
+	}
+	return transaction, err
+}
+
+func (mt MoneyTransfer) transfer(ctx context.Context, transferInput application.TransferInput) (*domain.Transaction, error) {
+	if err := checkTransfer(transferInput); err != nil {
+		return nil, err
+	}
+
+	verdict, err := mt.Screening.Screen(ctx, fraud.Transfer{
+		Source:      transferInput.SourceAccount,
+		Destination: transferInput.DestinationAccount,
+		Amount:      *transferInput.Amount,
+		At:          time.Now(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := mt.enforce(ctx, transferInput, verdict); err != nil {
+		return nil, err
+	}
+
+	// The balance checked above is checked again under the source account's lock, in case money moved
+	// out of it in the meantime from another process
+	description, drEntry, crEntry := transferEntries(transferInput)
+	return mt.Create.CreateTransfer(ctx, description, &drEntry, &crEntry)
+}
+
+// enforce takes the action fraud screening decided on, holding the transfer for review or blocking it.
+// Blocked transfers are not told which rule flagged them, so that the rules can not be probed
+func (mt MoneyTransfer) enforce(ctx context.Context, transferInput application.TransferInput, verdict fraud.Verdict) error {
+	trace.SpanFromContext(ctx).SetAttributes(attribute.String("transfer.screening", string(verdict.Action)))
+	logger := logging.For(ctx, mt.Logger)
+	for _, finding := range verdict.Findings {
+		logger.Info("transfer flagged",
+			zap.String("rule", finding.Rule),
+			zap.String("action", string(finding.Action)),
+			zap.String("reason", finding.Reason),
+			zap.String("source_account_id", transferInput.SourceAccount.UUID),
+			zap.String("destination_account_id", transferInput.DestinationAccount.UUID),
+		)
+	}
+
+	switch verdict.Action {
+	case domain.Block:
+		return domain.NewForbiddenError("transfer_blocked", "the transfer was blocked by fraud screening")
+
+	case domain.Hold:
+		held, err := mt.Fraud.HoldTransfer(ctx, &domain.HeldTransfer{
+			SourceAccountID:      transferInput.SourceAccount.UUID,
+			DestinationAccountID: transferInput.DestinationAccount.UUID,
+			Amount:               *transferInput.Amount,
+			Currency:             transferInput.SourceAccount.Currency,
+			Rules:                verdict.Rules(),
+			Reasons:              verdict.Reasons(),
+		})
+		if err != nil {
+			return err
+		}
+		return domain.NewHeldError(held.UUID)
+	}
+	return nil
+}
+
+// checkTransfer validates a transfer and ensures its source account can cover it
+func checkTransfer(transferInput application.TransferInput) error {
 	sourceAccount := transferInput.SourceAccount
 	destinationAccount := transferInput.DestinationAccount
 
 	if sourceAccount == nil {
-		return nil, fmt.Errorf("source account is required")
+		return domain.NewValidationError("missing_source_account", "source account is required")
 	}
 
 	if destinationAccount == nil {
-		return nil, fmt.Errorf("destination account is required")
+		return domain.NewValidationError("missing_destination_account", "destination account is required")
+	}
+
+	if sourceAccount.UUID == destinationAccount.UUID {
+		return domain.NewValidationError("same_account", "source and destination accounts should differ")
+	}
+
+	if sourceAccount.Currency != destinationAccount.Currency {
+		return domain.NewValidationError("currency_mismatch", "a %s account can not transfer to a %s account",
+			sourceAccount.Currency,
+			destinationAccount.Currency,
+		)
 	}
 
 	amount := transferInput.Amount
+	if amount == nil || !amount.IsPositive() {
+		return domain.NewValidationError("invalid_amount", "a transfer amount should be more than zero")
+	}
+
+	if !sourceAccount.Currency.Fits(*amount) {
+		return domain.NewValidationError("invalid_amount", "%v has more decimals than %s allows", amount, sourceAccount.Currency)
+	}
+
 	sourceAccountBalance := sourceAccount.Balance
 
 	if !sourceAccount.IsSystemAccount && amount.GreaterThan(*sourceAccountBalance) {
-		return nil, fmt.Errorf("%v is more than %s current account's balance of %v",
+		return domain.NewInsufficientFundsError("%v is more than %s current account's balance of %v",
 			amount,
 			sourceAccount.Name,
 			sourceAccountBalance,
 		)
 	}
+	return nil
+}
+
+// transferEntries builds the description and entries posting a checked transfer
+func transferEntries(transferInput application.TransferInput) (string, domain.AccountEntry, domain.AccountEntry) {
+	sourceAccount := transferInput.SourceAccount
+	destinationAccount := transferInput.DestinationAccount
+	amount := transferInput.Amount
 
 	var description string
 	var crEntry domain.AccountEntry
 	var drEntry domain.AccountEntry
 	switch sourceAccount.Header {
-	case domain.Deposit, domain.Cash:
+	case domain.Deposit, domain.Cash, domain.Suspense, domain.Fee:
 		description = fmt.Sprintf("Deposit of %v from account %s to account %s",
 			amount,
 			sourceAccount.Number,
@@ -142,5 +334,28 @@ func (mt MoneyTransfer) Transfer(transferInput application.TransferInput) (*doma
 		}
 	}
 
-	return mt.Create.CreateTransaction(description, &drEntry, &crEntry)
+	return description, drEntry, crEntry
+}
+
+// ReverseTransfer offsets a posted transfer, returning the money to its source account
+func (mt MoneyTransfer) ReverseTransfer(ctx context.Context, transactionID string, reason string) (reversal *domain.Transaction, err error) {
+	ctx, span := tracer.Start(ctx, "MoneyTransfer.ReverseTransfer", trace.WithAttributes(attribute.String("transaction.id", transactionID)))
+	defer func() { endSpan(span, err) }()
+
+	if transactionID == "" {
+		return nil, domain.NewValidationError("missing_transaction", "transaction to reverse is required")
+	}
+
+	var description string
+	if reason != "" {
+		description = fmt.Sprintf("Reversal of transaction %s: %s", transactionID, reason)
+	}
+
+	reversal, err = mt.Create.ReverseTransaction(ctx, transactionID, description)
+	if err != nil {
+		return nil, err
+	}
+
+	logging.For(ctx, mt.Logger).Info("transfer reversed", zap.String("transaction_id", transactionID), zap.String("reversal_id", reversal.UUID))
+	return reversal, nil
 }
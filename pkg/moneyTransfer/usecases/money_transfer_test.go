@@ -1,26 +1,44 @@
 package usecases_test
 
 import (
+	"context"
+	"errors"
 	"log"
 	"testing"
+	"time"
 
 	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/application"
+	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/config"
 	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/domain"
+	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/fraud"
 	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/infrastructure/database/postgresql"
+	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/metrics"
+	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/repository"
 	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/usecases"
 	"github.com/google/uuid"
 	"github.com/shopspring/decimal"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
 )
 
 func newTestMoneyTransferUsecases() *usecases.MoneyTransfer {
-	db, err := postgresql.ConnectToDatabase()
+	cfg, err := config.Read("")
+	if err != nil {
+		log.Panicf("error reading the testing database configuration: %v", err)
+	}
+	db, err := postgresql.ConnectToDatabase(cfg.Database, zap.NewNop())
 	if err != nil {
 		log.Panicf("error connecting to the testing database: %v", err)
 	}
-	create := postgresql.NewPostgreSQLDatabase(db)
-	get := postgresql.NewPostgreSQLDatabase(db)
+	create := postgresql.NewPostgreSQLDatabase(db, nil)
+	get := postgresql.NewPostgreSQLDatabase(db, nil)
 
-	return usecases.NewMoneyTransferUsecases(create, get)
+	return usecases.NewMoneyTransferUsecases(create, get, create, fraud.NewEngine(create), metrics.Noop{}, zap.NewNop())
 }
 func TestMoneyTransfer_CreateCustomerAccount(t *testing.T) {
 	amount := decimal.NewFromInt(100)
@@ -100,11 +118,11 @@ func TestMoneyTransfer_CreateCustomerAccount(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			mt := newTestMoneyTransferUsecases()
 			// Create system accounts
-			if err := mt.Create.CreateSystemAccount(); err != nil {
+			if err := mt.Create.CreateSystemAccount(context.Background()); err != nil {
 				log.Panicf("system error, unable to create default account(s): %v", err)
 			}
 
-			account, err := mt.CreateCustomerAccount(tt.args.accountInput)
+			account, err := mt.CreateCustomerAccount(context.Background(), tt.args.accountInput)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("MoneyTransfer.CreateCustomerAccount() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -123,7 +141,7 @@ func TestMoneyTransfer_CreateCustomerAccount(t *testing.T) {
 					return
 				}
 
-				account, err := mt.Get.Account(account.UUID)
+				account, err := mt.Get.Account(context.Background(), account.UUID)
 				if err != nil {
 					t.Errorf(err.Error())
 					return
@@ -153,12 +171,12 @@ func TestMoneyTransfer_Transfer(t *testing.T) {
 		Header:       domain.Deposit,
 	}
 
-	srcAccount, err := mt.CreateCustomerAccount(accountInput)
+	srcAccount, err := mt.CreateCustomerAccount(context.Background(), accountInput)
 	if err != nil {
 		t.Errorf("unable to create test src account: %v", err)
 	}
 
-	destAccount, err := mt.CreateCustomerAccount(accountInput)
+	destAccount, err := mt.CreateCustomerAccount(context.Background(), accountInput)
 	if err != nil {
 		t.Errorf("unable to create test dest account: %v", err)
 	}
@@ -187,6 +205,24 @@ func TestMoneyTransfer_Transfer(t *testing.T) {
 		Amount:             &largeAmount,
 	}
 
+	sameAccountInput := application.TransferInput{
+		SourceAccount:      srcAccount,
+		DestinationAccount: srcAccount,
+		Amount:             &transferAmount,
+	}
+
+	preciseAmount := decimal.RequireFromString("1.005")
+	tooPreciseInput := application.TransferInput{
+		SourceAccount:      srcAccount,
+		DestinationAccount: destAccount,
+		Amount:             &preciseAmount,
+	}
+
+	noAmountInput := application.TransferInput{
+		SourceAccount:      srcAccount,
+		DestinationAccount: destAccount,
+	}
+
 	type args struct {
 		transferInput application.TransferInput
 	}
@@ -202,6 +238,27 @@ func TestMoneyTransfer_Transfer(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name: "sad case - same source and destination account",
+			args: args{
+				transferInput: sameAccountInput,
+			},
+			wantErr: true,
+		},
+		{
+			name: "sad case - more decimals than the currency allows",
+			args: args{
+				transferInput: tooPreciseInput,
+			},
+			wantErr: true,
+		},
+		{
+			name: "sad case - missing amount",
+			args: args{
+				transferInput: noAmountInput,
+			},
+			wantErr: true,
+		},
 		{
 			name: "sad case - non-existent account(dest)",
 			args: args{
@@ -226,7 +283,7 @@ func TestMoneyTransfer_Transfer(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			transaction, err := mt.Transfer(tt.args.transferInput)
+			transaction, err := mt.Transfer(context.Background(), tt.args.transferInput)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("MoneyTransfer.Transfer() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -255,7 +312,7 @@ func TestMoneyTransfer_Account(t *testing.T) {
 		Header:       domain.Deposit,
 	}
 
-	srcAccount, err := mt.CreateCustomerAccount(accountInput)
+	srcAccount, err := mt.CreateCustomerAccount(context.Background(), accountInput)
 	if err != nil {
 		t.Errorf("unable to create test src account: %v", err)
 		return
@@ -286,7 +343,7 @@ func TestMoneyTransfer_Account(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			account, err := mt.Account(tt.args.accountID)
+			account, err := mt.Account(context.Background(), tt.args.accountID)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("MoneyTransfer.Account() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -302,3 +359,220 @@ func TestMoneyTransfer_Account(t *testing.T) {
 		})
 	}
 }
+
+type recordedTransfer struct {
+	currency domain.CurrencyType
+	outcome  string
+	amount   decimal.Decimal
+}
+
+type transferRecorder struct {
+	transfers []recordedTransfer
+}
+
+func (r *transferRecorder) ObserveTransfer(currency domain.CurrencyType, outcome string, amount decimal.Decimal) {
+	r.transfers = append(r.transfers, recordedTransfer{currency, outcome, amount})
+}
+
+type stubCreateRepository struct {
+	repository.CreateRepository
+}
+
+func (stubCreateRepository) CreateTransfer(context.Context, string, *domain.AccountEntry, *domain.AccountEntry) (*domain.Transaction, error) {
+	return &domain.Transaction{}, nil
+}
+
+// stubFraudRepository holds transfers in memory, with no transfers posted before
+type stubFraudRepository struct {
+	repository.FraudRepository
+	held []*domain.HeldTransfer
+}
+
+func (*stubFraudRepository) TransfersFrom(context.Context, string, time.Time) ([]*application.TransferRecord, error) {
+	return nil, nil
+}
+
+func (r *stubFraudRepository) HoldTransfer(ctx context.Context, held *domain.HeldTransfer) (*domain.HeldTransfer, error) {
+	held.UUID = uuid.NewString()
+	held.Status = domain.HoldPending
+	r.held = append(r.held, held)
+	return held, nil
+}
+
+func TestMoneyTransfer_CreateCustomerAccountHeader(t *testing.T) {
+	mt := usecases.NewMoneyTransferUsecases(stubCreateRepository{}, struct{ repository.GetRepository }{}, struct{ repository.FraudRepository }{}, fraud.NewEngine(nil), metrics.Noop{}, zap.NewNop())
+	currency := domain.Kenyan
+	amount := decimal.NewFromInt(100)
+
+	for _, header := range []domain.HeaderType{domain.Cash, domain.Suspense, domain.Fee} {
+		t.Run(string(header), func(t *testing.T) {
+			_, err := mt.CreateCustomerAccount(context.Background(), application.AccountCreationInput{
+				CustomerName: "Jane",
+				Amount:       &amount,
+				Currency:     &currency,
+				Header:       header,
+			})
+			var failure *domain.Error
+			if !errors.As(err, &failure) || failure.Code != "invalid_header" {
+				t.Errorf("expected customers not to open %s accounts, got %v", header, err)
+			}
+		})
+	}
+}
+
+func TestMoneyTransfer_TransferCurrencies(t *testing.T) {
+	balance := decimal.NewFromInt(500)
+	source := &application.AccountInformationOutput{UUID: uuid.NewString(), Currency: domain.Kenyan, Header: domain.Deposit, Balance: &balance}
+	amount := decimal.NewFromInt(100)
+
+	tests := []struct {
+		name     string
+		currency domain.CurrencyType
+		wantCode string
+	}{
+		{name: "happy case - same currency", currency: domain.Kenyan},
+		{name: "sad case - another currency", currency: domain.Ugandan, wantCode: "currency_mismatch"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mt := usecases.NewMoneyTransferUsecases(stubCreateRepository{}, struct{ repository.GetRepository }{}, struct{ repository.FraudRepository }{}, fraud.NewEngine(nil), metrics.Noop{}, zap.NewNop())
+			destination := &application.AccountInformationOutput{UUID: uuid.NewString(), Currency: tt.currency, Header: domain.Deposit}
+
+			_, err := mt.Transfer(context.Background(), application.TransferInput{SourceAccount: source, DestinationAccount: destination, Amount: &amount})
+			var failure *domain.Error
+			if tt.wantCode == "" && err != nil || tt.wantCode != "" && (!errors.As(err, &failure) || failure.Code != tt.wantCode) {
+				t.Errorf("MoneyTransfer.Transfer() error = %v, want code %q", err, tt.wantCode)
+			}
+		})
+	}
+}
+
+func TestMoneyTransfer_TransferScreening(t *testing.T) {
+	balance := decimal.NewFromInt(5000)
+	opened := time.Now().Add(-time.Hour)
+	source := &application.AccountInformationOutput{UUID: uuid.NewString(), Currency: domain.Kenyan, Header: domain.Deposit, Balance: &balance, CreatedAt: &opened}
+	destination := &application.AccountInformationOutput{UUID: uuid.NewString(), Currency: domain.Kenyan, Header: domain.Deposit, CreatedAt: &opened}
+	amount := decimal.NewFromInt(1000)
+
+	tests := []struct {
+		name     string
+		action   domain.FraudAction
+		wantCode string
+		wantHeld int
+	}{
+		{name: "happy case - flagged and allowed", action: domain.Allow},
+		{name: "sad case - held for review", action: domain.Hold, wantCode: "transfer_held", wantHeld: 1},
+		{name: "sad case - blocked", action: domain.Block, wantCode: "transfer_blocked"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := &stubFraudRepository{}
+			rule := fraud.NewAccountRule{Action: tt.action, MaxAge: 72 * time.Hour, Amounts: fraud.Amounts{domain.Kenyan: decimal.NewFromInt(500)}}
+			mt := usecases.NewMoneyTransferUsecases(stubCreateRepository{}, struct{ repository.GetRepository }{}, store, fraud.NewEngine(store, rule), metrics.Noop{}, zap.NewNop())
+
+			_, err := mt.Transfer(context.Background(), application.TransferInput{SourceAccount: source, DestinationAccount: destination, Amount: &amount})
+			var failure *domain.Error
+			if tt.wantCode == "" && err != nil || tt.wantCode != "" && (!errors.As(err, &failure) || failure.Code != tt.wantCode) {
+				t.Fatalf("MoneyTransfer.Transfer() error = %v, want code %q", err, tt.wantCode)
+			}
+			if len(store.held) != tt.wantHeld {
+				t.Fatalf("expected %d held transfers, got %d", tt.wantHeld, len(store.held))
+			}
+			if tt.wantHeld > 0 && (store.held[0].Rules != fraud.RULE_NEW_ACCOUNT || !store.held[0].Amount.Equal(amount)) {
+				t.Errorf("expected the transfer to be held with the rule that flagged it, got %+v", store.held[0])
+			}
+		})
+	}
+}
+
+func TestMoneyTransfer_TransferMetrics(t *testing.T) {
+	balance := decimal.NewFromInt(50)
+	source := &application.AccountInformationOutput{UUID: uuid.NewString(), Currency: domain.Kenyan, Header: domain.Deposit, Balance: &balance}
+	destination := &application.AccountInformationOutput{UUID: uuid.NewString(), Currency: domain.Kenyan, Header: domain.Deposit}
+	amount := func(value int64) *decimal.Decimal {
+		d := decimal.NewFromInt(value)
+		return &d
+	}
+
+	tests := []struct {
+		name    string
+		input   application.TransferInput
+		want    recordedTransfer
+		wantErr bool
+	}{
+		{
+			name:  "happy case - successful transfer",
+			input: application.TransferInput{SourceAccount: source, DestinationAccount: destination, Amount: amount(20)},
+			want:  recordedTransfer{domain.Kenyan, metrics.SUCCESS, decimal.NewFromInt(20)},
+		},
+		{
+			name:    "sad case - insufficient funds",
+			input:   application.TransferInput{SourceAccount: source, DestinationAccount: destination, Amount: amount(80)},
+			want:    recordedTransfer{domain.Kenyan, "insufficient_funds", decimal.NewFromInt(80)},
+			wantErr: true,
+		},
+		{
+			name:    "sad case - missing source account",
+			input:   application.TransferInput{DestinationAccount: destination},
+			want:    recordedTransfer{"", "validation", decimal.Zero},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := &transferRecorder{}
+			mt := usecases.NewMoneyTransferUsecases(stubCreateRepository{}, struct{ repository.GetRepository }{}, struct{ repository.FraudRepository }{}, fraud.NewEngine(nil), recorder, zap.NewNop())
+
+			if _, err := mt.Transfer(context.Background(), tt.input); (err != nil) != tt.wantErr {
+				t.Fatalf("MoneyTransfer.Transfer() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if len(recorder.transfers) != 1 {
+				t.Fatalf("expected a single recorded transfer, got %v", recorder.transfers)
+			}
+			got := recorder.transfers[0]
+			if got.currency != tt.want.currency || got.outcome != tt.want.outcome || !got.amount.Equal(tt.want.amount) {
+				t.Errorf("recorded %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMoneyTransfer_TransferSpans(t *testing.T) {
+	spans := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(spans))
+	otel.SetTracerProvider(provider)
+	defer otel.SetTracerProvider(trace.NewNoopTracerProvider())
+
+	ctx, parent := provider.Tracer("test").Start(context.Background(), "POST /transfer")
+	balance := decimal.NewFromInt(50)
+	amount := decimal.NewFromInt(80)
+	mt := usecases.NewMoneyTransferUsecases(stubCreateRepository{}, struct{ repository.GetRepository }{}, struct{ repository.FraudRepository }{}, fraud.NewEngine(nil), metrics.Noop{}, zap.NewNop())
+	_, err := mt.Transfer(ctx, application.TransferInput{
+		SourceAccount:      &application.AccountInformationOutput{UUID: uuid.NewString(), Currency: domain.Kenyan, Header: domain.Deposit, Balance: &balance},
+		DestinationAccount: &application.AccountInformationOutput{UUID: uuid.NewString(), Currency: domain.Kenyan, Header: domain.Deposit},
+		Amount:             &amount,
+	})
+	parent.End()
+	if err == nil {
+		t.Fatalf("expected the transfer to fail for insufficient funds")
+	}
+
+	ended := spans.Ended()
+	if len(ended) != 2 || ended[0].Name() != "MoneyTransfer.Transfer" {
+		t.Fatalf("expected a MoneyTransfer.Transfer span, got %v", ended)
+	}
+	span := ended[0]
+	if span.Parent().SpanID() != parent.SpanContext().SpanID() {
+		t.Errorf("expected the transfer span to be a child of the request's span")
+	}
+	if span.Status().Code != codes.Error {
+		t.Errorf("expected the failed transfer to mark its span as failed, got %v", span.Status())
+	}
+	attributes := map[attribute.Key]string{}
+	for _, kv := range span.Attributes() {
+		attributes[kv.Key] = kv.Value.Emit()
+	}
+	if attributes["transfer.outcome"] != "insufficient_funds" || attributes["transfer.currency"] != string(domain.Kenyan) {
+		t.Errorf("unexpected span attributes %v", attributes)
+	}
+}
@@ -0,0 +1,286 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync"
+
+	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/application"
+	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/domain"
+	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/logging"
+	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/metrics"
+	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/repository"
+	"go.uber.org/zap"
+)
+
+// ISO 20022 reason codes reported for rejected credit transfer instructions
+var (
+	// IncorrectAccountNumber is reported when the debtor or creditor account is unknown
+	IncorrectAccountNumber = "AC01"
+
+	// ClosedAccountNumber is reported when the debtor or creditor account is not active
+	ClosedAccountNumber = "AC04"
+
+	// ZeroAmount is reported for instructions without a positive amount
+	ZeroAmount = "AM01"
+
+	// NotAllowedCurrency is reported when the instructed currency differs from the accounts'
+	NotAllowedCurrency = "AM03"
+
+	// InsufficientFunds is reported when the debtor account can not cover the amount
+	InsufficientFunds = "AM04"
+
+	// Duplication is reported for an end to end identification repeated within a message
+	Duplication = "AM05"
+
+	// InvalidAmount is reported for amounts with more decimals than the currency allows
+	InvalidAmount = "AM12"
+
+	// MissingEndToEndID is reported for instructions without an end to end identification
+	MissingEndToEndID = "RC09"
+
+	// Narrative is reported when the ledger refuses an otherwise valid instruction
+	Narrative = "NARR"
+)
+
+// PaymentInitiationUsecases defines a contract the payment initiation usecase adheres to
+type PaymentInitiationUsecases interface {
+	ExecuteCreditTransfers(ctx context.Context, input *application.PaymentInitiationInput) ([]*application.CreditTransferResult, error)
+}
+
+// PaymentInitiation sets up the execution of imported credit transfer instructions and its dependencies
+type PaymentInitiation struct {
+	Transfer MoneyTransferUsecases
+	Get      repository.GetRepository
+	Payments repository.PaymentRepository
+	Logger   *zap.Logger
+
+	// Lock serializes each instruction's transfer with the money moved elsewhere, not the whole message
+	Lock sync.Locker
+}
+
+// CheckPreconditions ensures all dependencies are injected
+func (p PaymentInitiation) CheckPreconditions() {
+	if p.Transfer == nil {
+		log.Panic("payment initiation usecase did not initialize the money transfer usecase")
+	}
+
+	if p.Get == nil {
+		log.Panic("payment initiation usecase did not initialize the get repository")
+	}
+
+	if p.Payments == nil {
+		log.Panic("payment initiation usecase did not initialize the payment repository")
+	}
+
+	if p.Logger == nil {
+		log.Panic("payment initiation usecase did not initialize the logger")
+	}
+
+	if p.Lock == nil {
+		log.Panic("payment initiation usecase did not initialize the money movement lock")
+	}
+}
+
+// NewPaymentInitiationUsecases initializes a new payment initiation usecase
+func NewPaymentInitiationUsecases(
+	transfer MoneyTransferUsecases,
+	getRepo repository.GetRepository,
+	paymentRepo repository.PaymentRepository,
+	logger *zap.Logger,
+	lock sync.Locker,
+) *PaymentInitiation {
+	p := &PaymentInitiation{
+		Transfer: transfer,
+		Get:      getRepo,
+		Payments: paymentRepo,
+		Logger:   logger,
+		Lock:     lock,
+	}
+	p.CheckPreconditions()
+	return p
+}
+
+// ExecuteCreditTransfers executes each instruction as a transfer between the accounts it names.
+// Instructions are independent, a rejected instruction does not prevent the others from executing.
+// An instruction held for fraud review is pending, neither executed nor rejected.
+// A message is executed once, and an instruction its initiator sent in an earlier message is rejected as a duplicate
+func (p PaymentInitiation) ExecuteCreditTransfers(ctx context.Context, input *application.PaymentInitiationInput) ([]*application.CreditTransferResult, error) {
+	if input == nil || input.MessageID == "" {
+		return nil, domain.NewValidationError("missing_message_id", "payment initiation message identification is required")
+	}
+
+	if len(input.Instructions) == 0 {
+		return nil, domain.NewValidationError("empty_payment_initiation", "payment initiation %s has no instructions", input.MessageID)
+	}
+
+	if input.Initiator == "" {
+		return nil, domain.NewValidationError("missing_initiator", "the party initiating payment initiation %s should be identified", input.MessageID)
+	}
+
+	if _, err := p.Payments.RecordPaymentMessage(ctx, &domain.PaymentMessage{
+		Initiator: input.Initiator,
+		MessageID: input.MessageID,
+	}); err != nil {
+		return nil, err
+	}
+
+	seen := map[string]bool{}
+	accepted, pending := 0, 0
+	var results []*application.CreditTransferResult
+	for _, instruction := range input.Instructions {
+		result := p.execute(ctx, input, instruction, seen)
+		seen[instruction.EndToEndID] = true
+		results = append(results, result)
+		switch {
+		case result.Accepted:
+			accepted++
+		case result.HeldTransferID != "":
+			pending++
+		}
+	}
+
+	logging.For(ctx, p.Logger).Info("credit transfers executed",
+		zap.String("message_id", input.MessageID),
+		zap.Int("accepted", accepted),
+		zap.Int("pending", pending),
+		zap.Int("rejected", len(results)-accepted-pending),
+	)
+	return results, nil
+}
+
+// execute validates and executes a single credit transfer instruction
+func (p PaymentInitiation) execute(
+	ctx context.Context,
+	input *application.PaymentInitiationInput,
+	instruction *application.CreditTransferInstruction,
+	seen map[string]bool,
+) *application.CreditTransferResult {
+	reject := func(code string, reason string, args ...interface{}) *application.CreditTransferResult {
+		return &application.CreditTransferResult{
+			Instruction: instruction,
+			ReasonCode:  code,
+			Reason:      fmt.Sprintf(reason, args...),
+		}
+	}
+	// The status report goes back to the initiating party, the causes of internal errors are only logged
+	refuse := func(err error) *application.CreditTransferResult {
+		cause := domain.AsError(err)
+		if cause.Kind == domain.Internal {
+			logging.For(ctx, p.Logger).Error("credit transfer failed",
+				zap.String("end_to_end_id", instruction.EndToEndID),
+				zap.Error(err),
+			)
+			return reject(Narrative, "the transfer could not be executed")
+		}
+		return reject(Narrative, "%s", cause.Message)
+	}
+
+	if instruction.EndToEndID == "" {
+		return reject(MissingEndToEndID, "end to end identification is required")
+	}
+
+	if seen[instruction.EndToEndID] {
+		return reject(Duplication, "end to end identification %s is repeated in the message", instruction.EndToEndID)
+	}
+
+	if !instruction.Amount.IsPositive() {
+		return reject(ZeroAmount, "amount %v should be more than zero", instruction.Amount)
+	}
+
+	if !instruction.Currency.Fits(instruction.Amount) {
+		return reject(InvalidAmount, "amount %v has more decimals than %s allows", instruction.Amount, instruction.Currency)
+	}
+
+	debtor, err := p.Get.AccountByNumber(ctx, instruction.DebtorAccountNumber)
+	if err != nil {
+		return reject(IncorrectAccountNumber, "debtor account %s was not found", instruction.DebtorAccountNumber)
+	}
+
+	creditor, err := p.Get.AccountByNumber(ctx, instruction.CreditorAccountNumber)
+	if err != nil {
+		return reject(IncorrectAccountNumber, "creditor account %s was not found", instruction.CreditorAccountNumber)
+	}
+
+	if debtor.UUID == creditor.UUID {
+		return reject(IncorrectAccountNumber, "debtor and creditor account %s should differ", debtor.Number)
+	}
+
+	if !debtor.Active || !creditor.Active {
+		return reject(ClosedAccountNumber, "debtor and creditor accounts should be active")
+	}
+
+	if debtor.Currency != instruction.Currency || creditor.Currency != instruction.Currency {
+		return reject(NotAllowedCurrency, "%s transfers are not allowed between %s accounts %s and %s",
+			instruction.Currency,
+			debtor.Currency,
+			debtor.Number,
+			creditor.Number,
+		)
+	}
+
+	if !debtor.IsSystemAccount && instruction.Amount.GreaterThan(*debtor.Balance) {
+		return reject(InsufficientFunds, "debtor account %s has insufficient funds", debtor.Number)
+	}
+
+	claim, err := p.Payments.ClaimPaymentInstruction(ctx, &domain.PaymentInstruction{
+		Initiator:            input.Initiator,
+		MessageID:            input.MessageID,
+		PaymentInformationID: instruction.PaymentInformationID,
+		EndToEndID:           instruction.EndToEndID,
+	})
+	if domain.IsKind(err, domain.Conflict) {
+		return reject(Duplication, "end to end identification %s has already been executed", instruction.EndToEndID)
+	}
+	if err != nil {
+		return refuse(err)
+	}
+
+	var transaction *domain.Transaction
+	if err = metrics.LockContext(ctx, p.Lock); err == nil {
+		transaction, err = p.Transfer.Transfer(ctx, application.TransferInput{
+			SourceAccount:      debtor,
+			DestinationAccount: creditor,
+			Amount:             &instruction.Amount,
+		})
+		p.Lock.Unlock()
+	}
+	if domain.IsKind(err, domain.Held) {
+		claim.HeldTransferID = &domain.AsError(err).HeldTransferID
+		p.complete(ctx, claim)
+		return &application.CreditTransferResult{
+			Instruction:    instruction,
+			HeldTransferID: *claim.HeldTransferID,
+		}
+	}
+	if err != nil {
+		// The instruction was not executed, it may be sent again
+		if releaseErr := p.Payments.ReleasePaymentInstruction(ctx, claim.UUID); releaseErr != nil {
+			logging.For(ctx, p.Logger).Error("unable to release a payment instruction",
+				zap.String("end_to_end_id", instruction.EndToEndID),
+				zap.Error(releaseErr),
+			)
+		}
+		return refuse(err)
+	}
+
+	claim.TransactionID = &transaction.UUID
+	p.complete(ctx, claim)
+	return &application.CreditTransferResult{
+		Instruction:   instruction,
+		Accepted:      true,
+		TransactionID: transaction.UUID,
+	}
+}
+
+// complete records what an executed instruction was executed as. The instruction stays claimed when it can not be
+// recorded, it is not executed again either way
+func (p PaymentInitiation) complete(ctx context.Context, claim *domain.PaymentInstruction) {
+	if err := p.Payments.CompletePaymentInstruction(ctx, claim); err != nil {
+		logging.For(ctx, p.Logger).Error("unable to record an executed payment instruction",
+			zap.String("end_to_end_id", claim.EndToEndID),
+			zap.Error(err),
+		)
+	}
+}
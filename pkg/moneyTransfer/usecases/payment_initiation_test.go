@@ -0,0 +1,422 @@
+package usecases_test
+
+import (
+	"context"
+	"errors"
+	"log"
+	"sync"
+	"testing"
+
+	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/application"
+	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/config"
+	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/domain"
+	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/infrastructure/database/postgresql"
+	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/repository"
+	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/usecases"
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"go.uber.org/zap"
+)
+
+func newTestPaymentInitiationUsecases() *usecases.PaymentInitiation {
+	cfg, err := config.Read("")
+	if err != nil {
+		log.Panicf("error reading the testing database configuration: %v", err)
+	}
+	db, err := postgresql.ConnectToDatabase(cfg.Database, zap.NewNop())
+	if err != nil {
+		log.Panicf("error connecting to the testing database: %v", err)
+	}
+	get := postgresql.NewPostgreSQLDatabase(db, nil)
+	payments := postgresql.NewPostgreSQLDatabase(db, nil)
+
+	return usecases.NewPaymentInitiationUsecases(newTestMoneyTransferUsecases(), get, payments, zap.NewNop(), &sync.Mutex{})
+}
+
+func TestPaymentInitiation_ExecuteCreditTransfers(t *testing.T) {
+	p := newTestPaymentInitiationUsecases()
+	mt := newTestMoneyTransferUsecases()
+	if err := mt.Create.CreateSystemAccount(context.Background()); err != nil {
+		t.Errorf("unable to create system accounts: %v", err)
+		return
+	}
+
+	amount := decimal.NewFromInt(100)
+	currency := domain.Kenyan
+	accountInput := application.AccountCreationInput{
+		CustomerName: "John Doe",
+		Amount:       &amount,
+		Currency:     &currency,
+		Header:       domain.Deposit,
+	}
+	debtor, err := mt.CreateCustomerAccount(context.Background(), accountInput)
+	if err != nil {
+		t.Errorf("unable to create test debtor account: %v", err)
+		return
+	}
+	creditor, err := mt.CreateCustomerAccount(context.Background(), accountInput)
+	if err != nil {
+		t.Errorf("unable to create test creditor account: %v", err)
+		return
+	}
+
+	instruction := func(endToEndID string, amount string, currency domain.CurrencyType, creditor string) *application.CreditTransferInstruction {
+		return &application.CreditTransferInstruction{
+			PaymentInformationID:  "PMT-1",
+			EndToEndID:            endToEndID,
+			DebtorAccountNumber:   debtor.Number,
+			CreditorAccountNumber: creditor,
+			Amount:                decimal.RequireFromString(amount),
+			Currency:              currency,
+		}
+	}
+
+	tests := []struct {
+		name           string
+		instruction    *application.CreditTransferInstruction
+		wantAccepted   bool
+		wantReasonCode string
+	}{
+		{
+			name:         "happy case: funds transferred",
+			instruction:  instruction("E2E-1", "40", domain.Kenyan, creditor.Number),
+			wantAccepted: true,
+		},
+		{
+			name:           "sad case: repeated end to end identification",
+			instruction:    instruction("E2E-1", "1", domain.Kenyan, creditor.Number),
+			wantReasonCode: usecases.Duplication,
+		},
+		{
+			name:           "sad case: insufficient funds after the first transfer",
+			instruction:    instruction("E2E-2", "70", domain.Kenyan, creditor.Number),
+			wantReasonCode: usecases.InsufficientFunds,
+		},
+		{
+			name:           "sad case: unknown creditor",
+			instruction:    instruction("E2E-3", "1", domain.Kenyan, "AC-DOES-NOT-EXIST"),
+			wantReasonCode: usecases.IncorrectAccountNumber,
+		},
+		{
+			name:           "sad case: currency mismatch",
+			instruction:    instruction("E2E-4", "1", domain.Ugandan, creditor.Number),
+			wantReasonCode: usecases.NotAllowedCurrency,
+		},
+		{
+			name:           "sad case: too many decimals",
+			instruction:    instruction("E2E-5", "1.005", domain.Kenyan, creditor.Number),
+			wantReasonCode: usecases.InvalidAmount,
+		},
+	}
+
+	input := application.PaymentInitiationInput{MessageID: "MSG-1", Initiator: uuid.NewString()}
+	for _, tt := range tests {
+		input.Instructions = append(input.Instructions, tt.instruction)
+	}
+
+	results, err := p.ExecuteCreditTransfers(context.Background(), &input)
+	if err != nil {
+		t.Errorf("PaymentInitiation.ExecuteCreditTransfers() error = %v", err)
+		return
+	}
+	if len(results) != len(tests) {
+		t.Errorf("expected a result per instruction, got %d", len(results))
+		return
+	}
+
+	for i, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := results[i]
+			if result.Accepted != tt.wantAccepted {
+				t.Errorf("expected accepted %v, got %v (%s)", tt.wantAccepted, result.Accepted, result.Reason)
+				return
+			}
+			if result.ReasonCode != tt.wantReasonCode {
+				t.Errorf("expected reason code %s, got %s", tt.wantReasonCode, result.ReasonCode)
+			}
+			if tt.wantAccepted && result.TransactionID == "" {
+				t.Errorf("expected the ledger transaction of an accepted instruction")
+			}
+		})
+	}
+
+	creditorAccount, err := mt.Account(context.Background(), creditor.UUID)
+	if err != nil {
+		t.Errorf("unable to get creditor account: %v", err)
+		return
+	}
+	if !creditorAccount.Balance.Equal(decimal.NewFromInt(140)) {
+		t.Errorf("expected only the accepted instruction to move funds, got a balance of %v", creditorAccount.Balance)
+	}
+}
+
+// stubAccountsByNumber serves accounts by their number
+type stubAccountsByNumber struct {
+	repository.GetRepository
+	accounts map[string]*application.AccountInformationOutput
+}
+
+func (s stubAccountsByNumber) AccountByNumber(ctx context.Context, number string) (*application.AccountInformationOutput, error) {
+	if account, ok := s.accounts[number]; ok {
+		return account, nil
+	}
+	return nil, domain.NewNotFoundError("account_not_found", "account %s", number)
+}
+
+// holdingMoneyTransfer holds every transfer for review
+type holdingMoneyTransfer struct {
+	usecases.MoneyTransferUsecases
+}
+
+func (holdingMoneyTransfer) Transfer(context.Context, application.TransferInput) (*domain.Transaction, error) {
+	return nil, domain.NewHeldError("HELD-1")
+}
+
+func TestPaymentInitiation_HeldInstruction(t *testing.T) {
+	balance := decimal.NewFromInt(100)
+	accounts := stubAccountsByNumber{accounts: map[string]*application.AccountInformationOutput{
+		"AC-1": {UUID: uuid.NewString(), Number: "AC-1", Active: true, Currency: domain.Kenyan, Balance: &balance},
+		"AC-2": {UUID: uuid.NewString(), Number: "AC-2", Active: true, Currency: domain.Kenyan, Balance: &balance},
+	}}
+	p := usecases.NewPaymentInitiationUsecases(holdingMoneyTransfer{}, accounts, newMemoryPayments(), zap.NewNop(), &sync.Mutex{})
+
+	results, err := p.ExecuteCreditTransfers(context.Background(), &application.PaymentInitiationInput{
+		MessageID: "MSG-1",
+		Initiator: "customer",
+		Instructions: []*application.CreditTransferInstruction{{
+			EndToEndID:            "E2E-1",
+			DebtorAccountNumber:   "AC-1",
+			CreditorAccountNumber: "AC-2",
+			Amount:                decimal.NewFromInt(10),
+			Currency:              domain.Kenyan,
+		}},
+	})
+	if err != nil {
+		t.Fatalf("PaymentInitiation.ExecuteCreditTransfers() error = %v", err)
+	}
+
+	result := results[0]
+	if result.Accepted || result.ReasonCode != "" || result.HeldTransferID != "HELD-1" {
+		t.Errorf("expected the instruction to be pending review as HELD-1, got %+v", result)
+	}
+}
+
+// failingMoneyTransfer fails every transfer with err
+type failingMoneyTransfer struct {
+	usecases.MoneyTransferUsecases
+	err error
+}
+
+func (f failingMoneyTransfer) Transfer(context.Context, application.TransferInput) (*domain.Transaction, error) {
+	return nil, f.err
+}
+
+func TestPaymentInitiation_RefusedInstructionReason(t *testing.T) {
+	balance := decimal.NewFromInt(100)
+	accounts := stubAccountsByNumber{accounts: map[string]*application.AccountInformationOutput{
+		"AC-1": {UUID: uuid.NewString(), Number: "AC-1", Active: true, Currency: domain.Kenyan, Balance: &balance},
+		"AC-2": {UUID: uuid.NewString(), Number: "AC-2", Active: true, Currency: domain.Kenyan, Balance: &balance},
+	}}
+
+	tests := []struct {
+		name       string
+		err        error
+		wantReason string
+	}{
+		{
+			name:       "refused by the ledger",
+			err:        domain.NewConflictError("period_closed", "the period containing the transfer is closed"),
+			wantReason: "the period containing the transfer is closed",
+		},
+		{
+			name:       "internal error",
+			err:        errors.New("pq: password authentication failed for user \"ledger\""),
+			wantReason: "the transfer could not be executed",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := usecases.NewPaymentInitiationUsecases(failingMoneyTransfer{err: tt.err}, accounts, newMemoryPayments(), zap.NewNop(), &sync.Mutex{})
+			results, err := p.ExecuteCreditTransfers(context.Background(), &application.PaymentInitiationInput{
+				MessageID: "MSG-1",
+				Initiator: "customer",
+				Instructions: []*application.CreditTransferInstruction{{
+					EndToEndID:            "E2E-1",
+					DebtorAccountNumber:   "AC-1",
+					CreditorAccountNumber: "AC-2",
+					Amount:                decimal.NewFromInt(10),
+					Currency:              domain.Kenyan,
+				}},
+			})
+			if err != nil {
+				t.Fatalf("PaymentInitiation.ExecuteCreditTransfers() error = %v", err)
+			}
+
+			result := results[0]
+			if result.ReasonCode != usecases.Narrative || result.Reason != tt.wantReason {
+				t.Errorf("expected the instruction to be rejected with %q, got %s %q", tt.wantReason, result.ReasonCode, result.Reason)
+			}
+		})
+	}
+}
+
+// countingLocker counts the times it is locked
+type countingLocker struct {
+	sync.Mutex
+	locked int
+}
+
+func (c *countingLocker) Lock() {
+	c.Mutex.Lock()
+	c.locked++
+}
+
+func TestPaymentInitiation_LocksEachInstruction(t *testing.T) {
+	balance := decimal.NewFromInt(100)
+	accounts := stubAccountsByNumber{accounts: map[string]*application.AccountInformationOutput{
+		"AC-1": {UUID: uuid.NewString(), Number: "AC-1", Active: true, Currency: domain.Kenyan, Balance: &balance},
+		"AC-2": {UUID: uuid.NewString(), Number: "AC-2", Active: true, Currency: domain.Kenyan, Balance: &balance},
+	}}
+	lock := &countingLocker{}
+	p := usecases.NewPaymentInitiationUsecases(holdingMoneyTransfer{}, accounts, newMemoryPayments(), zap.NewNop(), lock)
+
+	instruction := func(endToEndID string) *application.CreditTransferInstruction {
+		return &application.CreditTransferInstruction{
+			EndToEndID:            endToEndID,
+			DebtorAccountNumber:   "AC-1",
+			CreditorAccountNumber: "AC-2",
+			Amount:                decimal.NewFromInt(10),
+			Currency:              domain.Kenyan,
+		}
+	}
+	if _, err := p.ExecuteCreditTransfers(context.Background(), &application.PaymentInitiationInput{
+		MessageID:    "MSG-1",
+		Initiator:    "customer",
+		Instructions: []*application.CreditTransferInstruction{instruction("E2E-1"), instruction("E2E-2")},
+	}); err != nil {
+		t.Fatalf("PaymentInitiation.ExecuteCreditTransfers() error = %v", err)
+	}
+
+	if lock.locked != 2 {
+		t.Errorf("expected the money movement lock to be taken once per instruction, it was taken %d times", lock.locked)
+	}
+}
+
+// memoryPayments keeps payment messages and instructions in memory
+type memoryPayments struct {
+	mu           sync.Mutex
+	messages     map[string]bool
+	instructions map[string]*domain.PaymentInstruction
+}
+
+func newMemoryPayments() *memoryPayments {
+	return &memoryPayments{messages: map[string]bool{}, instructions: map[string]*domain.PaymentInstruction{}}
+}
+
+func (m *memoryPayments) RecordPaymentMessage(ctx context.Context, message *domain.PaymentMessage) (*domain.PaymentMessage, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	key := message.Initiator + "/" + message.MessageID
+	if m.messages[key] {
+		return nil, domain.NewConflictError("duplicate_message", "payment initiation %s has already been received", message.MessageID)
+	}
+	m.messages[key] = true
+	return message, nil
+}
+
+func (m *memoryPayments) ClaimPaymentInstruction(ctx context.Context, instruction *domain.PaymentInstruction) (*domain.PaymentInstruction, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	key := instruction.Initiator + "/" + instruction.EndToEndID
+	if _, ok := m.instructions[key]; ok {
+		return nil, domain.NewConflictError("duplicate_instruction", "end to end identification %s has already been executed", instruction.EndToEndID)
+	}
+	instruction.UUID = key
+	m.instructions[key] = instruction
+	return instruction, nil
+}
+
+func (m *memoryPayments) CompletePaymentInstruction(ctx context.Context, instruction *domain.PaymentInstruction) error {
+	return nil
+}
+
+func (m *memoryPayments) ReleasePaymentInstruction(ctx context.Context, instructionID string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.instructions, instructionID)
+	return nil
+}
+
+// postingMoneyTransfer posts every transfer, counting them
+type postingMoneyTransfer struct {
+	usecases.MoneyTransferUsecases
+	posted *int
+}
+
+func (p postingMoneyTransfer) Transfer(context.Context, application.TransferInput) (*domain.Transaction, error) {
+	*p.posted++
+	return &domain.Transaction{AbstractBase: domain.AbstractBase{UUID: uuid.NewString()}}, nil
+}
+
+func TestPaymentInitiation_SentAgain(t *testing.T) {
+	balance := decimal.NewFromInt(100)
+	accounts := stubAccountsByNumber{accounts: map[string]*application.AccountInformationOutput{
+		"AC-1": {UUID: uuid.NewString(), Number: "AC-1", Active: true, Currency: domain.Kenyan, Balance: &balance},
+		"AC-2": {UUID: uuid.NewString(), Number: "AC-2", Active: true, Currency: domain.Kenyan, Balance: &balance},
+	}}
+	payments := newMemoryPayments()
+	posted := 0
+	p := usecases.NewPaymentInitiationUsecases(postingMoneyTransfer{posted: &posted}, accounts, payments, zap.NewNop(), &sync.Mutex{})
+	refusing := usecases.NewPaymentInitiationUsecases(
+		failingMoneyTransfer{err: domain.NewConflictError("period_closed", "the period is closed")},
+		accounts,
+		payments,
+		zap.NewNop(),
+		&sync.Mutex{},
+	)
+
+	message := func(messageID string, endToEndIDs ...string) *application.PaymentInitiationInput {
+		input := &application.PaymentInitiationInput{MessageID: messageID, Initiator: "customer"}
+		for _, endToEndID := range endToEndIDs {
+			input.Instructions = append(input.Instructions, &application.CreditTransferInstruction{
+				EndToEndID:            endToEndID,
+				DebtorAccountNumber:   "AC-1",
+				CreditorAccountNumber: "AC-2",
+				Amount:                decimal.NewFromInt(10),
+				Currency:              domain.Kenyan,
+			})
+		}
+		return input
+	}
+
+	if _, err := p.ExecuteCreditTransfers(context.Background(), message("MSG-1", "E2E-1")); err != nil {
+		t.Fatalf("PaymentInitiation.ExecuteCreditTransfers() error = %v", err)
+	}
+	if _, err := p.ExecuteCreditTransfers(context.Background(), message("MSG-1", "E2E-1")); !domain.IsKind(err, domain.Conflict) {
+		t.Errorf("expected a message sent again to be refused, got %v", err)
+	}
+
+	results, err := p.ExecuteCreditTransfers(context.Background(), message("MSG-2", "E2E-1", "E2E-2"))
+	if err != nil {
+		t.Fatalf("PaymentInitiation.ExecuteCreditTransfers() error = %v", err)
+	}
+	if results[0].ReasonCode != usecases.Duplication || !results[1].Accepted {
+		t.Errorf("expected only the instruction not sent before to be executed, got %+v and %+v", results[0], results[1])
+	}
+
+	// An instruction that was refused was not executed, it may be sent again
+	if _, err := refusing.ExecuteCreditTransfers(context.Background(), message("MSG-3", "E2E-3")); err != nil {
+		t.Fatalf("PaymentInitiation.ExecuteCreditTransfers() error = %v", err)
+	}
+	results, err = p.ExecuteCreditTransfers(context.Background(), message("MSG-4", "E2E-3"))
+	if err != nil {
+		t.Fatalf("PaymentInitiation.ExecuteCreditTransfers() error = %v", err)
+	}
+	if !results[0].Accepted {
+		t.Errorf("expected a refused instruction sent again to be executed, got %+v", results[0])
+	}
+
+	if posted != 3 {
+		t.Errorf("expected 3 transfers to be posted, got %d", posted)
+	}
+}
@@ -0,0 +1,302 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/application"
+	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/domain"
+	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/logging"
+	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/repository"
+	"github.com/shopspring/decimal"
+	"go.uber.org/zap"
+)
+
+// PeriodUsecases defines a contract the accounting period usecase adheres to
+type PeriodUsecases interface {
+	EndOfDay(ctx context.Context, date time.Time, actor string) (*domain.AccountingPeriod, error)
+	ClosePeriod(ctx context.Context, periodType domain.PeriodType, date time.Time, actor string) (*domain.AccountingPeriod, error)
+	Periods(ctx context.Context) ([]*domain.AccountingPeriod, error)
+	Period(ctx context.Context, periodID string) (*application.PeriodOutput, error)
+	RequestReopen(ctx context.Context, periodID string, actor string, reason string) (*domain.AccountingPeriod, error)
+	ApproveReopen(ctx context.Context, periodID string, actor string) (*domain.AccountingPeriod, error)
+	RejectReopen(ctx context.Context, periodID string, actor string, reason string) (*domain.AccountingPeriod, error)
+	PostAdjustment(ctx context.Context, input application.AdjustmentInput) (*domain.Transaction, error)
+}
+
+// AccountingPeriods sets up the period closing business logic and its dependencies
+type AccountingPeriods struct {
+	Create repository.CreateRepository
+	Get    repository.GetRepository
+	Ledger repository.PeriodRepository
+	Logger *zap.Logger
+
+	// Location is the business time zone, days and months are closed from midnight in it
+	Location *time.Location
+}
+
+// CheckPreconditions ensures all dependencies are injected
+func (ap AccountingPeriods) CheckPreconditions() {
+	if ap.Create == nil {
+		log.Panic("accounting periods usecase did not initialize the create repository")
+	}
+
+	if ap.Get == nil {
+		log.Panic("accounting periods usecase did not initialize the get repository")
+	}
+
+	if ap.Ledger == nil {
+		log.Panic("accounting periods usecase did not initialize the period repository")
+	}
+
+	if ap.Logger == nil {
+		log.Panic("accounting periods usecase did not initialize the logger")
+	}
+
+	if ap.Location == nil {
+		log.Panic("accounting periods usecase did not initialize the business time zone")
+	}
+}
+
+// NewPeriodUsecases initializes a new accounting periods usecase
+func NewPeriodUsecases(
+	createRepo repository.CreateRepository,
+	getRepo repository.GetRepository,
+	periodRepo repository.PeriodRepository,
+	logger *zap.Logger,
+	location *time.Location,
+) *AccountingPeriods {
+	ap := &AccountingPeriods{
+		Create:   createRepo,
+		Get:      getRepo,
+		Ledger:   periodRepo,
+		Logger:   logger,
+		Location: location,
+	}
+	ap.CheckPreconditions()
+	return ap
+}
+
+// EndOfDay closes the business day containing the given date
+func (ap AccountingPeriods) EndOfDay(ctx context.Context, date time.Time, actor string) (*domain.AccountingPeriod, error) {
+	return ap.ClosePeriod(ctx, domain.Day, date, actor)
+}
+
+// ClosePeriod snapshots all account balances and closes the day or month containing the given date
+func (ap AccountingPeriods) ClosePeriod(ctx context.Context, periodType domain.PeriodType, date time.Time, actor string) (*domain.AccountingPeriod, error) {
+	if periodType != domain.Day && periodType != domain.Month {
+		return nil, domain.NewValidationError("invalid_period_type", "%q is not a valid period type", periodType)
+	}
+
+	if actor == "" {
+		return nil, domain.NewValidationError("missing_actor", "the user closing the period should be identified")
+	}
+
+	start, end := domain.PeriodBounds(periodType, date, ap.Location)
+	if end.After(time.Now()) {
+		return nil, domain.NewValidationError("period_not_ended", "the %s period starting %s has not ended yet", periodType, start.Format("2006-01-02"))
+	}
+
+	period, err := ap.Ledger.ClosePeriod(ctx, &domain.AccountingPeriod{
+		Type:      periodType,
+		StartDate: start,
+		EndDate:   end,
+	}, actor)
+	if err != nil {
+		return nil, err
+	}
+
+	logging.For(ctx, ap.Logger).Info("period closed",
+		zap.String("period_id", period.UUID),
+		zap.String("period_type", string(periodType)),
+		zap.Time("start_date", start),
+		zap.String("actor", actor),
+	)
+	return period, nil
+}
+
+// Periods lists all accounting periods
+func (ap AccountingPeriods) Periods(ctx context.Context) ([]*domain.AccountingPeriod, error) {
+	return ap.Ledger.Periods(ctx)
+}
+
+// Period retrieves an accounting period with its closing balances and audit trail
+func (ap AccountingPeriods) Period(ctx context.Context, periodID string) (*application.PeriodOutput, error) {
+	period, err := ap.Ledger.Period(ctx, periodID)
+	if err != nil {
+		return nil, err
+	}
+
+	snapshots, err := ap.Ledger.BalanceSnapshots(ctx, periodID)
+	if err != nil {
+		return nil, err
+	}
+
+	audit, err := ap.Ledger.PeriodAudit(ctx, periodID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &application.PeriodOutput{
+		Period:    period,
+		Snapshots: snapshots,
+		Audit:     audit,
+	}, nil
+}
+
+// RequestReopen asks for a closed period to be reopened. The period stays closed until an admin approves
+func (ap AccountingPeriods) RequestReopen(ctx context.Context, periodID string, actor string, reason string) (*domain.AccountingPeriod, error) {
+	if reason == "" {
+		return nil, domain.NewValidationError("missing_reason", "a reason should be provided to reopen a period")
+	}
+
+	audit := &domain.PeriodAuditEntry{
+		Action: domain.ReopenRequestedAction,
+		Actor:  actor,
+		Reason: reason,
+	}
+	return ap.updatePeriod(ctx, periodID, audit, func(period *domain.AccountingPeriod) error {
+		if period.Status != domain.PeriodClosed {
+			return domain.NewConflictError("period_not_closed", "only a closed period can be reopened, period %s is %s", periodID, period.Status)
+		}
+
+		period.Status = domain.PeriodReopenRequested
+		period.ReopenRequestedBy = actor
+		period.ReopenReason = reason
+		return nil
+	})
+}
+
+// ApproveReopen reopens a period. The approver should not be the user who asked for it to be reopened
+func (ap AccountingPeriods) ApproveReopen(ctx context.Context, periodID string, actor string) (*domain.AccountingPeriod, error) {
+	audit := &domain.PeriodAuditEntry{
+		Action: domain.ReopenApprovedAction,
+		Actor:  actor,
+	}
+	return ap.updatePeriod(ctx, periodID, audit, func(period *domain.AccountingPeriod) error {
+		if period.Status != domain.PeriodReopenRequested {
+			return domain.NewConflictError("no_reopen_request", "period %s has no pending reopen request", periodID)
+		}
+
+		if period.ReopenRequestedBy == actor {
+			return domain.NewForbiddenError("same_approver", "a reopen request should be approved by someone other than its requester")
+		}
+
+		audit.Reason = period.ReopenReason
+		period.Status = domain.PeriodOpen
+		return nil
+	})
+}
+
+// RejectReopen declines a request to reopen a period, leaving it closed
+func (ap AccountingPeriods) RejectReopen(ctx context.Context, periodID string, actor string, reason string) (*domain.AccountingPeriod, error) {
+	audit := &domain.PeriodAuditEntry{
+		Action: domain.ReopenRejectedAction,
+		Actor:  actor,
+		Reason: reason,
+	}
+	return ap.updatePeriod(ctx, periodID, audit, func(period *domain.AccountingPeriod) error {
+		if period.Status != domain.PeriodReopenRequested {
+			return domain.NewConflictError("no_reopen_request", "period %s has no pending reopen request", periodID)
+		}
+
+		period.Status = domain.PeriodClosed
+		period.ReopenRequestedBy = ""
+		period.ReopenReason = ""
+		return nil
+	})
+}
+
+// updatePeriod changes a period's status, recording the change in its audit trail and the logs.
+// The status the update expects is checked on the locked period, so concurrent changes are refused rather than both applied
+func (ap AccountingPeriods) updatePeriod(
+	ctx context.Context,
+	periodID string,
+	audit *domain.PeriodAuditEntry,
+	update func(period *domain.AccountingPeriod) error,
+) (*domain.AccountingPeriod, error) {
+	updated, err := ap.Ledger.UpdatePeriod(ctx, periodID, audit, update)
+	if err != nil {
+		return nil, err
+	}
+
+	logging.For(ctx, ap.Logger).Info("period updated",
+		zap.String("period_id", updated.UUID),
+		zap.String("status", string(updated.Status)),
+		zap.String("action", string(audit.Action)),
+		zap.String("actor", audit.Actor),
+	)
+	return updated, nil
+}
+
+// PostAdjustment posts a manual journal entry. Adjustments dated in a closed period are
+// booked at the start of the next open period
+func (ap AccountingPeriods) PostAdjustment(ctx context.Context, input application.AdjustmentInput) (*domain.Transaction, error) {
+	if input.Amount == nil || input.Amount.LessThanOrEqual(decimal.Zero) {
+		return nil, domain.NewValidationError("invalid_amount", "an adjustment should be of a positive amount")
+	}
+
+	if input.DebitAccountID == input.CreditAccountID {
+		return nil, domain.NewValidationError("same_account", "an adjustment should debit and credit different accounts")
+	}
+
+	debitAccount, err := ap.Get.Account(ctx, input.DebitAccountID)
+	if err != nil {
+		return nil, err
+	}
+
+	creditAccount, err := ap.Get.Account(ctx, input.CreditAccountID)
+	if err != nil {
+		return nil, err
+	}
+
+	if debitAccount.Currency != creditAccount.Currency {
+		return nil, domain.NewValidationError("currency_mismatch", "an adjustment can not move value between %s and %s accounts",
+			debitAccount.Currency,
+			creditAccount.Currency,
+		)
+	}
+
+	effectiveDate := time.Now()
+	if input.EffectiveDate != nil {
+		effectiveDate = *input.EffectiveDate
+	}
+	effectiveDate, err = ap.Ledger.NextOpenDate(ctx, effectiveDate)
+	if err != nil {
+		return nil, err
+	}
+
+	description := input.Description
+	if description == "" {
+		description = fmt.Sprintf("Adjustment of %v from account %s to account %s",
+			input.Amount,
+			creditAccount.Number,
+			debitAccount.Number,
+		)
+	}
+
+	drEntry := domain.AccountEntry{
+		DebitAmount:   *input.Amount,
+		AccountID:     debitAccount.UUID,
+		EffectiveDate: &effectiveDate,
+	}
+	crEntry := domain.AccountEntry{
+		CreditAmount:  *input.Amount,
+		AccountID:     creditAccount.UUID,
+		EffectiveDate: &effectiveDate,
+	}
+
+	transaction, err := ap.Create.CreateTransaction(ctx, description, &drEntry, &crEntry)
+	if err != nil {
+		return nil, err
+	}
+
+	logging.For(ctx, ap.Logger).Info("adjustment posted",
+		zap.String("transaction_id", transaction.UUID),
+		zap.Time("effective_date", effectiveDate),
+		zap.Bool("moved_to_open_period", input.EffectiveDate != nil && !effectiveDate.Equal(*input.EffectiveDate)),
+	)
+	return transaction, nil
+}
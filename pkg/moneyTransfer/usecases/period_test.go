@@ -0,0 +1,301 @@
+package usecases_test
+
+import (
+	"context"
+	"log"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/application"
+	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/config"
+	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/domain"
+	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/infrastructure/database/postgresql"
+	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/usecases"
+	"github.com/brianvoe/gofakeit"
+	"github.com/shopspring/decimal"
+	"go.uber.org/zap"
+)
+
+func newTestPeriodUsecases() *usecases.AccountingPeriods {
+	cfg, err := config.Read("")
+	if err != nil {
+		log.Panicf("error reading the testing database configuration: %v", err)
+	}
+	db, err := postgresql.ConnectToDatabase(cfg.Database, zap.NewNop())
+	if err != nil {
+		log.Panicf("error connecting to the testing database: %v", err)
+	}
+	create := postgresql.NewPostgreSQLDatabase(db, nil)
+	get := postgresql.NewPostgreSQLDatabase(db, nil)
+	period := postgresql.NewPostgreSQLDatabase(db, nil)
+
+	return usecases.NewPeriodUsecases(create, get, period, zap.NewNop(), cfg.Accounting.Location())
+}
+
+// randomPastDay picks a day long before any test data so that runs do not close each other's days
+func randomPastDay() time.Time {
+	return time.Date(1970, 1, 1, 12, 0, 0, 0, time.Local).AddDate(0, 0, gofakeit.Number(0, 365*30))
+}
+
+func TestAccountingPeriods_ClosePeriod(t *testing.T) {
+	ap := newTestPeriodUsecases()
+	day := randomPastDay()
+
+	type args struct {
+		periodType domain.PeriodType
+		date       time.Time
+		actor      string
+	}
+	tests := []struct {
+		name    string
+		args    args
+		wantErr bool
+	}{
+		{
+			name: "happy case - end of day",
+			args: args{
+				periodType: domain.Day,
+				date:       day,
+				actor:      "operator",
+			},
+			wantErr: false,
+		},
+		{
+			name: "sad case - day already closed",
+			args: args{
+				periodType: domain.Day,
+				date:       day,
+				actor:      "operator",
+			},
+			wantErr: true,
+		},
+		{
+			name: "sad case - day has not ended",
+			args: args{
+				periodType: domain.Day,
+				date:       time.Now(),
+				actor:      "operator",
+			},
+			wantErr: true,
+		},
+		{
+			name: "sad case - unknown period type",
+			args: args{
+				periodType: "YEAR",
+				date:       day,
+				actor:      "operator",
+			},
+			wantErr: true,
+		},
+		{
+			name: "sad case - no actor",
+			args: args{
+				periodType: domain.Month,
+				date:       day,
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			period, err := ap.ClosePeriod(context.Background(), tt.args.periodType, tt.args.date, tt.args.actor)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("AccountingPeriods.ClosePeriod() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !tt.wantErr {
+				if period.Status != domain.PeriodClosed {
+					t.Errorf("expected the period to be closed")
+					return
+				}
+				if !period.Contains(tt.args.date) {
+					t.Errorf("expected the closed period to contain %v", tt.args.date)
+					return
+				}
+			}
+		})
+	}
+}
+
+func TestAccountingPeriods_PostingIntoClosedPeriod(t *testing.T) {
+	ap := newTestPeriodUsecases()
+	mt := newTestMoneyTransferUsecases()
+	if err := mt.Create.CreateSystemAccount(context.Background()); err != nil {
+		t.Errorf("unable to create system accounts: %v", err)
+		return
+	}
+
+	amount := decimal.NewFromInt(100)
+	currency := domain.Kenyan
+	accountInput := application.AccountCreationInput{
+		CustomerName: "John Doe",
+		Amount:       &amount,
+		Currency:     &currency,
+		Header:       domain.Deposit,
+	}
+	srcAccount, err := mt.CreateCustomerAccount(context.Background(), accountInput)
+	if err != nil {
+		t.Errorf("unable to create test src account: %v", err)
+		return
+	}
+	destAccount, err := mt.CreateCustomerAccount(context.Background(), accountInput)
+	if err != nil {
+		t.Errorf("unable to create test dest account: %v", err)
+		return
+	}
+
+	day := randomPastDay()
+	period, err := ap.EndOfDay(context.Background(), day, "operator")
+	if err != nil {
+		t.Errorf("unable to close test day: %v", err)
+		return
+	}
+
+	adjustmentAmount := decimal.NewFromInt(10)
+	entries := func() (*domain.AccountEntry, *domain.AccountEntry) {
+		return &domain.AccountEntry{
+			DebitAmount:   adjustmentAmount,
+			AccountID:     destAccount.UUID,
+			EffectiveDate: &day,
+		}, &domain.AccountEntry{
+			CreditAmount:  adjustmentAmount,
+			AccountID:     srcAccount.UUID,
+			EffectiveDate: &day,
+		}
+	}
+
+	drEntry, crEntry := entries()
+	if _, err := mt.Create.CreateTransaction(context.Background(), "Backdated transfer", drEntry, crEntry); err == nil {
+		t.Errorf("expected a posting into a closed period to be rejected")
+		return
+	}
+
+	transaction, err := ap.PostAdjustment(context.Background(), application.AdjustmentInput{
+		DebitAccountID:  destAccount.UUID,
+		CreditAccountID: srcAccount.UUID,
+		Amount:          &adjustmentAmount,
+		EffectiveDate:   &day,
+	})
+	if err != nil {
+		t.Errorf("AccountingPeriods.PostAdjustment() error = %v", err)
+		return
+	}
+	if transaction == nil {
+		t.Errorf("expected the adjustment to be posted to the next open period")
+		return
+	}
+
+	if _, err := ap.RequestReopen(context.Background(), period.UUID, "operator", "late entries"); err != nil {
+		t.Errorf("AccountingPeriods.RequestReopen() error = %v", err)
+		return
+	}
+
+	if _, err := ap.ApproveReopen(context.Background(), period.UUID, "operator"); err == nil {
+		t.Errorf("expected a requester to be unable to approve their own reopen request")
+		return
+	}
+
+	reopened, err := ap.ApproveReopen(context.Background(), period.UUID, "admin")
+	if err != nil {
+		t.Errorf("AccountingPeriods.ApproveReopen() error = %v", err)
+		return
+	}
+	if reopened.Status != domain.PeriodOpen {
+		t.Errorf("expected the period to be reopened")
+		return
+	}
+
+	drEntry, crEntry = entries()
+	if _, err := mt.Create.CreateTransaction(context.Background(), "Backdated transfer", drEntry, crEntry); err != nil {
+		t.Errorf("expected a posting into a reopened period to succeed: %v", err)
+		return
+	}
+
+	output, err := ap.Period(context.Background(), period.UUID)
+	if err != nil {
+		t.Errorf("AccountingPeriods.Period() error = %v", err)
+		return
+	}
+	if len(output.Audit) != 3 {
+		t.Errorf("expected close, reopen request and approval to be audited, got %d entries", len(output.Audit))
+		return
+	}
+	if len(output.Snapshots) == 0 {
+		t.Errorf("expected balances to be snapshotted on closing")
+		return
+	}
+}
+
+func TestAccountingPeriods_ConcurrentReopenReviews(t *testing.T) {
+	ap := newTestPeriodUsecases()
+	period, err := ap.EndOfDay(context.Background(), randomPastDay(), "operator")
+	if err != nil {
+		t.Fatalf("unable to close test day: %v", err)
+	}
+	if _, err := ap.RequestReopen(context.Background(), period.UUID, "operator", "late entries"); err != nil {
+		t.Fatalf("AccountingPeriods.RequestReopen() error = %v", err)
+	}
+
+	// An approval racing a rejection should see the request only once
+	reviews := []func() error{
+		func() error {
+			_, err := ap.ApproveReopen(context.Background(), period.UUID, "admin")
+			return err
+		},
+		func() error {
+			_, err := ap.RejectReopen(context.Background(), period.UUID, "other-admin", "not needed")
+			return err
+		},
+	}
+	errs := make(chan error, len(reviews))
+	var wg sync.WaitGroup
+	for _, review := range reviews {
+		wg.Add(1)
+		go func(review func() error) {
+			defer wg.Done()
+			errs <- review()
+		}(review)
+	}
+	wg.Wait()
+	close(errs)
+
+	succeeded := 0
+	for err := range errs {
+		switch {
+		case err == nil:
+			succeeded++
+		case !domain.IsKind(err, domain.Conflict):
+			t.Errorf("expected the losing review to conflict, got %v", err)
+		}
+	}
+	if succeeded != 1 {
+		t.Errorf("expected exactly one review to succeed, %d did", succeeded)
+	}
+
+	output, err := ap.Period(context.Background(), period.UUID)
+	if err != nil {
+		t.Fatalf("AccountingPeriods.Period() error = %v", err)
+	}
+	if len(output.Audit) != 3 {
+		t.Errorf("expected close, reopen request and a single review to be audited, got %d entries", len(output.Audit))
+	}
+}
+
+func TestAccountingPeriods_ClosePeriodInBusinessTimeZone(t *testing.T) {
+	ap := newTestPeriodUsecases()
+
+	// 22:00 UTC is already the next day in the business time zone
+	day := randomPastDay()
+	date := time.Date(day.Year(), day.Month(), day.Day(), 22, 0, 0, 0, time.UTC)
+	period, err := ap.EndOfDay(context.Background(), date, "operator")
+	if err != nil {
+		t.Fatalf("AccountingPeriods.EndOfDay() error = %v", err)
+	}
+
+	year, month, dayOfMonth := date.In(ap.Location).Date()
+	want := time.Date(year, month, dayOfMonth, 0, 0, 0, 0, ap.Location)
+	if !period.StartDate.Equal(want) {
+		t.Errorf("expected the business day to start at %v, got %v", want, period.StartDate)
+	}
+}
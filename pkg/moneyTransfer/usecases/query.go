@@ -0,0 +1,164 @@
+package usecases
+
+import (
+	"context"
+	"log"
+
+	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/application"
+	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/domain"
+	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/repository"
+	"github.com/shopspring/decimal"
+)
+
+// DEFAULT_PAGE_SIZE is the number of records listed when a page size is not requested
+var DEFAULT_PAGE_SIZE = 20
+
+// MAX_PAGE_SIZE is the largest number of records listed in a single page
+var MAX_PAGE_SIZE = 100
+
+// QueryUsecases defines a contract the ledger query usecase adheres to
+type QueryUsecases interface {
+	Accounts(ctx context.Context, filter application.AccountFilter, page application.PageInput) (*application.Page[*domain.Account], error)
+	AccountsByID(ctx context.Context, accountIDs []string) ([]*domain.Account, error)
+	AccountBalances(ctx context.Context, accountIDs []string) (map[string]decimal.Decimal, error)
+	Transactions(ctx context.Context, filter application.TransactionFilter, page application.PageInput) (*application.Page[*domain.Transaction], error)
+	TransactionsByID(ctx context.Context, transactionIDs []string) ([]*domain.Transaction, error)
+	Entries(ctx context.Context, filter application.EntryFilter, page application.PageInput) (*application.Page[*domain.AccountEntry], error)
+	EntriesByTransaction(ctx context.Context, transactionIDs []string) ([]*domain.AccountEntry, error)
+}
+
+// Queries sets up the ledger query business logic and its dependencies
+type Queries struct {
+	Query repository.QueryRepository
+}
+
+// CheckPreconditions ensures all dependencies are injected
+func (q Queries) CheckPreconditions() {
+	if q.Query == nil {
+		log.Panic("query usecase did not initialize the query repository")
+	}
+}
+
+// NewQueryUsecases initializes a new ledger query business usecase
+func NewQueryUsecases(queryRepo repository.QueryRepository) *Queries {
+	q := &Queries{
+		Query: queryRepo,
+	}
+	q.CheckPreconditions()
+	return q
+}
+
+// pageBounds decodes a page request into its cursor and the number of records to fetch,
+// one more than the page size to tell whether a next page exists
+func pageBounds(page application.PageInput) (*application.Cursor, int, error) {
+	size := page.First
+	if size < 0 {
+		return nil, 0, domain.NewValidationError("invalid_page_size", "a page size should not be negative")
+	}
+	if size == 0 {
+		size = DEFAULT_PAGE_SIZE
+	}
+	if size > MAX_PAGE_SIZE {
+		size = MAX_PAGE_SIZE
+	}
+
+	if page.After == "" {
+		return nil, size + 1, nil
+	}
+
+	cursor, err := application.DecodeCursor(page.After)
+	if err != nil {
+		return nil, 0, err
+	}
+	return cursor, size + 1, nil
+}
+
+// newPage trims the records fetched for a page to its size
+func newPage[T any](records []T, limit int, base func(T) domain.AbstractBase) *application.Page[T] {
+	page := application.Page[T]{Items: records}
+	if len(records) == limit {
+		page.Items = records[:limit-1]
+		page.HasNextPage = true
+	}
+	if len(page.Items) > 0 {
+		last := base(page.Items[len(page.Items)-1])
+		page.EndCursor = application.EncodeCursor(last.CreatedAt, last.UUID)
+	}
+	return &page
+}
+
+// Accounts lists a page of the accounts matching a filter, oldest first
+func (q Queries) Accounts(ctx context.Context, filter application.AccountFilter, page application.PageInput) (*application.Page[*domain.Account], error) {
+	after, limit, err := pageBounds(page)
+	if err != nil {
+		return nil, err
+	}
+	filter.After, filter.Limit = after, limit
+
+	accounts, err := q.Query.Accounts(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	return newPage(accounts, limit, func(a *domain.Account) domain.AbstractBase { return a.AbstractBase }), nil
+}
+
+// AccountsByID retrieves the accounts with the given IDs
+func (q Queries) AccountsByID(ctx context.Context, accountIDs []string) ([]*domain.Account, error) {
+	return q.Query.AccountsByID(ctx, accountIDs)
+}
+
+// AccountBalances computes the current balances of the given accounts, keyed by account ID
+func (q Queries) AccountBalances(ctx context.Context, accountIDs []string) (map[string]decimal.Decimal, error) {
+	return q.Query.AccountBalances(ctx, accountIDs)
+}
+
+// Transactions lists a page of the transactions matching a filter, oldest first
+func (q Queries) Transactions(ctx context.Context, filter application.TransactionFilter, page application.PageInput) (*application.Page[*domain.Transaction], error) {
+	if filter.From != nil && filter.To != nil && filter.To.Before(*filter.From) {
+		return nil, domain.NewValidationError("invalid_period", "the start of the period should be before its end")
+	}
+
+	after, limit, err := pageBounds(page)
+	if err != nil {
+		return nil, err
+	}
+	filter.After, filter.Limit = after, limit
+
+	transactions, err := q.Query.Transactions(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	return newPage(transactions, limit, func(t *domain.Transaction) domain.AbstractBase { return t.AbstractBase }), nil
+}
+
+// TransactionsByID retrieves the transactions with the given IDs
+func (q Queries) TransactionsByID(ctx context.Context, transactionIDs []string) ([]*domain.Transaction, error) {
+	return q.Query.TransactionsByID(ctx, transactionIDs)
+}
+
+// Entries lists a page of the account entries matching a filter, oldest first
+func (q Queries) Entries(ctx context.Context, filter application.EntryFilter, page application.PageInput) (*application.Page[*domain.AccountEntry], error) {
+	if filter.From != nil && filter.To != nil && filter.To.Before(*filter.From) {
+		return nil, domain.NewValidationError("invalid_period", "the start of the period should be before its end")
+	}
+
+	after, limit, err := pageBounds(page)
+	if err != nil {
+		return nil, err
+	}
+	filter.After, filter.Limit = after, limit
+
+	entries, err := q.Query.Entries(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	return newPage(entries, limit, func(e *domain.AccountEntry) domain.AbstractBase { return e.AbstractBase }), nil
+}
+
+// EntriesByTransaction retrieves the entries posted by the given transactions
+func (q Queries) EntriesByTransaction(ctx context.Context, transactionIDs []string) ([]*domain.AccountEntry, error) {
+	return q.Query.EntriesByTransaction(ctx, transactionIDs)
+}
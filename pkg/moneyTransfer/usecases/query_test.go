@@ -0,0 +1,111 @@
+package usecases_test
+
+import (
+	"context"
+	"log"
+	"testing"
+
+	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/application"
+	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/config"
+	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/domain"
+	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/infrastructure/database/postgresql"
+	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/usecases"
+	"github.com/shopspring/decimal"
+	"go.uber.org/zap"
+)
+
+func newTestQueryUsecases() *usecases.Queries {
+	cfg, err := config.Read("")
+	if err != nil {
+		log.Panicf("error reading the testing database configuration: %v", err)
+	}
+	db, err := postgresql.ConnectToDatabase(cfg.Database, zap.NewNop())
+	if err != nil {
+		log.Panicf("error connecting to the testing database: %v", err)
+	}
+
+	return usecases.NewQueryUsecases(postgresql.NewPostgreSQLDatabase(db, nil))
+}
+
+func TestQueries_Entries(t *testing.T) {
+	q := newTestQueryUsecases()
+	mt := newTestMoneyTransferUsecases()
+	if err := mt.Create.CreateSystemAccount(context.Background()); err != nil {
+		t.Errorf("unable to create system accounts: %v", err)
+		return
+	}
+
+	amount := decimal.NewFromInt(100)
+	currency := domain.Kenyan
+	accountInput := application.AccountCreationInput{
+		CustomerName: "John Doe",
+		Amount:       &amount,
+		Currency:     &currency,
+		Header:       domain.Deposit,
+	}
+	srcAccount, err := mt.CreateCustomerAccount(context.Background(), accountInput)
+	if err != nil {
+		t.Errorf("unable to create test src account: %v", err)
+		return
+	}
+	destAccount, err := mt.CreateCustomerAccount(context.Background(), accountInput)
+	if err != nil {
+		t.Errorf("unable to create test dest account: %v", err)
+		return
+	}
+
+	transferAmount := decimal.NewFromInt(40)
+	for i := 0; i < 2; i++ {
+		if _, err := mt.Transfer(context.Background(), application.TransferInput{
+			SourceAccount:      srcAccount,
+			DestinationAccount: destAccount,
+			Amount:             &transferAmount,
+		}); err != nil {
+			t.Errorf("unable to transfer between test accounts: %v", err)
+			return
+		}
+	}
+
+	filter := application.EntryFilter{AccountID: srcAccount.UUID}
+	first, err := q.Entries(context.Background(), filter, application.PageInput{First: 2})
+	if err != nil {
+		t.Errorf("Queries.Entries() error = %v", err)
+		return
+	}
+	if len(first.Items) != 2 || !first.HasNextPage {
+		t.Errorf("expected a full first page followed by another, got %d entries", len(first.Items))
+		return
+	}
+
+	second, err := q.Entries(context.Background(), filter, application.PageInput{First: 2, After: first.EndCursor})
+	if err != nil {
+		t.Errorf("Queries.Entries() error = %v", err)
+		return
+	}
+	if len(second.Items) != 1 || second.HasNextPage {
+		t.Errorf("expected the last entry on the second page, got %d entries", len(second.Items))
+		return
+	}
+	if second.Items[0].UUID == first.Items[1].UUID {
+		t.Errorf("expected the second page to start after the first")
+		return
+	}
+
+	if _, err := q.Entries(context.Background(), filter, application.PageInput{After: "not-a-cursor"}); !domain.IsKind(err, domain.Validation) {
+		t.Errorf("expected an invalid cursor to be rejected, got %v", err)
+		return
+	}
+
+	balances, err := q.AccountBalances(context.Background(), []string{srcAccount.UUID, destAccount.UUID, "not-an-account"})
+	if err != nil {
+		t.Errorf("Queries.AccountBalances() error = %v", err)
+		return
+	}
+	if len(balances) != 2 {
+		t.Errorf("expected balances for the two accounts, got %v", balances)
+		return
+	}
+	if !balances[srcAccount.UUID].Equal(decimal.NewFromInt(20)) || !balances[destAccount.UUID].Equal(decimal.NewFromInt(180)) {
+		t.Errorf("unexpected balances %v", balances)
+	}
+}
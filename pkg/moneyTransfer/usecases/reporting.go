@@ -0,0 +1,186 @@
+package usecases
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/application"
+	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/domain"
+	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/repository"
+	"github.com/shopspring/decimal"
+)
+
+// ReportingUsecases defines a contract the accounting reports usecase adheres to
+type ReportingUsecases interface {
+	TrialBalance(ctx context.Context, asOf time.Time) (*application.TrialBalance, error)
+	GeneralLedger(ctx context.Context, accountID string, from time.Time, to time.Time) (*application.GeneralLedger, error)
+	LedgerCheck(ctx context.Context) (*application.LedgerCheck, error)
+}
+
+// Reporting sets up the accounting reports business logic and its dependencies
+type Reporting struct {
+	Get    repository.GetRepository
+	Report repository.ReportRepository
+}
+
+// CheckPreconditions ensures all dependencies are injected
+func (r Reporting) CheckPreconditions() {
+	if r.Get == nil {
+		log.Panic("reporting usecase did not initialize the get repository")
+	}
+
+	if r.Report == nil {
+		log.Panic("reporting usecase did not initialize the report repository")
+	}
+}
+
+// NewReportingUsecases initializes a new accounting reports usecase
+func NewReportingUsecases(
+	getRepo repository.GetRepository,
+	reportRepo repository.ReportRepository,
+) *Reporting {
+	r := &Reporting{
+		Get:    getRepo,
+		Report: reportRepo,
+	}
+	r.CheckPreconditions()
+	return r
+}
+
+// TrialBalance sums the debits and credits per account, and per header and currency, as of a date
+func (r Reporting) TrialBalance(ctx context.Context, asOf time.Time) (*application.TrialBalance, error) {
+	lines, err := r.Report.TrialBalance(ctx, asOf)
+	if err != nil {
+		return nil, err
+	}
+
+	trialBalance := application.TrialBalance{
+		AsOf:     asOf,
+		Accounts: lines,
+		Balanced: true,
+	}
+
+	type group struct {
+		header   domain.HeaderType
+		currency domain.CurrencyType
+	}
+	totals := map[group]*application.TrialBalanceTotal{}
+	currencies := map[domain.CurrencyType]*application.TrialBalanceTotal{}
+	for _, line := range lines {
+		key := group{header: line.Header, currency: line.Currency}
+		total, ok := totals[key]
+		if !ok {
+			total = &application.TrialBalanceTotal{Header: line.Header, Currency: line.Currency}
+			totals[key] = total
+			trialBalance.Totals = append(trialBalance.Totals, total)
+		}
+		total.TotalDebit = total.TotalDebit.Add(line.TotalDebit)
+		total.TotalCredit = total.TotalCredit.Add(line.TotalCredit)
+
+		currencyTotal, ok := currencies[line.Currency]
+		if !ok {
+			currencyTotal = &application.TrialBalanceTotal{Currency: line.Currency}
+			currencies[line.Currency] = currencyTotal
+		}
+		currencyTotal.TotalDebit = currencyTotal.TotalDebit.Add(line.TotalDebit)
+		currencyTotal.TotalCredit = currencyTotal.TotalCredit.Add(line.TotalCredit)
+
+		trialBalance.TotalDebit = trialBalance.TotalDebit.Add(line.TotalDebit)
+		trialBalance.TotalCredit = trialBalance.TotalCredit.Add(line.TotalCredit)
+	}
+
+	// Transfers never cross currencies so the books must balance within each currency
+	for _, total := range currencies {
+		if !total.TotalDebit.Equal(total.TotalCredit) {
+			trialBalance.Balanced = false
+		}
+	}
+
+	return &trialBalance, nil
+}
+
+// GeneralLedger lists an account's postings over a period with opening, running and closing balances
+func (r Reporting) GeneralLedger(ctx context.Context, accountID string, from time.Time, to time.Time) (*application.GeneralLedger, error) {
+	if to.Before(from) {
+		return nil, domain.NewValidationError("invalid_period", "the start of the period should be before its end")
+	}
+
+	accountOutput, err := r.Get.Account(ctx, accountID)
+	if err != nil {
+		return nil, err
+	}
+	account := domain.Account{
+		AbstractBase: domain.AbstractBase{UUID: accountOutput.UUID},
+		BalanceType:  accountOutput.BalanceType,
+	}
+
+	opening, err := r.Report.AccountBalanceAsOf(ctx, &account, from.Add(-time.Nanosecond))
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err := r.Report.AccountEntries(ctx, accountID, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	ledger := application.GeneralLedger{
+		Account:        accountOutput,
+		From:           from,
+		To:             to,
+		OpeningBalance: *opening,
+		Entries:        []*application.GeneralLedgerEntry{},
+	}
+
+	running := *opening
+	for _, entry := range entries {
+		running = running.Add(account.ComputeBalance(entry.DebitAmount, entry.CreditAmount))
+
+		effectiveDate := entry.EffectiveDate
+		if effectiveDate == nil {
+			effectiveDate = entry.CreatedAt
+		}
+		ledgerEntry := application.GeneralLedgerEntry{
+			EntryID:        entry.UUID,
+			TransactionID:  entry.TransactionID,
+			Description:    entry.Transaction.Description,
+			DebitAmount:    entry.DebitAmount,
+			CreditAmount:   entry.CreditAmount,
+			RunningBalance: running,
+		}
+		if effectiveDate != nil {
+			ledgerEntry.EffectiveDate = *effectiveDate
+		}
+		ledger.Entries = append(ledger.Entries, &ledgerEntry)
+	}
+	ledger.ClosingBalance = running
+
+	return &ledger, nil
+}
+
+// LedgerCheck verifies the invariant that total debits equal total credits across all entries
+func (r Reporting) LedgerCheck(ctx context.Context) (*application.LedgerCheck, error) {
+	debits, credits, err := r.Report.LedgerTotals(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	unbalanced, err := r.Report.UnbalancedTransactions(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if unbalanced == nil {
+		unbalanced = []string{}
+	}
+
+	difference := debits.Sub(*credits)
+	return &application.LedgerCheck{
+		CheckedAt:              time.Now(),
+		TotalDebit:             *debits,
+		TotalCredit:            *credits,
+		Difference:             difference,
+		UnbalancedTransactions: unbalanced,
+		Balanced:               difference.Equal(decimal.Zero) && len(unbalanced) == 0,
+	}, nil
+}
@@ -0,0 +1,219 @@
+package usecases_test
+
+import (
+	"context"
+	"log"
+	"testing"
+	"time"
+
+	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/application"
+	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/config"
+	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/domain"
+	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/infrastructure/database/postgresql"
+	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/usecases"
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"go.uber.org/zap"
+)
+
+func newTestReportingUsecases() *usecases.Reporting {
+	cfg, err := config.Read("")
+	if err != nil {
+		log.Panicf("error reading the testing database configuration: %v", err)
+	}
+	db, err := postgresql.ConnectToDatabase(cfg.Database, zap.NewNop())
+	if err != nil {
+		log.Panicf("error connecting to the testing database: %v", err)
+	}
+	get := postgresql.NewPostgreSQLDatabase(db, nil)
+	report := postgresql.NewPostgreSQLDatabase(db, nil)
+
+	return usecases.NewReportingUsecases(get, report)
+}
+
+func TestReporting_TrialBalance(t *testing.T) {
+	r := newTestReportingUsecases()
+	mt := newTestMoneyTransferUsecases()
+	if err := mt.Create.CreateSystemAccount(context.Background()); err != nil {
+		t.Errorf("unable to create system accounts: %v", err)
+		return
+	}
+
+	amount := decimal.NewFromInt(100)
+	currency := domain.Kenyan
+	account, err := mt.CreateCustomerAccount(context.Background(), application.AccountCreationInput{
+		CustomerName: "John Doe",
+		Amount:       &amount,
+		Currency:     &currency,
+		Header:       domain.Deposit,
+	})
+	if err != nil {
+		t.Errorf("unable to create test account: %v", err)
+		return
+	}
+
+	trialBalance, err := r.TrialBalance(context.Background(), time.Now())
+	if err != nil {
+		t.Errorf("Reporting.TrialBalance() error = %v", err)
+		return
+	}
+	if !trialBalance.Balanced {
+		t.Errorf("expected the books to balance")
+		return
+	}
+	if !trialBalance.TotalDebit.Equal(trialBalance.TotalCredit) {
+		t.Errorf("expected total debits %v to equal total credits %v", trialBalance.TotalDebit, trialBalance.TotalCredit)
+		return
+	}
+
+	var found bool
+	for _, line := range trialBalance.Accounts {
+		if line.AccountID == account.UUID {
+			found = true
+			if !line.Balance.Equal(amount) {
+				t.Errorf("expected the account's trial balance to be %v, got %v", amount, line.Balance)
+				return
+			}
+		}
+	}
+	if !found {
+		t.Errorf("expected account %s in the trial balance", account.UUID)
+		return
+	}
+
+	past, err := r.TrialBalance(context.Background(), time.Now().AddDate(-50, 0, 0))
+	if err != nil {
+		t.Errorf("Reporting.TrialBalance() error = %v", err)
+		return
+	}
+	if !past.TotalDebit.IsZero() {
+		t.Errorf("did not expect entries before the ledger existed")
+		return
+	}
+}
+
+func TestReporting_GeneralLedger(t *testing.T) {
+	r := newTestReportingUsecases()
+	mt := newTestMoneyTransferUsecases()
+	if err := mt.Create.CreateSystemAccount(context.Background()); err != nil {
+		t.Errorf("unable to create system accounts: %v", err)
+		return
+	}
+
+	amount := decimal.NewFromInt(100)
+	currency := domain.Kenyan
+	accountInput := application.AccountCreationInput{
+		CustomerName: "John Doe",
+		Amount:       &amount,
+		Currency:     &currency,
+		Header:       domain.Deposit,
+	}
+	srcAccount, err := mt.CreateCustomerAccount(context.Background(), accountInput)
+	if err != nil {
+		t.Errorf("unable to create test src account: %v", err)
+		return
+	}
+	destAccount, err := mt.CreateCustomerAccount(context.Background(), accountInput)
+	if err != nil {
+		t.Errorf("unable to create test dest account: %v", err)
+		return
+	}
+
+	transferAmount := decimal.NewFromInt(30)
+	if _, err := mt.Transfer(context.Background(), application.TransferInput{
+		SourceAccount:      srcAccount,
+		DestinationAccount: destAccount,
+		Amount:             &transferAmount,
+	}); err != nil {
+		t.Errorf("unable to transfer between test accounts: %v", err)
+		return
+	}
+
+	now := time.Now()
+	type args struct {
+		accountID string
+		from      time.Time
+		to        time.Time
+	}
+	tests := []struct {
+		name        string
+		args        args
+		wantEntries int
+		wantClosing decimal.Decimal
+		wantErr     bool
+	}{
+		{
+			name: "happy case",
+			args: args{
+				accountID: srcAccount.UUID,
+				from:      now.Add(-time.Hour),
+				to:        now.Add(time.Hour),
+			},
+			wantEntries: 2,
+			wantClosing: decimal.NewFromInt(70),
+			wantErr:     false,
+		},
+		{
+			name: "happy case - period after the postings",
+			args: args{
+				accountID: srcAccount.UUID,
+				from:      now.Add(time.Hour),
+				to:        now.Add(2 * time.Hour),
+			},
+			wantEntries: 0,
+			wantClosing: decimal.NewFromInt(70),
+			wantErr:     false,
+		},
+		{
+			name: "sad case - nonexistent account",
+			args: args{
+				accountID: uuid.NewString(),
+				from:      now.Add(-time.Hour),
+				to:        now,
+			},
+			wantErr: true,
+		},
+		{
+			name: "sad case - period ends before it starts",
+			args: args{
+				accountID: srcAccount.UUID,
+				from:      now,
+				to:        now.Add(-time.Hour),
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ledger, err := r.GeneralLedger(context.Background(), tt.args.accountID, tt.args.from, tt.args.to)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Reporting.GeneralLedger() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !tt.wantErr {
+				if len(ledger.Entries) != tt.wantEntries {
+					t.Errorf("expected %d entries, got %d", tt.wantEntries, len(ledger.Entries))
+					return
+				}
+				if !ledger.ClosingBalance.Equal(tt.wantClosing) {
+					t.Errorf("expected a closing balance of %v, got %v", tt.wantClosing, ledger.ClosingBalance)
+					return
+				}
+			}
+		})
+	}
+}
+
+func TestReporting_LedgerCheck(t *testing.T) {
+	r := newTestReportingUsecases()
+
+	check, err := r.LedgerCheck(context.Background())
+	if err != nil {
+		t.Errorf("Reporting.LedgerCheck() error = %v", err)
+		return
+	}
+	if !check.Balanced {
+		t.Errorf("expected the ledger to balance, difference of %v", check.Difference)
+		return
+	}
+}
@@ -0,0 +1,109 @@
+package usecases
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/application"
+	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/domain"
+	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/repository"
+)
+
+// StatementUsecases defines a contract the account statement usecase adheres to
+type StatementUsecases interface {
+	Statement(ctx context.Context, accountID string, from time.Time, to time.Time) (*application.Statement, error)
+}
+
+// Statements sets up the account statement business logic and its dependencies
+type Statements struct {
+	Get    repository.GetRepository
+	Report repository.ReportRepository
+}
+
+// CheckPreconditions ensures all dependencies are injected
+func (s Statements) CheckPreconditions() {
+	if s.Get == nil {
+		log.Panic("statements usecase did not initialize the get repository")
+	}
+
+	if s.Report == nil {
+		log.Panic("statements usecase did not initialize the report repository")
+	}
+}
+
+// NewStatementUsecases initializes a new account statement usecase
+func NewStatementUsecases(
+	getRepo repository.GetRepository,
+	reportRepo repository.ReportRepository,
+) *Statements {
+	s := &Statements{
+		Get:    getRepo,
+		Report: reportRepo,
+	}
+	s.CheckPreconditions()
+	return s
+}
+
+// Statement generates an account's statement for a period from its entries
+func (s Statements) Statement(ctx context.Context, accountID string, from time.Time, to time.Time) (*application.Statement, error) {
+	if to.Before(from) {
+		return nil, domain.NewValidationError("invalid_period", "the start of the statement period should be before its end")
+	}
+
+	accountOutput, err := s.Get.Account(ctx, accountID)
+	if err != nil {
+		return nil, err
+	}
+	account := domain.Account{
+		AbstractBase: domain.AbstractBase{UUID: accountOutput.UUID},
+		BalanceType:  accountOutput.BalanceType,
+	}
+
+	opening, err := s.Report.AccountBalanceAsOf(ctx, &account, from.Add(-time.Nanosecond))
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err := s.Report.AccountEntries(ctx, accountID, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	statement := application.Statement{
+		Account:        accountOutput,
+		From:           from,
+		To:             to,
+		GeneratedAt:    time.Now(),
+		OpeningBalance: *opening,
+		Lines:          []*application.StatementLine{},
+	}
+
+	running := *opening
+	for _, entry := range entries {
+		amount := account.ComputeBalance(entry.DebitAmount, entry.CreditAmount)
+		running = running.Add(amount)
+		if amount.IsPositive() {
+			statement.TotalIn = statement.TotalIn.Add(amount)
+		} else {
+			statement.TotalOut = statement.TotalOut.Add(amount.Neg())
+		}
+
+		line := application.StatementLine{
+			EntryID:        entry.UUID,
+			TransactionID:  entry.TransactionID,
+			Description:    entry.Transaction.Description,
+			Amount:         amount,
+			RunningBalance: running,
+		}
+		if entry.EffectiveDate != nil {
+			line.BookingDate = *entry.EffectiveDate
+		} else if entry.CreatedAt != nil {
+			line.BookingDate = *entry.CreatedAt
+		}
+		statement.Lines = append(statement.Lines, &line)
+	}
+	statement.ClosingBalance = running
+
+	return &statement, nil
+}
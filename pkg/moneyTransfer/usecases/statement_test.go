@@ -0,0 +1,97 @@
+package usecases_test
+
+import (
+	"context"
+	"log"
+	"testing"
+	"time"
+
+	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/application"
+	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/config"
+	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/domain"
+	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/infrastructure/database/postgresql"
+	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/usecases"
+	"github.com/shopspring/decimal"
+	"go.uber.org/zap"
+)
+
+func newTestStatementUsecases() *usecases.Statements {
+	cfg, err := config.Read("")
+	if err != nil {
+		log.Panicf("error reading the testing database configuration: %v", err)
+	}
+	db, err := postgresql.ConnectToDatabase(cfg.Database, zap.NewNop())
+	if err != nil {
+		log.Panicf("error connecting to the testing database: %v", err)
+	}
+	get := postgresql.NewPostgreSQLDatabase(db, nil)
+	report := postgresql.NewPostgreSQLDatabase(db, nil)
+
+	return usecases.NewStatementUsecases(get, report)
+}
+
+func TestStatements_Statement(t *testing.T) {
+	s := newTestStatementUsecases()
+	mt := newTestMoneyTransferUsecases()
+	if err := mt.Create.CreateSystemAccount(context.Background()); err != nil {
+		t.Errorf("unable to create system accounts: %v", err)
+		return
+	}
+
+	amount := decimal.NewFromInt(100)
+	currency := domain.Kenyan
+	accountInput := application.AccountCreationInput{
+		CustomerName: "John Doe",
+		Amount:       &amount,
+		Currency:     &currency,
+		Header:       domain.Deposit,
+	}
+	srcAccount, err := mt.CreateCustomerAccount(context.Background(), accountInput)
+	if err != nil {
+		t.Errorf("unable to create test src account: %v", err)
+		return
+	}
+	destAccount, err := mt.CreateCustomerAccount(context.Background(), accountInput)
+	if err != nil {
+		t.Errorf("unable to create test dest account: %v", err)
+		return
+	}
+
+	transferAmount := decimal.NewFromInt(40)
+	if _, err := mt.Transfer(context.Background(), application.TransferInput{
+		SourceAccount:      srcAccount,
+		DestinationAccount: destAccount,
+		Amount:             &transferAmount,
+	}); err != nil {
+		t.Errorf("unable to transfer between test accounts: %v", err)
+		return
+	}
+
+	now := time.Now()
+	statement, err := s.Statement(context.Background(), srcAccount.UUID, now.Add(-time.Hour), now.Add(time.Hour))
+	if err != nil {
+		t.Errorf("Statements.Statement() error = %v", err)
+		return
+	}
+	if len(statement.Lines) != 2 {
+		t.Errorf("expected the opening deposit and the transfer, got %d lines", len(statement.Lines))
+		return
+	}
+	if !statement.OpeningBalance.IsZero() {
+		t.Errorf("expected a new account to open with a zero balance")
+		return
+	}
+	if !statement.ClosingBalance.Equal(decimal.NewFromInt(60)) {
+		t.Errorf("expected a closing balance of 60, got %v", statement.ClosingBalance)
+		return
+	}
+	if !statement.Lines[1].Amount.Equal(transferAmount.Neg()) {
+		t.Errorf("expected the transfer out to be negative, got %v", statement.Lines[1].Amount)
+		return
+	}
+
+	if _, err := s.Statement(context.Background(), srcAccount.UUID, now, now.Add(-time.Hour)); err == nil {
+		t.Errorf("expected a period ending before it starts to be rejected")
+		return
+	}
+}
@@ -0,0 +1,150 @@
+package usecases
+
+import (
+	"context"
+	"log"
+
+	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/application"
+	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/domain"
+	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/logging"
+	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/repository"
+	"go.uber.org/zap"
+)
+
+// TransferReviewUsecases defines a contract the held transfers review usecase adheres to
+type TransferReviewUsecases interface {
+	HeldTransfers(ctx context.Context, status domain.HoldStatus) ([]*domain.HeldTransfer, error)
+	HeldTransfer(ctx context.Context, heldTransferID string) (*domain.HeldTransfer, error)
+	ReleaseTransfer(ctx context.Context, heldTransferID string, actor string) (*domain.HeldTransfer, error)
+	RejectTransfer(ctx context.Context, heldTransferID string, actor string, reason string) (*domain.HeldTransfer, error)
+}
+
+// TransferReview sets up the review of the transfers fraud screening held and its dependencies
+type TransferReview struct {
+	Get    repository.GetRepository
+	Fraud  repository.FraudRepository
+	Logger *zap.Logger
+}
+
+// CheckPreconditions ensures all dependencies are injected
+func (tr TransferReview) CheckPreconditions() {
+	if tr.Get == nil {
+		log.Panic("transfer review usecase did not initialize the get repository")
+	}
+
+	if tr.Fraud == nil {
+		log.Panic("transfer review usecase did not initialize the fraud repository")
+	}
+
+	if tr.Logger == nil {
+		log.Panic("transfer review usecase did not initialize the logger")
+	}
+}
+
+// NewTransferReviewUsecases initializes a new held transfers review usecase
+func NewTransferReviewUsecases(
+	getRepo repository.GetRepository,
+	fraudRepo repository.FraudRepository,
+	logger *zap.Logger,
+) *TransferReview {
+	tr := &TransferReview{
+		Get:    getRepo,
+		Fraud:  fraudRepo,
+		Logger: logger,
+	}
+	tr.CheckPreconditions()
+	return tr
+}
+
+// HeldTransfers lists the held transfers with a status, the ones pending review when no status is given
+func (tr TransferReview) HeldTransfers(ctx context.Context, status domain.HoldStatus) ([]*domain.HeldTransfer, error) {
+	switch status {
+	case "":
+		status = domain.HoldPending
+	case domain.HoldPending, domain.HoldReleased, domain.HoldRejected:
+	default:
+		return nil, domain.NewValidationError("invalid_status", "%q is not a held transfer status", status)
+	}
+
+	return tr.Fraud.HeldTransfers(ctx, status)
+}
+
+// HeldTransfer retrieves a held transfer with the rules that flagged it
+func (tr TransferReview) HeldTransfer(ctx context.Context, heldTransferID string) (*domain.HeldTransfer, error) {
+	return tr.Fraud.HeldTransfer(ctx, heldTransferID)
+}
+
+// ReleaseTransfer posts a held transfer. Holding it reserved nothing, so the source account should still cover it
+func (tr TransferReview) ReleaseTransfer(ctx context.Context, heldTransferID string, actor string) (*domain.HeldTransfer, error) {
+	held, err := tr.pending(ctx, heldTransferID)
+	if err != nil {
+		return nil, err
+	}
+
+	sourceAccount, err := tr.Get.Account(ctx, held.SourceAccountID)
+	if err != nil {
+		return nil, err
+	}
+
+	destinationAccount, err := tr.Get.Account(ctx, held.DestinationAccountID)
+	if err != nil {
+		return nil, err
+	}
+
+	transferInput := application.TransferInput{
+		SourceAccount:      sourceAccount,
+		DestinationAccount: destinationAccount,
+		Amount:             &held.Amount,
+	}
+	if err := checkTransfer(transferInput); err != nil {
+		return nil, err
+	}
+
+	description, drEntry, crEntry := transferEntries(transferInput)
+	released, err := tr.Fraud.ReleaseHeldTransfer(ctx, heldTransferID, actor, description, &drEntry, &crEntry)
+	if err != nil {
+		return nil, err
+	}
+
+	logging.For(ctx, tr.Logger).Info("held transfer released",
+		zap.String("held_transfer_id", released.UUID),
+		zap.String("transaction_id", *released.TransactionID),
+		zap.String("actor", actor),
+	)
+	return released, nil
+}
+
+// RejectTransfer declines a held transfer, it is never posted
+func (tr TransferReview) RejectTransfer(ctx context.Context, heldTransferID string, actor string, reason string) (*domain.HeldTransfer, error) {
+	if reason == "" {
+		return nil, domain.NewValidationError("missing_reason", "a reason should be provided to reject a held transfer")
+	}
+
+	if _, err := tr.pending(ctx, heldTransferID); err != nil {
+		return nil, err
+	}
+
+	rejected, err := tr.Fraud.RejectHeldTransfer(ctx, heldTransferID, actor, reason)
+	if err != nil {
+		return nil, err
+	}
+
+	logging.For(ctx, tr.Logger).Info("held transfer rejected",
+		zap.String("held_transfer_id", rejected.UUID),
+		zap.String("actor", actor),
+	)
+	return rejected, nil
+}
+
+// pending retrieves a held transfer that has not been reviewed yet
+func (tr TransferReview) pending(ctx context.Context, heldTransferID string) (*domain.HeldTransfer, error) {
+	held, err := tr.Fraud.HeldTransfer(ctx, heldTransferID)
+	if err != nil {
+		return nil, err
+	}
+
+	if held.Status != domain.HoldPending {
+		return nil, domain.NewConflictError("not_pending_review", "held transfer %s has already been reviewed, it is %s", heldTransferID, held.Status)
+	}
+	return held, nil
+}
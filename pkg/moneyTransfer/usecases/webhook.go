@@ -0,0 +1,207 @@
+package usecases
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"log"
+	"net"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/application"
+	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/domain"
+	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/logging"
+	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/repository"
+	"go.uber.org/zap"
+)
+
+// WebhookUsecases defines a contract the webhook subscriptions usecase adheres to
+type WebhookUsecases interface {
+	Subscribe(ctx context.Context, owner string, input application.WebhookSubscriptionInput) (*application.WebhookSubscriptionOutput, error)
+	Subscriptions(ctx context.Context, owner string) ([]*domain.WebhookSubscription, error)
+	Unsubscribe(ctx context.Context, owner string, subscriptionID string) error
+	DeadLetters(ctx context.Context, owner string, subscriptionID string) ([]*domain.WebhookDelivery, error)
+	Redeliver(ctx context.Context, owner string, deliveryID string) (*domain.WebhookDelivery, error)
+}
+
+// Webhooks sets up the webhook subscriptions business logic and its dependencies
+type Webhooks struct {
+	Webhook repository.WebhookRepository
+	Get     repository.GetRepository
+	Logger  *zap.Logger
+}
+
+// CheckPreconditions ensures all dependencies are injected
+func (w Webhooks) CheckPreconditions() {
+	if w.Webhook == nil {
+		log.Panic("webhooks usecase did not initialize the webhook repository")
+	}
+
+	if w.Get == nil {
+		log.Panic("webhooks usecase did not initialize the get repository")
+	}
+
+	if w.Logger == nil {
+		log.Panic("webhooks usecase did not initialize the logger")
+	}
+}
+
+// NewWebhookUsecases initializes a new webhook subscriptions usecase
+func NewWebhookUsecases(webhookRepo repository.WebhookRepository, getRepo repository.GetRepository, logger *zap.Logger) *Webhooks {
+	w := &Webhooks{
+		Webhook: webhookRepo,
+		Get:     getRepo,
+		Logger:  logger,
+	}
+	w.CheckPreconditions()
+	return w
+}
+
+// Subscribe registers an endpoint for event types about accounts, generating a signing secret when none is given
+func (w Webhooks) Subscribe(ctx context.Context, owner string, input application.WebhookSubscriptionInput) (*application.WebhookSubscriptionOutput, error) {
+	endpoint, err := url.Parse(input.URL)
+	if err != nil || (endpoint.Scheme != "http" && endpoint.Scheme != "https") || endpoint.Host == "" {
+		return nil, domain.NewValidationError("invalid_url", "%q is not a valid http(s) webhook url", input.URL)
+	}
+
+	if err := publicEndpoint(ctx, endpoint); err != nil {
+		return nil, err
+	}
+
+	if len(input.EventTypes) == 0 {
+		return nil, domain.NewValidationError("missing_event_types", "at least one event type is required")
+	}
+
+	var eventTypes []string
+	for _, eventType := range input.EventTypes {
+		if !domain.IsKnownEventType(eventType) {
+			return nil, domain.NewValidationError("unknown_event_type", "%q is not a known event type", eventType)
+		}
+		eventTypes = append(eventTypes, string(eventType))
+	}
+
+	for _, accountID := range input.AccountIDs {
+		if _, err := w.Get.Account(ctx, accountID); err != nil {
+			return nil, err
+		}
+	}
+
+	secret := input.Secret
+	if secret == "" {
+		random := make([]byte, 32)
+		if _, err := rand.Read(random); err != nil {
+			return nil, domain.NewInternalError(err, "unable to generate a webhook secret")
+		}
+		secret = hex.EncodeToString(random)
+	}
+
+	subscription, err := w.Webhook.CreateSubscription(ctx, &domain.WebhookSubscription{
+		Owner:      owner,
+		URL:        endpoint.String(),
+		EventTypes: strings.Join(eventTypes, ","),
+		AccountIDs: strings.Join(input.AccountIDs, ","),
+		Secret:     secret,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	logging.For(ctx, w.Logger).Info("webhook subscribed",
+		zap.String("subscription_id", subscription.UUID),
+		zap.String("owner", owner),
+		zap.String("host", endpoint.Host),
+		zap.Strings("event_types", eventTypes),
+		zap.Strings("account_ids", input.AccountIDs),
+	)
+	return &application.WebhookSubscriptionOutput{
+		Subscription: subscription,
+		Secret:       secret,
+	}, nil
+}
+
+// publicEndpoint refuses endpoints on the network the service runs in. The addresses a host resolves to may
+// change, they are checked again when deliveries are posted
+func publicEndpoint(ctx context.Context, endpoint *url.URL) error {
+	addresses, err := net.DefaultResolver.LookupIPAddr(ctx, endpoint.Hostname())
+	if err != nil {
+		// A host that does not resolve yet is refused when delivering if it resolves to an internal address
+		return nil
+	}
+
+	for _, address := range addresses {
+		if !domain.IsPublicAddress(address.IP) {
+			return domain.NewValidationError("internal_url", "webhooks can not be delivered to %s, it is not a public address", endpoint.Host)
+		}
+	}
+	return nil
+}
+
+// Subscriptions lists an owner's webhook subscriptions
+func (w Webhooks) Subscriptions(ctx context.Context, owner string) ([]*domain.WebhookSubscription, error) {
+	return w.Webhook.Subscriptions(ctx, owner)
+}
+
+// ownSubscription retrieves a subscription, ensuring it belongs to the owner
+func (w Webhooks) ownSubscription(ctx context.Context, owner string, subscriptionID string) (*domain.WebhookSubscription, error) {
+	subscription, err := w.Webhook.Subscription(ctx, subscriptionID)
+	if err != nil {
+		return nil, err
+	}
+
+	if subscription.Owner != owner {
+		return nil, domain.NewForbiddenError("subscription_forbidden", "webhook subscription %s does not belong to %s", subscriptionID, owner)
+	}
+
+	return subscription, nil
+}
+
+// Unsubscribe deletes one of an owner's webhook subscriptions
+func (w Webhooks) Unsubscribe(ctx context.Context, owner string, subscriptionID string) error {
+	if _, err := w.ownSubscription(ctx, owner, subscriptionID); err != nil {
+		return err
+	}
+
+	if err := w.Webhook.DeleteSubscription(ctx, subscriptionID); err != nil {
+		return err
+	}
+
+	logging.For(ctx, w.Logger).Info("webhook unsubscribed", zap.String("subscription_id", subscriptionID), zap.String("owner", owner))
+	return nil
+}
+
+// DeadLetters lists the deliveries of a subscription that exhausted their retries
+func (w Webhooks) DeadLetters(ctx context.Context, owner string, subscriptionID string) ([]*domain.WebhookDelivery, error) {
+	if _, err := w.ownSubscription(ctx, owner, subscriptionID); err != nil {
+		return nil, err
+	}
+
+	return w.Webhook.Deliveries(ctx, subscriptionID, domain.DeliveryDead)
+}
+
+// Redeliver queues a delivery for an immediate attempt with a fresh retry schedule
+func (w Webhooks) Redeliver(ctx context.Context, owner string, deliveryID string) (*domain.WebhookDelivery, error) {
+	delivery, err := w.Webhook.Delivery(ctx, deliveryID)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := w.ownSubscription(ctx, owner, delivery.SubscriptionID); err != nil {
+		return nil, err
+	}
+
+	if delivery.Status == domain.DeliveryPending {
+		return nil, domain.NewConflictError("delivery_already_queued", "webhook delivery %s is already queued", deliveryID)
+	}
+
+	delivery.Status = domain.DeliveryPending
+	delivery.Attempts = 0
+	delivery.NextAttemptAt = time.Now()
+	if err := w.Webhook.UpdateDelivery(ctx, delivery); err != nil {
+		return nil, err
+	}
+
+	logging.For(ctx, w.Logger).Info("webhook redelivery queued", zap.String("delivery_id", deliveryID), zap.String("owner", owner))
+	return delivery, nil
+}
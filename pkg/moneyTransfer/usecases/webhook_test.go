@@ -0,0 +1,168 @@
+package usecases_test
+
+import (
+	"context"
+	"log"
+	"testing"
+
+	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/application"
+	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/config"
+	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/domain"
+	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/infrastructure/database/postgresql"
+	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/usecases"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+func newTestWebhookUsecases() *usecases.Webhooks {
+	cfg, err := config.Read("")
+	if err != nil {
+		log.Panicf("error reading the testing database configuration: %v", err)
+	}
+	db, err := postgresql.ConnectToDatabase(cfg.Database, zap.NewNop())
+	if err != nil {
+		log.Panicf("error connecting to the testing database: %v", err)
+	}
+
+	store := postgresql.NewPostgreSQLDatabase(db, nil)
+	return usecases.NewWebhookUsecases(store, store, zap.NewNop())
+}
+
+func TestWebhooks_Subscribe(t *testing.T) {
+	w := newTestWebhookUsecases()
+	owner := uuid.New().String()
+
+	tests := []struct {
+		name    string
+		input   application.WebhookSubscriptionInput
+		wantErr bool
+	}{
+		{
+			name: "happy case: secret generated",
+			input: application.WebhookSubscriptionInput{
+				URL:        "https://partner.example.com/hooks",
+				EventTypes: []domain.EventType{domain.TransferPosted, domain.TransferReversed},
+			},
+		},
+		{
+			name: "sad case: not an http url",
+			input: application.WebhookSubscriptionInput{
+				URL:        "ftp://partner.example.com/hooks",
+				EventTypes: []domain.EventType{domain.TransferPosted},
+			},
+			wantErr: true,
+		},
+		{
+			name: "sad case: unknown event type",
+			input: application.WebhookSubscriptionInput{
+				URL:        "https://partner.example.com/hooks",
+				EventTypes: []domain.EventType{"account.closed"},
+			},
+			wantErr: true,
+		},
+		{
+			name: "sad case: loopback address",
+			input: application.WebhookSubscriptionInput{
+				URL:        "http://localhost:8080/hooks",
+				EventTypes: []domain.EventType{domain.TransferPosted},
+			},
+			wantErr: true,
+		},
+		{
+			name: "sad case: cloud metadata service",
+			input: application.WebhookSubscriptionInput{
+				URL:        "http://169.254.169.254/latest/meta-data",
+				EventTypes: []domain.EventType{domain.TransferPosted},
+			},
+			wantErr: true,
+		},
+		{
+			name: "sad case: private address",
+			input: application.WebhookSubscriptionInput{
+				URL:        "https://10.0.0.12/hooks",
+				EventTypes: []domain.EventType{domain.TransferPosted},
+			},
+			wantErr: true,
+		},
+		{
+			name: "sad case: unknown account",
+			input: application.WebhookSubscriptionInput{
+				URL:        "https://partner.example.com/hooks",
+				EventTypes: []domain.EventType{domain.TransferPosted},
+				AccountIDs: []string{uuid.New().String()},
+			},
+			wantErr: true,
+		},
+		{
+			name: "sad case: no event types",
+			input: application.WebhookSubscriptionInput{
+				URL: "https://partner.example.com/hooks",
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			output, err := w.Subscribe(context.Background(), owner, tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Webhooks.Subscribe() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !tt.wantErr && (output.Secret == "" || !output.Subscription.Subscribes(domain.TransferReversed)) {
+				t.Errorf("expected a subscription with a generated secret, got %+v", output)
+			}
+		})
+	}
+}
+
+func TestWebhooks_Redeliver(t *testing.T) {
+	w := newTestWebhookUsecases()
+	owner := uuid.New().String()
+
+	output, err := w.Subscribe(context.Background(), owner, application.WebhookSubscriptionInput{
+		URL:        "https://partner.example.com/hooks",
+		EventTypes: []domain.EventType{domain.AccountOpened},
+	})
+	if err != nil {
+		t.Errorf("unable to subscribe: %v", err)
+		return
+	}
+
+	dead := &domain.WebhookDelivery{
+		SubscriptionID: output.Subscription.UUID,
+		EventID:        uuid.New().String(),
+		EventType:      domain.AccountOpened,
+		Payload:        "{}",
+		Status:         domain.DeliveryDead,
+		Attempts:       8,
+	}
+	if err := w.Webhook.CreateDeliveries(context.Background(), []*domain.WebhookDelivery{dead}); err != nil {
+		t.Errorf("unable to create a dead delivery: %v", err)
+		return
+	}
+
+	deadLetters, err := w.DeadLetters(context.Background(), owner, output.Subscription.UUID)
+	if err != nil || len(deadLetters) != 1 {
+		t.Errorf("expected one dead letter, got %d (%v)", len(deadLetters), err)
+		return
+	}
+
+	if _, err := w.Redeliver(context.Background(), uuid.New().String(), dead.UUID); err == nil {
+		t.Errorf("expected only the subscription's owner to redeliver")
+		return
+	}
+
+	delivery, err := w.Redeliver(context.Background(), owner, dead.UUID)
+	if err != nil {
+		t.Errorf("Webhooks.Redeliver() error = %v", err)
+		return
+	}
+	if delivery.Status != domain.DeliveryPending || delivery.Attempts != 0 {
+		t.Errorf("expected the delivery to be queued with a fresh retry schedule")
+		return
+	}
+
+	if _, err := w.Redeliver(context.Background(), owner, dead.UUID); err == nil {
+		t.Errorf("expected a queued delivery not to be redelivered")
+	}
+}
@@ -3,53 +3,167 @@ package main
 import (
 	"context"
 	"errors"
+	"flag"
 	"fmt"
 	"log"
+	"net"
 	"net/http"
 	"os"
 	"os/signal"
 	"syscall"
 	"time"
 
-	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/presentation"
+	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/app"
+	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/config"
+	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/infrastructure/database/postgresql"
+	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/infrastructure/tracing"
+	"github.com/ageeknamedslickback/simpleMoneyTransfer/pkg/moneyTransfer/logging"
+	"go.uber.org/zap"
 )
 
+// migrate applies the pending schema migrations before the servers start
+func migrate(cfg config.Database, logger *zap.Logger) {
+	db, err := postgresql.ConnectToDatabase(cfg, logger)
+	if err != nil {
+		logger.Fatal("unable to connect to the database to migrate it", zap.Error(err))
+	}
+	if err := postgresql.Migrate(db); err != nil {
+		logger.Fatal("unable to migrate the database", zap.Error(err))
+	}
+
+	sqlDB, err := db.DB()
+	if err == nil {
+		sqlDB.Close()
+	}
+}
+
 func main() {
-	router := presentation.Router()
+	migrateFirst := flag.Bool("migrate", false, "apply the pending schema migrations before serving")
+	configFile := flag.String("config", "", "YAML configuration file, settings in the environment take precedence")
+	flag.Parse()
+
+	cfg, err := config.Load(*configFile)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	logger, err := logging.New(cfg.Log.Level, os.Stdout)
+	if err != nil {
+		log.Fatalf("unable to set up logging: %v", err)
+	}
+	defer logger.Sync()
+
+	if *migrateFirst {
+		migrate(cfg.Database, logger)
+	}
+
+	shutdownTracing, err := tracing.Setup(context.Background(), cfg.Tracing.Exporter)
+	if err != nil {
+		logger.Fatal("unable to set up tracing", zap.Error(err))
+	}
+
+	// Requests and the background workers run in a context cancelled once the servers have shut down
+	base, cancelBase := context.WithCancel(context.Background())
+	defer cancelBase()
+
+	service, err := app.New(context.Background(), cfg, logger)
+	if err != nil {
+		logger.Fatal("unable to set up the service", zap.Error(err))
+	}
+	workersStopped := service.Run(base)
+	grpcServer := service.GRPC
 
-	port := os.Getenv("PORT")
 	srv := &http.Server{
-		Addr:    fmt.Sprintf(":%s", port),
-		Handler: router,
+		Addr:        fmt.Sprintf(":%s", cfg.Server.Port),
+		Handler:     service.Router,
+		BaseContext: func(net.Listener) context.Context { return base },
 	}
 
 	// Initializing the server in a goroutine so that
 	// it won't block the graceful shutdown handling below
 	go func() {
-		if err := srv.ListenAndServe(); err != nil &&
-			errors.Is(err, http.ErrServerClosed) {
-			log.Printf("listen: %s\n", err)
+		var err error
+		if cfg.Server.TLS.Enabled() {
+			err = srv.ListenAndServeTLS(cfg.Server.TLS.CertFile, cfg.Server.TLS.KeyFile)
+		} else {
+			err = srv.ListenAndServe()
+		}
+		if err != nil && !errors.Is(err, http.ErrServerClosed) {
+			logger.Error("listen", zap.Error(err))
 		}
 	}()
 
+	if grpcServer != nil {
+		listener, err := net.Listen("tcp", fmt.Sprintf(":%s", cfg.Server.GRPCPort))
+		if err != nil {
+			logger.Fatal("unable to listen for gRPC calls", zap.String("port", cfg.Server.GRPCPort), zap.Error(err))
+		}
+
+		go func() {
+			if err := grpcServer.Serve(listener); err != nil {
+				logger.Error("gRPC serve", zap.Error(err))
+			}
+		}()
+	}
+
 	// Wait for interrupt signal to gracefully shutdown the server with
-	// a timeout of 5 seconds.
+	// the configured timeout.
 	quit := make(chan os.Signal, 1)
 	// kill (no param) default send syscall.SIGTERM
 	// kill -2 is syscall.SIGINT
 	// kill -9 is syscall.SIGKILL but can't be caught, so don't need to add it
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
 	<-quit
-	log.Println("Shutting down server...")
 
-	// The context is used to inform the server it has 5 seconds to finish
+	// Fail the readiness probe and keep serving while the load balancers take the server out of rotation,
+	// unless a second signal asks to shut down right away
+	service.Health.Drain()
+	logger.Info("draining server", zap.Duration("delay", cfg.Server.DrainDelay))
+	select {
+	case <-time.After(cfg.Server.DrainDelay):
+	case <-quit:
+	}
+
+	logger.Info("shutting down server")
+
+	// The context is used to inform the server it has the shutdown timeout to finish
 	// the request it is currently handling
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	ctx, cancel := context.WithTimeout(context.Background(), cfg.Server.ShutdownTimeout)
 	defer cancel()
 
-	if err := srv.Shutdown(ctx); err != nil {
-		log.Fatal("Server forced to shutdown:", err)
+	// Let in-flight gRPC calls finish within the same deadline, cutting off those that do not
+	stopped := make(chan struct{})
+	if grpcServer == nil {
+		close(stopped)
+	} else {
+		go func() {
+			grpcServer.GracefulStop()
+			close(stopped)
+		}()
+	}
+
+	err = srv.Shutdown(ctx)
+	// Cancel the requests still running past the deadline, rolling back their queries
+	cancelBase()
+	if err != nil {
+		logger.Fatal("server forced to shutdown", zap.Error(err))
+	}
+
+	select {
+	case <-stopped:
+	case <-ctx.Done():
+		grpcServer.Stop()
+	}
+
+	// Let the workers finish their batch before the database connections are closed
+	workersStopped()
+	if err := service.Close(); err != nil {
+		logger.Warn("unable to release the service's dependencies", zap.Error(err))
+	}
+
+	if err := shutdownTracing(ctx); err != nil {
+		logger.Warn("unable to flush the pending spans", zap.Error(err))
 	}
 
-	log.Println("Server exiting")
+	logger.Info("server exiting")
 }